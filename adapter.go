@@ -0,0 +1,109 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/goccy/go-json"
+)
+
+// RegisterTypeAdapter adds a function converting values of type t found in instances, such as
+// decimal.Decimal, sql.NullString or custom enums, to JSON-compatible values before evaluation.
+// The adapter receives values of exactly type t and may return any value the validator accepts,
+// including maps and slices holding other adapted types.
+func (c *Compiler) RegisterTypeAdapter(t reflect.Type, adapter func(interface{}) (interface{}, error)) *Compiler {
+	if c.TypeAdapters == nil {
+		c.TypeAdapters = make(map[reflect.Type]func(interface{}) (interface{}, error))
+	}
+	c.TypeAdapters[t] = adapter
+	return c
+}
+
+// adaptInstance converts the values of instance that have a registered type adapter, looking
+// into map[string]interface{} and []interface{} values. Other Go values without a generic JSON
+// form, such as structs, time.Time, named types and typed maps and slices, are converted to their
+// wire representation with encoding/json semantics by reflectValue, so json tags, json.Marshaler
+// and encoding.TextMarshaler implementations are honored. Containers are copied only when one of
+// their values changes.
+func (s *Schema) adaptInstance(instance interface{}) (interface{}, *EvaluationError) {
+	adapted, _, err := adaptValue(s.compiler, instance, "")
+	return adapted, err
+}
+
+// maxAdapterDepth bounds the adapters applied in a row to a single value, in case adapters
+// convert values back and forth.
+const maxAdapterDepth = 8
+
+// adaptValue converts value, located at the JSON Pointer location of the instance, with the type
+// adapters of compiler, which may be nil, and reports whether it changed.
+func adaptValue(compiler *Compiler, value interface{}, location string) (interface{}, bool, *EvaluationError) {
+	changed := false
+	for i := 0; value != nil && i < maxAdapterDepth; i++ {
+		adapter, ok := compiler.typeAdapter(reflect.TypeOf(value))
+		if !ok {
+			break
+		}
+		adapted, err := adapter(value)
+		if err != nil {
+			return nil, false, conversionError(location, err)
+		}
+		value, changed = adapted, true
+	}
+
+	switch v := value.(type) {
+	case nil, bool, string, json.Number, float64, float32, int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
+	case map[string]interface{}:
+		var adapted map[string]interface{}
+		for key, item := range v {
+			converted, itemChanged, err := adaptValue(compiler, item, location+"/"+escapeJSONPointer(key))
+			if err != nil {
+				return nil, false, err
+			}
+			if itemChanged && adapted == nil {
+				adapted = make(map[string]interface{}, len(v))
+				for k, original := range v {
+					adapted[k] = original
+				}
+			}
+			if itemChanged {
+				adapted[key] = converted
+			}
+		}
+		if adapted != nil {
+			return adapted, true, nil
+		}
+	case []interface{}:
+		var adapted []interface{}
+		for i, item := range v {
+			converted, itemChanged, err := adaptValue(compiler, item, location+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, false, err
+			}
+			if itemChanged && adapted == nil {
+				adapted = append([]interface{}(nil), v...)
+			}
+			if itemChanged {
+				adapted[i] = converted
+			}
+		}
+		if adapted != nil {
+			return adapted, true, nil
+		}
+	default:
+		converted, err := reflectValue(compiler, reflect.ValueOf(v), location, 0)
+		if err != nil {
+			return nil, false, err
+		}
+		return converted, true, nil
+	}
+	return value, changed, nil
+}
+
+// marshalInstance converts a Go value to a generic JSON value through its JSON encoding.
+func marshalInstance(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return decodeInstance(data)
+}
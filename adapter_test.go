@@ -0,0 +1,101 @@
+package jsonschema
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLevel int
+
+func (l testLevel) String() string {
+	return [...]string{"low", "high"}[l]
+}
+
+func TestTypeAdapters(t *testing.T) {
+	compiler := NewCompiler().
+		RegisterTypeAdapter(reflect.TypeOf(sql.NullString{}), func(v interface{}) (interface{}, error) {
+			if s := v.(sql.NullString); s.Valid {
+				return s.String, nil
+			}
+			return nil, nil
+		}).
+		RegisterTypeAdapter(reflect.TypeOf(testLevel(0)), func(v interface{}) (interface{}, error) {
+			return v.(testLevel).String(), nil
+		})
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"nickname": {"type": ["string", "null"], "minLength": 2},
+			"levels": {"type": "array", "items": {"enum": ["low", "high"]}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	instance := map[string]interface{}{
+		"nickname": sql.NullString{String: "ann", Valid: true},
+		"levels":   []interface{}{testLevel(0), testLevel(1)},
+	}
+	assert.True(t, schema.Validate(instance).IsValid())
+	assert.IsType(t, sql.NullString{}, instance["nickname"], "the instance is not modified")
+
+	assert.True(t, schema.Validate(map[string]interface{}{"nickname": sql.NullString{}}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"nickname": sql.NullString{String: "a", Valid: true}}).IsValid())
+}
+
+func TestTypeAdapterError(t *testing.T) {
+	compiler := NewCompiler().RegisterTypeAdapter(reflect.TypeOf(testLevel(0)), func(interface{}) (interface{}, error) {
+		return nil, errors.New("unknown level")
+	})
+	schema, err := compiler.Compile([]byte(`{"type": "object"}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{"level": testLevel(3)})
+	require.False(t, result.IsValid())
+	assert.Equal(t, "type_adapter_failed", result.Errors["type"].Code)
+	assert.Equal(t, "Value at '/level' could not be converted: unknown level", result.Errors["type"].Error())
+}
+
+type testColor int
+
+func (c testColor) MarshalText() ([]byte, error) {
+	if c > 1 {
+		return nil, errors.New("unknown color")
+	}
+	return []byte([...]string{"red", "green"}[c]), nil
+}
+
+func TestMarshalerInstances(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"at": {"type": "string", "format": "date-time"},
+			"color": {"enum": ["red", "green"]},
+			"sizes": {"items": {"type": "integer", "minimum": 1}},
+			"owner": {"type": "object", "required": ["name"]}
+		}
+	}`))
+	require.NoError(t, err)
+
+	type owner struct {
+		Name string `json:"name"`
+	}
+	instance := map[string]interface{}{
+		"at":    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		"color": testColor(1),
+		"sizes": []int{1, 2},
+		"owner": owner{Name: "Ann"},
+	}
+	assert.True(t, schema.Validate(instance).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"sizes": []int{0}}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"owner": struct{}{}}).IsValid())
+
+	result := schema.Validate(map[string]interface{}{"color": testColor(5)})
+	require.False(t, result.IsValid())
+	assert.Equal(t, "type_adapter_failed", result.Errors["type"].Code)
+}
@@ -26,7 +26,11 @@ func evaluateAdditionalProperties(schema *Schema, object map[string]interface{},
 		}
 	}
 	if schema.PatternProperties != nil {
-		for _, regex := range schema.compiledPatterns {
+		for pattern := range *schema.PatternProperties {
+			regex, err := schema.regex(pattern)
+			if err != nil {
+				continue
+			}
 			for propName := range object {
 				if regex.MatchString(propName) {
 					properties[propName] = true
@@ -39,7 +43,9 @@ func evaluateAdditionalProperties(schema *Schema, object map[string]interface{},
 	if schema.AdditionalProperties != nil {
 		for propName, propValue := range object {
 			if !properties[propName] {
+				dynamicScope.pushInstance(propName)
 				result, _, _ := schema.AdditionalProperties.evaluate(propValue, dynamicScope)
+				dynamicScope.popInstance()
 				if result != nil {
 					result.SetEvaluationPath(fmt.Sprintf("/additionalProperties/%s", propName)).
 						SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/additionalProperties/%s", propName))).
@@ -57,6 +63,12 @@ func evaluateAdditionalProperties(schema *Schema, object map[string]interface{},
 		}
 	}
 
+	if schema.AdditionalProperties.IsFalse() {
+		if err := notAllowedPropertiesError("additionalProperties", "additional_property_not_allowed", "Additional property {property} is not allowed", "additional_properties_not_allowed", "Additional properties {properties} are not allowed", invalid_properties); err != nil {
+			return results, err
+		}
+	}
+
 	if len(invalid_properties) == 1 {
 		return results, NewEvaluationError("additionalProperties", "additional_property_mismatch", "Additional property {property} does not match the schema", map[string]interface{}{
 			"property": fmt.Sprintf("'%s'", invalid_properties[0]),
@@ -0,0 +1,127 @@
+package jsonschema
+
+// arenaChunkSize is the number of results an Arena allocates at once.
+const arenaChunkSize = 256
+
+// EvaluationAllocator supplies the values an evaluation allocates for every subschema it applies:
+// the result and the sets of the properties and items the subschema evaluated. Values it returns
+// may have been used before; the evaluation clears them, keeping the capacity of their maps and
+// slices. Every value must stay untouched until the result of the validation is no longer used.
+type EvaluationAllocator interface {
+	Result() *EvaluationResult
+	PropertySet() map[string]bool
+	ItemSet() map[int]bool
+}
+
+// Arena is an EvaluationAllocator that hands out the values of previous validations again once
+// Reset is called, so that batch jobs validating many instances allocate close to nothing once
+// the arena has grown to the size of a validation. An Arena is not safe for concurrent use; use
+// one per goroutine.
+type Arena struct {
+	results    [][]EvaluationResult // Chunks of arenaChunkSize results.
+	properties []map[string]bool
+	items      []map[int]bool
+
+	nextResult     int
+	nextProperties int
+	nextItems      int
+}
+
+// NewArena returns an empty Arena.
+func NewArena() *Arena {
+	return &Arena{}
+}
+
+// Result returns the next unused result.
+func (a *Arena) Result() *EvaluationResult {
+	chunk, index := a.nextResult/arenaChunkSize, a.nextResult%arenaChunkSize
+	if chunk == len(a.results) {
+		a.results = append(a.results, make([]EvaluationResult, arenaChunkSize))
+	}
+	a.nextResult++
+	return &a.results[chunk][index]
+}
+
+// PropertySet returns the next unused set of properties.
+func (a *Arena) PropertySet() map[string]bool {
+	if a.nextProperties == len(a.properties) {
+		a.properties = append(a.properties, make(map[string]bool))
+	}
+	a.nextProperties++
+	return a.properties[a.nextProperties-1]
+}
+
+// ItemSet returns the next unused set of items.
+func (a *Arena) ItemSet() map[int]bool {
+	if a.nextItems == len(a.items) {
+		a.items = append(a.items, make(map[int]bool))
+	}
+	a.nextItems++
+	return a.items[a.nextItems-1]
+}
+
+// Reset makes every value of the arena available again. Results of the validations that used the
+// arena must not be used afterwards.
+func (a *Arena) Reset() {
+	a.nextResult, a.nextProperties, a.nextItems = 0, 0, 0
+}
+
+// ValidateWithAllocator checks if the given instance conforms to the schema like Validate, taking
+// the results and sets the evaluation needs from allocator, such as an Arena, instead of the heap:
+//
+//	arena := jsonschema.NewArena()
+//	for _, instance := range batch {
+//		valid := schema.ValidateWithAllocator(instance, arena).IsValid()
+//		arena.Reset()
+//		...
+//	}
+//
+// The result is only valid until the allocator reuses its values.
+func (s *Schema) ValidateWithAllocator(instance interface{}, allocator EvaluationAllocator) *EvaluationResult {
+	dynamicScope := NewDynamicScope()
+	dynamicScope.assertFormat = s.assertFormat
+	dynamicScope.allocator = allocator
+
+	return s.validate(instance, dynamicScope)
+}
+
+// newResult returns a result for schema, from the allocator of the evaluation when it has one.
+func (ds *DynamicScope) newResult(schema *Schema) *EvaluationResult {
+	if ds.allocator == nil {
+		return NewEvaluationResult(schema)
+	}
+	result := ds.allocator.Result()
+	annotations, errors, details := result.Annotations, result.Errors, result.Details
+	clear(annotations)
+	clear(errors)
+	*result = EvaluationResult{
+		schema:      schema,
+		Valid:       true,
+		Annotations: annotations,
+		Errors:      errors,
+		Details:     details[:0],
+	}
+	return result.CollectAnnotations()
+}
+
+// newPropertySet returns an empty set of evaluated properties, from the allocator of the
+// evaluation when it has one.
+func (ds *DynamicScope) newPropertySet() map[string]bool {
+	if ds.allocator == nil {
+		return make(map[string]bool)
+	}
+	set := ds.allocator.PropertySet()
+	clear(set)
+	return set
+}
+
+// newItemSet returns an empty set of evaluated items, from the allocator of the evaluation when
+// it has one.
+func (ds *DynamicScope) newItemSet() map[int]bool {
+	if ds.allocator == nil {
+		return make(map[int]bool)
+	}
+	set := ds.allocator.ItemSet()
+	clear(set)
+	return set
+}
@@ -0,0 +1,90 @@
+package jsonschema
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const arenaSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 2},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"age": {"type": "integer", "minimum": 0}
+	},
+	"required": ["name"],
+	"unevaluatedProperties": false
+}`
+
+func TestValidateWithAllocator(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(arenaSchema))
+	require.NoError(t, err)
+
+	instances := []interface{}{
+		map[string]interface{}{"name": "Ada", "tags": []interface{}{"a", "b"}, "age": 36},
+		map[string]interface{}{"name": "A", "tags": []interface{}{"a", 1}, "age": -1, "extra": true},
+		map[string]interface{}{"tags": []interface{}{}},
+		"not an object",
+	}
+
+	arena := NewArena()
+	for round := 0; round < 3; round++ {
+		for _, instance := range instances {
+			expected := sortedList(schema.Validate(instance).ToList())
+			got := sortedList(schema.ValidateWithAllocator(instance, arena).ToList())
+			assert.Equal(t, expected, got)
+			arena.Reset()
+		}
+	}
+}
+
+// sortedList sorts the details of list, whose order follows the iteration of properties.
+func sortedList(list *List) *List {
+	sort.Slice(list.Details, func(i, j int) bool {
+		return list.Details[i].EvaluationPath+list.Details[i].InstanceLocation <
+			list.Details[j].EvaluationPath+list.Details[j].InstanceLocation
+	})
+	for i := range list.Details {
+		sortedList(&list.Details[i])
+	}
+	return list
+}
+
+func TestArenaReusesValues(t *testing.T) {
+	arena := NewArena()
+	result := arena.Result()
+	properties := arena.PropertySet()
+	items := arena.ItemSet()
+	assert.NotSame(t, result, arena.Result())
+
+	arena.Reset()
+	assert.Same(t, result, arena.Result())
+	properties["name"] = true
+	assert.True(t, arena.PropertySet()["name"], "PropertySet returns the previous set")
+	items[0] = true
+	assert.True(t, arena.ItemSet()[0], "ItemSet returns the previous set")
+}
+
+func TestArenaAllocations(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(arenaSchema))
+	require.NoError(t, err)
+	instance := map[string]interface{}{"name": "Ada", "tags": []interface{}{"a", "b"}, "age": 36}
+
+	arena := NewArena()
+	schema.ValidateWithAllocator(instance, arena)
+	arena.Reset()
+
+	withArena := testing.AllocsPerRun(50, func() {
+		schema.ValidateWithAllocator(instance, arena)
+		arena.Reset()
+	})
+	withoutArena := testing.AllocsPerRun(50, func() {
+		schema.Validate(instance)
+	})
+	assert.Less(t, withArena, withoutArena)
+}
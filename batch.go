@@ -0,0 +1,154 @@
+package jsonschema
+
+import (
+	"sort"
+	"strings"
+)
+
+// BatchResult holds the results of ValidateBatch, in the order of the instances, and their summary.
+type BatchResult struct {
+	Results []*EvaluationResult `json:"results"`
+	Summary *BatchSummary       `json:"summary"`
+}
+
+// BatchSummary aggregates the results of validating many instances, for data-quality reporting.
+type BatchSummary struct {
+	Total   int `json:"total"`   // Number of instances validated.
+	Valid   int `json:"valid"`   // Number of valid instances.
+	Invalid int `json:"invalid"` // Number of invalid instances.
+
+	ErrorCodes map[string]int          `json:"errorCodes"` // Number of errors by code, over every instance.
+	Paths      map[string]*PathSummary `json:"paths"`      // Errors by instance location, see WorstPaths.
+}
+
+// PathSummary counts the errors at an instance location over the instances of a batch.
+type PathSummary struct {
+	InstanceLocation string `json:"instanceLocation"`
+	Instances        int    `json:"instances"` // Number of instances with errors at the location.
+	Errors           int    `json:"errors"`    // Number of errors at the location, over every instance.
+}
+
+// NewBatchSummary returns an empty summary, to aggregate results with Add.
+func NewBatchSummary() *BatchSummary {
+	return &BatchSummary{
+		ErrorCodes: make(map[string]int),
+		Paths:      make(map[string]*PathSummary),
+	}
+}
+
+// ValidateBatch validates every instance and returns the results along with their summary.
+func (s *Schema) ValidateBatch(instances []interface{}) *BatchResult {
+	batch := &BatchResult{
+		Results: make([]*EvaluationResult, len(instances)),
+		Summary: NewBatchSummary(),
+	}
+	for i, instance := range instances {
+		batch.Results[i] = s.Validate(instance)
+		batch.Summary.Add(batch.Results[i])
+	}
+	return batch
+}
+
+// ValidateChannel validates the instances received from instances until it is closed, passing
+// every result to each, which may be nil, along with the index of the instance, and returns the
+// summary of the results. Results are not kept, so that datasets of any size can be validated.
+func (s *Schema) ValidateChannel(instances <-chan interface{}, each func(index int, result *EvaluationResult)) *BatchSummary {
+	summary := NewBatchSummary()
+	index := 0
+	for instance := range instances {
+		result := s.Validate(instance)
+		summary.Add(result)
+		if each != nil {
+			each(index, result)
+		}
+		index++
+	}
+	return summary
+}
+
+// Add adds the result of validating an instance to the summary. Errors of applicators such as
+// properties or $ref that only summarize the errors of their subschemas are not counted, so that
+// the counts point at the values that failed.
+func (b *BatchSummary) Add(result *EvaluationResult) {
+	b.Total++
+	if result.IsValid() {
+		b.Valid++
+		return
+	}
+	b.Invalid++
+
+	counted := map[string]bool{}
+	var visit func(result *EvaluationResult)
+	visit = func(result *EvaluationResult) {
+		for _, err := range result.Errors {
+			if explainedByDetails(result, err.Keyword) {
+				continue
+			}
+			b.ErrorCodes[err.Code]++
+
+			path, ok := b.Paths[result.InstanceLocation]
+			if !ok {
+				path = &PathSummary{InstanceLocation: result.InstanceLocation}
+				b.Paths[result.InstanceLocation] = path
+			}
+			path.Errors++
+			if !counted[result.InstanceLocation] {
+				counted[result.InstanceLocation] = true
+				path.Instances++
+			}
+		}
+		for _, detail := range result.Details {
+			visit(detail)
+		}
+	}
+	visit(result)
+}
+
+// explainedByDetails reports whether the error of keyword in result only summarizes errors of the
+// subschemas of the keyword, which are counted instead.
+func explainedByDetails(result *EvaluationResult, keyword string) bool {
+	var target *Schema
+	if result.schema != nil {
+		switch keyword {
+		case "$ref":
+			target = result.schema.ResolvedRef
+		case "$dynamicRef":
+			target = result.schema.ResolvedDynamicRef
+		}
+	}
+	prefix := "/" + keyword
+	for _, detail := range result.Details {
+		if detail.IsValid() {
+			continue
+		}
+		if target != nil && detail.schema == target {
+			return true
+		}
+		if detail.EvaluationPath == prefix || strings.HasPrefix(detail.EvaluationPath, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// WorstPaths returns the n instance locations with errors in the most instances, then with the
+// most errors, or all of them when n is not positive.
+func (b *BatchSummary) WorstPaths(n int) []PathSummary {
+	paths := make([]PathSummary, 0, len(b.Paths))
+	for _, path := range b.Paths {
+		paths = append(paths, *path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if paths[i].Instances != paths[j].Instances {
+			return paths[i].Instances > paths[j].Instances
+		}
+		if paths[i].Errors != paths[j].Errors {
+			return paths[i].Errors > paths[j].Errors
+		}
+		return paths[i].InstanceLocation < paths[j].InstanceLocation
+	})
+	if n > 0 && n < len(paths) {
+		paths = paths[:n]
+	}
+	return paths
+}
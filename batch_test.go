@@ -0,0 +1,74 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatchSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"email": {"type": "string", "minLength": 3}
+		},
+		"required": ["id"]
+	}`))
+	require.NoError(t, err)
+	return schema
+}
+
+func TestValidateBatch(t *testing.T) {
+	schema := newBatchSchema(t)
+	batch := schema.ValidateBatch([]interface{}{
+		map[string]interface{}{"id": 1, "email": "ada@example.com"},
+		map[string]interface{}{"id": "2", "email": "x"},
+		map[string]interface{}{"id": 3, "email": 3},
+		map[string]interface{}{"id": 4, "email": "a"},
+	})
+
+	require.Len(t, batch.Results, 4)
+	assert.True(t, batch.Results[0].IsValid())
+	assert.False(t, batch.Results[1].IsValid())
+
+	summary := batch.Summary
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 1, summary.Valid)
+	assert.Equal(t, 3, summary.Invalid)
+	assert.Equal(t, map[string]int{"type_mismatch": 2, "string_too_short": 2}, summary.ErrorCodes, "errors of properties are counted at the properties")
+
+	assert.Equal(t, []PathSummary{{InstanceLocation: "/email", Instances: 3, Errors: 3}}, summary.WorstPaths(1))
+	assert.Equal(t, []PathSummary{
+		{InstanceLocation: "/email", Instances: 3, Errors: 3},
+		{InstanceLocation: "/id", Instances: 1, Errors: 1},
+	}, summary.WorstPaths(0))
+}
+
+func TestValidateChannel(t *testing.T) {
+	schema := newBatchSchema(t)
+	instances := make(chan interface{})
+	go func() {
+		defer close(instances)
+		for i := 0; i < 100; i++ {
+			if i%10 == 0 {
+				instances <- map[string]interface{}{"id": "invalid"}
+			} else {
+				instances <- map[string]interface{}{"id": i}
+			}
+		}
+	}()
+
+	var invalid []int
+	summary := schema.ValidateChannel(instances, func(index int, result *EvaluationResult) {
+		if !result.IsValid() {
+			invalid = append(invalid, index)
+		}
+	})
+	assert.Equal(t, 100, summary.Total)
+	assert.Equal(t, 10, summary.Invalid)
+	assert.Equal(t, []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90}, invalid)
+	assert.Equal(t, []PathSummary{{InstanceLocation: "/id", Instances: 10, Errors: 10}}, summary.WorstPaths(1))
+}
@@ -0,0 +1,58 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IsBoolean reports whether s is a boolean schema, true or false, rather than an object schema.
+func (s *Schema) IsBoolean() bool {
+	return s != nil && s.Boolean != nil
+}
+
+// IsTrue reports whether s is the true schema, which accepts every instance. The empty object
+// schema accepts every instance too, but is not a boolean schema.
+func (s *Schema) IsTrue() bool {
+	return s.IsBoolean() && *s.Boolean
+}
+
+// IsFalse reports whether s is the false schema, which rejects every instance.
+func (s *Schema) IsFalse() bool {
+	return s.IsBoolean() && !*s.Boolean
+}
+
+// notAllowedPropertiesError returns the error of keyword for the properties rejected by a false
+// subschema, which are not allowed at all rather than mismatching a schema, or nil.
+func notAllowedPropertiesError(keyword, code, message, pluralCode, pluralMessage string, properties []string) *EvaluationError {
+	switch len(properties) {
+	case 0:
+		return nil
+	case 1:
+		return NewEvaluationError(keyword, code, message, map[string]interface{}{
+			"property": fmt.Sprintf("'%s'", properties[0]),
+		})
+	}
+	quoted := make([]string, len(properties))
+	for i, property := range properties {
+		quoted[i] = fmt.Sprintf("'%s'", property)
+	}
+	return NewEvaluationError(keyword, pluralCode, pluralMessage, map[string]interface{}{
+		"properties": strings.Join(quoted, ", "),
+	})
+}
+
+// notAllowedItemsError returns the error of keyword for the items at indexes rejected by a false
+// subschema, or nil.
+func notAllowedItemsError(keyword string, indexes []string) *EvaluationError {
+	switch len(indexes) {
+	case 0:
+		return nil
+	case 1:
+		return NewEvaluationError(keyword, "item_not_allowed", "Item at index {index} is not allowed", map[string]interface{}{
+			"index": indexes[0],
+		})
+	}
+	return NewEvaluationError(keyword, "items_not_allowed", "Items at index {indexs} are not allowed", map[string]interface{}{
+		"indexs": strings.Join(indexes, ", "),
+	})
+}
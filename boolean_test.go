@@ -0,0 +1,118 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBooleanSchemasEverywhere(t *testing.T) {
+	tests := []struct {
+		schema  string
+		valid   []interface{}
+		invalid []interface{}
+	}{
+		{`true`, []interface{}{1, "x", nil}, nil},
+		{`false`, nil, []interface{}{1, "x", nil}},
+		{`{"properties": {"a": false, "b": true}}`, []interface{}{map[string]interface{}{"b": 1}}, []interface{}{map[string]interface{}{"a": 1}}},
+		{`{"additionalProperties": false}`, []interface{}{map[string]interface{}{}}, []interface{}{map[string]interface{}{"a": 1}}},
+		{`{"patternProperties": {"^x-": false}}`, []interface{}{map[string]interface{}{"y": 1}}, []interface{}{map[string]interface{}{"x-a": 1}}},
+		{`{"propertyNames": false}`, []interface{}{map[string]interface{}{}}, []interface{}{map[string]interface{}{"a": 1}}},
+		{`{"dependentSchemas": {"a": false}}`, []interface{}{map[string]interface{}{"b": 1}}, []interface{}{map[string]interface{}{"a": 1}}},
+		{`{"unevaluatedProperties": false, "properties": {"a": true}}`, []interface{}{map[string]interface{}{"a": 1}}, []interface{}{map[string]interface{}{"b": 1}}},
+		{`{"prefixItems": [true, false]}`, []interface{}{[]interface{}{1}}, []interface{}{[]interface{}{1, 2}}},
+		{`{"items": false}`, []interface{}{[]interface{}{}}, []interface{}{[]interface{}{1}}},
+		{`{"contains": false}`, nil, []interface{}{[]interface{}{1}, []interface{}{}}},
+		{`{"unevaluatedItems": false, "prefixItems": [true]}`, []interface{}{[]interface{}{1}}, []interface{}{[]interface{}{1, 2}}},
+		{`{"allOf": [true, false]}`, nil, []interface{}{1}},
+		{`{"anyOf": [false, true]}`, []interface{}{1}, nil},
+		{`{"oneOf": [true, true]}`, nil, []interface{}{1}},
+		{`{"not": false}`, []interface{}{1}, nil},
+		{`{"not": true}`, nil, []interface{}{1}},
+		{`{"if": true, "then": false}`, nil, []interface{}{1}},
+		{`{"if": false, "else": false}`, nil, []interface{}{1}},
+		{`{"$defs": {"never": false}, "$ref": "#/$defs/never"}`, nil, []interface{}{1}},
+	}
+
+	compiler := NewCompiler()
+	for _, test := range tests {
+		schema, err := compiler.Compile([]byte(test.schema))
+		require.NoError(t, err, test.schema)
+		for _, instance := range test.valid {
+			assert.True(t, schema.Validate(instance).IsValid(), "%s: %v", test.schema, instance)
+		}
+		for _, instance := range test.invalid {
+			assert.False(t, schema.Validate(instance).IsValid(), "%s: %v", test.schema, instance)
+		}
+	}
+}
+
+func TestBooleanSchemaErrors(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance interface{}
+		keyword  string
+		code     string
+		message  string
+	}{
+		{`{"properties": {"a": false}}`, map[string]interface{}{"a": 1}, "properties", "property_not_allowed", "Property 'a' is not allowed"},
+		{`{"additionalProperties": false}`, map[string]interface{}{"a": 1}, "additionalProperties", "additional_property_not_allowed", "Additional property 'a' is not allowed"},
+		{`{"unevaluatedProperties": false}`, map[string]interface{}{"a": 1}, "properties", "unevaluated_property_not_allowed", "Property 'a' is not allowed"},
+		{`{"items": false}`, []interface{}{1}, "items", "item_not_allowed", "Item at index 0 is not allowed"},
+		{`{"prefixItems": [true], "items": false}`, []interface{}{1, 2, 3}, "items", "items_not_allowed", "Items at index 1, 2 are not allowed"},
+		{`{"unevaluatedItems": false}`, []interface{}{1}, "unevaluatedItems", "item_not_allowed", "Item at index 0 is not allowed"},
+		{`{"properties": {"a": {"type": "string"}}}`, map[string]interface{}{"a": 1}, "properties", "property_mismatch", "Property 'a' does not match the schema"},
+	}
+
+	compiler := NewCompiler()
+	for _, test := range tests {
+		schema, err := compiler.Compile([]byte(test.schema))
+		require.NoError(t, err)
+		result := schema.Validate(test.instance)
+		require.Contains(t, result.Errors, test.keyword, test.schema)
+		assert.Equal(t, test.code, result.Errors[test.keyword].Code, test.schema)
+		assert.Equal(t, test.message, result.Errors[test.keyword].Error(), test.schema)
+	}
+
+	schema, err := compiler.Compile([]byte(`false`))
+	require.NoError(t, err)
+	assert.Equal(t, "false_schema_mismatch", schema.Validate(1).Errors["schema"].Code)
+}
+
+func TestBooleanSchemaIntrospection(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{"properties": {"a": true, "b": false, "c": {}}, "items": false}`))
+	require.NoError(t, err)
+
+	properties := *schema.Properties
+	assert.True(t, properties["a"].IsBoolean())
+	assert.True(t, properties["a"].IsTrue())
+	assert.False(t, properties["a"].IsFalse())
+	assert.True(t, properties["b"].IsFalse())
+	assert.False(t, properties["c"].IsBoolean(), "the empty schema is not a boolean schema")
+	assert.False(t, properties["c"].IsTrue())
+	assert.False(t, schema.IsBoolean())
+	assert.True(t, schema.Items.IsFalse())
+	assert.False(t, schema.Contains.IsBoolean(), "absent subschemas are not boolean schemas")
+
+	assert.Equal(t, 3, schema.Stats().BooleanSchemas)
+}
+
+func TestBooleanSchemaLocations(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{"$id": "https://example.com/never.json", "not": true, "items": false}`))
+	require.NoError(t, err)
+
+	list := schema.Validate(1).ToList()
+	require.Len(t, list.Details, 1)
+	assert.Equal(t, "/not", list.Details[0].EvaluationPath)
+
+	output := schema.Validate([]interface{}{1}).ToBasic()
+	locations := map[string]string{}
+	for _, unit := range output.Errors {
+		locations[unit.KeywordLocation] = unit.AbsoluteKeywordLocation
+	}
+	assert.Equal(t, "https://example.com/never.json#/items", locations["/items"], "the false schema is the failing keyword")
+	assert.NotContains(t, locations, "/items/schema")
+}
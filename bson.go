@@ -0,0 +1,317 @@
+package jsonschema
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// BSON element types, as defined by https://bsonspec.org/spec.html.
+const (
+	bsonDouble        = 0x01
+	bsonString        = 0x02
+	bsonDocument      = 0x03
+	bsonArray         = 0x04
+	bsonBinary        = 0x05
+	bsonUndefined     = 0x06
+	bsonObjectID      = 0x07
+	bsonBoolean       = 0x08
+	bsonDateTime      = 0x09
+	bsonNull          = 0x0A
+	bsonRegex         = 0x0B
+	bsonJavaScript    = 0x0D
+	bsonSymbol        = 0x0E
+	bsonCodeWithScope = 0x0F
+	bsonInt32         = 0x10
+	bsonTimestamp     = 0x11
+	bsonInt64         = 0x12
+	bsonDecimal128    = 0x13
+)
+
+// BSON binary subtypes given a specific representation.
+const (
+	bsonBinaryOld  = 0x02
+	bsonBinaryUUID = 0x04
+)
+
+// unmarshalBSON is the handler of the application/bson media type.
+func unmarshalBSON(data []byte) (interface{}, error) {
+	return BSONInstance(data)
+}
+
+// BSONInstance decodes a BSON document, such as one read from MongoDB, to an object instance.
+// Values without a JSON counterpart are mapped to representations that JSON Schema can describe:
+//
+//   - ObjectId becomes its 24 character hexadecimal string;
+//   - Date becomes an RFC 3339 string in UTC, matching the date-time format;
+//   - Binary becomes a base64 string, matching contentEncoding base64, except UUIDs (subtype 4),
+//     which become strings matching the uuid format;
+//   - Int32 and Int64 become integers, and Decimal128 an exact number, or the string NaN,
+//     Infinity or -Infinity;
+//   - Timestamp becomes an object with the seconds t and the increment i, and Regex an object with
+//     its pattern and options;
+//   - JavaScript code and symbols become strings, and Undefined becomes null.
+func BSONInstance(data []byte) (map[string]interface{}, error) {
+	r := &bsonReader{data: data}
+	document, err := r.document(false)
+	if err != nil {
+		return nil, err
+	}
+	if r.offset != len(data) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidBSON, len(data)-r.offset)
+	}
+	return document.(map[string]interface{}), nil
+}
+
+// ValidateBSON decodes a BSON document with BSONInstance and validates it. The error reports a
+// document that cannot be decoded.
+func (s *Schema) ValidateBSON(data []byte) (*EvaluationResult, error) {
+	instance, err := BSONInstance(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.Validate(instance), nil
+}
+
+// bsonReader decodes BSON data from its offset.
+type bsonReader struct {
+	data   []byte
+	offset int
+}
+
+// next returns the next n bytes.
+func (r *bsonReader) next(n int) ([]byte, error) {
+	if n < 0 || len(r.data)-r.offset < n {
+		return nil, fmt.Errorf("%w: unexpected end of data at offset %d", ErrInvalidBSON, r.offset)
+	}
+	b := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return b, nil
+}
+
+// int32 reads a little-endian int32.
+func (r *bsonReader) int32() (int32, error) {
+	b, err := r.next(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.LittleEndian.Uint32(b)), nil //nolint:gosec
+}
+
+// uint64 reads a little-endian uint64.
+func (r *bsonReader) uint64() (uint64, error) {
+	b, err := r.next(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// cstring reads a null-terminated string.
+func (r *bsonReader) cstring() (string, error) {
+	for i := r.offset; i < len(r.data); i++ {
+		if r.data[i] == 0 {
+			s := string(r.data[r.offset:i])
+			r.offset = i + 1
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unterminated string at offset %d", ErrInvalidBSON, r.offset)
+}
+
+// string reads a length-prefixed, null-terminated string.
+func (r *bsonReader) string() (string, error) {
+	length, err := r.int32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.next(int(length))
+	if err != nil {
+		return "", err
+	}
+	if length < 1 || b[length-1] != 0 {
+		return "", fmt.Errorf("%w: malformed string at offset %d", ErrInvalidBSON, r.offset-len(b))
+	}
+	return string(b[:length-1]), nil
+}
+
+// document reads an embedded document, or an array when array is true, in which case the keys of
+// the elements are ignored.
+func (r *bsonReader) document(array bool) (interface{}, error) {
+	start := r.offset
+	length, err := r.int32()
+	if err != nil {
+		return nil, err
+	}
+	end := start + int(length)
+	if length < 5 || end > len(r.data) || r.data[end-1] != 0 {
+		return nil, fmt.Errorf("%w: malformed document at offset %d", ErrInvalidBSON, start)
+	}
+
+	object := map[string]interface{}{}
+	items := []interface{}{}
+	for r.offset < end-1 {
+		kind := r.data[r.offset]
+		r.offset++
+		name, err := r.cstring()
+		if err != nil {
+			return nil, err
+		}
+		value, err := r.value(kind)
+		if err != nil {
+			return nil, err
+		}
+		if array {
+			items = append(items, value)
+		} else {
+			object[name] = value
+		}
+	}
+	if r.offset != end-1 {
+		return nil, fmt.Errorf("%w: element overflows its document at offset %d", ErrInvalidBSON, start)
+	}
+	r.offset = end
+
+	if array {
+		return items, nil
+	}
+	return object, nil
+}
+
+// value reads a value of the element type kind.
+func (r *bsonReader) value(kind byte) (interface{}, error) {
+	switch kind {
+	case bsonDouble:
+		bits, err := r.uint64()
+		return math.Float64frombits(bits), err
+	case bsonString, bsonJavaScript, bsonSymbol:
+		return r.string()
+	case bsonDocument:
+		return r.document(false)
+	case bsonArray:
+		return r.document(true)
+	case bsonBinary:
+		return r.binary()
+	case bsonUndefined, bsonNull:
+		return nil, nil
+	case bsonObjectID:
+		b, err := r.next(12)
+		if err != nil {
+			return nil, err
+		}
+		return hex.EncodeToString(b), nil
+	case bsonBoolean:
+		b, err := r.next(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case bsonDateTime:
+		millis, err := r.uint64()
+		return time.UnixMilli(int64(millis)).UTC().Format(time.RFC3339Nano), err //nolint:gosec
+	case bsonRegex:
+		pattern, err := r.cstring()
+		if err != nil {
+			return nil, err
+		}
+		options, err := r.cstring()
+		return map[string]interface{}{"pattern": pattern, "options": options}, err
+	case bsonCodeWithScope:
+		if _, err := r.int32(); err != nil {
+			return nil, err
+		}
+		code, err := r.string()
+		if err != nil {
+			return nil, err
+		}
+		_, err = r.document(false) // The scope has no JSON counterpart.
+		return code, err
+	case bsonInt32:
+		i, err := r.int32()
+		return int(i), err
+	case bsonTimestamp:
+		bits, err := r.uint64()
+		return map[string]interface{}{"t": int64(bits >> 32), "i": int64(bits & math.MaxUint32)}, err
+	case bsonInt64:
+		bits, err := r.uint64()
+		return int64(bits), err //nolint:gosec
+	case bsonDecimal128:
+		low, err := r.uint64()
+		if err != nil {
+			return nil, err
+		}
+		high, err := r.uint64()
+		return decimal128Value(high, low), err
+	}
+	return nil, fmt.Errorf("%w: unsupported element type 0x%02X", ErrInvalidBSON, kind)
+}
+
+// binary reads binary data.
+func (r *bsonReader) binary() (interface{}, error) {
+	length, err := r.int32()
+	if err != nil {
+		return nil, err
+	}
+	subtype, err := r.next(1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.next(int(length))
+	if err != nil {
+		return nil, err
+	}
+
+	switch subtype[0] {
+	case bsonBinaryOld:
+		// The deprecated generic subtype repeats the length inside the data.
+		if len(data) >= 4 {
+			data = data[4:]
+		}
+	case bsonBinaryUUID:
+		if len(data) == 16 {
+			s := hex.EncodeToString(data)
+			return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:], nil
+		}
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decimal128Value converts an IEEE 754-2008 decimal128, in the binary integer decimal encoding
+// used by BSON, to an exact json.Number, or a string for the special values.
+func decimal128Value(high, low uint64) interface{} {
+	negative := high>>63 == 1
+	var exponent int
+	significand := new(big.Int)
+	switch {
+	case (high>>58)&0x1F == 0x1F:
+		return "NaN"
+	case (high>>58)&0x1F == 0x1E:
+		if negative {
+			return "-Infinity"
+		}
+		return "Infinity"
+	case (high>>61)&0x3 == 0x3:
+		// The significand of this form exceeds the maximum, so it is non-canonical and reads as zero.
+		exponent = int((high>>47)&0x3FFF) - 6176
+	default:
+		exponent = int((high>>49)&0x3FFF) - 6176
+		significand.SetUint64(high & (1<<49 - 1))
+		significand.Lsh(significand, 64)
+		significand.Or(significand, new(big.Int).SetUint64(low))
+	}
+
+	number := significand.String()
+	if negative {
+		number = "-" + number
+	}
+	if exponent != 0 {
+		number += fmt.Sprintf("E%d", exponent)
+	}
+	return json.Number(number)
+}
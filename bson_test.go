@@ -0,0 +1,145 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bsonElement is an element of a BSON document built by bsonDoc.
+type bsonElement struct {
+	kind  byte
+	name  string
+	value []byte
+}
+
+// bsonDoc encodes elements as a BSON document.
+func bsonDoc(elements ...bsonElement) []byte {
+	var body bytes.Buffer
+	for _, element := range elements {
+		body.WriteByte(element.kind)
+		body.WriteString(element.name)
+		body.WriteByte(0)
+		body.Write(element.value)
+	}
+	body.WriteByte(0)
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(body.Len()+4)), body.Bytes()...)
+}
+
+func bsonStr(s string) []byte {
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(len(s)+1)), append([]byte(s), 0)...)
+}
+
+func bsonBin(subtype byte, data []byte) []byte {
+	return append(append(binary.LittleEndian.AppendUint32(nil, uint32(len(data))), subtype), data...)
+}
+
+func bsonU64(v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, v)
+}
+
+// userBSON is a document as MongoDB stores it, with most element types.
+var userBSON = bsonDoc(
+	bsonElement{bsonObjectID, "_id", []byte{0x65, 0x0a, 0x1b, 0x2c, 0x3d, 0x4e, 0x5f, 0x60, 0x71, 0x82, 0x93, 0xa4}},
+	bsonElement{bsonString, "name", bsonStr("John")},
+	bsonElement{bsonInt32, "age", binary.LittleEndian.AppendUint32(nil, 42)},
+	bsonElement{bsonInt64, "visits", bsonU64(9007199254740993)},
+	bsonElement{bsonDouble, "score", bsonU64(math.Float64bits(4.5))},
+	bsonElement{bsonDecimal128, "balance", append(bsonU64(123), bsonU64(6174<<49)...)},
+	bsonElement{bsonBoolean, "active", []byte{1}},
+	bsonElement{bsonDateTime, "createdAt", bsonU64(1700000000123)},
+	bsonElement{bsonBinary, "avatar", bsonBin(0, []byte("png"))},
+	bsonElement{bsonBinary, "session", bsonBin(bsonBinaryUUID, []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00})},
+	bsonElement{bsonNull, "deletedAt", nil},
+	bsonElement{bsonArray, "tags", bsonDoc(
+		bsonElement{bsonString, "0", bsonStr("a")},
+		bsonElement{bsonString, "1", bsonStr("b")},
+	)},
+	bsonElement{bsonDocument, "address", bsonDoc(bsonElement{bsonString, "city", bsonStr("Oslo")})},
+	bsonElement{bsonTimestamp, "op", bsonU64(1700000000<<32 | 7)},
+	bsonElement{bsonRegex, "filter", []byte("^a\x00i\x00")},
+)
+
+func TestBSONInstance(t *testing.T) {
+	instance, err := BSONInstance(userBSON)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"_id":       "650a1b2c3d4e5f60718293a4",
+		"name":      "John",
+		"age":       42,
+		"visits":    int64(9007199254740993),
+		"score":     4.5,
+		"balance":   json.Number("123E-2"),
+		"active":    true,
+		"createdAt": "2023-11-14T22:13:20.123Z",
+		"avatar":    base64.StdEncoding.EncodeToString([]byte("png")),
+		"session":   "123e4567-e89b-12d3-a456-426614174000",
+		"deletedAt": nil,
+		"tags":      []interface{}{"a", "b"},
+		"address":   map[string]interface{}{"city": "Oslo"},
+		"op":        map[string]interface{}{"t": int64(1700000000), "i": int64(7)},
+		"filter":    map[string]interface{}{"pattern": "^a", "options": "i"},
+	}, instance)
+}
+
+func TestBSONInstanceInvalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"empty":       nil,
+		"truncated":   userBSON[:len(userBSON)-3],
+		"trailing":    append(append([]byte(nil), userBSON...), 0),
+		"unsupported": bsonDoc(bsonElement{0x0C, "pointer", bsonStr("db.users")}),
+		"string":      bsonDoc(bsonElement{bsonString, "name", bsonU64(math.MaxUint64)}),
+	} {
+		_, err := BSONInstance(data)
+		assert.ErrorIs(t, err, ErrInvalidBSON, name)
+	}
+}
+
+func TestValidateBSON(t *testing.T) {
+	schema, err := NewCompiler().SetAssertFormat(true).Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"_id": {"type": "string", "pattern": "^[0-9a-f]{24}$"},
+			"age": {"type": "integer", "minimum": 18},
+			"visits": {"type": "integer"},
+			"balance": {"type": "number", "multipleOf": 0.01},
+			"createdAt": {"type": "string", "format": "date-time"},
+			"session": {"type": "string", "format": "uuid"},
+			"avatar": {"type": "string", "contentEncoding": "base64"}
+		},
+		"required": ["_id", "createdAt"]
+	}`))
+	require.NoError(t, err)
+
+	result, err := schema.ValidateBSON(userBSON)
+	require.NoError(t, err)
+	assert.True(t, result.IsValid(), result.ToList())
+
+	result, err = schema.ValidateBSON(bsonDoc(bsonElement{bsonInt32, "age", binary.LittleEndian.AppendUint32(nil, 12)}))
+	require.NoError(t, err)
+	assert.False(t, result.IsValid())
+
+	_, err = schema.ValidateBSON([]byte{1, 2})
+	require.ErrorIs(t, err, ErrInvalidBSON)
+}
+
+func TestBSONContentMediaType(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "string",
+		"contentEncoding": "base64",
+		"contentMediaType": "application/bson",
+		"contentSchema": {"required": ["name"], "properties": {"age": {"minimum": 50}}}
+	}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(base64.StdEncoding.EncodeToString(userBSON))
+	assert.False(t, result.IsValid())
+	assert.Contains(t, result.ToList().Errors, "contentSchema")
+}
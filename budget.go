@@ -0,0 +1,204 @@
+package jsonschema
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Budget limits the resources of a single validation, so that services can bound the time spent
+// on untrusted instances. A zero field leaves its resource unlimited.
+type Budget struct {
+	MaxDuration time.Duration // Time the validation may take, measured with the clock of the compiler.
+	MaxDepth    int           // Nesting of the subschemas evaluated at once, which grows with the instance and with references.
+	MaxErrors   int           // Errors reported before the validation stops.
+	MaxBytes    int64         // Size of the instance encoded as compact JSON.
+}
+
+// BudgetLimit identifies a limit of a Budget.
+type BudgetLimit int
+
+const (
+	// BudgetDuration is the MaxDuration limit.
+	BudgetDuration BudgetLimit = iota
+	// BudgetDepth is the MaxDepth limit.
+	BudgetDepth
+	// BudgetErrors is the MaxErrors limit.
+	BudgetErrors
+	// BudgetBytes is the MaxBytes limit.
+	BudgetBytes
+)
+
+// String returns the name of the limit.
+func (l BudgetLimit) String() string {
+	switch l {
+	case BudgetDuration:
+		return "duration"
+	case BudgetDepth:
+		return "depth"
+	case BudgetErrors:
+		return "errors"
+	case BudgetBytes:
+		return "bytes"
+	}
+	return "unknown"
+}
+
+// BudgetExceededError is returned by ValidateWithBudget when a validation is stopped by a limit of
+// its budget. It matches ErrBudgetExceeded with errors.Is.
+type BudgetExceededError struct {
+	Limit BudgetLimit // Limit that stopped the validation.
+	Max   int64       // Value of the limit, in nanoseconds for BudgetDuration.
+}
+
+// Error describes the limit that stopped the validation.
+func (e *BudgetExceededError) Error() string {
+	value := strconv.FormatInt(e.Max, 10)
+	if e.Limit == BudgetDuration {
+		value = time.Duration(e.Max).String()
+	}
+	return fmt.Sprintf("%s: %s limit of %s", ErrBudgetExceeded, e.Limit, value)
+}
+
+// Unwrap returns ErrBudgetExceeded.
+func (e *BudgetExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// budgetClockInterval is the number of subschemas evaluated between two readings of the clock.
+const budgetClockInterval = 64
+
+// ValidateWithBudget validates instance like Validate within the limits of budget. When a limit is
+// exceeded, the evaluation stops and the returned result, which is invalid, holds the errors found
+// so far with a budget_exceeded error at the root; the returned error is a *BudgetExceededError
+// telling which limit tripped.
+//
+// Limits are checked as subschemas are entered, so a single keyword, such as a uniqueItems over a
+// large array, completes before the duration is checked again.
+func (s *Schema) ValidateWithBudget(instance interface{}, budget Budget) (*EvaluationResult, error) {
+	dynamicScope := NewDynamicScope()
+	dynamicScope.assertFormat = s.assertFormat
+
+	var result *EvaluationResult
+	state := &budgetState{budget: budget, compiler: s.compiler, start: s.compiler.Now()}
+	if budget.MaxBytes > 0 {
+		adapted, err := s.adaptInstance(instance)
+		if err != nil {
+			result = dynamicScope.newResult(s)
+			result.AddError(err)
+			return result, nil
+		}
+		if instanceSize(adapted, budget.MaxBytes) > budget.MaxBytes {
+			state.exceed(BudgetBytes)
+			result = dynamicScope.newResult(s)
+		}
+		instance = adapted
+	}
+	if result == nil {
+		dynamicScope.budget = state
+		result = s.validate(instance, dynamicScope)
+	}
+
+	if state.exceeded == nil {
+		return result, nil
+	}
+	result.AddError(NewEvaluationError("budget", "budget_exceeded", "Validation stopped after exceeding the {limit} limit of its budget", map[string]interface{}{
+		"limit": state.exceeded.Limit.String(),
+	}))
+	return result, state.exceeded
+}
+
+// budgetState tracks the resources used by a validation with ValidateWithBudget, or the context
+// of a validation with ValidateContext.
+type budgetState struct {
+	budget   Budget
+	ctx      context.Context
+	compiler *Compiler
+	start    time.Time
+	entered  int // Subschemas entered so far.
+	errors   int // Errors reported by the subschemas evaluated so far.
+	exceeded *BudgetExceededError
+	canceled error // Error of the context that stopped the validation.
+}
+
+// enter reports whether a subschema at depth can be evaluated within the budget and before the
+// context is done. Once the validation is stopped, no further subschema is.
+func (b *budgetState) enter(depth int) bool {
+	if b.exceeded != nil || b.canceled != nil {
+		return false
+	}
+	if b.ctx != nil && b.entered%budgetClockInterval == 0 {
+		if b.canceled = b.ctx.Err(); b.canceled != nil {
+			return false
+		}
+	}
+	switch {
+	case b.budget.MaxErrors > 0 && b.errors >= b.budget.MaxErrors:
+		b.exceed(BudgetErrors)
+	case b.budget.MaxDepth > 0 && depth > b.budget.MaxDepth:
+		b.exceed(BudgetDepth)
+	case b.budget.MaxDuration > 0 && b.entered%budgetClockInterval == 0 && b.compiler.Now().Sub(b.start) > b.budget.MaxDuration:
+		b.exceed(BudgetDuration)
+	}
+	b.entered++
+	return b.exceeded == nil
+}
+
+// exit counts the errors of an evaluated subschema.
+func (b *budgetState) exit(result *EvaluationResult) {
+	b.errors += len(result.Errors)
+}
+
+// exceed records that limit stopped the validation.
+func (b *budgetState) exceed(limit BudgetLimit) {
+	limits := map[BudgetLimit]int64{
+		BudgetDuration: int64(b.budget.MaxDuration),
+		BudgetDepth:    int64(b.budget.MaxDepth),
+		BudgetErrors:   int64(b.budget.MaxErrors),
+		BudgetBytes:    b.budget.MaxBytes,
+	}
+	b.exceeded = &BudgetExceededError{Limit: limit, Max: limits[limit]}
+}
+
+// instanceSize returns the size of instance encoded as compact JSON, without escaping strings. It
+// stops counting once the size exceeds limit.
+func instanceSize(instance interface{}, limit int64) int64 {
+	switch v := instance.(type) {
+	case nil:
+		return 4
+	case bool:
+		if v {
+			return 4
+		}
+		return 5
+	case string:
+		return int64(len(v)) + 2
+	case map[string]interface{}:
+		size := int64(1 + max(len(v), 1))
+		for key, value := range v {
+			if size > limit {
+				break
+			}
+			size += int64(len(key)) + 3 + instanceSize(value, limit-size)
+		}
+		return size
+	case []interface{}:
+		size := int64(1 + max(len(v), 1))
+		for _, item := range v {
+			if size > limit {
+				break
+			}
+			size += instanceSize(item, limit-size)
+		}
+		return size
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return 4
+		}
+		return int64(len(strconv.FormatFloat(v, 'g', -1, 64)))
+	default:
+		return int64(len(fmt.Sprint(v)))
+	}
+}
@@ -0,0 +1,108 @@
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// steppingClock advances by step each time it is read.
+type steppingClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func TestValidateWithBudget(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}}}},
+		"properties": {
+			"node": {"$ref": "#/$defs/node"},
+			"values": {"items": {"type": "integer"}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	instance := map[string]interface{}{
+		"node":   map[string]interface{}{"next": map[string]interface{}{"next": map[string]interface{}{}}},
+		"values": []interface{}{1, 2, 3},
+	}
+	result, err := schema.ValidateWithBudget(instance, Budget{MaxDepth: 100, MaxErrors: 5, MaxBytes: 1000, MaxDuration: time.Minute})
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+
+	result, err = schema.ValidateWithBudget(instance, Budget{MaxDepth: 4})
+	var exceeded *BudgetExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, BudgetDepth, exceeded.Limit)
+	assert.True(t, errors.Is(err, ErrBudgetExceeded))
+	assert.Equal(t, "validation budget exceeded: depth limit of 4", err.Error())
+	assert.False(t, result.IsValid())
+	assert.Equal(t, "budget_exceeded", result.Errors["budget"].Code)
+	assert.Equal(t, "Validation stopped after exceeding the depth limit of its budget", result.Errors["budget"].Error())
+}
+
+func TestValidateWithBudgetErrors(t *testing.T) {
+	evaluated := 0
+	compiler := NewCompiler().RegisterKeyword("counted", func(*Schema, interface{}) (Keyword, error) {
+		return KeywordFunc(func(*KeywordContext, interface{}) *EvaluationError {
+			evaluated++
+			return nil
+		}), nil
+	})
+	schema, err := compiler.Compile([]byte(`{"items": {"type": "integer", "counted": true}}`))
+	require.NoError(t, err)
+
+	instance := []interface{}{"a", "b", "c", "d", 1}
+	result, err := schema.ValidateWithBudget(instance, Budget{MaxErrors: 2})
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Equal(t, BudgetErrors, err.(*BudgetExceededError).Limit)
+	assert.Equal(t, 2, evaluated, "the evaluation stops at the limit")
+	assert.False(t, result.IsValid())
+
+	evaluated = 0
+	_, err = schema.ValidateWithBudget(instance, Budget{MaxErrors: 10})
+	assert.NoError(t, err, "invalid instances within the budget")
+	assert.Equal(t, 5, evaluated)
+}
+
+func TestValidateWithBudgetBytes(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"type": "object"}`))
+	require.NoError(t, err)
+
+	instance := map[string]interface{}{"name": "Ann", "tags": []interface{}{"a", true, nil, 1.5}}
+	assert.Equal(t, int64(len(`{"name":"Ann","tags":["a",true,null,1.5]}`)), instanceSize(instance, 1000))
+
+	_, err = schema.ValidateWithBudget(instance, Budget{MaxBytes: 41})
+	assert.NoError(t, err)
+	result, err := schema.ValidateWithBudget(instance, Budget{MaxBytes: 40})
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Equal(t, BudgetBytes, err.(*BudgetExceededError).Limit)
+	assert.False(t, result.IsValid())
+	assert.Len(t, result.Errors, 1, "instances over the size limit are not evaluated")
+}
+
+func TestValidateWithBudgetDuration(t *testing.T) {
+	compiler := NewCompiler().SetClock(&steppingClock{now: time.Unix(0, 0), step: time.Second})
+	schema, err := compiler.Compile([]byte(`{"items": {"type": "integer"}}`))
+	require.NoError(t, err)
+
+	instance := make([]interface{}, 1000)
+	for i := range instance {
+		instance[i] = i
+	}
+	_, err = schema.ValidateWithBudget(instance, Budget{MaxDuration: time.Hour})
+	require.NoError(t, err)
+
+	result, err := schema.ValidateWithBudget(instance, Budget{MaxDuration: 5 * time.Second})
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Equal(t, "validation budget exceeded: duration limit of 5s", err.Error())
+	assert.False(t, result.IsValid())
+}
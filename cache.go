@@ -0,0 +1,78 @@
+package jsonschema
+
+import (
+	"context"
+	"time"
+)
+
+// cacheKeyPrefix prefixes the keys of the documents stored in a Cache, so that the cache can be
+// shared with other data.
+const cacheKeyPrefix = "jsonschema:"
+
+// Cache is an external store for the schema documents a compiler fetches through its loaders,
+// such as Redis or memcached, so that a fleet of validators shares fetch results instead of each
+// fetching every remote $ref. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored under key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl, or without expiration when ttl is 0.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the value stored under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// SetCache sets the cache consulted before fetching a document with a loader, and the time to
+// live of the documents stored in it. The cache is best effort: documents are fetched with the
+// loaders when the cache fails, and failures to store them are ignored.
+func (c *Compiler) SetCache(cache Cache, ttl time.Duration) *Compiler {
+	c.Cache = cache
+	c.CacheTTL = ttl
+	return c
+}
+
+// fetchCachedDocument returns the document at url from the mirror or the cache when present, and
+// otherwise fetches it with fetchDocument and stores it in the cache and the mirror.
+func (c *Compiler) fetchCachedDocument(ctx context.Context, url string) ([]byte, error) {
+	if c.mirror == nil {
+		return c.fetchSharedDocument(ctx, url)
+	}
+
+	id, _ := splitRef(url)
+	if data, ok, err := c.mirror.read(id); err != nil || ok {
+		return data, err
+	}
+	data, err := c.fetchSharedDocument(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.mirror.store(id, data, c.Now()); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// fetchSharedDocument returns the document at url from the cache when present, and otherwise
+// fetches it with fetchDocument and stores it in the cache.
+func (c *Compiler) fetchSharedDocument(ctx context.Context, url string) ([]byte, error) {
+	if c.Cache == nil {
+		return c.fetchDocument(ctx, url)
+	}
+
+	key := documentCacheKey(url)
+	if data, ok, err := c.Cache.Get(ctx, key); err == nil && ok {
+		return data, nil
+	}
+
+	data, err := c.fetchDocument(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.Cache.Set(ctx, key, data, c.CacheTTL)
+	return data, nil
+}
+
+// documentCacheKey returns the cache key of the document at url.
+func documentCacheKey(url string) string {
+	id, _ := splitRef(url)
+	return cacheKeyPrefix + id
+}
@@ -0,0 +1,102 @@
+package jsonschema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapCache is a Cache backed by a map, ignoring time to live.
+type mapCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newMapCache() *mapCache {
+	return &mapCache{values: map[string][]byte{}, ttls: map[string]time.Duration{}}
+}
+
+func (c *mapCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, false, c.err
+	}
+	value, ok := c.values[key]
+	return value, ok, nil
+}
+
+func (c *mapCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.values[key] = value
+	c.ttls[key] = ttl
+	return nil
+}
+
+func (c *mapCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
+	return nil
+}
+
+func TestCacheSharesFetchedDocuments(t *testing.T) {
+	cache := newMapCache()
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/name.json": `{"type": "string"}`,
+	}}
+	schemaJSON := []byte(`{"properties": {"name": {"$ref": "test://schemas/name.json"}}}`)
+
+	first := NewCompiler().RegisterLoader("test", source.load).SetCache(cache, time.Hour)
+	_, err := first.Compile(schemaJSON)
+	require.NoError(t, err)
+	assert.Equal(t, 1, source.fetched)
+	assert.Equal(t, []byte(`{"type": "string"}`), cache.values["jsonschema:test://schemas/name.json"])
+	assert.Equal(t, time.Hour, cache.ttls["jsonschema:test://schemas/name.json"])
+
+	second := NewCompiler().RegisterLoader("test", source.load).SetCache(cache, time.Hour)
+	schema, err := second.Compile(schemaJSON)
+	require.NoError(t, err)
+	assert.Equal(t, 1, source.fetched, "second compiler uses the cache")
+	assert.False(t, schema.Validate(map[string]interface{}{"name": 1}).IsValid())
+}
+
+func TestCacheFailureFallsBackToLoader(t *testing.T) {
+	cache := newMapCache()
+	cache.err = errors.New("connection refused")
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/name.json": `{"type": "string"}`,
+	}}
+
+	compiler := NewCompiler().RegisterLoader("test", source.load).SetCache(cache, 0)
+	schema, err := compiler.Compile([]byte(`{"$ref": "test://schemas/name.json"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, source.fetched)
+	assert.True(t, schema.Validate("John").IsValid())
+}
+
+func TestCheckRemotesInvalidatesCache(t *testing.T) {
+	cache := newMapCache()
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/name.json": `{"type": "string"}`,
+	}}
+	compiler := NewCompiler().RegisterLoader("test", source.load).SetCache(cache, 0)
+	_, err := compiler.Compile([]byte(`{"$ref": "test://schemas/name.json"}`))
+	require.NoError(t, err)
+
+	source.set("test://schemas/name.json", `{"type": "integer"}`)
+	_, err = compiler.CheckRemotes(context.Background())
+	require.ErrorIs(t, err, ErrRemoteChanged)
+	assert.NotContains(t, cache.values, "jsonschema:test://schemas/name.json")
+}
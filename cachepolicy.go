@@ -0,0 +1,162 @@
+package jsonschema
+
+import (
+	"container/heap"
+	"sync/atomic"
+	"time"
+)
+
+// CachePolicy bounds the schemas a compiler keeps of the documents its loaders fetched, such as
+// the targets of remote $ref, for long-running services that resolve many of them. Schemas
+// compiled from bytes are never evicted by the policy.
+type CachePolicy struct {
+	MaxEntries int           // Fetched schemas kept, evicting the least recently used beyond it; unbounded when 0.
+	TTL        time.Duration // Age after which a fetched schema is fetched and compiled again; never when 0.
+}
+
+// cacheEntry tracks the use of a schema compiled from a fetched document.
+type cacheEntry struct {
+	uri     string
+	fetched time.Time    // When the document was fetched.
+	used    atomic.Int64 // Value of Compiler.uses when the schema was last looked up.
+	stamp   int64        // Value of used when the entry was last placed in the cacheHeap.
+	index   int          // Index of the entry in the cacheHeap.
+}
+
+// cacheHeap orders the entries of the fetched schemas by their stamp, the least recently used at
+// the top. Lookups only update used, without a write lock, so stamps lag behind: an entry at the
+// top whose used moved on is placed again before it is evicted.
+type cacheHeap []*cacheEntry
+
+func (h cacheHeap) Len() int           { return len(h) }
+func (h cacheHeap) Less(i, j int) bool { return h[i].stamp < h[j].stamp }
+
+func (h cacheHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *cacheHeap) Push(x interface{}) {
+	entry := x.(*cacheEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *cacheHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// SetCachePolicy sets the bounds of the schemas compiled from fetched documents. Once a schema is
+// older than policy.TTL, references to it fetch and compile the document again, from the Cache
+// or the mirror of the compiler when it has one, so that changes are seen once they expire from
+// them. Schemas compiled before keep the schemas they reference, evicted or not.
+func (c *Compiler) SetCachePolicy(policy CachePolicy) *Compiler {
+	c.CachePolicy = policy
+	return c
+}
+
+// Evict removes the schema cached under uri, and the document it was compiled from, so that the
+// next reference to uri fetches and compiles it again. It reports whether a schema was cached
+// under uri. Schemas of the compilers an overlay is layered on are not evicted.
+func (c *Compiler) Evict(uri string) bool {
+	id, _ := splitRef(uri)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.schemas[id]
+	c.evict(id)
+	return ok
+}
+
+// Reset removes every cached schema and fetched document, keeping the settings, loaders and
+// registrations of the compiler, so that schemas are compiled and documents fetched again.
+func (c *Compiler) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schemas = make(map[string]*Schema)
+	c.loaded = nil
+	c.entries = nil
+	c.lru = nil
+	c.snapshots.Range(func(schema, _ interface{}) bool {
+		c.snapshots.Delete(schema)
+		return true
+	})
+	c.timings.reset()
+}
+
+// cachedSchema returns the schema cached under uri in c, unless it was compiled from a document
+// fetched longer than the TTL of the cache policy ago, and marks it as used. c.mu must be held.
+func (c *Compiler) cachedSchema(uri string) (*Schema, bool) {
+	schema, ok := c.schemas[uri]
+	if !ok {
+		return nil, false
+	}
+	if entry := c.entries[uri]; entry != nil {
+		if c.expired(entry) {
+			return nil, false
+		}
+		entry.used.Store(c.uses.Add(1))
+	}
+	return schema, true
+}
+
+// recordFetched records the document fetched from uri and the schema compiled from it, and
+// evicts the expired schemas and then the least recently used ones beyond the cache policy.
+// c.mu must be held for writing.
+func (c *Compiler) recordFetched(uri string, data []byte) {
+	if c.loaded == nil {
+		c.loaded = make(map[string][]byte)
+	}
+	c.loaded[uri] = data
+	if c.entries == nil {
+		c.entries = make(map[string]*cacheEntry)
+	}
+	if previous := c.entries[uri]; previous != nil {
+		heap.Remove(&c.lru, previous.index)
+	}
+	entry := &cacheEntry{uri: uri, fetched: c.Now()}
+	entry.stamp = c.uses.Add(1)
+	entry.used.Store(entry.stamp)
+	c.entries[uri] = entry
+	heap.Push(&c.lru, entry)
+
+	if c.CachePolicy.TTL > 0 {
+		for id, entry := range c.entries {
+			if c.expired(entry) {
+				c.evict(id)
+			}
+		}
+	}
+	for c.CachePolicy.MaxEntries > 0 && len(c.entries) > c.CachePolicy.MaxEntries {
+		oldest := c.lru[0]
+		if used := oldest.used.Load(); used != oldest.stamp {
+			oldest.stamp = used
+			heap.Fix(&c.lru, 0)
+			continue
+		}
+		c.evict(oldest.uri)
+	}
+}
+
+// expired reports whether the schema of entry is older than the TTL of the cache policy.
+func (c *Compiler) expired(entry *cacheEntry) bool {
+	return c.CachePolicy.TTL > 0 && c.Now().Sub(entry.fetched) >= c.CachePolicy.TTL
+}
+
+// evict removes the schema cached under uri, its fetched document and its measurements. c.mu must
+// be held for writing.
+func (c *Compiler) evict(uri string) {
+	if schema, ok := c.schemas[uri]; ok {
+		c.snapshots.Delete(schema)
+		c.registry().forget(schema)
+	}
+	if entry := c.entries[uri]; entry != nil {
+		heap.Remove(&c.lru, entry.index)
+	}
+	delete(c.schemas, uri)
+	delete(c.loaded, uri)
+	delete(c.entries, uri)
+}
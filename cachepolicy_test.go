@@ -0,0 +1,118 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLoader serves {"const": n} documents, where n counts the fetches of each URL.
+func countingLoader(fetches map[string]int) func(string) (io.ReadCloser, error) {
+	return func(url string) (io.ReadCloser, error) {
+		fetches[url]++
+		return io.NopCloser(strings.NewReader(fmt.Sprintf(`{"const": %d}`, fetches[url]))), nil
+	}
+}
+
+func TestCachePolicyMaxEntries(t *testing.T) {
+	fetches := map[string]int{}
+	compiler := NewCompiler().SetCachePolicy(CachePolicy{MaxEntries: 2})
+	compiler.RegisterLoader("mem", countingLoader(fetches))
+	_, err := compiler.Compile([]byte(`{"$id": "mem:///root.json"}`))
+	require.NoError(t, err)
+
+	for _, uri := range []string{"mem:///a.json", "mem:///b.json", "mem:///a.json", "mem:///c.json"} {
+		_, err := compiler.GetSchema(uri)
+		require.NoError(t, err)
+	}
+	assert.Contains(t, compiler.schemas, "mem:///a.json")
+	assert.NotContains(t, compiler.schemas, "mem:///b.json", "the least recently used schema is evicted")
+	assert.Contains(t, compiler.schemas, "mem:///c.json")
+	assert.Contains(t, compiler.schemas, "mem:///root.json", "compiled schemas are not evicted")
+	assert.Len(t, compiler.loaded, 2)
+
+	schema, err := compiler.GetSchema("mem:///b.json")
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(2).IsValid(), "evicted schemas are fetched again")
+}
+
+func TestCachePolicyTTL(t *testing.T) {
+	fetches := map[string]int{}
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	compiler := NewCompiler().SetClock(clock).SetCachePolicy(CachePolicy{TTL: time.Minute})
+	compiler.RegisterLoader("mem", countingLoader(fetches))
+
+	root, err := compiler.Compile([]byte(`{"$id": "mem:///root.json", "$ref": "mem:///value.json"}`))
+	require.NoError(t, err)
+	clock.Advance(30 * time.Second)
+	schema, err := compiler.GetSchema("mem:///value.json")
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(1).IsValid())
+
+	clock.Advance(30 * time.Second)
+	schema, err = compiler.GetSchema("mem:///value.json")
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(2).IsValid(), "expired schemas are fetched again")
+	assert.True(t, root.Validate(1).IsValid(), "compiled schemas keep the schemas they reference")
+
+	other, err := compiler.Compile([]byte(`{"$id": "mem:///other.json", "$ref": "mem:///value.json"}`))
+	require.NoError(t, err)
+	assert.True(t, other.Validate(2).IsValid())
+	assert.Equal(t, 2, fetches["mem:///value.json"])
+}
+
+func TestEvict(t *testing.T) {
+	fetches := map[string]int{}
+	compiler := NewCompiler()
+	compiler.RegisterLoader("mem", countingLoader(fetches))
+	_, err := compiler.Compile([]byte(`{"$id": "mem:///root.json"}`))
+	require.NoError(t, err)
+	_, err = compiler.GetSchema("mem:///value.json")
+	require.NoError(t, err)
+
+	assert.True(t, compiler.Evict("mem:///value.json#/const"))
+	assert.False(t, compiler.Evict("mem:///value.json"))
+	assert.NotContains(t, compiler.loaded, "mem:///value.json")
+	schema, err := compiler.GetSchema("mem:///value.json")
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(2).IsValid())
+
+	compiler.Reset()
+	assert.Empty(t, compiler.schemas)
+	assert.Empty(t, compiler.loaded)
+	schema, err = compiler.GetSchema("mem:///value.json")
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(3).IsValid())
+	_, err = compiler.Compile([]byte(`{"$id": "mem:///root.json"}`))
+	assert.NoError(t, err, "the compiler stays usable")
+}
+
+func TestCachePolicyForgetsStats(t *testing.T) {
+	fetches := map[string]int{}
+	compiler := NewCompiler().SetCollectStats(true).SetCollectFailures(true).SetCachePolicy(CachePolicy{MaxEntries: 3})
+	compiler.RegisterLoader("mem", countingLoader(fetches))
+
+	for i := 0; i < 50; i++ {
+		schema, err := compiler.GetSchema(fmt.Sprintf("mem:///%d.json", i))
+		require.NoError(t, err)
+		schema.Validate(1)
+		// The first schema is used all along, so it is never the least recently used.
+		first, err := compiler.GetSchema("mem:///0.json")
+		require.NoError(t, err)
+		first.Validate(0)
+	}
+	assert.Len(t, compiler.entries, 3)
+	assert.Len(t, compiler.lru, 3)
+	assert.Contains(t, compiler.schemas, "mem:///0.json")
+	assert.Len(t, compiler.Stats(), 3, "the measurements of evicted schemas are removed")
+	assert.Len(t, compiler.timings.schemas, 3)
+
+	compiler.Reset()
+	assert.Empty(t, compiler.Stats())
+	assert.Empty(t, compiler.lru)
+}
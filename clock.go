@@ -0,0 +1,71 @@
+package jsonschema
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of the current time of a compiler, used wherever time matters, such as
+// timing statistics, recordings and reload ages. Tests and replay tooling set a ManualClock so
+// that they run deterministically; custom formats that depend on the current time should read it
+// from Compiler.Now for the same reason.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock of the system time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the Clock of the system time, used by compilers without another clock.
+var SystemClock Clock = systemClock{}
+
+// ManualClock is a Clock whose time only changes when it is set or advanced. It is safe for
+// concurrent use.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock returns a ManualClock set to now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set sets the time of the clock.
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the time of the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// SetClock sets the source of the current time of the compiler; nil restores SystemClock.
+func (c *Compiler) SetClock(clock Clock) *Compiler {
+	c.Clock = clock
+	return c
+}
+
+// Now returns the current time of the compiler's clock.
+func (c *Compiler) Now() time.Time {
+	if c == nil || c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
@@ -0,0 +1,65 @@
+package jsonschema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), clock.Now())
+
+	clock.Set(start)
+	assert.Equal(t, start, clock.Now())
+}
+
+func TestCompilerClock(t *testing.T) {
+	recordedAt := time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)
+	compiler := NewCompiler().SetClock(NewManualClock(recordedAt))
+	compiler.CollectStats = true
+	assert.Equal(t, recordedAt, compiler.Now())
+	assert.Equal(t, recordedAt, compiler.Overlay().Now())
+
+	schema, err := compiler.Compile([]byte(`{"$id": "https://example.com/clock.json", "type": "string"}`))
+	require.NoError(t, err)
+	_, recording, err := schema.Record("x")
+	require.NoError(t, err)
+	assert.Equal(t, recordedAt, recording.RecordedAt)
+
+	stats := compiler.Stats()
+	require.Len(t, stats, 1)
+	assert.Zero(t, stats[0].CompileDuration, "durations are measured with the clock")
+
+	assert.WithinDuration(t, time.Now(), NewCompiler().Now(), time.Minute)
+	assert.WithinDuration(t, time.Now(), compiler.SetClock(nil).Now(), time.Minute)
+}
+
+func TestReloaderMaxAgeClock(t *testing.T) {
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/value.json": `{"type": "string"}`,
+	}}
+	clock := NewManualClock(time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC))
+	compiler := NewCompiler().SetClock(clock).RegisterLoader("test", source.load)
+	reloader, err := NewReloader(compiler, "test://schemas/value.json")
+	require.NoError(t, err)
+	reloader.SetMaxAge(time.Minute)
+	source.set("test://schemas/value.json", `{"type": "integer"}`)
+
+	clock.Advance(time.Minute)
+	reloader.Schema()
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, reloader.Schema().Validate(1).IsValid(), "the schema is not older than the maximum age on the clock")
+
+	clock.Advance(time.Second)
+	reloader.Schema()
+	assert.Eventually(t, func() bool {
+		return reloader.Schema().Validate(1).IsValid()
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/goccy/go-yaml"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// Document formats understood by the convert subcommand.
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+var (
+	// errMissingInput is returned when convert is called without an input file.
+	errMissingInput = errors.New("expected exactly one input file (use - for stdin)")
+
+	// errUnknownFormat is returned for unsupported --from/--to values.
+	errUnknownFormat = errors.New("unknown format")
+
+	// errUndetectableFormat is returned when the input format cannot be derived from the file name.
+	errUndetectableFormat = errors.New("cannot detect input format, set --from")
+
+	// errUnsupportedDraft is returned for --to-draft values other than 2020-12.
+	errUnsupportedDraft = errors.New("unsupported target draft")
+)
+
+// runConvert implements "jsonschema convert".
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	from := flags.String("from", "", "input format: json or yaml (detected from the file extension by default)")
+	to := flags.String("to", "", "output format: json or yaml (the opposite of the input format by default)")
+	toDraft := flags.String("to-draft", "", "migrate the schema to this draft: 2020-12 (object keys are then sorted)")
+	output := flags.String("o", "", "output file (stdout by default)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errMissingInput
+	}
+	if *toDraft != "" && *toDraft != jsonschema.Draft2020.String() {
+		return fmt.Errorf("%w %q, expected %s", errUnsupportedDraft, *toDraft, jsonschema.Draft2020)
+	}
+
+	input := flags.Arg(0)
+	source, err := detectFormat(input, *from)
+	if err != nil {
+		return err
+	}
+
+	target := *to
+	if target == "" {
+		target = oppositeFormat(source)
+	}
+
+	data, err := readInput(input)
+	if err != nil {
+		return err
+	}
+
+	if *toDraft != "" {
+		migrated, warnings, err := migrateSchemaDocument(data, source)
+		if err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", input, warning)
+		}
+		data, source = migrated, formatJSON
+	}
+
+	converted, err := convertSchemaDocument(data, source, target)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(converted)
+		return err
+	}
+	return os.WriteFile(*output, converted, 0o644) //nolint:gosec
+}
+
+// convertSchemaDocument converts a schema document between the JSON and YAML
+// representations, preserving the order of object keys. YAML comments cannot
+// be represented in JSON and are dropped when converting to JSON.
+func convertSchemaDocument(data []byte, source, target string) ([]byte, error) {
+	if err := checkFormat(source); err != nil {
+		return nil, err
+	}
+	if err := checkFormat(target); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case source == formatYAML && target == formatJSON:
+		compact, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		return indentJSON(compact)
+	case source == formatJSON && target == formatYAML:
+		return yaml.JSONToYAML(data)
+	case source == formatJSON:
+		return indentJSON(data)
+	default:
+		// YAML to YAML is a no-op that keeps comments intact; parse it to report syntax errors.
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+}
+
+// migrateSchemaDocument rewrites a schema document, written in the draft its $schema declares, to
+// its 2020-12 equivalent in JSON, and returns the keywords dropped because the draft ignores them.
+func migrateSchemaDocument(data []byte, source string) ([]byte, []jsonschema.CompileWarning, error) {
+	if err := checkFormat(source); err != nil {
+		return nil, nil, err
+	}
+	if source == formatYAML {
+		converted, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = converted
+	}
+	return jsonschema.NewCompiler().MigrateDraft(data)
+}
+
+// indentJSON re-indents a JSON document without reordering its keys.
+func indentJSON(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// detectFormat returns the explicit format if set, otherwise derives it from the file extension.
+func detectFormat(path, explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, checkFormat(explicit)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return formatJSON, nil
+	case ".yaml", ".yml":
+		return formatYAML, nil
+	default:
+		return "", errUndetectableFormat
+	}
+}
+
+// checkFormat verifies that format is one of the supported document formats.
+func checkFormat(format string) error {
+	if format != formatJSON && format != formatYAML {
+		return fmt.Errorf("%w %q", errUnknownFormat, format)
+	}
+	return nil
+}
+
+// oppositeFormat returns the format a document is converted to by default.
+func oppositeFormat(format string) string {
+	if format == formatYAML {
+		return formatJSON
+	}
+	return formatYAML
+}
+
+// readInput reads the named file, or stdin when name is "-".
+func readInput(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(name) //nolint:gosec
+}
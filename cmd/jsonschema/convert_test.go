@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertSchemaDocument(t *testing.T) {
+	yamlSchema := "# user schema\ntype: object\nproperties:\n  name:\n    type: string\nrequired:\n  - name\n"
+
+	converted, err := convertSchemaDocument([]byte(yamlSchema), formatYAML, formatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "type": "object",
+  "properties": {
+    "name": {
+      "type": "string"
+    }
+  },
+  "required": [
+    "name"
+  ]
+}
+`, string(converted))
+
+	back, err := convertSchemaDocument(converted, formatJSON, formatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, "type: object\nproperties:\n  name:\n    type: string\nrequired:\n- name\n", string(back))
+
+	_, err = convertSchemaDocument([]byte(yamlSchema), formatYAML, "toml")
+	assert.ErrorIs(t, err, errUnknownFormat)
+}
+
+func TestDetectFormat(t *testing.T) {
+	format, err := detectFormat("schemas/user.yml", "")
+	require.NoError(t, err)
+	assert.Equal(t, formatYAML, format)
+
+	_, err = detectFormat("-", "")
+	assert.ErrorIs(t, err, errUndetectableFormat)
+}
+
+func TestMigrateSchemaDocument(t *testing.T) {
+	yamlSchema := "$schema: http://json-schema.org/draft-07/schema#\n" +
+		"definitions:\n  name:\n    type: string\n" +
+		"properties:\n  name:\n    $ref: '#/definitions/name'\n    minLength: 1\n" +
+		"dependencies:\n  name: [id]\n"
+
+	migrated, warnings, err := migrateSchemaDocument([]byte(yamlSchema), formatYAML)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "#/properties/name/minLength", warnings[0].KeywordLocation)
+
+	converted, err := convertSchemaDocument(migrated, formatJSON, formatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, "$defs:\n  name:\n    type: string\n"+
+		"$schema: https://json-schema.org/draft/2020-12/schema\n"+
+		"dependentRequired:\n  name:\n  - id\n"+
+		"properties:\n  name:\n    $ref: \"#/$defs/name\"\n", string(converted))
+
+	_, _, err = migrateSchemaDocument([]byte(yamlSchema), "toml")
+	assert.ErrorIs(t, err, errUnknownFormat)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+var (
+	// errMissingSchemaName is returned when instances are given without --schema.
+	errMissingSchemaName = errors.New("set --schema to validate instances")
+
+	// errUnknownSchema is returned when --schema names no schema of --schema-dir.
+	errUnknownSchema = errors.New("unknown schema")
+
+	// errDeprecatedInUse is returned with --strict when an instance uses a deprecated subschema.
+	errDeprecatedInUse = errors.New("deprecated subschemas in use")
+)
+
+// runDeprecations implements "jsonschema deprecations".
+func runDeprecations(args []string) error {
+	flags := flag.NewFlagSet("deprecations", flag.ContinueOnError)
+	schemaDir := flags.String("schema-dir", ".", "directory containing *.json schemas")
+	name := flags.String("schema", "", "schema validating the instances, named by its path in --schema-dir without .json")
+	strict := flags.Bool("strict", false, "fail when an instance uses a deprecated subschema")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 && *name == "" {
+		return errMissingSchemaName
+	}
+
+	compiler := jsonschema.NewCompiler().SetCollectFailures(true)
+	schemas, err := loadSchemaDir(compiler, *schemaDir)
+	if err != nil {
+		return err
+	}
+	if *name != "" {
+		schema, ok := schemas[*name]
+		if !ok {
+			return fmt.Errorf("%w: %s", errUnknownSchema, *name)
+		}
+		for _, path := range flags.Args() {
+			if err := validateFile(schema, path); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+
+	inUse := writeDeprecations(os.Stdout, compiler, schemas)
+	if *strict && inUse > 0 {
+		return fmt.Errorf("%w: %d", errDeprecatedInUse, inUse)
+	}
+	return nil
+}
+
+// validateFile validates the JSON instance of the file at path against schema.
+func validateFile(schema *jsonschema.Schema, path string) error {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	var instance interface{}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return err
+	}
+	schema.Validate(instance)
+	return nil
+}
+
+// writeDeprecations writes the deprecated subschemas of schemas, by schema name, with the number
+// of validated instances that used them, and returns the number of those in use. Locations in
+// the file of the named schema are written relative to it.
+func writeDeprecations(w io.Writer, compiler *jsonschema.Compiler, schemas map[string]*jsonschema.Schema) int {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inUse := 0
+	for _, name := range names {
+		report := compiler.DeprecationReport(schemas[name])
+		uri := schemas[name].GetSchemaURI()
+		for _, usage := range report.Schemas {
+			location := strings.TrimPrefix(usage.Location, uri)
+			if report.Validations == 0 {
+				fmt.Fprintf(w, "%s %s: deprecated\n", name, location)
+				continue
+			}
+			fmt.Fprintf(w, "%s %s: used by %d of %d instances\n", name, location, usage.Uses, report.Validations)
+		}
+		inUse += len(report.InUse())
+	}
+	return inUse
+}
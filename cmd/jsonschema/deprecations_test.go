@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+func TestDeprecations(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user.json"), []byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"nickname": {"type": "string", "deprecated": true},
+			"fax": {"type": "string", "deprecated": true}
+		}
+	}`), 0o600))
+	instances := t.TempDir()
+	for name, instance := range map[string]string{"a.json": `{"name": "Ada", "nickname": "ada"}`, "b.json": `{"name": "Bob"}`} {
+		require.NoError(t, os.WriteFile(filepath.Join(instances, name), []byte(instance), 0o600))
+	}
+
+	compiler := jsonschema.NewCompiler().SetCollectFailures(true)
+	schemas, err := loadSchemaDir(compiler, dir)
+	require.NoError(t, err)
+	var out strings.Builder
+	assert.Equal(t, 0, writeDeprecations(&out, compiler, schemas))
+	assert.Equal(t, "user #/properties/fax: deprecated\nuser #/properties/nickname: deprecated\n", out.String())
+
+	for _, name := range []string{"a.json", "b.json"} {
+		require.NoError(t, validateFile(schemas["user"], filepath.Join(instances, name)))
+	}
+	out.Reset()
+	assert.Equal(t, 1, writeDeprecations(&out, compiler, schemas))
+	assert.Equal(t, "user #/properties/nickname: used by 1 of 2 instances\nuser #/properties/fax: used by 0 of 2 instances\n", out.String())
+
+	args := []string{"-schema-dir", dir, "-schema", "user", "-strict", filepath.Join(instances, "a.json")}
+	assert.ErrorIs(t, runDeprecations(args), errDeprecatedInUse)
+	assert.NoError(t, runDeprecations([]string{"-schema-dir", dir, "-schema", "user", filepath.Join(instances, "b.json")}))
+	assert.ErrorIs(t, runDeprecations([]string{"-schema-dir", dir, filepath.Join(instances, "b.json")}), errMissingSchemaName)
+	assert.ErrorIs(t, runDeprecations([]string{"-schema-dir", dir, "-schema", "missing"}), errUnknownSchema)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kaptinlin/jsonschema"
+	"github.com/kaptinlin/jsonschema/codegen"
+)
+
+// errOutsideBaseDir is returned when gen-go is given a schema file outside the working directory.
+var errOutsideBaseDir = errors.New("schema file outside the working directory")
+
+// runGenGo implements "jsonschema gen-go".
+func runGenGo(args []string) error {
+	flags := flag.NewFlagSet("gen-go", flag.ContinueOnError)
+	pkg := flags.String("package", "schemas", "name of the package of the generated file")
+	validators := flags.Bool("validators", false, "generate Validate methods checking values against their schema")
+	validateUnmarshal := flags.Bool("validate-unmarshal", false, "generate UnmarshalJSON methods rejecting invalid documents; implies -validators")
+	output := flags.String("o", "", "output file (stdout by default)")
+	paths, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return errMissingSchemaPath
+	}
+
+	files, err := schemaFiles(paths)
+	if err != nil {
+		return err
+	}
+	source, err := generateGo(".", files, codegen.Options{
+		Package:           *pkg,
+		Validators:        *validators,
+		ValidateUnmarshal: *validateUnmarshal,
+	})
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return os.WriteFile(*output, source, 0o644) //nolint:gosec
+}
+
+// generateGo returns the Go types of the schema files, which must be below the directory base.
+// Each file is compiled under a file URI relative to base, such as file:///schemas/user.json,
+// with references to other files resolved below base, so that the generated source, which embeds
+// the URIs with the validators, does not depend on where base is.
+func generateGo(base string, files []string, options codegen.Options) ([]byte, error) {
+	root, err := filepath.Abs(base)
+	if err != nil {
+		return nil, err
+	}
+	compiler := jsonschema.NewCompiler().UseFS(os.DirFS(root), "file:///")
+
+	schemas := make([]*jsonschema.Schema, 0, len(files))
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%w: %s", errOutsideBaseDir, file)
+		}
+		data, err := os.ReadFile(abs) //nolint:gosec
+		if err != nil {
+			return nil, err
+		}
+		schema, err := compiler.Compile(data, "file:///"+filepath.ToSlash(rel))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		schemas = append(schemas, schema)
+	}
+	return codegen.GenerateSchemas(schemas, options)
+}
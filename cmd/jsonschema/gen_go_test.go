@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema/codegen"
+)
+
+func TestGenerateGo(t *testing.T) {
+	dir := t.TempDir()
+	schemasDir := filepath.Join(dir, "schemas")
+	require.NoError(t, os.Mkdir(schemasDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(schemasDir, "user.json"), []byte(`{
+		"title": "User",
+		"type": "object",
+		"properties": {"name": {"type": "string"}, "home": {"$ref": "address.json"}},
+		"required": ["name"]
+	}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(schemasDir, "address.json"), []byte(`{
+		"type": "object",
+		"properties": {"city": {"type": "string"}}
+	}`), 0o600))
+
+	files, err := schemaFiles([]string{schemasDir})
+	require.NoError(t, err)
+	source, err := generateGo(dir, files, codegen.Options{Package: "models", Validators: true})
+	require.NoError(t, err)
+	code := string(source)
+	assert.Contains(t, code, "package models")
+	assert.Contains(t, code, "type User struct")
+	assert.Equal(t, 1, strings.Count(code, "type Address struct"), "shared types are declared once")
+	assert.Contains(t, code, "Home *Address `json:\"home,omitempty\"`")
+	assert.Contains(t, code, `return validate(v, "file:///schemas/user.json#")`)
+	assert.NotContains(t, code, filepath.ToSlash(dir), "the output does not depend on the location of the files")
+
+	again, err := generateGo(dir, files, codegen.Options{Package: "models", Validators: true})
+	require.NoError(t, err)
+	assert.Equal(t, code, string(again), "the output is stable")
+
+	_, err = generateGo(schemasDir, []string{filepath.Join(dir, "other.json")}, codegen.Options{})
+	assert.ErrorIs(t, err, errOutsideBaseDir)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/goccy/go-json"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// errMissingSample is returned when infer is called without sample documents.
+var errMissingSample = errors.New("expected at least one sample file or directory")
+
+// sniffedFormats are the formats infer detects on strings, in order of preference: a string
+// property gets the first format all its values have.
+var sniffedFormats = []struct {
+	name  string
+	check func(interface{}) bool
+}{
+	{"date-time", jsonschema.IsDateTime},
+	{"date", jsonschema.IsDate},
+	{"time", jsonschema.IsTime},
+	{"email", jsonschema.IsEmail},
+	{"uuid", jsonschema.IsUUID},
+	{"ipv4", jsonschema.IsIPV4},
+	{"ipv6", jsonschema.IsIPV6},
+	{"uri", jsonschema.IsURI},
+}
+
+// inferOptions configures the schema inferred from samples.
+type inferOptions struct {
+	// requiredThreshold is the fraction of the objects at a location a property must appear in to
+	// be required; properties are never required when it exceeds 1.
+	requiredThreshold float64
+	// formats enables detecting the format of strings, see sniffedFormats.
+	formats bool
+}
+
+// runInfer implements "jsonschema infer".
+func runInfer(args []string) error {
+	flags := flag.NewFlagSet("infer", flag.ContinueOnError)
+	threshold := flags.Float64("required-threshold", 1, "fraction of the samples of an object a property must appear in to be required (above 1 for none)")
+	formats := flags.Bool("formats", true, "detect the format of strings, such as date-time, email or uuid")
+	output := flags.String("o", "", "output file (stdout by default)")
+	paths, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return errMissingSample
+	}
+
+	files, err := schemaFiles(paths)
+	if err != nil {
+		return err
+	}
+	schema, err := inferFiles(files, inferOptions{requiredThreshold: *threshold, formats: *formats})
+	if err != nil {
+		return err
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output) //nolint:gosec
+		if err != nil {
+			return err
+		}
+		defer file.Close() //nolint:errcheck
+		w = file
+	}
+	return writeIndentedJSON(w, schema)
+}
+
+// inferFiles returns a draft 2020-12 schema every sample file validates against.
+func inferFiles(files []string, options inferOptions) (map[string]interface{}, error) {
+	root := &inferredNode{}
+	for _, file := range files {
+		data, err := os.ReadFile(file) //nolint:gosec
+		if err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var sample interface{}
+		if err := decoder.Decode(&sample); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		root.add(sample)
+	}
+	schema := root.schema(options)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	return schema, nil
+}
+
+// inferredNode accumulates the values seen at a location of the samples.
+type inferredNode struct {
+	types      map[string]bool          // JSON types of the values.
+	strings    []string                 // String values, for format detection.
+	objects    int                      // Number of object values.
+	properties map[string]*inferredNode // Values of the properties of the objects, by name.
+	counts     map[string]int           // Number of objects with each property.
+	items      *inferredNode            // Items of the array values.
+}
+
+// add merges value into the node.
+func (n *inferredNode) add(value interface{}) {
+	if n.types == nil {
+		n.types = map[string]bool{}
+	}
+	switch value := value.(type) {
+	case nil:
+		n.types["null"] = true
+	case bool:
+		n.types["boolean"] = true
+	case json.Number:
+		if f, err := value.Float64(); err == nil && f == math.Trunc(f) {
+			n.types["integer"] = true
+		} else {
+			n.types["number"] = true
+		}
+	case string:
+		n.types["string"] = true
+		n.strings = append(n.strings, value)
+	case []interface{}:
+		n.types["array"] = true
+		for _, item := range value {
+			if n.items == nil {
+				n.items = &inferredNode{}
+			}
+			n.items.add(item)
+		}
+	case map[string]interface{}:
+		n.types["object"] = true
+		n.objects++
+		if n.properties == nil {
+			n.properties = map[string]*inferredNode{}
+			n.counts = map[string]int{}
+		}
+		for name, property := range value {
+			if n.properties[name] == nil {
+				n.properties[name] = &inferredNode{}
+			}
+			n.properties[name].add(property)
+			n.counts[name]++
+		}
+	}
+}
+
+// schema returns the schema of the values of the node.
+func (n *inferredNode) schema(options inferOptions) map[string]interface{} {
+	schema := map[string]interface{}{}
+	types := make([]string, 0, len(n.types))
+	for name := range n.types {
+		// Integers are numbers, so a location with both has numbers.
+		if name != "integer" || !n.types["number"] {
+			types = append(types, name)
+		}
+	}
+	sort.Strings(types)
+	switch len(types) {
+	case 0:
+		return schema
+	case 1:
+		schema["type"] = types[0]
+	default:
+		schema["type"] = types
+	}
+
+	if options.formats && len(n.strings) > 0 {
+		if format := sniffFormat(n.strings); format != "" {
+			schema["format"] = format
+		}
+	}
+	if n.items != nil {
+		schema["items"] = n.items.schema(options)
+	}
+	if n.objects > 0 && len(n.properties) > 0 {
+		properties := make(map[string]interface{}, len(n.properties))
+		required := []string{}
+		for name, property := range n.properties {
+			properties[name] = property.schema(options)
+			if float64(n.counts[name]) >= options.requiredThreshold*float64(n.objects) {
+				required = append(required, name)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+	}
+	return schema
+}
+
+// sniffFormat returns the first of sniffedFormats that every value has, or "".
+func sniffFormat(values []string) string {
+	for _, format := range sniffedFormats {
+		matched := true
+		for _, value := range values {
+			if !format.check(value) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return format.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+func TestInfer(t *testing.T) {
+	dir := t.TempDir()
+	samples := map[string]string{
+		"a.json": `{"id": "6f1c2e4a-3b7d-4c8e-9f10-1a2b3c4d5e6f", "email": "ada@example.com", "age": 36, "tags": ["x"], "createdAt": "2024-01-02T03:04:05Z"}`,
+		"b.json": `{"id": "0b9e8d7c-6a5f-4e3d-8c2b-1a0f9e8d7c6b", "email": "bob@example.com", "age": 41.5, "tags": [], "nickname": null}`,
+		"c.json": `{"id": "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f", "email": "not an email", "age": 7, "nickname": "cy"}`,
+	}
+	for name, data := range samples {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600))
+	}
+	files, err := schemaFiles([]string{dir})
+	require.NoError(t, err)
+
+	inferred, err := inferFiles(files, inferOptions{requiredThreshold: 1, formats: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"age", "email", "id"}, inferred["required"])
+	properties := inferred["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "uuid"}, properties["id"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, properties["email"], "formats all values have")
+	assert.Equal(t, map[string]interface{}{"type": "number"}, properties["age"])
+	assert.Equal(t, map[string]interface{}{"type": []string{"null", "string"}}, properties["nickname"])
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}, properties["tags"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "date-time"}, properties["createdAt"])
+
+	data, err := json.Marshal(inferred)
+	require.NoError(t, err)
+	schema, err := jsonschema.NewCompiler().SetAssertFormat(true).Compile(data)
+	require.NoError(t, err)
+	for name, sample := range samples {
+		result, err := schema.ValidateJSON([]byte(sample))
+		require.NoError(t, err)
+		assert.True(t, result.IsValid(), "%s validates against the inferred schema", name)
+	}
+
+	inferred, err = inferFiles(files, inferOptions{requiredThreshold: 0.6})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"age", "email", "id", "nickname", "tags"}, inferred["required"])
+	assert.NotContains(t, inferred["properties"].(map[string]interface{})["id"], "format")
+
+	output := filepath.Join(t.TempDir(), "inferred.json")
+	require.NoError(t, runInfer([]string{filepath.Join(dir, "a.json"), "-o", output, "--formats=false"}))
+	written, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(written), `"$schema": "https://json-schema.org/draft/2020-12/schema"`)
+	assert.NotContains(t, string(written), "format")
+	assert.ErrorIs(t, runInfer(nil), errMissingSample)
+}
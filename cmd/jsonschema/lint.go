@@ -0,0 +1,325 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/goccy/go-yaml"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// Levels of lint findings, as in SARIF; levelOff disables a rule.
+const (
+	levelError   = "error"
+	levelWarning = "warning"
+	levelNote    = "note"
+	levelOff     = "off"
+)
+
+// invalidSchemaRule is the rule of the findings for files that fail to compile.
+const invalidSchemaRule = "invalid_schema"
+
+// lintRules describes the rules of the linter: the codes of jsonschema.CompileWarning, and
+// invalidSchemaRule. Every rule reports at levelWarning unless configured otherwise, except
+// invalidSchemaRule, which reports at levelError.
+var lintRules = map[string]string{
+	"deprecated_draft":     "The document declares a draft before 2020-12.",
+	"ignored_keyword":      "The draft of the schema ignores the keyword.",
+	"unknown_keyword":      "The keyword is unknown but close to a standard one, likely misspelled.",
+	"unanchored_pattern":   "The regular expression matches anywhere in the string.",
+	"unsatisfiable_schema": "No instance can satisfy the subschema.",
+	invalidSchemaRule:      "The document does not compile.",
+}
+
+var (
+	// errMissingSchemaPath is returned when a subcommand reading schemas is called without paths.
+	errMissingSchemaPath = errors.New("expected at least one schema file or directory")
+
+	// errUnknownRule is returned when the rules file configures a rule the linter does not have.
+	errUnknownRule = errors.New("unknown lint rule")
+
+	// errUnknownLevel is returned when the rules file sets a level other than error, warning, note or off.
+	errUnknownLevel = errors.New("unknown lint level")
+
+	// errLintFailed is returned when lint reports findings at the error level.
+	errLintFailed = errors.New("lint failed")
+)
+
+// lintConfig is the rules file of lint, such as .jsonschemalint.yaml:
+//
+//	rules:
+//	  unanchored_pattern: error
+//	  deprecated_draft: off
+type lintConfig struct {
+	Rules map[string]string `json:"rules" yaml:"rules"`
+}
+
+// lintFinding is a finding of lint, a compile warning or error of a schema file.
+type lintFinding struct {
+	File            string `json:"file"`
+	KeywordLocation string `json:"keywordLocation,omitempty"`
+	Rule            string `json:"rule"`
+	Level           string `json:"level"`
+	Message         string `json:"message"`
+}
+
+// runLint implements "jsonschema lint".
+func runLint(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	rulesFile := flags.String("rules", "", "YAML or JSON file setting the level of each rule: error, warning, note or off")
+	format := flags.String("format", "text", "output format: text, json or sarif")
+	output := flags.String("o", "", "output file (stdout by default)")
+	paths, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return errMissingSchemaPath
+	}
+
+	levels, err := loadLintLevels(*rulesFile)
+	if err != nil {
+		return err
+	}
+	files, err := schemaFiles(paths)
+	if err != nil {
+		return err
+	}
+	findings := lintFiles(files, levels)
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output) //nolint:gosec
+		if err != nil {
+			return err
+		}
+		defer file.Close() //nolint:errcheck
+		w = file
+	}
+	if err := writeFindings(w, *format, findings); err != nil {
+		return err
+	}
+
+	errorCount := 0
+	for _, finding := range findings {
+		if finding.Level == levelError {
+			errorCount++
+		}
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("%w with %d errors", errLintFailed, errorCount)
+	}
+	return nil
+}
+
+// loadLintLevels returns the level of every rule, as configured in the rules file at path, if any.
+func loadLintLevels(path string) (map[string]string, error) {
+	levels := make(map[string]string, len(lintRules))
+	for rule := range lintRules {
+		levels[rule] = levelWarning
+	}
+	levels[invalidSchemaRule] = levelError
+	if path == "" {
+		return levels, nil
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	var config lintConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for rule, level := range config.Rules {
+		if _, ok := lintRules[rule]; !ok {
+			return nil, fmt.Errorf("%s: %w %q", path, errUnknownRule, rule)
+		}
+		switch level {
+		case levelError, levelWarning, levelNote, levelOff:
+			levels[rule] = level
+		default:
+			return nil, fmt.Errorf("%s: %w %q for %s", path, errUnknownLevel, level, rule)
+		}
+	}
+	return levels, nil
+}
+
+// schemaFiles returns the files of paths and the *.json files below the directories of paths,
+// sorted.
+func schemaFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && filepath.Ext(path) == ".json" {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// lintFiles compiles files with one compiler, so that references between them resolve, and
+// returns the findings of the rules not turned off by levels, in order of file and location.
+// The warnings of other documents the files reference are reported under their URIs.
+func lintFiles(files []string, levels map[string]string) []lintFinding {
+	compiler := jsonschema.NewCompiler()
+	names := make(map[string]string, len(files))
+	for _, file := range files {
+		names[fileURI(file)] = file
+	}
+
+	seen := make(map[lintFinding]bool)
+	var findings []lintFinding
+	add := func(finding lintFinding) {
+		finding.Level = levels[finding.Rule]
+		if finding.Level == "" || finding.Level == levelOff || seen[finding] {
+			return
+		}
+		seen[finding] = true
+		findings = append(findings, finding)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file) //nolint:gosec
+		if err == nil {
+			var warnings []jsonschema.CompileWarning
+			_, warnings, err = compiler.CompileWithWarnings(data, fileURI(file))
+			for _, warning := range warnings {
+				name := warning.URI
+				if local, ok := names[warning.URI]; ok {
+					name = local
+				}
+				add(lintFinding{File: name, KeywordLocation: warning.KeywordLocation, Rule: warning.Code, Message: warning.Message})
+			}
+		}
+		if err != nil {
+			add(lintFinding{File: file, Rule: invalidSchemaRule, Message: err.Error()})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].KeywordLocation < findings[j].KeywordLocation
+	})
+	return findings
+}
+
+// writeFindings writes findings to w in format.
+func writeFindings(w io.Writer, format string, findings []lintFinding) error {
+	switch format {
+	case "text":
+		for _, finding := range findings {
+			if _, err := fmt.Fprintf(w, "%s%s: %s: %s [%s]\n", finding.File, finding.KeywordLocation, finding.Level, finding.Message, finding.Rule); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		if findings == nil {
+			findings = []lintFinding{}
+		}
+		return writeIndentedJSON(w, findings)
+	case "sarif":
+		return writeIndentedJSON(w, sarifLog(findings))
+	default:
+		return fmt.Errorf("%w %q", errUnknownFormat, format)
+	}
+}
+
+// writeIndentedJSON writes v to w as indented JSON.
+func writeIndentedJSON(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// sarifLog returns findings as a SARIF 2.1.0 log, which code review tools annotate files with.
+// Findings are located by the URI of their file and the JSON Pointer of their keyword, as a
+// logical location.
+func sarifLog(findings []lintFinding) map[string]interface{} {
+	ruleIDs := make([]string, 0, len(lintRules))
+	for rule := range lintRules {
+		ruleIDs = append(ruleIDs, rule)
+	}
+	sort.Strings(ruleIDs)
+	rules := make([]map[string]interface{}, 0, len(ruleIDs))
+	for _, rule := range ruleIDs {
+		rules = append(rules, map[string]interface{}{
+			"id":               rule,
+			"shortDescription": map[string]string{"text": lintRules[rule]},
+		})
+	}
+
+	results := make([]map[string]interface{}, 0, len(findings))
+	for _, finding := range findings {
+		location := map[string]interface{}{
+			"physicalLocation": map[string]interface{}{
+				"artifactLocation": map[string]string{"uri": sarifURI(finding.File)},
+			},
+		}
+		if finding.KeywordLocation != "" {
+			location["logicalLocations"] = []map[string]string{{"fullyQualifiedName": finding.KeywordLocation}}
+		}
+		results = append(results, map[string]interface{}{
+			"ruleId":    finding.Rule,
+			"level":     finding.Level,
+			"message":   map[string]string{"text": finding.Message},
+			"locations": []interface{}{location},
+		})
+	}
+
+	return map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []interface{}{map[string]interface{}{
+			"tool": map[string]interface{}{
+				"driver": map[string]interface{}{
+					"name":           "jsonschema lint",
+					"informationUri": "https://github.com/kaptinlin/jsonschema",
+					"rules":          rules,
+				},
+			},
+			"results": results,
+		}},
+	}
+}
+
+// sarifURI returns the artifact URI of a finding file: local paths with forward slashes, relative
+// to the working directory, or the URI of a referenced document.
+func sarifURI(file string) string {
+	if strings.Contains(file, "://") {
+		return file
+	}
+	return filepath.ToSlash(file)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user.json"), []byte(`{
+		"properties": {"code": {"pattern": "[0-9]+"}, "name": {"$ref": "name.json"}},
+		"minLenght": 1
+	}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "name.json"), []byte(`{"type": "string", "minLength": 5, "maxLength": 2}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"type": 1}`), 0o600))
+	rules := filepath.Join(dir, ".jsonschemalint.yaml")
+	require.NoError(t, os.WriteFile(rules, []byte("rules:\n  unanchored_pattern: error\n  unknown_keyword: off\n"), 0o600))
+
+	levels, err := loadLintLevels(rules)
+	require.NoError(t, err)
+	files, err := schemaFiles([]string{dir})
+	require.NoError(t, err)
+	findings := lintFiles(files, levels)
+
+	var out strings.Builder
+	require.NoError(t, writeFindings(&out, "text", findings))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4, out.String())
+	assert.True(t, strings.HasPrefix(lines[0], filepath.Join(dir, "broken.json")+": error: "), lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], filepath.Join(dir, "name.json")+"#: warning: No instance can satisfy"), lines[1])
+	assert.Contains(t, lines[1], "[unsatisfiable_schema]", "referenced files are reported once")
+	assert.Equal(t, filepath.Join(dir, "user.json")+"#/properties/code/pattern: error: Pattern [0-9]+ matches anywhere in the string; anchor it with ^ and $ to match whole strings [unanchored_pattern]", lines[2])
+	assert.Contains(t, lines[3], "user.json#/properties/name: warning: No instance can satisfy the schema: $ref is unsatisfiable")
+
+	out.Reset()
+	require.NoError(t, writeFindings(&out, "sarif", findings))
+	var sarif struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+				Level  string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &sarif))
+	assert.Equal(t, "2.1.0", sarif.Version)
+	require.Len(t, sarif.Runs[0].Results, 4)
+	assert.Equal(t, "unanchored_pattern", sarif.Runs[0].Results[2].RuleID)
+
+	output := filepath.Join(dir, "findings.json")
+	assert.ErrorIs(t, runLint([]string{dir, "--rules", rules, "-format", "json", "-o", output}), errLintFailed)
+	var written []lintFinding
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, findings, written)
+
+	assert.NoError(t, runLint([]string{filepath.Join(dir, "name.json")}), "warnings do not fail")
+	assert.ErrorIs(t, runLint(nil), errMissingSchemaPath)
+}
+
+func TestLoadLintLevelsErrors(t *testing.T) {
+	dir := t.TempDir()
+	for content, want := range map[string]error{
+		"rules:\n  no_such_rule: error\n":    errUnknownRule,
+		"rules:\n  unknown_keyword: fatal\n": errUnknownLevel,
+	} {
+		path := filepath.Join(dir, "rules.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := loadLintLevels(path)
+		assert.ErrorIs(t, err, want)
+	}
+}
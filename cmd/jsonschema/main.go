@@ -0,0 +1,90 @@
+// Command jsonschema exposes the validator on the command line.
+//
+// Usage:
+//
+//	jsonschema <command> [flags]
+//
+// Run "jsonschema <command> -h" for the flags of a specific command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// command describes a subcommand of the jsonschema tool.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// commands lists every subcommand in the order they are shown in the usage text.
+var commands = []command{
+	{name: "convert", summary: "convert schema documents between JSON and YAML, and to draft 2020-12", run: runConvert},
+	{name: "deprecations", summary: "report the deprecated subschemas that instances still use", run: runDeprecations},
+	{name: "gen-go", summary: "generate Go types, and optionally validators, from schemas", run: runGenGo},
+	{name: "infer", summary: "infer a schema from sample documents", run: runInfer},
+	{name: "lint", summary: "report likely mistakes in schemas as text, JSON or SARIF", run: runLint},
+	{name: "replay", summary: "replay recorded validations and check their results", run: runReplay},
+	{name: "serve", summary: "expose a validation HTTP API for a directory of schemas", run: runServe},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "-h" || name == "--help" || name == "help" {
+		usage()
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "jsonschema %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "jsonschema: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// parseArgs parses the flags of args, which may follow the positional arguments as in
+// "jsonschema lint schemas/ --rules lint.yaml", and returns the positional arguments. Arguments
+// after "--" are positional.
+func parseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := flags.Args()
+		if parsed := len(args) - len(rest); parsed > 0 && args[parsed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// usage prints the list of available subcommands to stderr.
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: jsonschema <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-13s %s\n", cmd.name, cmd.summary)
+	}
+}
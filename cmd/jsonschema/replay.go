@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-json"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+var (
+	// errMissingRecording is returned when replay is called without recording files.
+	errMissingRecording = errors.New("expected at least one recording file")
+
+	// errNotReproduced is returned when a replayed validation differs from the recorded one.
+	errNotReproduced = errors.New("recorded result not reproduced")
+)
+
+// runReplay implements "jsonschema replay".
+func runReplay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	verbose := flags.Bool("v", false, "print the recorded and replayed results")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errMissingRecording
+	}
+
+	failed := 0
+	for _, path := range flags.Args() {
+		reproduced, err := replayFile(path, *verbose)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if !reproduced {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w in %d of %d recordings", errNotReproduced, failed, flags.NArg())
+	}
+	return nil
+}
+
+// replayFile replays a single recording and reports whether its result was reproduced.
+func replayFile(path string, verbose bool) (bool, error) {
+	file, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return false, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	recording, err := jsonschema.LoadRecording(file)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := recording.Replay()
+	if err != nil {
+		return false, err
+	}
+
+	reproduced := recording.Reproduces(result)
+	status := "reproduced"
+	if !reproduced {
+		status = "NOT reproduced"
+	}
+	fmt.Printf("%s: %s (replayed valid=%v)\n", path, status, result.IsValid())
+
+	if verbose || !reproduced {
+		if err := printJSON("recorded", recording.Result); err != nil {
+			return false, err
+		}
+		if err := printJSON("replayed", result.ToList()); err != nil {
+			return false, err
+		}
+	}
+	return reproduced, nil
+}
+
+// printJSON prints v as indented JSON under a label.
+func printJSON(label string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %s:\n  %s\n", label, data)
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	schema, err := jsonschema.NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {"age": {"type": "integer", "minimum": 20}}
+	}`))
+	require.NoError(t, err)
+
+	recordDir := t.TempDir()
+	handler := newValidationHandler(map[string]*jsonschema.Schema{"users/user": schema})
+	handler.recordDir = recordDir
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	for _, body := range []string{`{"age": 21}`, `{"age": 19}`} {
+		resp, err := http.Post(server.URL+"/validate/users/user", "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	recordings, err := filepath.Glob(filepath.Join(recordDir, "users_user-*.json"))
+	require.NoError(t, err)
+	require.Len(t, recordings, 1, "only the failed validation is recorded")
+
+	require.NoError(t, runReplay(recordings))
+
+	data, err := os.ReadFile(recordings[0])
+	require.NoError(t, err)
+	tampered := filepath.Join(t.TempDir(), "tampered.json")
+	require.NoError(t, os.WriteFile(tampered, []byte(strings.Replace(string(data), `"minimum": 20`, `"minimum": 10`, 1)), 0o600))
+
+	assert.ErrorIs(t, runReplay([]string{tampered}), errNotReproduced)
+	assert.ErrorIs(t, runReplay(nil), errMissingRecording)
+}
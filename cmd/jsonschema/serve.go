@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// maxInstanceSize caps the size of a posted instance document.
+const maxInstanceSize = 10 << 20
+
+// errNotADirectory is returned when --schema-dir does not point to a directory.
+var errNotADirectory = errors.New("schema dir is not a directory")
+
+// runServe implements "jsonschema serve".
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	schemaDir := flags.String("schema-dir", ".", "directory containing *.json schemas")
+	listen := flags.String("listen", ":8080", "address to listen on")
+	assertFormat := flags.Bool("assert-format", false, "treat format as an assertion")
+	recordDir := flags.String("record-dir", "", "directory where failed validations are recorded for \"jsonschema replay\"")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	compiler := jsonschema.NewCompiler().SetAssertFormat(*assertFormat)
+	schemas, err := loadSchemaDir(compiler, *schemaDir)
+	if err != nil {
+		return err
+	}
+
+	handler := newValidationHandler(schemas)
+	handler.recordDir = *recordDir
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("serving %d schemas from %s on %s", len(schemas), *schemaDir, *listen)
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+// loadSchemaDir compiles every *.json file below dir under its file:// URI, so that relative
+// $ref between the files resolve. Schemas are keyed by their path relative to dir, using forward
+// slashes and without the .json extension.
+func loadSchemaDir(compiler *jsonschema.Compiler, dir string) (map[string]*jsonschema.Schema, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", errNotADirectory, dir)
+	}
+
+	schemas := make(map[string]*jsonschema.Schema)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path) //nolint:gosec
+		if err != nil {
+			return err
+		}
+
+		schema, err := compiler.Compile(data, fileURI(path))
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		schemas[strings.TrimSuffix(filepath.ToSlash(rel), ".json")] = schema
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schemas, nil
+}
+
+// fileURI returns the file:// URI of the local file at path.
+func fileURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // C:/schemas/user.json
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// validationHandler serves the validation HTTP API:
+//
+//	GET  /schemas          lists the names of the loaded schemas
+//	POST /validate/{name}  validates the request body against the named schema
+//
+// The output query parameter selects the result format of /validate: "flag",
+// "list" (flat), "hierarchical" (the default), or the "basic", "detailed" and
+// "verbose" formats of the specification. Bodies larger than maxInstanceSize are
+// rejected with 413. When recordDir is set, every failed validation is recorded
+// there as a file that "jsonschema replay" reads.
+type validationHandler struct {
+	schemas   map[string]*jsonschema.Schema
+	names     []string
+	recordDir string
+}
+
+// newValidationHandler creates an http.Handler validating instances against the given schemas.
+func newValidationHandler(schemas map[string]*jsonschema.Schema) *validationHandler {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &validationHandler{schemas: schemas, names: names}
+}
+
+// ServeHTTP dispatches requests to the schema listing and validation endpoints.
+func (h *validationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/schemas":
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, h.names)
+	case strings.HasPrefix(r.URL.Path, "/validate/"):
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		h.validate(w, r, strings.TrimPrefix(r.URL.Path, "/validate/"))
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// validate decodes the request body and evaluates it against the named schema.
+func (h *validationHandler) validate(w http.ResponseWriter, r *http.Request, name string) {
+	schema, ok := h.schemas[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown schema %q", name))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInstanceSize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+
+	var instance interface{}
+	if err := json.Unmarshal(body, &instance); err != nil {
+		writeError(w, http.StatusBadRequest, "request body is not valid JSON")
+		return
+	}
+
+	var result *jsonschema.EvaluationResult
+	if h.recordDir == "" {
+		result = schema.Validate(instance)
+	} else {
+		result = h.validateAndRecord(schema, name, instance)
+	}
+
+	switch output := r.URL.Query().Get("output"); output {
+	case "flag":
+		writeJSON(w, http.StatusOK, result.ToFlag())
+	case "list":
+		writeJSON(w, http.StatusOK, result.ToList(false))
+	case "", "hierarchical":
+		writeJSON(w, http.StatusOK, result.ToList())
+	case "basic":
+		writeJSON(w, http.StatusOK, result.ToBasic())
+	case "detailed":
+		writeJSON(w, http.StatusOK, result.ToDetailed())
+	case "verbose":
+		writeJSON(w, http.StatusOK, result.ToVerbose())
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown output format %q", output))
+	}
+}
+
+// validateAndRecord validates instance and saves a recording to recordDir when it is invalid.
+// Failing to save the recording is logged and does not affect the response.
+func (h *validationHandler) validateAndRecord(schema *jsonschema.Schema, name string, instance interface{}) *jsonschema.EvaluationResult {
+	result, recording, err := schema.Record(instance)
+	if err != nil {
+		log.Printf("failed to record validation against %s: %v", name, err)
+		return schema.Validate(instance)
+	}
+	if result.IsValid() {
+		return result
+	}
+
+	fileName := fmt.Sprintf("%s-%d.json", strings.ReplaceAll(name, "/", "_"), recording.RecordedAt.UnixNano())
+	if err := saveRecording(filepath.Join(h.recordDir, fileName), recording); err != nil {
+		log.Printf("failed to save recording of %s: %v", name, err)
+	}
+	return result
+}
+
+// saveRecording writes recording to path, creating its directory if needed.
+func saveRecording(path string, recording *jsonschema.Recording) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	file, err := os.Create(path) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	if err := recording.Save(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error body with the given status code.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+func TestValidationHandler(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "users"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users", "user.json"), []byte(`{
+		"type": "object",
+		"properties": {"age": {"type": "integer", "minimum": 20}, "email": {"$ref": "../common/email.json"}},
+		"required": ["age"]
+	}`), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "common"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "common", "email.json"), []byte(`{"type": "string", "maxLength": 20}`), 0o600))
+
+	schemas, err := loadSchemaDir(jsonschema.NewCompiler(), dir)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(newValidationHandler(schemas))
+	defer server.Close()
+
+	t.Run("list schemas", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/schemas")
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+
+		var names []string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&names))
+		assert.Equal(t, []string{"common/email", "users/user"}, names)
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		status   int
+		expected bool
+	}{
+		{name: "valid instance", path: "/validate/users/user?output=flag", body: `{"age": 21}`, status: http.StatusOK, expected: true},
+		{name: "invalid instance", path: "/validate/users/user", body: `{"age": 19}`, status: http.StatusOK, expected: false},
+		{name: "relative reference", path: "/validate/users/user?output=flag", body: `{"age": 21, "email": "a-very-long-address@example.com"}`, status: http.StatusOK, expected: false},
+		{name: "too large body", path: "/validate/users/user", body: `{"age": 21, "pad": "` + strings.Repeat("x", maxInstanceSize) + `"}`, status: http.StatusRequestEntityTooLarge},
+		{name: "unknown schema", path: "/validate/missing", body: `{}`, status: http.StatusNotFound},
+		{name: "malformed body", path: "/validate/users/user", body: `{`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+tt.path, "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			defer resp.Body.Close() //nolint:errcheck
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var flag jsonschema.Flag
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&flag))
+			assert.Equal(t, tt.expected, flag.Valid)
+		})
+	}
+
+	t.Run("output formats", func(t *testing.T) {
+		for _, output := range []string{"basic", "detailed", "verbose"} {
+			resp, err := http.Post(server.URL+"/validate/users/user?output="+output, "application/json", strings.NewReader(`{"age": 19}`))
+			require.NoError(t, err)
+
+			var unit jsonschema.OutputUnit
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&unit))
+			require.NoError(t, resp.Body.Close())
+			assert.False(t, unit.Valid, output)
+			assert.NotEmpty(t, unit.Errors, output)
+		}
+	})
+}
@@ -0,0 +1,724 @@
+// Package codegen generates Go type definitions from compiled JSON schemas, so that schemas stay
+// the source of truth of the data types a service exchanges:
+//
+//	schema, err := jsonschema.NewCompiler().Compile(data)
+//	...
+//	source, err := codegen.Generate(schema, codegen.Options{Package: "models", RootName: "User"})
+//
+// Objects with properties become structs with json tags, required properties being the only ones
+// without omitempty. Schemas reached through $ref, and those of $defs, are declared once under
+// the name of their definition and reused. Enums of strings or numbers become named types with a
+// constant per value, oneOf and anyOf with several branches become interface types implemented by
+// the types of their branches, and properties that accept null are pointers.
+//
+// With Options.Validators, the types also get a Validate method checking their values against
+// their schema, which the generated file embeds in compiled form, and with
+// Options.ValidateUnmarshal, the structs get an UnmarshalJSON method rejecting invalid documents.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// Options configures the generated file.
+type Options struct {
+	Package  string // Name of the package of the generated file, "schemas" when empty.
+	RootName string // Name of the type of the root schema, derived from its title, or Root, when empty.
+
+	// Validators generates a Validate method for every type other than the interface types,
+	// which validates the value against the schema the type was generated from. The compiled
+	// schema is embedded in the file, so that the generated package validates without the schema
+	// documents. Validate returns the *jsonschema.EvaluationResult of invalid values as error.
+	// The schema is validated with the settings of jsonschema.NewCompiler, so format is not
+	// asserted.
+	Validators bool
+	// ValidateUnmarshal generates an UnmarshalJSON method for every struct type, which validates
+	// the document against the schema of the struct before decoding it. It implies Validators.
+	ValidateUnmarshal bool
+}
+
+// placeholderURI prefixes the name of the type of a root schema without a URI to form the URI the
+// generated validators register it under, so that the schemas of the types are looked up by
+// location alike.
+const placeholderURI = "urn:codegen:"
+
+// initialisms are the words written in upper case in Go identifiers.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// Generate returns the gofmt-formatted Go source declaring the types of schema and of the
+// definitions of its $defs.
+//
+// Interface types generated for oneOf and anyOf document their variants but cannot be decoded by
+// encoding/json without a custom UnmarshalJSON, since the branch an instance matches is only
+// known after validating it. Keywords without a Go equivalent, such as pattern or minimum, are
+// left to validation.
+func Generate(schema *jsonschema.Schema, options Options) ([]byte, error) {
+	rootName := options.RootName
+	if rootName == "" && schema.Title != nil {
+		rootName = identifier(*schema.Title)
+	}
+	if rootName == "" {
+		rootName = "Root"
+	}
+	return generate([]*jsonschema.Schema{schema}, []string{rootName}, options)
+}
+
+// GenerateSchemas is like Generate for several schemas, such as the documents of a directory,
+// declared in one file: the types of the schemas they share are declared once. The type of each
+// schema is named after its title, or else the base name of its URI, such as Address for
+// file:///schemas/address.json; Options.RootName is ignored.
+func GenerateSchemas(schemas []*jsonschema.Schema, options Options) ([]byte, error) {
+	names := make([]string, len(schemas))
+	for i, schema := range schemas {
+		if schema.Title != nil {
+			names[i] = identifier(*schema.Title)
+		}
+		if names[i] == "" {
+			uri := schema.ResourceURI()
+			base := uri[strings.LastIndex(uri, "/")+1:]
+			names[i] = identifier(strings.SplitN(base, ".", 2)[0])
+		}
+		if names[i] == "" {
+			names[i] = "Root"
+		}
+	}
+	return generate(schemas, names, options)
+}
+
+// generate returns the source declaring the types of roots, under names, and of their $defs.
+func generate(roots []*jsonschema.Schema, names []string, options Options) ([]byte, error) {
+	if options.Package == "" {
+		options.Package = "schemas"
+	}
+
+	g := &generator{
+		names:      map[*jsonschema.Schema]string{},
+		used:       map[string]bool{},
+		interfaces: map[string][]string{},
+		structs:    map[string]bool{},
+		imports:    map[string]bool{},
+		roots:      map[*jsonschema.Schema]string{},
+		resources:  map[string]*jsonschema.Schema{},
+		validators: options.Validators || options.ValidateUnmarshal,
+		unmarshal:  options.ValidateUnmarshal,
+	}
+	// The names of the roots are reserved first, so that they keep them when other roots
+	// reference them.
+	for i, root := range roots {
+		if _, ok := g.roots[root]; !ok {
+			g.roots[root] = g.unique(names[i])
+		}
+	}
+	for _, root := range roots {
+		g.resourceURI = root.ResourceURI()
+		if g.resourceURI == "" {
+			g.resourceURI = placeholderURI + g.roots[root]
+		}
+		g.resources[g.resourceURI] = root
+		g.declare(root, g.roots[root])
+		for _, name := range sortedKeys(root.Defs) {
+			g.declare(root.Defs[name], identifier(name))
+		}
+	}
+	if g.validators {
+		decl, err := g.validationHelpers()
+		if err != nil {
+			return nil, err
+		}
+		g.decls = append(g.decls, decl)
+	}
+
+	var source strings.Builder
+	source.WriteString("// Code generated by github.com/kaptinlin/jsonschema/codegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&source, "package %s\n\n", options.Package)
+	if len(g.imports) > 0 {
+		// Standard library packages come first, separated from the others.
+		source.WriteString("import (\n")
+		paths := sortedKeys(g.imports)
+		sort.SliceStable(paths, func(i, j int) bool { return !isStandard(paths[j]) && isStandard(paths[i]) })
+		for i, path := range paths {
+			if i > 0 && isStandard(paths[i-1]) && !isStandard(path) {
+				source.WriteString("\n")
+			}
+			fmt.Fprintf(&source, "\t%q\n", path)
+		}
+		source.WriteString(")\n\n")
+	}
+	for _, decl := range g.decls {
+		source.WriteString(decl)
+		source.WriteString("\n")
+	}
+	return format.Source([]byte(source.String()))
+}
+
+// generator accumulates the declarations of the types of a schema.
+type generator struct {
+	names      map[*jsonschema.Schema]string // Names of the declared types, by schema.
+	used       map[string]bool               // Names taken by types and constants.
+	interfaces map[string][]string           // Variants of the declared interface types, by name.
+	structs    map[string]bool               // Whether each declared struct type is complete, by name.
+	imports    map[string]bool               // Packages the declarations use.
+	decls      []string
+
+	roots map[*jsonschema.Schema]string // Names reserved for the types of the root schemas.
+
+	validators  bool                          // Whether types get Validate methods, see Options.Validators.
+	unmarshal   bool                          // Whether structs get validating UnmarshalJSON methods, see Options.ValidateUnmarshal.
+	resourceURI string                        // URI of the root schema being declared in the generated validators.
+	resources   map[string]*jsonschema.Schema // Root schemas, by URI in the generated validators.
+}
+
+// typeOf returns the Go type of the values of schema s, declaring the named types it needs; name
+// is the name given to the type declared for s itself, if any.
+func (g *generator) typeOf(s *jsonschema.Schema, name string) string {
+	if s == nil || s.Boolean != nil {
+		return "interface{}"
+	}
+	if target := refTarget(s); target != nil {
+		return g.declare(target, refName(s.Ref, target))
+	}
+	if variants := nonNullSchemas(branches(s)); len(variants) == 1 && !isStruct(s) {
+		return g.typeOf(variants[0], name)
+	}
+	if isUnion(s) || isEnum(s) || isStruct(s) {
+		return g.declare(s, name)
+	}
+	return g.underlying(s, name)
+}
+
+// underlying returns the Go type of the values of s that is not a named type of its own.
+func (g *generator) underlying(s *jsonschema.Schema, name string) string {
+	types := schemaTypes(s)
+	if len(types) != 1 {
+		return "interface{}"
+	}
+	switch types[0] {
+	case "string":
+		if s.Format != nil && *s.Format == "date-time" {
+			g.imports["time"] = true
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		if s.Items == nil && len(s.PrefixItems) > 0 {
+			return "[]interface{}"
+		}
+		return "[]" + g.typeOf(s.Items, name+"Item")
+	case "object":
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Boolean == nil {
+			return "map[string]" + g.typeOf(s.AdditionalProperties, name+"Value")
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// declare declares the named type of s once, and returns its name.
+func (g *generator) declare(s *jsonschema.Schema, hint string) string {
+	if name, ok := g.names[s]; ok {
+		return name
+	}
+	name, ok := g.roots[s]
+	if !ok {
+		name = g.unique(hint)
+	}
+	g.names[s] = name
+	// The slot keeps declarations in the order their types are first used, the types of fields
+	// following the struct declaring them.
+	slot := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var decl strings.Builder
+	writeDoc(&decl, "", name, s)
+	switch {
+	case isUnion(s):
+		g.declareUnion(&decl, s, name)
+	case isEnum(s):
+		g.declareEnum(&decl, s, name)
+	case isStruct(s):
+		g.declareStruct(&decl, s, name)
+	default:
+		fmt.Fprintf(&decl, "type %s %s\n", name, g.typeOf(s, name))
+	}
+	if g.validators && !isUnion(s) {
+		g.declareValidators(&decl, s, name)
+	}
+	g.decls[slot] = decl.String()
+	return name
+}
+
+// declareValidators declares the Validate method of the type name of s, and its UnmarshalJSON
+// method when it is a struct and unmarshaling validates.
+func (g *generator) declareValidators(decl *strings.Builder, s *jsonschema.Schema, name string) {
+	location := s.Location()
+	if strings.HasPrefix(location, "#") {
+		location = g.resourceURI + location
+	}
+	fmt.Fprintf(decl, "\n// Validate validates v against the schema of %s.\n", name)
+	fmt.Fprintf(decl, "func (v %s) Validate() error {\n\treturn validate(v, %q)\n}\n", name, location)
+	if !g.unmarshal || !isStruct(s) {
+		return
+	}
+	g.imports["encoding/json"] = true
+	fmt.Fprintf(decl, "\n// UnmarshalJSON validates data against the schema of %s before decoding it into v.\n", name)
+	fmt.Fprintf(decl, "func (v *%s) UnmarshalJSON(data []byte) error {\n", name)
+	fmt.Fprintf(decl, "\tif err := validateJSON(data, %q); err != nil {\n\t\treturn err\n\t}\n", location)
+	fmt.Fprintf(decl, "\ttype plain %s\n\treturn json.Unmarshal(data, (*plain)(v))\n}\n", name)
+}
+
+// validationHelpers returns the declarations the Validate and UnmarshalJSON methods share: the
+// compiled root schemas, exported by jsonschema.Schema.Export, and the functions validating
+// against them.
+func (g *generator) validationHelpers() (string, error) {
+	for _, path := range []string{"github.com/kaptinlin/jsonschema", "strings", "sync"} {
+		g.imports[path] = true
+	}
+
+	var decl strings.Builder
+	decl.WriteString("// compiledSchemas are the schemas of the types, exported by jsonschema.Schema.Export, by URI.\n")
+	decl.WriteString("var compiledSchemas = map[string]string{\n")
+	for _, uri := range sortedKeys(g.resources) {
+		var exported bytes.Buffer
+		if err := g.resources[uri].Export(&exported); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&decl, "\t%q: %s,\n", uri, strconv.Quote(exported.String()))
+	}
+	decl.WriteString("}\n\n")
+	decl.WriteString("// schemas imports compiledSchemas once, into a compiler holding the schemas of the types.\n")
+	decl.WriteString("var schemas = sync.OnceValues(func() (*jsonschema.Compiler, error) {\n")
+	decl.WriteString("\tcompiler := jsonschema.NewCompiler()\n")
+	decl.WriteString("\tfor uri, data := range compiledSchemas {\n")
+	decl.WriteString("\t\troot, err := compiler.Import(strings.NewReader(data))\n")
+	decl.WriteString("\t\tif err != nil {\n\t\t\treturn nil, err\n\t\t}\n")
+	decl.WriteString("\t\tcompiler.SetSchema(uri, root)\n\t}\n")
+	decl.WriteString("\treturn compiler, nil\n})\n\n")
+	decl.WriteString("// validate validates v against the schema at location.\n")
+	decl.WriteString("func validate(v interface{}, location string) error {\n")
+	decl.WriteString("\tschema, err := schemaAt(location)\n\tif err != nil {\n\t\treturn err\n\t}\n")
+	decl.WriteString("\tif result := schema.Validate(v); !result.IsValid() {\n\t\treturn result\n\t}\n\treturn nil\n}\n")
+	if g.unmarshal {
+		decl.WriteString("\n// validateJSON validates the JSON document data against the schema at location.\n")
+		decl.WriteString("func validateJSON(data []byte, location string) error {\n")
+		decl.WriteString("\tschema, err := schemaAt(location)\n\tif err != nil {\n\t\treturn err\n\t}\n")
+		decl.WriteString("\tresult, err := schema.ValidateJSON(data)\n\tif err != nil {\n\t\treturn err\n\t}\n")
+		decl.WriteString("\tif !result.IsValid() {\n\t\treturn result\n\t}\n\treturn nil\n}\n")
+	}
+	decl.WriteString("\n// schemaAt returns the schema at location, a URI followed by a JSON Pointer.\n")
+	decl.WriteString("func schemaAt(location string) (*jsonschema.Schema, error) {\n")
+	decl.WriteString("\tcompiler, err := schemas()\n\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	decl.WriteString("\treturn compiler.GetSchema(location)\n}\n")
+	return decl.String(), nil
+}
+
+// declareStruct declares the struct type of the object schema s.
+func (g *generator) declareStruct(decl *strings.Builder, s *jsonschema.Schema, name string) {
+	properties, required := objectProperties(s)
+	g.structs[name] = false
+	defer func() { g.structs[name] = true }()
+
+	var fields strings.Builder
+	fieldNames := map[string]bool{}
+	for _, property := range sortedKeys(properties) {
+		schema := properties[property]
+		fieldName := identifier(property)
+		if fieldName == "" || !unicode.IsLetter([]rune(fieldName)[0]) {
+			fieldName = "X" + fieldName
+		}
+		for i, base := 2, fieldName; fieldNames[fieldName]; i++ {
+			fieldName = base + strconv.Itoa(i)
+		}
+		fieldNames[fieldName] = true
+
+		// Optional structs are pointers, since omitempty does not apply to structs, and so are the
+		// structs being declared, which would otherwise contain themselves.
+		fieldType := g.typeOf(schema, name+fieldName)
+		complete, isStruct := g.structs[fieldType]
+		if g.isPointable(fieldType) && (isNullable(schema) || isStruct && (!required[property] || !complete)) {
+			fieldType = "*" + fieldType
+		}
+		tag := property
+		if !required[property] {
+			tag += ",omitempty"
+		}
+		writeDoc(&fields, "\t", "", schema)
+		fmt.Fprintf(&fields, "\t%s %s `json:%q`\n", fieldName, fieldType, tag)
+	}
+	fmt.Fprintf(decl, "type %s struct {\n%s}\n", name, fields.String())
+}
+
+// declareEnum declares the named type of the enum of s with a constant per value.
+func (g *generator) declareEnum(decl *strings.Builder, s *jsonschema.Schema, name string) {
+	underlying := "string"
+	if _, ok := nonNullValues(s.Enum)[0].(string); !ok {
+		underlying = "float64"
+		if isIntegerEnum(s.Enum) {
+			underlying = "int64"
+		}
+	}
+	fmt.Fprintf(decl, "type %s %s\n\nconst (\n", name, underlying)
+	for _, value := range nonNullValues(s.Enum) {
+		constant := g.unique(name + constantSuffix(value))
+		literal := fmt.Sprint(value)
+		if text, ok := value.(string); ok {
+			literal = strconv.Quote(text)
+		}
+		fmt.Fprintf(decl, "\t%s %s = %s\n", constant, name, literal)
+	}
+	decl.WriteString(")\n")
+}
+
+// declareUnion declares the interface type of the oneOf or anyOf of s, implemented by the types of
+// its branches through a marker method.
+func (g *generator) declareUnion(decl *strings.Builder, s *jsonschema.Schema, name string) {
+	marker := "is" + name
+	var variants []string
+	for i, branch := range nonNullSchemas(branches(s)) {
+		hint := name + "Option" + strconv.Itoa(i+1)
+		if branch.Title != nil && identifier(*branch.Title) != "" {
+			hint = name + identifier(*branch.Title)
+		}
+		target := branch
+		if ref := refTarget(branch); ref != nil {
+			target, hint = ref, refName(branch.Ref, ref)
+		}
+		variant := g.declare(target, hint)
+		variants = append(variants, g.concreteVariants(variant)...)
+	}
+	g.interfaces[name] = variants
+
+	fmt.Fprintf(decl, "type %s interface {\n\t%s()\n}\n", name, marker)
+	for _, variant := range variants {
+		fmt.Fprintf(decl, "\nfunc (%s) %s() {}\n", variant, marker)
+	}
+}
+
+// concreteVariants returns the non-interface types standing for the type name in an interface:
+// name itself, or the variants of name when it is an interface.
+func (g *generator) concreteVariants(name string) []string {
+	variants, ok := g.interfaces[name]
+	if !ok {
+		return []string{name}
+	}
+	return variants
+}
+
+// isPointable reports whether a nullable value of goType is represented by a pointer, as maps,
+// slices and interfaces are nil already.
+func (g *generator) isPointable(goType string) bool {
+	if _, ok := g.interfaces[goType]; ok {
+		return false
+	}
+	return !strings.HasPrefix(goType, "[]") && !strings.HasPrefix(goType, "map[") && goType != "interface{}"
+}
+
+// unique returns name, or name followed by a number when it is already used.
+func (g *generator) unique(name string) string {
+	candidate := name
+	for i := 2; g.used[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	g.used[candidate] = true
+	return candidate
+}
+
+// writeDoc writes the description of s, or its title, as the doc comment of a declaration.
+func writeDoc(w *strings.Builder, indent, name string, s *jsonschema.Schema) {
+	var text string
+	switch {
+	case s == nil:
+		return
+	case s.Description != nil:
+		text = *s.Description
+	case s.Title != nil:
+		text = *s.Title
+	default:
+		return
+	}
+	text = strings.TrimSpace(text)
+	if name != "" && !strings.HasPrefix(text, name+" ") {
+		text = name + " is " + lowerFirst(text)
+		if !strings.HasSuffix(text, ".") {
+			text += "."
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(w, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+// constantSuffix returns the suffix of the name of the constant of an enum value.
+func constantSuffix(value interface{}) string {
+	text := fmt.Sprint(value)
+	if _, ok := value.(string); !ok {
+		text = strings.NewReplacer("-", "minus ", ".", " point ").Replace(text)
+	}
+	if suffix := identifier(text); suffix != "" {
+		return suffix
+	}
+	return "Empty"
+}
+
+// refTarget returns the schema s stands for when it is only a $ref.
+func refTarget(s *jsonschema.Schema) *jsonschema.Schema {
+	if s.ResolvedRef == nil || s.Properties != nil || len(s.Type) > 0 || len(s.AllOf) > 0 {
+		return nil
+	}
+	return s.ResolvedRef
+}
+
+// refName returns the name of the type declared for the target of ref: the last segment of the
+// reference, such as the name of a definition or of a document.
+func refName(ref string, target *jsonschema.Schema) string {
+	if target.Title != nil && identifier(*target.Title) != "" && !strings.Contains(ref, "#/") {
+		return identifier(*target.Title)
+	}
+	segment := ref[strings.LastIndexAny(ref, "/#:")+1:]
+	segment = strings.TrimSuffix(strings.TrimSuffix(segment, ".json"), ".yaml")
+	if name := identifier(segment); name != "" {
+		return name
+	}
+	return "Ref"
+}
+
+// branches returns the oneOf branches of s, or its anyOf branches.
+func branches(s *jsonschema.Schema) []*jsonschema.Schema {
+	if len(s.OneOf) > 0 {
+		return s.OneOf
+	}
+	return s.AnyOf
+}
+
+// isUnion reports whether s is declared as an interface type.
+func isUnion(s *jsonschema.Schema) bool {
+	return len(nonNullSchemas(branches(s))) > 1 && !isStruct(s)
+}
+
+// isEnum reports whether s is declared as an enum type, whose values are all strings or all numbers.
+func isEnum(s *jsonschema.Schema) bool {
+	values := nonNullValues(s.Enum)
+	if len(values) == 0 {
+		return false
+	}
+	_, isString := values[0].(string)
+	for _, value := range values {
+		if _, ok := value.(string); ok != isString {
+			return false
+		}
+		if !isString && !isNumber(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// isStruct reports whether s is declared as a struct type.
+func isStruct(s *jsonschema.Schema) bool {
+	if s.Properties != nil {
+		return true
+	}
+	for _, branch := range s.AllOf {
+		if target := refTarget(branch); target != nil {
+			branch = target
+		}
+		if branch.Properties != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isNullable reports whether s accepts null.
+func isNullable(s *jsonschema.Schema) bool {
+	if s == nil {
+		return false
+	}
+	for _, t := range s.Type {
+		if t == "null" {
+			return true
+		}
+	}
+	for _, value := range s.Enum {
+		if value == nil {
+			return true
+		}
+	}
+	for _, branch := range branches(s) {
+		if isNullType(branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNullType reports whether s only accepts null.
+func isNullType(s *jsonschema.Schema) bool {
+	return len(s.Type) == 1 && s.Type[0] == "null"
+}
+
+// nonNullSchemas returns the schemas that accept more than null.
+func nonNullSchemas(schemas []*jsonschema.Schema) []*jsonschema.Schema {
+	var result []*jsonschema.Schema
+	for _, s := range schemas {
+		if !isNullType(s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// nonNullValues returns the values other than null.
+func nonNullValues(values []interface{}) []interface{} {
+	var result []interface{}
+	for _, value := range values {
+		if value != nil {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// schemaTypes returns the types of s other than null, inferred from its keywords when it has no
+// type keyword.
+func schemaTypes(s *jsonschema.Schema) []string {
+	var types []string
+	for _, t := range s.Type {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	if len(s.Type) > 0 {
+		return types
+	}
+	switch {
+	case s.Properties != nil || s.AdditionalProperties != nil:
+		return []string{"object"}
+	case s.Items != nil || len(s.PrefixItems) > 0:
+		return []string{"array"}
+	case s.Const != nil && s.Const.IsSet:
+		switch value := s.Const.Value.(type) {
+		case string:
+			return []string{"string"}
+		case bool:
+			return []string{"boolean"}
+		default:
+			if isNumber(value) {
+				return []string{"number"}
+			}
+		}
+	}
+	return nil
+}
+
+// objectProperties returns the properties of the object schema s and of the objects of its allOf,
+// with the names of the required ones.
+func objectProperties(s *jsonschema.Schema) (map[string]*jsonschema.Schema, map[string]bool) {
+	properties := map[string]*jsonschema.Schema{}
+	required := map[string]bool{}
+	var collect func(*jsonschema.Schema)
+	collect = func(s *jsonschema.Schema) {
+		if s.Properties != nil {
+			for name, property := range *s.Properties {
+				properties[name] = property
+			}
+		}
+		for _, name := range s.Required {
+			required[name] = true
+		}
+		for _, branch := range s.AllOf {
+			if target := refTarget(branch); target != nil {
+				branch = target
+			}
+			collect(branch)
+		}
+	}
+	collect(s)
+	return properties, required
+}
+
+// isNumber reports whether value is a number decoded from JSON.
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case float64, float32, int, int64, int32, uint, uint64, uint32:
+		return true
+	}
+	_, ok := value.(interface{ Float64() (float64, error) })
+	return ok
+}
+
+// isIntegerEnum reports whether the numbers of values are all integers.
+func isIntegerEnum(values []interface{}) bool {
+	for _, value := range nonNullValues(values) {
+		if strings.ContainsAny(fmt.Sprint(value), ".eE") {
+			return false
+		}
+	}
+	return true
+}
+
+// identifier converts text, such as a property name or a title, to an exported Go identifier:
+// words separated by any character other than letters and digits are capitalized and joined, and
+// initialisms such as ID are upper-cased.
+func identifier(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var name strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			name.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		name.WriteString(string(runes))
+	}
+	return name.String()
+}
+
+// lowerFirst returns text with its first letter in lower case, unless it starts an initialism.
+func lowerFirst(text string) string {
+	word, _, _ := strings.Cut(text, " ")
+	if len(word) > 1 && strings.ToUpper(word) == word {
+		return text
+	}
+	runes := []rune(text)
+	if len(runes) > 0 {
+		runes[0] = unicode.ToLower(runes[0])
+	}
+	return string(runes)
+}
+
+// isStandard reports whether the import path is that of a standard library package, whose first
+// element has no dot.
+func isStandard(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,168 @@
+package codegen
+
+import (
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// update makes the tests write the golden files instead of comparing against them.
+var update = flag.Bool("update", false, "update golden files")
+
+const orderSchema = `{
+	"$id": "https://example.com/order.json",
+	"title": "Order",
+	"description": "An order placed by a customer.",
+	"type": "object",
+	"properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"status": {"enum": ["pending", "shipped", "in-transit", null], "description": "Delivery status."},
+		"priority": {"type": "integer", "enum": [1, 2, 3]},
+		"customer": {"$ref": "#/$defs/customer"},
+		"items": {"type": "array", "items": {"$ref": "#/$defs/item"}, "minItems": 1},
+		"notes": {"type": ["string", "null"]},
+		"placedAt": {"type": "string", "format": "date-time"},
+		"payment": {"oneOf": [{"$ref": "#/$defs/card"}, {"title": "bank transfer", "type": "object", "properties": {"iban": {"type": "string"}}}]},
+		"shipping": {"oneOf": [{"$ref": "#/$defs/address"}, {"type": "null"}]},
+		"metadata": {"type": "object", "additionalProperties": {"type": "string"}},
+		"parent": {"$ref": "#"}
+	},
+	"required": ["id", "customer", "items"],
+	"$defs": {
+		"customer": {
+			"allOf": [{"$ref": "#/$defs/address"}],
+			"properties": {"name": {"type": "string"}, "email": {"type": "string", "format": "email"}},
+			"required": ["name"]
+		},
+		"address": {"type": "object", "properties": {"street": {"type": "string"}, "city": {"type": "string"}}, "required": ["city"]},
+		"item": {
+			"type": "object",
+			"properties": {
+				"sku": {"type": "string"},
+				"quantity": {"type": "integer", "minimum": 1},
+				"options": {"type": "object", "properties": {"color": {"type": "string"}}}
+			}
+		},
+		"card": {"type": "object", "properties": {"number": {"type": "string"}}},
+		"currency": {"type": "string", "maxLength": 3}
+	}
+}`
+
+func TestGenerate(t *testing.T) {
+	schema, err := jsonschema.NewCompiler().Compile([]byte(orderSchema))
+	require.NoError(t, err)
+
+	source, err := Generate(schema, Options{Package: "models"})
+	require.NoError(t, err)
+	typeCheck(t, source)
+
+	golden := filepath.Join("testdata", "order.golden.go")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, source, 0o644)) //nolint:gosec
+	}
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(source))
+}
+
+const userSchema = `{
+	"$id": "https://example.com/user.json",
+	"title": "User",
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"age": {"type": "integer", "minimum": 0},
+		"role": {"enum": ["admin", "member"]},
+		"address": {"$ref": "#/$defs/address"}
+	},
+	"required": ["name"],
+	"$defs": {
+		"address": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]}
+	}
+}`
+
+// TestGenerateValidators checks the source of the usermodels package, whose tests exercise the
+// generated methods.
+func TestGenerateValidators(t *testing.T) {
+	schema, err := jsonschema.NewCompiler().Compile([]byte(userSchema))
+	require.NoError(t, err)
+
+	source, err := Generate(schema, Options{Package: "usermodels", ValidateUnmarshal: true})
+	require.NoError(t, err)
+
+	golden := filepath.Join("internal", "usermodels", "user.go")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, source, 0o644)) //nolint:gosec
+	}
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(source))
+}
+
+func TestGenerateValidatorsWithoutURI(t *testing.T) {
+	schema, err := jsonschema.NewCompiler().Compile([]byte(`{"title": "Tag", "type": "string", "maxLength": 3}`))
+	require.NoError(t, err)
+
+	source, err := Generate(schema, Options{Validators: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(source), `"urn:codegen:Tag": "JSIR`)
+	assert.Contains(t, string(source), `return validate(v, "urn:codegen:Tag#")`)
+	assert.NotContains(t, string(source), "UnmarshalJSON")
+}
+
+func TestGenerateSchemas(t *testing.T) {
+	compiler := jsonschema.NewCompiler()
+	address, err := compiler.Compile([]byte(`{"type": "object", "properties": {"city": {"type": "string"}}}`), "https://example.com/address.json")
+	require.NoError(t, err)
+	user, err := compiler.Compile([]byte(`{"title": "User", "type": "object", "properties": {"home": {"$ref": "address.json"}}}`), "https://example.com/user.json")
+	require.NoError(t, err)
+
+	source, err := GenerateSchemas([]*jsonschema.Schema{user, address}, Options{Validators: true})
+	require.NoError(t, err)
+	typeCheck(t, source)
+	assert.Contains(t, string(source), "type User struct")
+	assert.Contains(t, string(source), "type Address struct", "roots keep their names when referenced")
+	assert.NotContains(t, string(source), "Home struct")
+	assert.Contains(t, string(source), `"https://example.com/address.json": "JSIR`)
+}
+
+func TestGenerateRootName(t *testing.T) {
+	schema, err := jsonschema.NewCompiler().Compile([]byte(`{"type": "string", "enum": ["a", "b"]}`))
+	require.NoError(t, err)
+
+	source, err := Generate(schema, Options{RootName: "Letter"})
+	require.NoError(t, err)
+	typeCheck(t, source)
+	assert.Contains(t, string(source), "package schemas")
+	assert.Contains(t, string(source), "type Letter string")
+	assert.Contains(t, string(source), `LetterA Letter = "a"`)
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "UserID", identifier("user_id"))
+	assert.Equal(t, "HTTPSURL", identifier("https-url"))
+	assert.Equal(t, "CreatedAt", identifier("createdAt"))
+	assert.Equal(t, "", identifier("--"))
+}
+
+// typeCheck fails the test when source is not a valid Go file.
+func typeCheck(t *testing.T, source []byte) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "generated.go", source, parser.ParseComments)
+	require.NoError(t, err)
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = config.Check(file.Name.Name, fset, []*ast.File{file}, nil)
+	require.NoError(t, err, "%s", source)
+}
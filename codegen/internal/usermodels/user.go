@@ -0,0 +1,118 @@
+// Code generated by github.com/kaptinlin/jsonschema/codegen. DO NOT EDIT.
+
+package usermodels
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// User is user.
+type User struct {
+	Address *Address `json:"address,omitempty"`
+	Age     int64    `json:"age,omitempty"`
+	Name    string   `json:"name"`
+	Role    UserRole `json:"role,omitempty"`
+}
+
+// Validate validates v against the schema of User.
+func (v User) Validate() error {
+	return validate(v, "https://example.com/user.json#")
+}
+
+// UnmarshalJSON validates data against the schema of User before decoding it into v.
+func (v *User) UnmarshalJSON(data []byte) error {
+	if err := validateJSON(data, "https://example.com/user.json#"); err != nil {
+		return err
+	}
+	type plain User
+	return json.Unmarshal(data, (*plain)(v))
+}
+
+type Address struct {
+	City string `json:"city"`
+}
+
+// Validate validates v against the schema of Address.
+func (v Address) Validate() error {
+	return validate(v, "https://example.com/user.json#/$defs/address")
+}
+
+// UnmarshalJSON validates data against the schema of Address before decoding it into v.
+func (v *Address) UnmarshalJSON(data []byte) error {
+	if err := validateJSON(data, "https://example.com/user.json#/$defs/address"); err != nil {
+		return err
+	}
+	type plain Address
+	return json.Unmarshal(data, (*plain)(v))
+}
+
+type UserRole string
+
+const (
+	UserRoleAdmin  UserRole = "admin"
+	UserRoleMember UserRole = "member"
+)
+
+// Validate validates v against the schema of UserRole.
+func (v UserRole) Validate() error {
+	return validate(v, "https://example.com/user.json#/properties/role")
+}
+
+// compiledSchemas are the schemas of the types, exported by jsonschema.Schema.Export, by URI.
+var compiledSchemas = map[string]string{
+	"https://example.com/user.json": "JSIR\x00\x01{\"root\":0,\"nodes\":[{\"keywords\":{\"$id\":\"https://example.com/user.json\",\"properties\":{},\"type\":\"object\",\"required\":[\"name\"],\"title\":\"User\"},\"children\":[{\"pointer\":\"/$defs/address\",\"node\":2},{\"pointer\":\"/properties/address\",\"node\":4},{\"pointer\":\"/properties/age\",\"node\":5},{\"pointer\":\"/properties/name\",\"node\":6},{\"pointer\":\"/properties/role\",\"node\":7}],\"uri\":\"https://example.com/user.json\",\"baseURI\":\"https://example.com/\",\"schemas\":{\"https://example.com/user.json\":1}},{\"keywords\":{\"properties\":{},\"type\":\"object\",\"required\":[\"city\"]},\"children\":[{\"pointer\":\"/properties/city\",\"node\":3}],\"parent\":1,\"baseURI\":\"https://example.com/\"},{\"keywords\":{\"type\":\"string\"},\"parent\":2,\"baseURI\":\"https://example.com/\"},{\"keywords\":{\"$ref\":\"#/$defs/address\"},\"parent\":1,\"ref\":2,\"baseURI\":\"https://example.com/\"},{\"keywords\":{\"type\":\"integer\",\"minimum\":0},\"parent\":1,\"baseURI\":\"https://example.com/\"},{\"keywords\":{\"type\":\"string\",\"minLength\":1},\"parent\":1,\"baseURI\":\"https://example.com/\"},{\"keywords\":{\"enum\":[\"admin\",\"member\"]},\"parent\":1,\"baseURI\":\"https://example.com/\"}]}",
+}
+
+// schemas imports compiledSchemas once, into a compiler holding the schemas of the types.
+var schemas = sync.OnceValues(func() (*jsonschema.Compiler, error) {
+	compiler := jsonschema.NewCompiler()
+	for uri, data := range compiledSchemas {
+		root, err := compiler.Import(strings.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		compiler.SetSchema(uri, root)
+	}
+	return compiler, nil
+})
+
+// validate validates v against the schema at location.
+func validate(v interface{}, location string) error {
+	schema, err := schemaAt(location)
+	if err != nil {
+		return err
+	}
+	if result := schema.Validate(v); !result.IsValid() {
+		return result
+	}
+	return nil
+}
+
+// validateJSON validates the JSON document data against the schema at location.
+func validateJSON(data []byte, location string) error {
+	schema, err := schemaAt(location)
+	if err != nil {
+		return err
+	}
+	result, err := schema.ValidateJSON(data)
+	if err != nil {
+		return err
+	}
+	if !result.IsValid() {
+		return result
+	}
+	return nil
+}
+
+// schemaAt returns the schema at location, a URI followed by a JSON Pointer.
+func schemaAt(location string) (*jsonschema.Schema, error) {
+	compiler, err := schemas()
+	if err != nil {
+		return nil, err
+	}
+	return compiler.GetSchema(location)
+}
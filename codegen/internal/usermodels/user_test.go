@@ -0,0 +1,33 @@
+package usermodels
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, User{Name: "Ada", Age: 36, Role: UserRoleAdmin}.Validate())
+
+	err := User{Name: "", Age: -1}.Validate()
+	var result *jsonschema.EvaluationResult
+	require.ErrorAs(t, err, &result)
+	assert.False(t, result.IsValid())
+
+	assert.Error(t, UserRole("owner").Validate())
+	assert.NoError(t, Address{City: "Paris"}.Validate())
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var user User
+	require.NoError(t, json.Unmarshal([]byte(`{"name": "Ada", "address": {"city": "London"}}`), &user))
+	assert.Equal(t, User{Name: "Ada", Address: &Address{City: "London"}}, user)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"age": 3}`), &user), "name is required")
+	assert.Error(t, json.Unmarshal([]byte(`{"name": "Ada", "address": {}}`), &user), "nested structs are validated")
+	assert.Error(t, json.Unmarshal([]byte(`{"name": "Ada", "age": "3"}`), &user))
+}
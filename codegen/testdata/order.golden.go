@@ -0,0 +1,81 @@
+// Code generated by github.com/kaptinlin/jsonschema/codegen. DO NOT EDIT.
+
+package models
+
+import (
+	"time"
+)
+
+// Order is an order placed by a customer.
+type Order struct {
+	Customer Customer          `json:"customer"`
+	ID       string            `json:"id"`
+	Items    []Item            `json:"items"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Notes    *string           `json:"notes,omitempty"`
+	Parent   *Order            `json:"parent,omitempty"`
+	Payment  OrderPayment      `json:"payment,omitempty"`
+	PlacedAt time.Time         `json:"placedAt,omitempty"`
+	Priority OrderPriority     `json:"priority,omitempty"`
+	Shipping *Address          `json:"shipping,omitempty"`
+	// Delivery status.
+	Status *OrderStatus `json:"status,omitempty"`
+}
+
+type Customer struct {
+	City   string `json:"city"`
+	Email  string `json:"email,omitempty"`
+	Name   string `json:"name"`
+	Street string `json:"street,omitempty"`
+}
+
+type Item struct {
+	Options  *ItemOptions `json:"options,omitempty"`
+	Quantity int64        `json:"quantity,omitempty"`
+	Sku      string       `json:"sku,omitempty"`
+}
+
+type ItemOptions struct {
+	Color string `json:"color,omitempty"`
+}
+
+type OrderPayment interface {
+	isOrderPayment()
+}
+
+func (Card) isOrderPayment() {}
+
+func (OrderPaymentBankTransfer) isOrderPayment() {}
+
+type Card struct {
+	Number string `json:"number,omitempty"`
+}
+
+// OrderPaymentBankTransfer is bank transfer.
+type OrderPaymentBankTransfer struct {
+	Iban string `json:"iban,omitempty"`
+}
+
+type OrderPriority int64
+
+const (
+	OrderPriority1 OrderPriority = 1
+	OrderPriority2 OrderPriority = 2
+	OrderPriority3 OrderPriority = 3
+)
+
+type Address struct {
+	City   string `json:"city"`
+	Street string `json:"street,omitempty"`
+}
+
+// OrderStatus is delivery status.
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusInTransit OrderStatus = "in-transit"
+)
+
+type Currency string
@@ -2,25 +2,108 @@ package jsonschema
 
 import (
 	"context"
+	"encoding/base32"
 	"encoding/base64"
-	"encoding/xml"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
-	"net/http"
+	"mime/quotedprintable"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/goccy/go-json"
-
-	"github.com/goccy/go-yaml"
 )
 
 // Compiler is a structure that manages schema compilation and validation.
+//
+// A compiler is safe for concurrent use once configured: schemas can be compiled and retrieved
+// from several goroutines, and compilations run one at a time. Validation does not modify compiled
+// schemas, so a *Schema is safe for concurrent Validate calls. The Set* and Register* methods
+// configure the compiler and must not be called concurrently with compilations. The setters of
+// Schema, SetAssertFormat, SetEmailMode and SetTransforms, modify the cached schema in place, so
+// they must be called before it is shared with other goroutines, and they apply to every schema
+// that references it as well.
 type Compiler struct {
-	schemas        map[string]*Schema                                 // Cache of compiled schemas.
-	Decoders       map[string]func(string) ([]byte, error)            // Decoders for various encoding formats.
-	MediaTypes     map[string]func([]byte) (interface{}, error)       // Media type handlers for unmarshalling data.
-	Loaders        map[string]func(url string) (io.ReadCloser, error) // Functions to load schemas from URLs.
-	DefaultBaseURI string                                             // Base URI used to resolve relative references.
-	AssertFormat   bool                                               // Flag to enforce format validation.
+	base             *Compiler                                          // Compiler this overlay is layered on, see Overlay.
+	mu               sync.RWMutex                                       // Guards schemas, loaded, entries and lru.
+	compileMu        sync.Mutex                                         // Serializes compilations, see Compile.
+	schemas          map[string]*Schema                                 // Cache of compiled schemas.
+	formatDialects   map[string]bool                                    // Whether each custom dialect asserts format.
+	metaVocabularies map[string]map[string]bool                         // Vocabularies declared by each custom meta-schema.
+	formatOverride   bool                                               // Whether SetAssertFormat was called.
+	types            map[reflect.Type]string                            // Schema URIs of the types registered with RegisterType.
+	timings          timingRegistry                                     // Measurements reported by Stats.
+	measuredBy       *Compiler                                          // Compiler recording the measurements instead, see reloadCompiler.
+	loaded           map[string][]byte                                  // Documents fetched by loaders, by URI.
+	entries          map[string]*cacheEntry                             // Use of the schemas compiled from loaded, by URI, see SetCachePolicy.
+	lru              cacheHeap                                          // Entries, the least recently used first.
+	uses             atomic.Int64                                       // Lookups of the schemas of entries, ordering their use.
+	prefetched       map[string][]byte                                  // Documents fetched by Preload, by URI, while it compiles them.
+	mirror           *schemaMirror                                      // Copy of the fetched documents on disk, see Mirror.
+	mounts           []fsMount                                          // File systems serving documents by URI prefix, see UseFS.
+	resources        map[string][]byte                                  // Local copies of documents by URI, see AddResource.
+	ctx              context.Context                                    // Context of the compilation in progress, see CompileContext.
+	snapshots        sync.Map                                           // Documents of the schemas marshaled by Record, by *Schema.
+	http             httpOptions                                        // Configuration of the default HTTP loader, see SetHTTPClient.
+	Decoders         map[string]func(string) ([]byte, error)            // Decoders for various encoding formats.
+	MediaTypes       map[string]func([]byte) (interface{}, error)       // Media type handlers for unmarshalling data.
+	Loaders          map[string]func(url string) (io.ReadCloser, error) // Functions to load schemas from URLs.
+	ContextLoaders   map[string]ContextLoader                           // Context-aware versions of Loaders, see RegisterContextLoader.
+	DefaultBaseURI   string                                             // Base URI used to resolve relative references.
+	AssertFormat     bool                                               // Flag to enforce format validation.
+
+	AnnotateExtensions bool // Flag to report extension keywords as annotations.
+	MaxRedirects       int  // Number of HTTP redirects the default loader follows; 0 disables redirects.
+	CollectStats       bool // Flag to measure compile and validation times, see Stats.
+	CollectFailures    bool // Flag to count the failures of each keyword, see SetCollectFailures.
+	AllowRemoteChanges bool // Flag to accept changed remote documents in CheckRemotes.
+	Offline            bool // Flag to forbid fetching documents over HTTP, see SetOffline.
+
+	UnresolvedRefs UnresolvedRefPolicy // Evaluation of references that could not be resolved.
+	Timezones      TimezonePolicy      // Time zones accepted by the date-time and time formats.
+	StringLength   StringLength        // Measure of strings of minLength and maxLength.
+	EmailMode      EmailMode           // Strictness of the email format.
+	URISchemes     []string            // Schemes accepted by the URI formats; all when empty.
+
+	UnicodeHostnames     bool // Flag to accept U-labels in the hostname format.
+	SuggestPropertyNames bool // Flag to suggest conforming names for property names rejected by propertyNames.
+	AnchorPatterns       bool // Flag to match pattern and patternProperties against whole strings, see SetAnchorPatterns.
+
+	DuplicateKeys DuplicateKeyPolicy // Handling of properties given more than once in raw JSON instances.
+	Parser        JSONParser         // Parser of raw JSON instances, see SetJSONParser; DefaultJSONParser when nil.
+	RegexEngine   RegexEngine        // Engine of pattern keywords, see SetRegexEngine; DefaultRegexEngine when nil.
+
+	Dialects       map[string]*Dialect // Custom dialects by URI, see RegisterDialect.
+	DefaultDialect string              // URI of the dialect of schemas without $schema.
+	DefaultDraft   Draft               // Draft of schemas without $schema, see SetDefaultDraft.
+
+	Environment map[string]bool // Flags of the environment schemas are compiled for, see SetEnvironment.
+
+	Keywords     map[string]KeywordFactory // Custom keywords by name, see RegisterKeyword.
+	Vocabularies map[string]*Vocabulary    // Custom vocabularies by URI, see RegisterVocabulary.
+
+	WarningHandler func(CompileWarning) // Receiver of the warnings of compiled documents, see SetWarningHandler.
+	Severities     map[string]Severity  // Severities of keywords by name or location, see SetSeverity.
+
+	Middlewares  []Middleware        // Preprocessors of schema documents, see Use.
+	Suppressions []SuppressionRule   // Errors of instance locations downgraded to warnings, see RegisterSuppression.
+	Transforms   []InstanceTransform // Normalizers of the values of instances, see RegisterTransform.
+
+	Clock Clock // Source of the current time, see SetClock; SystemClock when nil.
+
+	TypeAdapters map[reflect.Type]func(interface{}) (interface{}, error) // Converters of custom Go types in instances.
+
+	Decompressors map[string]func(io.Reader) (io.ReadCloser, error) // Decompressors of HTTP content codings, see RegisterDecompressor.
+
+	Cache    Cache         // Shared store of fetched documents, see SetCache.
+	CacheTTL time.Duration // Time to live of the documents stored in Cache.
+
+	CachePolicy CachePolicy // Bounds of the schemas compiled from fetched documents, see SetCachePolicy.
 }
 
 // NewCompiler creates a new Compiler instance and initializes it with default settings.
@@ -30,8 +113,10 @@ func NewCompiler() *Compiler {
 		Decoders:       make(map[string]func(string) ([]byte, error)),
 		MediaTypes:     make(map[string]func([]byte) (interface{}, error)),
 		Loaders:        make(map[string]func(url string) (io.ReadCloser, error)),
+		ContextLoaders: make(map[string]ContextLoader),
 		DefaultBaseURI: "",
 		AssertFormat:   false,
+		MaxRedirects:   defaultMaxRedirects,
 	}
 	compiler.initDefaults()
 	return compiler
@@ -39,10 +124,44 @@ func NewCompiler() *Compiler {
 
 // Compile compiles a JSON schema and caches it. If an URI is provided, it uses that as the key; otherwise, it generates a hash.
 func (c *Compiler) Compile(jsonSchema []byte, uris ...string) (*Schema, error) {
+	c.compileMu.Lock()
+	defer c.compileMu.Unlock()
+	return c.compile(jsonSchema, uris...)
+}
+
+// compile compiles a JSON schema like Compile, for callers that serialize compilations already.
+func (c *Compiler) compile(jsonSchema []byte, uris ...string) (*Schema, error) {
+	start := c.Now()
+	if len(c.Middlewares) > 0 {
+		var uri string
+		if len(uris) > 0 {
+			uri = uris[0]
+		}
+		var err error
+		if jsonSchema, err = c.applyMiddlewares(jsonSchema, uri); err != nil {
+			return nil, err
+		}
+	}
+	jsonSchema, err := c.applyEnvironment(jsonSchema)
+	if err != nil {
+		return nil, err
+	}
+	jsonSchema, dialect, err := c.applyDialects(jsonSchema)
+	if err != nil {
+		return nil, err
+	}
+	var warnings *compileWarnings
+	if c.WarningHandler != nil {
+		warnings = &compileWarnings{}
+	}
+	if jsonSchema, err = c.applyDrafts(jsonSchema, warnings); err != nil {
+		return nil, err
+	}
 	schema, err := newSchema(jsonSchema)
 	if err != nil {
 		return nil, err
 	}
+	schema.localRefs = dialect != nil && dialect.LocalRefs
 
 	uri := schema.ID
 	if uri == "" && len(uris) > 0 {
@@ -52,74 +171,195 @@ func (c *Compiler) Compile(jsonSchema []byte, uris ...string) (*Schema, error) {
 	if uri != "" && isValidURI(uri) {
 		schema.uri = uri
 
-		if existingSchema, exists := c.schemas[uri]; exists {
+		c.mu.RLock()
+		existingSchema, exists := c.cachedSchema(uri)
+		c.mu.RUnlock()
+		if exists {
 			return existingSchema, nil
 		}
 	}
 
-	schema.initializeSchema(c, nil)
-
-	if schema.uri != "" && isValidURI(schema.uri) {
+	// The schema is cached before its references are resolved, so that documents referencing
+	// each other resolve to it instead of being compiled again.
+	cached := schema.uri != "" && isValidURI(schema.uri)
+	if cached {
 		c.SetSchema(schema.uri, schema)
 	}
+	schema.initializeSchema(c, nil)
+	err = c.compileKeywords(schema)
+	if err == nil && len(c.Severities) > 0 {
+		c.compileSeverities(schema)
+	}
+	if err == nil && c.Offline {
+		err = checkOfflineRefs(schema)
+	}
+	if err != nil {
+		if cached {
+			c.removeSchema(schema)
+		}
+		return nil, err
+	}
+	if warnings != nil {
+		c.reportWarnings(schema, *warnings)
+	}
 
+	if c.CollectStats {
+		c.registry().recordCompile(schema, c.Now().Sub(start))
+	}
 	return schema, nil
 }
 
+// CompileYAML compiles a JSON schema authored in YAML and caches it. The document is read with the
+// YAML 1.2 core schema, so scalars such as yes, on or 2024-01-01 stay strings, and then compiled exactly like Compile.
+func (c *Compiler) CompileYAML(yamlSchema []byte, uris ...string) (*Schema, error) {
+	jsonSchema, err := yamlToJSON(yamlSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Compile(jsonSchema, uris...)
+}
+
 // resolveSchemaURL attempts to fetch and compile a schema from a URL.
 func (c *Compiler) resolveSchemaURL(url string) (*Schema, error) {
 	id, anchor := splitRef(url)
-	if schema, exists := c.schemas[id]; exists {
+	if schema, exists := c.lookupSchema(id); exists {
 		return schema, nil // Return cached schema if available
 	}
 
-	loader, ok := c.Loaders[getURLScheme(url)]
-	if !ok {
-		return nil, ErrNoLoaderRegistered
+	data, local := c.resource(id)
+	if !local {
+		var ok bool
+		if data, ok = c.prefetched[id]; !ok {
+			var err error
+			if data, err = c.fetchCachedDocument(c.context(), url); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	body, err := loader(url)
+	schema, err := c.compile(data, id)
+
 	if err != nil {
 		return nil, err
 	}
-	defer body.Close() //nolint:errcheck
 
-	data, err := io.ReadAll(body)
-	if err != nil {
-		return nil, ErrFailedToReadData
+	if !local {
+		c.mu.Lock()
+		c.recordFetched(id, data)
+		c.mu.Unlock()
 	}
 
-	schema, err := c.Compile(data, id)
+	return schemaFragment(schema, url, anchor)
+}
 
+// fetchDocument loads the document at url from the file system mounted for it with UseFS, or with
+// the loader registered for its scheme, decompressing .gz documents and converting YAML documents
+// to JSON. Offline compilers refuse to fetch documents over HTTP.
+func (c *Compiler) fetchDocument(ctx context.Context, url string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrFailedToFetch, url, err)
+	}
+	id, _ := splitRef(url)
+	body, mounted, err := c.openMounted(id)
+	if !mounted {
+		if c.Offline && isNetworkURI(url) {
+			return nil, fmt.Errorf("%w: %s", ErrOffline, url)
+		}
+		loader, ok := c.loader(getURLScheme(url))
+		if !ok {
+			return nil, ErrNoLoaderRegistered
+		}
+		body, err = loader(ctx, url)
+	}
 	if err != nil {
 		return nil, err
 	}
+	defer body.Close() //nolint:errcheck
 
-	if anchor != "" {
-		return schema.resolveAnchor(anchor)
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, ErrFailedToReadData
 	}
 
-	return schema, nil
+	if strings.HasSuffix(strings.ToLower(id), ".gz") {
+		if data, err = gunzipDocument(data); err != nil {
+			return nil, err
+		}
+		id = id[:len(id)-len(".gz")]
+	}
+	if isYAMLPath(id) {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
 }
 
 // SetSchema associates a specific schema with a URI.
 func (c *Compiler) SetSchema(uri string, schema *Schema) *Compiler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.schemas[uri] = schema
 	return c
 }
 
+// removeSchema removes schema from the cache, unless another schema took its URI meanwhile.
+func (c *Compiler) removeSchema(schema *Schema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.schemas[schema.uri] == schema {
+		delete(c.schemas, schema.uri)
+	}
+}
+
 // GetSchema retrieves a schema by reference. If the schema is not found in the cache and the ref is a URL, it tries to resolve it.
+//
+// The reference is a URI or a name given to SetSchema, which need not be a URI, optionally followed
+// by a fragment: a JSON Pointer such as mySchemaName#/$defs/Address, or an anchor. References to
+// a name that was not set, or to a missing subschema, return ErrSchemaNotRegistered or
+// ErrSubschemaNotFound.
 func (c *Compiler) GetSchema(ref string) (*Schema, error) {
-	baseURI, anchor := splitRef(ref)
+	baseURI, fragment := splitRef(ref)
+	if schema, exists := c.lookupSchema(baseURI); exists {
+		return schemaFragment(schema, ref, fragment)
+	}
 
-	if schema, exists := c.schemas[baseURI]; exists {
-		if baseURI == ref {
-			return schema, nil
-		}
-		return schema.resolveAnchor(anchor)
+	c.compileMu.Lock()
+	defer c.compileMu.Unlock()
+	return c.getSchema(ref)
+}
+
+// getSchema retrieves a schema by reference like GetSchema, for callers that serialize
+// compilations already.
+func (c *Compiler) getSchema(ref string) (*Schema, error) {
+	baseURI, fragment := splitRef(ref)
+
+	if schema, exists := c.lookupSchema(baseURI); exists {
+		return schemaFragment(schema, ref, fragment)
+	}
+
+	schema, err := c.resolveSchemaURL(ref)
+	if errors.Is(err, ErrNoLoaderRegistered) && !isValidURI(baseURI) {
+		return nil, fmt.Errorf("%w: %s", ErrSchemaNotRegistered, baseURI)
 	}
+	return schema, err
+}
 
-	return c.resolveSchemaURL(ref)
+// schemaFragment returns the subschema of schema at fragment, a JSON Pointer or an anchor, or
+// schema itself when fragment is empty, for the reference ref.
+func schemaFragment(schema *Schema, ref, fragment string) (*Schema, error) {
+	if fragment == "" {
+		return schema, nil
+	}
+	subschema, err := schema.resolveAnchor(fragment)
+	if err != nil {
+		return nil, err
+	}
+	if subschema == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSubschemaNotFound, ref)
+	}
+	return subschema, nil
 }
 
 // SetDefaultBaseURI sets the default base URL for resolving relative references.
@@ -128,9 +368,119 @@ func (c *Compiler) SetDefaultBaseURI(baseURI string) *Compiler {
 	return c
 }
 
-// SetAssertFormat enables or disables format assertion.
+// SetAssertFormat enables or disables format assertion for every schema, overriding the dialect.
+// Without it, format is only an annotation unless the meta-schema of the schema declares the
+// format-assertion vocabulary.
 func (c *Compiler) SetAssertFormat(assert bool) *Compiler {
 	c.AssertFormat = assert
+	c.formatOverride = true
+	return c
+}
+
+// UnresolvedRefPolicy selects how $ref and $dynamicRef that could not be resolved when the schema
+// was compiled, typically because a remote document could not be fetched, are evaluated.
+type UnresolvedRefPolicy int
+
+const (
+	// UnresolvedRefIgnore skips unresolved references, which therefore accept every instance.
+	UnresolvedRefIgnore UnresolvedRefPolicy = iota
+	// UnresolvedRefWarn accepts every instance and reports the unresolved references in the
+	// unresolvedRefs annotation of the referencing subschema.
+	UnresolvedRefWarn
+	// UnresolvedRefInvalid rejects every instance with a ref_unresolved error.
+	UnresolvedRefInvalid
+)
+
+// SetUnresolvedRefPolicy sets how references that could not be resolved are evaluated, so that
+// availability-critical services can keep validating while a remote schema is unavailable, either
+// accepting or rejecting what depends on it. The default is UnresolvedRefIgnore.
+func (c *Compiler) SetUnresolvedRefPolicy(policy UnresolvedRefPolicy) *Compiler {
+	c.UnresolvedRefs = policy
+	return c
+}
+
+// TimezonePolicy selects the time zones accepted by the date-time and time formats.
+type TimezonePolicy int
+
+const (
+	// TimezoneRequireOffset accepts times with Z or a numeric offset, as RFC 3339 requires.
+	TimezoneRequireOffset TimezonePolicy = iota
+	// TimezoneRequireUTC accepts only times in UTC, written with Z.
+	TimezoneRequireUTC
+	// TimezoneAllowLocal also accepts local times, written without an offset.
+	TimezoneAllowLocal
+)
+
+// SetTimezonePolicy sets the time zones accepted by the date-time and time formats when formats
+// are asserted, so that each API can enforce its own convention instead of post-validating. The
+// default is TimezoneRequireOffset.
+func (c *Compiler) SetTimezonePolicy(policy TimezonePolicy) *Compiler {
+	c.Timezones = policy
+	return c
+}
+
+// StringLength selects how minLength and maxLength measure strings.
+type StringLength int
+
+const (
+	// LengthCodePoints counts Unicode code points, as the specification requires.
+	LengthCodePoints StringLength = iota
+	// LengthUTF16 counts UTF-16 code units, as the length of JavaScript strings does: code
+	// points outside the Basic Multilingual Plane, such as most emoji, count twice.
+	LengthUTF16
+	// LengthBytes counts the bytes of the UTF-8 encoding, as limits of storage columns do.
+	LengthBytes
+)
+
+// SetStringLength sets how minLength and maxLength measure strings, so that a contract enforced
+// in several languages accepts the same strings everywhere, such as those a JavaScript producer
+// checked. The default is LengthCodePoints.
+func (c *Compiler) SetStringLength(length StringLength) *Compiler {
+	c.StringLength = length
+	return c
+}
+
+// stringLength returns the length of value as measured by the compiler of s.
+func (s *Schema) stringLength(value string) int {
+	if s.compiler == nil {
+		return utf8.RuneCountInString(value)
+	}
+	switch s.compiler.StringLength {
+	case LengthUTF16:
+		length := 0
+		for _, r := range value {
+			if r >= 0x10000 {
+				length += 2 // A surrogate pair.
+			} else {
+				length++
+			}
+		}
+		return length
+	case LengthBytes:
+		return len(value)
+	}
+	return utf8.RuneCountInString(value)
+}
+
+// SetURISchemes restricts the schemes accepted by the uri, iri, uri-reference and iri-reference
+// formats to schemes, compared case-insensitively, such as "https" and "mailto", so that format
+// validation rejects javascript: and file: URLs in user content. Relative references, which have
+// no scheme, are still accepted by uri-reference and iri-reference. Calling it without schemes
+// accepts every scheme again.
+func (c *Compiler) SetURISchemes(schemes ...string) *Compiler {
+	c.URISchemes = schemes
+	return c
+}
+
+// SetAnnotateExtensions enables or disables reporting the extension keywords of evaluated schemas as annotations.
+func (c *Compiler) SetAnnotateExtensions(annotate bool) *Compiler {
+	c.AnnotateExtensions = annotate
+	return c
+}
+
+// SetMaxRedirects sets the number of HTTP redirects the default loader follows.
+func (c *Compiler) SetMaxRedirects(maxRedirects int) *Compiler {
+	c.MaxRedirects = maxRedirects
 	return c
 }
 
@@ -149,14 +499,43 @@ func (c *Compiler) RegisterMediaType(mediaTypeName string, unmarshalFunc func([]
 // RegisterLoader adds a new loader function for a specific URI scheme.
 func (c *Compiler) RegisterLoader(scheme string, loaderFunc func(url string) (io.ReadCloser, error)) *Compiler {
 	c.Loaders[scheme] = loaderFunc
+	delete(c.ContextLoaders, scheme)
+	return c
+}
+
+// RegisterContextLoader adds a loader for a specific URI scheme that receives the context of
+// CompileContext or GetSchemaContext, so that fetches stop when it is canceled or its deadline
+// passes. It is used as long as the scheme has a loader in Loaders, where it is registered with a
+// background context for code calling loaders directly.
+func (c *Compiler) RegisterContextLoader(scheme string, loader ContextLoader) *Compiler {
+	c.ContextLoaders[scheme] = loader
+	c.Loaders[scheme] = func(url string) (io.ReadCloser, error) {
+		return loader(context.Background(), url)
+	}
 	return c
 }
 
 // initDefaults initializes default values for decoders, media types, and loaders.
 func (c *Compiler) initDefaults() {
-	c.Decoders["base64"] = base64.StdEncoding.DecodeString
+	c.setupDecoders()
+	c.setupDecompressors()
 	c.setupMediaTypes()
 	c.setupLoaders()
+	c.setupFileLoader()
+}
+
+// setupDecoders configures default content encodings: base64, base64url, base32, hex and quoted-printable.
+func (c *Compiler) setupDecoders() {
+	c.Decoders["base64"] = base64.StdEncoding.DecodeString
+	c.Decoders["base64url"] = func(data string) ([]byte, error) {
+		// JWS and JWT omit the padding; accept it when present.
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(data, "="))
+	}
+	c.Decoders["base32"] = base32.StdEncoding.DecodeString
+	c.Decoders["hex"] = hex.DecodeString
+	c.Decoders["quoted-printable"] = func(data string) ([]byte, error) {
+		return io.ReadAll(quotedprintable.NewReader(strings.NewReader(data)))
+	}
 }
 
 // setupMediaTypes configures default media type handlers.
@@ -169,51 +548,12 @@ func (c *Compiler) setupMediaTypes() {
 		return temp, nil
 	}
 
-	c.MediaTypes["application/xml"] = func(data []byte) (interface{}, error) {
-		var temp interface{}
-		if err := xml.Unmarshal(data, &temp); err != nil {
-			return nil, ErrXMLUnmarshalError
-		}
-		return temp, nil
-	}
+	c.MediaTypes["multipart/form-data"] = unmarshalMultipart
+	c.MediaTypes["application/x-www-form-urlencoded"] = unmarshalFormURLEncoded
+	c.MediaTypes["application/bson"] = unmarshalBSON
 
-	c.MediaTypes["application/yaml"] = func(data []byte) (interface{}, error) {
-		var temp interface{}
-		if err := yaml.Unmarshal(data, &temp); err != nil {
-			return nil, ErrYAMLUnmarshalError
-		}
-		return temp, nil
-	}
+	c.setupMarkupMediaTypes()
 }
 
-// setupLoaders configures default loaders for fetching schemas via HTTP/HTTPS.
-func (c *Compiler) setupLoaders() {
-	client := &http.Client{
-		Timeout: 10 * time.Second, // Set a reasonable timeout for network requests.
-	}
-
-	defaultHTTPLoader := func(url string) (io.ReadCloser, error) {
-		req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, ErrFailedToFetch
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			err = resp.Body.Close()
-			if err != nil {
-				return nil, err
-			}
-			return nil, ErrInvalidHTTPStatusCode
-		}
-
-		return resp.Body, nil
-	}
-
-	c.RegisterLoader("http", defaultHTTPLoader)
-	c.RegisterLoader("https", defaultHTTPLoader)
-}
+// defaultMaxRedirects is the number of redirects followed unless SetMaxRedirects is called.
+const defaultMaxRedirects = 10
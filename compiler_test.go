@@ -1,14 +1,11 @@
 package jsonschema
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 )
 
-const (
-	remoteSchemaURL = "https://json-schema.org/draft/2020-12/schema"
-)
-
 func TestCompileWithID(t *testing.T) {
 	compiler := NewCompiler()
 	schemaJSON := createTestSchemaJSON("http://example.com/schema", map[string]string{"name": "string"}, []string{"name"})
@@ -41,24 +38,39 @@ func TestGetSchema(t *testing.T) {
 	}
 }
 
-func TestValidateRemoteSchema(t *testing.T) {
+func TestGetSchemaByName(t *testing.T) {
 	compiler := NewCompiler()
-
-	// Load the meta-schema
-	metaSchema, err := compiler.GetSchema(remoteSchemaURL)
+	schema, err := compiler.Compile([]byte(`{"$defs": {"Address": {"type": "string", "$anchor": "address"}}}`))
 	if err != nil {
-		t.Fatalf("Failed to load meta-schema: %v", err)
+		t.Fatalf("Failed to compile schema: %s", err)
 	}
+	compiler.SetSchema("mySchemaName", schema)
 
-	// Ensure that the schema is not nil
-	if metaSchema == nil {
-		t.Fatal("Meta-schema is nil")
+	for _, ref := range []string{"mySchemaName#/$defs/Address", "mySchemaName#address"} {
+		address, err := compiler.GetSchema(ref)
+		if err != nil {
+			t.Fatalf("Failed to retrieve %s: %s", ref, err)
+		}
+		if address.Anchor != "address" {
+			t.Errorf("Expected %s to be the Address definition", ref)
+		}
+	}
+	if root, err := compiler.GetSchema("mySchemaName#"); err != nil || root != schema {
+		t.Errorf("Expected an empty fragment to return the named schema, got %v, %v", root, err)
+	}
+	if _, err := compiler.GetSchema("mySchemaName#unknown"); !errors.Is(err, ErrSubschemaNotFound) {
+		t.Errorf("Expected ErrSubschemaNotFound for an unknown anchor, got %v", err)
+	}
+	if _, err := compiler.GetSchema("otherName#/$defs/Address"); !errors.Is(err, ErrSchemaNotRegistered) {
+		t.Errorf("Expected ErrSchemaNotRegistered for an unknown name, got %v", err)
 	}
 
-	// Verify the ID of the retrieved schema
-	expectedID := remoteSchemaURL
-	if metaSchema.ID != expectedID {
-		t.Errorf("Expected schema with ID %s, got %s", expectedID, metaSchema.ID)
+	referencing, err := compiler.Compile([]byte(`{"$id": "http://example.com/customer", "properties": {"address": {"$ref": "mySchemaName#/$defs/Address"}}}`))
+	if err != nil {
+		t.Fatalf("Failed to compile referencing schema: %s", err)
+	}
+	if referencing.Validate(map[string]interface{}{"address": 1}).IsValid() {
+		t.Errorf("Expected the reference to the named schema to resolve despite the base URI")
 	}
 }
 
@@ -158,6 +170,62 @@ func TestResolveReferencesCorrectly(t *testing.T) {
 	}
 }
 
+func TestDefaultContentDecoders(t *testing.T) {
+	tests := []struct {
+		encoding string
+		valid    string
+		invalid  string
+	}{
+		{"base64", "eyJhIjogMX0=", "eyJhIjogMX0"},
+		{"base64url", "eyJhIjogIj8_In0", "eyJhIjogIj8/In0"},
+		{"base64url", "eyJhIjogIj8_In0=", "eyJhIjogIj8_In0!"},
+		{"base32", "PMRGCIR2EAYX2===", "PMRGCIR2EAYX2"},
+		{"hex", "7b2261223a20317d", "7b2261223a20317"},
+		{"quoted-printable", "{\"a\"=3A 1}", "{\"a\"=3Z 1}"},
+	}
+
+	compiler := NewCompiler()
+	for _, tt := range tests {
+		schema, err := compiler.Compile([]byte(fmt.Sprintf(`{"contentEncoding": %q, "contentMediaType": "application/json"}`, tt.encoding)))
+		if err != nil {
+			t.Fatalf("Failed to compile schema: %v", err)
+		}
+		if result := schema.Validate(tt.valid); !result.IsValid() {
+			t.Errorf("Expected %q to decode with %s, got %v", tt.valid, tt.encoding, result.ToList().Errors)
+		}
+		if schema.Validate(tt.invalid).IsValid() {
+			t.Errorf("Expected %q not to decode with %s", tt.invalid, tt.encoding)
+		}
+	}
+}
+
+func TestStringLength(t *testing.T) {
+	tests := []struct {
+		length StringLength
+		value  string
+		want   int
+	}{
+		{LengthCodePoints, "héllo😀", 6},
+		{LengthUTF16, "héllo😀", 7},
+		{LengthBytes, "héllo😀", 10},
+		{LengthUTF16, "abc", 3},
+	}
+
+	for _, tt := range tests {
+		compiler := NewCompiler().SetStringLength(tt.length)
+		schema, err := compiler.Compile([]byte(fmt.Sprintf(`{"minLength": %d, "maxLength": %d}`, tt.want, tt.want)))
+		if err != nil {
+			t.Fatalf("Failed to compile schema: %v", err)
+		}
+		if result := schema.Validate(tt.value); !result.IsValid() {
+			t.Errorf("Expected %q to have length %d with %d, got %v", tt.value, tt.want, tt.length, result.ToList().Errors)
+		}
+		if schema.Validate(tt.value+"x").IsValid() || schema.Validate(tt.value[:len(tt.value)-1]).IsValid() {
+			t.Errorf("Expected strings of another length than %d to be rejected with %d", tt.want, tt.length)
+		}
+	}
+}
+
 // createTestSchemaJSON simplifies creating JSON schema strings for testing.
 func createTestSchemaJSON(id string, properties map[string]string, required []string) string {
 	propsStr := ""
@@ -0,0 +1,321 @@
+package jsonschema
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KeywordInfo describes a keyword a schema may use, for editors and language servers completing
+// the keywords of schemas, see Compiler.KeywordsAt.
+type KeywordInfo struct {
+	Name        string   `json:"name"`                 // Name of the keyword, as written in the schema.
+	Types       []string `json:"types,omitempty"`      // JSON types of the value of the keyword, or nil for any value.
+	Subschemas  string   `json:"subschemas,omitempty"` // Whether the value is a "schema", an "array" of schemas or a "map" of schemas by name.
+	Vocabulary  string   `json:"vocabulary,omitempty"` // URI of the vocabulary of the keyword, for 2019-09 and later.
+	Description string   `json:"description"`          // One line describing the keyword.
+}
+
+// Kinds of values of keywords, by their JSON types.
+var (
+	stringValue  = []string{"string"}
+	numberValue  = []string{"number"}
+	integerValue = []string{"integer"}
+	booleanValue = []string{"boolean"}
+	arrayValue   = []string{"array"}
+	objectValue  = []string{"object"}
+	schemaValue  = []string{"object", "boolean"}
+)
+
+// standardKeyword is a keyword of the 2020-12 dialect, with its vocabulary without the URI prefix.
+type standardKeyword struct {
+	KeywordInfo
+	vocabulary string
+}
+
+// standardKeywords lists the keywords of the 2020-12 dialect in the order of the specification.
+var standardKeywords = []standardKeyword{
+	{KeywordInfo{Name: "$schema", Types: stringValue, Description: "URI of the meta-schema of the schema, selecting its dialect."}, "core"},
+	{KeywordInfo{Name: "$id", Types: stringValue, Description: "Canonical URI of the schema resource."}, "core"},
+	{KeywordInfo{Name: "$ref", Types: stringValue, Description: "Reference to a schema the instance must also conform to."}, "core"},
+	{KeywordInfo{Name: "$anchor", Types: stringValue, Description: "Plain name fragment identifying the schema in its resource."}, "core"},
+	{KeywordInfo{Name: "$dynamicRef", Types: stringValue, Description: "Reference resolved against the dynamic scope of the evaluation."}, "core"},
+	{KeywordInfo{Name: "$dynamicAnchor", Types: stringValue, Description: "Anchor that $dynamicRef can resolve to."}, "core"},
+	{KeywordInfo{Name: "$vocabulary", Types: objectValue, Description: "Vocabularies used by the schemas of a meta-schema, by URI."}, "core"},
+	{KeywordInfo{Name: "$comment", Types: stringValue, Description: "Comment for the maintainers of the schema."}, "core"},
+	{KeywordInfo{Name: "$defs", Types: objectValue, Subschemas: "map", Description: "Schemas defined for reuse, by name."}, "core"},
+	{KeywordInfo{Name: "allOf", Types: arrayValue, Subschemas: "array", Description: "Schemas the instance must all conform to."}, "applicator"},
+	{KeywordInfo{Name: "anyOf", Types: arrayValue, Subschemas: "array", Description: "Schemas the instance must conform to at least one of."}, "applicator"},
+	{KeywordInfo{Name: "oneOf", Types: arrayValue, Subschemas: "array", Description: "Schemas the instance must conform to exactly one of."}, "applicator"},
+	{KeywordInfo{Name: "not", Types: schemaValue, Subschemas: "schema", Description: "Schema the instance must not conform to."}, "applicator"},
+	{KeywordInfo{Name: "if", Types: schemaValue, Subschemas: "schema", Description: "Condition selecting whether then or else applies."}, "applicator"},
+	{KeywordInfo{Name: "then", Types: schemaValue, Subschemas: "schema", Description: "Schema applied when the instance conforms to if."}, "applicator"},
+	{KeywordInfo{Name: "else", Types: schemaValue, Subschemas: "schema", Description: "Schema applied when the instance does not conform to if."}, "applicator"},
+	{KeywordInfo{Name: "dependentSchemas", Types: objectValue, Subschemas: "map", Description: "Schemas applied when the object has the property they are named after."}, "applicator"},
+	{KeywordInfo{Name: "prefixItems", Types: arrayValue, Subschemas: "array", Description: "Schemas of the first items of the array, by position."}, "applicator"},
+	{KeywordInfo{Name: "items", Types: schemaValue, Subschemas: "schema", Description: "Schema of the items of the array after those of prefixItems."}, "applicator"},
+	{KeywordInfo{Name: "contains", Types: schemaValue, Subschemas: "schema", Description: "Schema at least one item of the array must conform to."}, "applicator"},
+	{KeywordInfo{Name: "properties", Types: objectValue, Subschemas: "map", Description: "Schemas of the properties of the object, by name."}, "applicator"},
+	{KeywordInfo{Name: "patternProperties", Types: objectValue, Subschemas: "map", Description: "Schemas of the properties of the object whose names match a pattern."}, "applicator"},
+	{KeywordInfo{Name: "additionalProperties", Types: schemaValue, Subschemas: "schema", Description: "Schema of the properties matched by neither properties nor patternProperties."}, "applicator"},
+	{KeywordInfo{Name: "propertyNames", Types: schemaValue, Subschemas: "schema", Description: "Schema the names of the properties of the object must conform to."}, "applicator"},
+	{KeywordInfo{Name: "unevaluatedItems", Types: schemaValue, Subschemas: "schema", Description: "Schema of the items no other keyword evaluated."}, "unevaluated"},
+	{KeywordInfo{Name: "unevaluatedProperties", Types: schemaValue, Subschemas: "schema", Description: "Schema of the properties no other keyword evaluated."}, "unevaluated"},
+	{KeywordInfo{Name: "type", Types: []string{"string", "array"}, Description: "Type of the instance, or list of the types it may have."}, "validation"},
+	{KeywordInfo{Name: "enum", Types: arrayValue, Description: "Values the instance must be equal to one of."}, "validation"},
+	{KeywordInfo{Name: "const", Description: "Value the instance must be equal to."}, "validation"},
+	{KeywordInfo{Name: "multipleOf", Types: numberValue, Description: "Number the instance must be a multiple of."}, "validation"},
+	{KeywordInfo{Name: "maximum", Types: numberValue, Description: "Inclusive upper limit of the number."}, "validation"},
+	{KeywordInfo{Name: "exclusiveMaximum", Types: numberValue, Description: "Exclusive upper limit of the number."}, "validation"},
+	{KeywordInfo{Name: "minimum", Types: numberValue, Description: "Inclusive lower limit of the number."}, "validation"},
+	{KeywordInfo{Name: "exclusiveMinimum", Types: numberValue, Description: "Exclusive lower limit of the number."}, "validation"},
+	{KeywordInfo{Name: "maxLength", Types: integerValue, Description: "Maximum number of characters of the string."}, "validation"},
+	{KeywordInfo{Name: "minLength", Types: integerValue, Description: "Minimum number of characters of the string."}, "validation"},
+	{KeywordInfo{Name: "pattern", Types: stringValue, Description: "Regular expression the string must match."}, "validation"},
+	{KeywordInfo{Name: "maxItems", Types: integerValue, Description: "Maximum number of items of the array."}, "validation"},
+	{KeywordInfo{Name: "minItems", Types: integerValue, Description: "Minimum number of items of the array."}, "validation"},
+	{KeywordInfo{Name: "uniqueItems", Types: booleanValue, Description: "Whether the items of the array must be unique."}, "validation"},
+	{KeywordInfo{Name: "maxContains", Types: integerValue, Description: "Maximum number of items conforming to contains."}, "validation"},
+	{KeywordInfo{Name: "minContains", Types: integerValue, Description: "Minimum number of items conforming to contains."}, "validation"},
+	{KeywordInfo{Name: "maxProperties", Types: integerValue, Description: "Maximum number of properties of the object."}, "validation"},
+	{KeywordInfo{Name: "minProperties", Types: integerValue, Description: "Minimum number of properties of the object."}, "validation"},
+	{KeywordInfo{Name: "required", Types: arrayValue, Description: "Names of the properties the object must have."}, "validation"},
+	{KeywordInfo{Name: "dependentRequired", Types: objectValue, Description: "Properties the object must have when it has the property they are listed under."}, "validation"},
+	{KeywordInfo{Name: "title", Types: stringValue, Description: "Short summary of the schema."}, "meta-data"},
+	{KeywordInfo{Name: "description", Types: stringValue, Description: "Explanation of the purpose of the schema."}, "meta-data"},
+	{KeywordInfo{Name: "default", Description: "Default value of the instance."}, "meta-data"},
+	{KeywordInfo{Name: "deprecated", Types: booleanValue, Description: "Whether the instance should no longer be used."}, "meta-data"},
+	{KeywordInfo{Name: "readOnly", Types: booleanValue, Description: "Whether the instance is managed by its owner and should not be modified."}, "meta-data"},
+	{KeywordInfo{Name: "writeOnly", Types: booleanValue, Description: "Whether the instance is never returned by its owner."}, "meta-data"},
+	{KeywordInfo{Name: "examples", Types: arrayValue, Description: "Example values of the instance."}, "meta-data"},
+	{KeywordInfo{Name: "format", Types: stringValue, Description: "Semantic format of the string, such as date-time or email."}, "format-annotation"},
+	{KeywordInfo{Name: "contentEncoding", Types: stringValue, Description: "Encoding of the content of the string, such as base64."}, "content"},
+	{KeywordInfo{Name: "contentMediaType", Types: stringValue, Description: "Media type of the content of the string."}, "content"},
+	{KeywordInfo{Name: "contentSchema", Types: schemaValue, Subschemas: "schema", Description: "Schema of the decoded content of the string."}, "content"},
+}
+
+// legacyKeywords lists the keywords of the drafts before 2020-12 that 2020-12 replaced, with the
+// drafts defining them.
+var legacyKeywords = []struct {
+	KeywordInfo
+	since, until Draft
+}{
+	{KeywordInfo{Name: "id", Types: stringValue, Description: "Canonical URI of the schema."}, Draft4, Draft4},
+	{KeywordInfo{Name: "definitions", Types: objectValue, Subschemas: "map", Description: "Schemas defined for reuse, by name."}, Draft4, Draft7},
+	{KeywordInfo{Name: "dependencies", Types: objectValue, Description: "Schemas, or lists of properties, applied when the object has the property they are named after."}, Draft4, Draft7},
+	{KeywordInfo{Name: "additionalItems", Types: schemaValue, Subschemas: "schema", Description: "Schema of the items after those of an items array."}, Draft4, Draft2019},
+	{KeywordInfo{Name: "$recursiveRef", Types: stringValue, Description: "Reference resolved against the outermost $recursiveAnchor of the evaluation."}, Draft2019, Draft2019},
+	{KeywordInfo{Name: "$recursiveAnchor", Types: booleanValue, Description: "Whether $recursiveRef can resolve to the schema."}, Draft2019, Draft2019},
+}
+
+// draftVocabularies maps the vocabularies of 2020-12 to those of 2019-09 defining the same keywords.
+var draftVocabularies = map[string]string{"unevaluated": "applicator", "format-annotation": "format"}
+
+// KeywordsAt returns the keywords a schema may use at pointer, a JSON Pointer to a schema of the
+// schema document, in the order of the specification followed by the custom keywords in order of
+// name. The keywords are those of the dialect in effect there: the draft, registered dialect or
+// meta-schema named by the nearest $schema along the pointer, or the default dialect or draft of
+// the compiler, with the custom keywords registered with RegisterKeyword or enabled by the
+// vocabularies of the meta-schema, and errorMessage.
+//
+// The pointer may go past the end of the document, as when the schema is still being written, but
+// every keyword along it must hold subschemas; otherwise KeywordsAt fails with
+// ErrInvalidSchemaLocation. Custom keywords have no types nor description.
+func (c *Compiler) KeywordsAt(document []byte, pointer string) ([]KeywordInfo, error) {
+	var value interface{}
+	if err := decodeExactJSON(document, &value); err != nil {
+		return nil, err
+	}
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSchemaLocation, pointer)
+	}
+
+	metaSchema := c.DefaultDialect
+	tokens := strings.Split(pointer, "/")[1:]
+	for i := 0; ; {
+		object, _ := value.(map[string]interface{})
+		if uri, ok := object["$schema"].(string); ok {
+			metaSchema = uri
+		}
+		if i == len(tokens) {
+			break
+		}
+
+		keyword := unescapeJSONPointer(tokens[i])
+		draft, dialect := c.keywordDialect(metaSchema)
+		kind := c.completionKind(keyword, draft, dialect)
+		if kind == subschemaSingle && keyword == "items" && draft < Draft2020 && i+1 < len(tokens) {
+			if _, err := strconv.Atoi(tokens[i+1]); err == nil {
+				kind = subschemaList
+			}
+		}
+		if kind == 0 || kind != subschemaSingle && i+1 == len(tokens) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSchemaLocation, pointer)
+		}
+		value = object[keyword]
+		i++
+		if kind != subschemaSingle {
+			switch container := value.(type) {
+			case []interface{}:
+				index, err := strconv.Atoi(tokens[i])
+				if err != nil || index < 0 || index >= len(container) {
+					value = nil
+				} else {
+					value = container[index]
+				}
+			case map[string]interface{}:
+				value = container[unescapeJSONPointer(tokens[i])]
+			default:
+				value = nil
+			}
+			i++
+		}
+	}
+
+	draft, dialect := c.keywordDialect(metaSchema)
+	return c.dialectKeywords(metaSchema, draft, dialect), nil
+}
+
+// keywordDialect returns the draft of the schemas whose $schema is metaSchema, and their
+// registered dialect, if any. Registered dialects and custom meta-schemas extend 2020-12.
+func (c *Compiler) keywordDialect(metaSchema string) (Draft, *Dialect) {
+	if dialect := c.dialect(metaSchema); dialect != nil {
+		return Draft2020, dialect
+	}
+	if metaSchema != "" {
+		if draft, ok := draftOf(metaSchema); ok {
+			return draft, nil
+		}
+		return Draft2020, nil
+	}
+	if c.DefaultDraft != 0 {
+		return c.DefaultDraft, nil
+	}
+	return Draft2020, nil
+}
+
+// completionKind returns the kind of the subschemas held by keyword in a schema of draft and
+// dialect, or 0 when its value is not a subschema.
+func (c *Compiler) completionKind(keyword string, draft Draft, dialect *Dialect) int {
+	if dialect != nil {
+		if standard, ok := dialect.Aliases[keyword]; ok {
+			keyword = standard
+		} else if slices.Contains(dialect.Removed, keyword) {
+			return 0
+		}
+	}
+	for _, legacy := range legacyKeywords {
+		if legacy.Name == keyword && legacy.Subschemas != "" && draft >= legacy.since && draft <= legacy.until {
+			if legacy.Subschemas == "map" {
+				return subschemaMap
+			}
+			return subschemaSingle
+		}
+	}
+	if keyword == "dependencies" && draft <= Draft7 {
+		return subschemaMap
+	}
+	if !draftDefines(draft, keyword) {
+		return 0
+	}
+	return subschemaKeywords[keyword]
+}
+
+// draftDefines reports whether draft defines the keyword of 2020-12.
+func draftDefines(draft Draft, keyword string) bool {
+	for _, keywords := range draftKeywords {
+		if draft < keywords.since {
+			for _, introduced := range keywords.keywords {
+				if introduced == keyword {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// dialectKeywords returns the keywords of the schemas whose $schema is metaSchema, of draft and
+// dialect, see KeywordsAt.
+func (c *Compiler) dialectKeywords(metaSchema string, draft Draft, dialect *Dialect) []KeywordInfo {
+	removed := map[string]bool{}
+	aliases := map[string][]string{}
+	if dialect != nil {
+		for _, keyword := range dialect.Removed {
+			removed[keyword] = true
+		}
+		for alias, keyword := range dialect.Aliases {
+			aliases[keyword] = append(aliases[keyword], alias)
+		}
+	}
+
+	var keywords []KeywordInfo
+	for _, standard := range standardKeywords {
+		if !draftDefines(draft, standard.Name) {
+			continue
+		}
+		info := standard.KeywordInfo
+		switch draft {
+		case Draft2020:
+			info.Vocabulary = "https://json-schema.org/draft/2020-12/vocab/" + standard.vocabulary
+		case Draft2019:
+			vocabulary := standard.vocabulary
+			if renamed, ok := draftVocabularies[vocabulary]; ok {
+				vocabulary = renamed
+			}
+			info.Vocabulary = "https://json-schema.org/draft/2019-09/vocab/" + vocabulary
+		}
+		if info.Name == "items" && draft < Draft2020 {
+			info.Types = []string{"object", "boolean", "array"}
+			info.Description = "Schema of the items of the array, or schemas of its first items by position."
+		}
+		if (info.Name == "exclusiveMinimum" || info.Name == "exclusiveMaximum") && draft == Draft4 {
+			info.Types = booleanValue
+			info.Description = "Whether the limit of " + strings.ToLower(strings.TrimPrefix(info.Name, "exclusive")) + " is exclusive."
+		}
+		if !removed[info.Name] {
+			keywords = append(keywords, info)
+		}
+		names := aliases[info.Name]
+		sort.Strings(names)
+		for _, alias := range names {
+			aliased := info
+			aliased.Name = alias
+			keywords = append(keywords, aliased)
+		}
+	}
+	for _, legacy := range legacyKeywords {
+		if draft >= legacy.since && draft <= legacy.until {
+			keywords = append(keywords, legacy.KeywordInfo)
+		}
+	}
+
+	custom := map[string]KeywordInfo{}
+	if c.Keywords[errorMessageKeyword] == nil {
+		custom[errorMessageKeyword] = KeywordInfo{
+			Name:        errorMessageKeyword,
+			Types:       []string{"string", "object"},
+			Description: "Message replacing those of the errors of the schema, or messages by keyword.",
+		}
+	}
+	for name := range c.Keywords {
+		custom[name] = KeywordInfo{Name: name}
+	}
+	if metaSchema != "" && dialect == nil && len(c.Vocabularies) > 0 {
+		if _, standard := draftOf(metaSchema); !standard {
+			for uri := range c.metaSchemaVocabularies(metaSchema, &Schema{}) {
+				if vocabulary := c.Vocabularies[uri]; vocabulary != nil {
+					for name := range vocabulary.Keywords {
+						custom[name] = KeywordInfo{Name: name, Vocabulary: uri}
+					}
+				}
+			}
+		}
+	}
+	for _, name := range sortedKeys(custom) {
+		keywords = append(keywords, custom[name])
+	}
+	return keywords
+}
@@ -0,0 +1,105 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keywordNames returns the names of keywords.
+func keywordNames(keywords []KeywordInfo) []string {
+	names := make([]string, len(keywords))
+	for i, keyword := range keywords {
+		names[i] = keyword.Name
+	}
+	return names
+}
+
+// findKeyword returns the keyword named name, or nil.
+func findKeyword(keywords []KeywordInfo, name string) *KeywordInfo {
+	for i := range keywords {
+		if keywords[i].Name == name {
+			return &keywords[i]
+		}
+	}
+	return nil
+}
+
+func TestKeywordsAt(t *testing.T) {
+	compiler := NewCompiler()
+	keywords, err := compiler.KeywordsAt([]byte(`{"properties": {"name": {}}}`), "/properties/name")
+	require.NoError(t, err)
+
+	names := keywordNames(keywords)
+	assert.Equal(t, "$schema", names[0])
+	assert.Contains(t, names, "prefixItems")
+	assert.Contains(t, names, "errorMessage")
+	assert.NotContains(t, names, "definitions")
+
+	minLength := findKeyword(keywords, "minLength")
+	require.NotNil(t, minLength)
+	assert.Equal(t, []string{"integer"}, minLength.Types)
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/vocab/validation", minLength.Vocabulary)
+	assert.NotEmpty(t, minLength.Description)
+	assert.Equal(t, "map", findKeyword(keywords, "properties").Subschemas)
+	assert.Nil(t, findKeyword(keywords, "const").Types)
+
+	// The location need not exist yet.
+	_, err = compiler.KeywordsAt([]byte(`{}`), "/items/allOf/0/not")
+	require.NoError(t, err)
+
+	for _, pointer := range []string{"/properties", "/required/0", "/type", "properties"} {
+		_, err = compiler.KeywordsAt([]byte(`{"properties": {}, "required": ["a"]}`), pointer)
+		assert.ErrorIs(t, err, ErrInvalidSchemaLocation, pointer)
+	}
+}
+
+func TestKeywordsAtDraft(t *testing.T) {
+	document := []byte(`{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"definitions": {"a": {"items": [{}, {"$schema": "http://json-schema.org/draft-07/schema#"}]}}
+	}`)
+	keywords, err := NewCompiler().KeywordsAt(document, "/definitions/a")
+	require.NoError(t, err)
+	names := keywordNames(keywords)
+	assert.Contains(t, names, "id")
+	assert.Contains(t, names, "definitions")
+	assert.Contains(t, names, "additionalItems")
+	assert.NotContains(t, names, "$id")
+	assert.NotContains(t, names, "const")
+	assert.NotContains(t, names, "prefixItems")
+	assert.Equal(t, []string{"boolean"}, findKeyword(keywords, "exclusiveMinimum").Types)
+	assert.Empty(t, findKeyword(keywords, "minLength").Vocabulary)
+
+	keywords, err = NewCompiler().KeywordsAt(document, "/definitions/a/items/1")
+	require.NoError(t, err)
+	names = keywordNames(keywords)
+	assert.Contains(t, names, "$id")
+	assert.Contains(t, names, "if")
+	assert.NotContains(t, names, "$defs")
+
+	keywords, err = NewCompiler().SetDefaultDraft(Draft2019).KeywordsAt([]byte(`{}`), "")
+	require.NoError(t, err)
+	assert.Contains(t, keywordNames(keywords), "$recursiveRef")
+	assert.Equal(t, "https://json-schema.org/draft/2019-09/vocab/applicator", findKeyword(keywords, "unevaluatedItems").Vocabulary)
+}
+
+func TestKeywordsAtDialect(t *testing.T) {
+	compiler := NewCompiler().RegisterDialect(Dialect{
+		URI:     "https://example.com/dialect",
+		Aliases: map[string]string{"definitions": "$defs"},
+		Removed: []string{"$defs", "$dynamicRef"},
+	}).RegisterKeyword("x-currency", func(*Schema, interface{}) (Keyword, error) { return nil, nil })
+
+	keywords, err := compiler.KeywordsAt([]byte(`{"$schema": "https://example.com/dialect", "definitions": {"a": {}}}`), "/definitions/a")
+	require.NoError(t, err)
+	names := keywordNames(keywords)
+	assert.Contains(t, names, "definitions")
+	assert.NotContains(t, names, "$defs")
+	assert.NotContains(t, names, "$dynamicRef")
+	assert.Equal(t, "x-currency", names[len(names)-1])
+
+	_, err = compiler.KeywordsAt([]byte(`{"$schema": "https://example.com/dialect"}`), "/$defs/a")
+	assert.ErrorIs(t, err, ErrInvalidSchemaLocation)
+}
@@ -0,0 +1,93 @@
+package jsonschema
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// RegisterDecompressor adds a decompressor for an HTTP content coding, such as br with
+// github.com/andybalholm/brotli. The default HTTP loader advertises the registered codings in
+// Accept-Encoding and decompresses responses with them; gzip and deflate are registered by default.
+func (c *Compiler) RegisterDecompressor(coding string, decompress func(io.Reader) (io.ReadCloser, error)) *Compiler {
+	if c.Decompressors == nil {
+		c.Decompressors = make(map[string]func(io.Reader) (io.ReadCloser, error))
+	}
+	c.Decompressors[strings.ToLower(coding)] = decompress
+	return c
+}
+
+// setupDecompressors configures the default content codings: gzip and deflate.
+func (c *Compiler) setupDecompressors() {
+	c.RegisterDecompressor("gzip", func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	})
+	c.RegisterDecompressor("deflate", func(r io.Reader) (io.ReadCloser, error) {
+		// Deflate is the zlib format, but some servers send raw deflate data.
+		buffered := bufio.NewReader(r)
+		if header, err := buffered.Peek(2); err == nil && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 && header[0]&0x0f == 8 {
+			return zlib.NewReader(buffered)
+		}
+		return flate.NewReader(buffered), nil
+	})
+}
+
+// acceptEncoding returns the Accept-Encoding header listing the registered content codings.
+func (c *Compiler) acceptEncoding() string {
+	codings := make([]string, 0, len(c.Decompressors))
+	for coding := range c.Decompressors {
+		codings = append(codings, coding)
+	}
+	sort.Strings(codings)
+	return strings.Join(codings, ", ")
+}
+
+// decompressBody wraps body, sent with the Content-Encoding header contentEncoding, into a reader
+// of the decompressed content. Closing the returned reader closes body.
+func (c *Compiler) decompressBody(contentEncoding string, body io.ReadCloser) (io.ReadCloser, error) {
+	coding := strings.ToLower(strings.TrimSpace(contentEncoding))
+	if coding == "" || coding == "identity" {
+		return body, nil
+	}
+
+	decompress, ok := c.Decompressors[coding]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentEncoding, contentEncoding)
+	}
+	reader, err := decompress(body)
+	if err != nil {
+		return nil, err
+	}
+	return &decompressedBody{ReadCloser: reader, body: body}, nil
+}
+
+// decompressedBody is a decompressing reader that also closes the compressed body.
+type decompressedBody struct {
+	io.ReadCloser
+	body io.Closer
+}
+
+// Close closes the decompressor and the compressed body.
+func (b *decompressedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if closeErr := b.body.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// gunzipDocument decompresses a gzip document, such as a .json.gz schema file.
+func gunzipDocument(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close() //nolint:errcheck
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,95 @@
+//go:build !jsonschema_core
+
+package jsonschema
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const compressedSchema = `{"type": "object", "required": ["name"]}`
+
+func compress(t *testing.T, coding string, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	switch coding {
+	case "gzip":
+		writer = gzip.NewWriter(&buf)
+	case "deflate":
+		writer = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		var err error
+		writer, err = flate.NewWriter(&buf, flate.DefaultCompression)
+		require.NoError(t, err)
+	}
+	_, err := writer.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestHTTPLoaderDecompression(t *testing.T) {
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		switch r.URL.Path {
+		case "/gzip.json":
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write(compress(t, "gzip", compressedSchema))
+		case "/deflate.json":
+			w.Header().Set("Content-Encoding", "deflate")
+			_, _ = w.Write(compress(t, "deflate", compressedSchema))
+		case "/raw-deflate.json":
+			w.Header().Set("Content-Encoding", "deflate")
+			_, _ = w.Write(compress(t, "raw-deflate", compressedSchema))
+		case "/br.json":
+			w.Header().Set("Content-Encoding", "br")
+			_, _ = w.Write([]byte("not brotli"))
+		}
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/gzip.json", "/deflate.json", "/raw-deflate.json"} {
+		schema, err := NewCompiler().GetSchema(server.URL + path)
+		require.NoError(t, err, path)
+		assert.False(t, schema.Validate(map[string]interface{}{}).IsValid(), path)
+	}
+	assert.Equal(t, "deflate, gzip", acceptEncoding)
+
+	_, err := NewCompiler().GetSchema(server.URL + "/br.json")
+	require.ErrorIs(t, err, ErrUnsupportedContentEncoding)
+
+	compiler := NewCompiler().RegisterDecompressor("br", func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte(compressedSchema))), nil
+	})
+	_, err = compiler.GetSchema(server.URL + "/br.json")
+	require.NoError(t, err)
+	assert.Equal(t, "br, deflate, gzip", acceptEncoding)
+}
+
+func TestGzipSchemaFiles(t *testing.T) {
+	files := map[string][]byte{
+		"test://schemas/user.json.gz": compress(t, "gzip", compressedSchema),
+		"test://schemas/user.yaml.gz": compress(t, "gzip", "type: object\nrequired: [name]\n"),
+	}
+	compiler := NewCompiler().RegisterLoader("test", func(url string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(files[url])), nil
+	})
+
+	for url := range files {
+		schema, err := compiler.GetSchema(url)
+		require.NoError(t, err, url)
+		assert.False(t, schema.Validate(map[string]interface{}{}).IsValid(), url)
+		assert.True(t, schema.Validate(map[string]interface{}{"name": "John"}).IsValid(), url)
+	}
+}
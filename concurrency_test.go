@@ -0,0 +1,128 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const concurrentWorkers = 16
+
+// concurrentItem returns the document of the schema mem:///item-i.json, which references the
+// next one.
+func concurrentItem(i int) []byte {
+	return []byte(fmt.Sprintf(`{
+		"$id": "mem:///item-%d.json",
+		"properties": {"name": {"$ref": "name.json"}, "next": {"$ref": "item-%d.json"}}
+	}`, i, (i+1)%concurrentWorkers))
+}
+
+func TestConcurrentCompile(t *testing.T) {
+	compiler := NewCompiler().SetCollectStats(true)
+	compiler.RegisterLoader("mem", func(url string) (io.ReadCloser, error) {
+		var i int
+		if _, err := fmt.Sscanf(url, "mem:///item-%d.json", &i); err == nil {
+			return io.NopCloser(bytes.NewReader(concurrentItem(i))), nil
+		}
+		return io.NopCloser(strings.NewReader(`{"type": "string", "minLength": 1}`)), nil
+	})
+
+	var wg sync.WaitGroup
+	schemas := make([]*Schema, concurrentWorkers)
+	errs := make([]error, concurrentWorkers)
+	for i := 0; i < concurrentWorkers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			schemas[i], errs[i] = compiler.Compile(concurrentItem(i))
+			if errs[i] == nil {
+				_, errs[i] = compiler.GetSchema("mem:///name.json")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, schema := range schemas {
+		require.NoError(t, errs[i])
+		assert.False(t, schema.Validate(map[string]interface{}{"name": ""}).IsValid())
+		cached, err := compiler.GetSchema(fmt.Sprintf("mem:///item-%d.json", i))
+		require.NoError(t, err)
+		assert.Same(t, schema, cached)
+	}
+}
+
+func TestConcurrentValidate(t *testing.T) {
+	compiler := NewCompiler().SetCollectStats(true).SetCollectFailures(true)
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/person.json",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}, "uniqueItems": true},
+			"nickname": {"type": "string", "deprecated": true}
+		},
+		"required": ["name"],
+		"$defs": {"tag": {"type": "string", "pattern": "^[a-z]+$"}}
+	}`))
+	require.NoError(t, err)
+
+	valid := map[string]interface{}{"name": "Ada", "tags": []interface{}{"a", "b"}, "nickname": "ada"}
+	invalid := map[string]interface{}{"name": "", "tags": []interface{}{"a", "a", "B"}}
+
+	var wg sync.WaitGroup
+	results := make([]bool, concurrentWorkers*2)
+	for i := 0; i < concurrentWorkers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[2*i] = schema.Validate(valid).IsValid()
+			results[2*i+1] = schema.Validate(invalid).IsValid()
+			_, _ = compiler.Compile([]byte(fmt.Sprintf(`{"$id": "https://example.com/extra-%d.json", "$ref": "person.json#/$defs/tag"}`, i)))
+		}(i)
+	}
+	wg.Wait()
+
+	for i, valid := range results {
+		assert.Equal(t, i%2 == 0, valid)
+	}
+	stats := compiler.Stats()
+	require.NotEmpty(t, stats)
+	assert.Equal(t, int64(2*concurrentWorkers), stats[0].Validations)
+	assert.Equal(t, int64(concurrentWorkers), compiler.DeprecationReport(schema).Schemas[0].Uses)
+}
+
+func TestConcurrentValidatePatternProperties(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"patternProperties": {"^x-": {"type": "string"}, "^[0-9]+$": {"type": "integer"}},
+		"additionalProperties": false
+	}`))
+	require.NoError(t, err)
+
+	valid := map[string]interface{}{"x-trace": "abc", "42": 1}
+	invalid := map[string]interface{}{"x-trace": 1, "other": true}
+
+	var wg sync.WaitGroup
+	results := make([]bool, concurrentWorkers*3)
+	for i := 0; i < concurrentWorkers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[3*i] = schema.Validate(valid).IsValid()
+			results[3*i+1] = schema.Validate(invalid).IsValid()
+			result, err := schema.ValidateJSON([]byte(`{"x-trace": "abc", "7": 7}`))
+			results[3*i+2] = err == nil && result.IsValid()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, valid := range results {
+		assert.Equal(t, i%3 != 1, valid)
+	}
+}
@@ -1,9 +1,5 @@
 package jsonschema
 
-import (
-	"reflect"
-)
-
 // EvaluateConst checks if the data matches exactly the value specified in the schema's 'const' keyword.
 // According to the JSON Schema Draft 2020-12:
 //   - The value of the "const" keyword may be of any type, including null.
@@ -24,7 +20,7 @@ func evaluateConst(schema *Schema, instance interface{}) *EvaluationError {
 		}
 	}
 
-	if !reflect.DeepEqual(instance, schema.Const.Value) {
+	if !equalJSON(instance, schema.Const.Value) {
 		return NewEvaluationError("const", "const_mismatch", "Value does not match the constant value")
 	}
 	return nil
@@ -25,7 +25,9 @@ func evaluateContains(schema *Schema, data []interface{}, evaluatedProps map[str
 
 	var validCount int
 	for i, item := range data {
+		dynamicScope.pushIndex(i)
 		result, _, _ := schema.Contains.evaluate(item, dynamicScope)
+		dynamicScope.popInstance()
 
 		if result != nil {
 			result.SetEvaluationPath("/contains").
@@ -25,7 +25,7 @@ func evaluateContent(schema *Schema, instance interface{}, evaluatedProps map[st
 
 	// Decode the content if encoding is specified
 	if schema.ContentEncoding != nil {
-		decoder, exists := schema.compiler.Decoders[*schema.ContentEncoding]
+		decoder, exists := schema.compiler.decoder(*schema.ContentEncoding)
 		if !exists {
 			return nil, NewEvaluationError("contentEncoding", "unsupported_encoding", "Encoding '{encoding}' is not supported", map[string]interface{}{
 				"encoding": *schema.ContentEncoding,
@@ -44,7 +44,7 @@ func evaluateContent(schema *Schema, instance interface{}, evaluatedProps map[st
 
 	// Handle content media type validation
 	if schema.ContentMediaType != nil {
-		unmarshal, exists := schema.compiler.MediaTypes[*schema.ContentMediaType]
+		unmarshal, exists := schema.compiler.mediaType(*schema.ContentMediaType)
 		if !exists {
 			return nil, NewEvaluationError("contentMediaType", "unsupported_media_type", "Media type '{media_type}' is not supported", map[string]interface{}{
 				"media_type": *schema.ContentMediaType,
@@ -63,6 +63,10 @@ func evaluateContent(schema *Schema, instance interface{}, evaluatedProps map[st
 
 	// Evaluate against the content schema if specified and value was decoded
 	if schema.ContentSchema != nil {
+		if isFormMediaType(schema.ContentMediaType) {
+			parsedValue = schema.ContentSchema.coerceFormInstance(parsedValue)
+		}
+
 		result, _, _ := schema.ContentSchema.evaluate(parsedValue, dynamicScope)
 		if result != nil {
 			result.SetEvaluationPath("/contentSchema").
@@ -0,0 +1,88 @@
+package jsonschema
+
+import (
+	"context"
+	"io"
+)
+
+// ContextLoader loads the document at url like the functions of Compiler.Loaders, and stops when
+// ctx is canceled or its deadline passes.
+type ContextLoader func(ctx context.Context, url string) (io.ReadCloser, error)
+
+// CompileContext compiles a JSON schema like Compile, passing ctx to the loaders of the documents
+// it references, so that fetching them stops when ctx is canceled or its deadline passes. When ctx
+// is done before the compilation completes, the error is the error of ctx and the schema is not
+// cached, since the references that were not fetched are unresolved.
+func (c *Compiler) CompileContext(ctx context.Context, jsonSchema []byte, uris ...string) (*Schema, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.compileMu.Lock()
+	defer c.compileMu.Unlock()
+	defer c.withContext(ctx)()
+	return c.completeWithin(ctx)(c.compile(jsonSchema, uris...))
+}
+
+// GetSchemaContext retrieves a schema by reference like GetSchema, passing ctx to the loaders of
+// the documents it fetches, with the same handling of ctx as CompileContext.
+func (c *Compiler) GetSchemaContext(ctx context.Context, ref string) (*Schema, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.compileMu.Lock()
+	defer c.compileMu.Unlock()
+	defer c.withContext(ctx)()
+	return c.completeWithin(ctx)(c.getSchema(ref))
+}
+
+// completeWithin returns the function checking that a compilation completed before ctx was done,
+// and otherwise removing the schema it returned from the cache.
+func (c *Compiler) completeWithin(ctx context.Context) func(*Schema, error) (*Schema, error) {
+	return func(schema *Schema, err error) (*Schema, error) {
+		if err != nil || ctx.Err() == nil {
+			return schema, err
+		}
+		if root := schema.getRootSchema(); root.uri != "" {
+			c.removeSchema(root)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// withContext makes ctx the context of the compilation in progress, and returns the function
+// restoring the previous one.
+func (c *Compiler) withContext(ctx context.Context) func() {
+	previous := c.ctx
+	c.ctx = ctx
+	return func() { c.ctx = previous }
+}
+
+// context returns the context of the compilation in progress, or the background context.
+func (c *Compiler) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// ValidateContext validates instance like Validate, stopping when ctx is canceled or its deadline
+// passes, so that the validation of very large or deeply nested instances can be abandoned. The
+// returned result of a stopped validation is invalid and holds the errors found so far, with a
+// validation_canceled error at the root; the returned error is the error of ctx.
+//
+// Like the limits of ValidateWithBudget, ctx is checked as subschemas are entered.
+func (s *Schema) ValidateContext(ctx context.Context, instance interface{}) (*EvaluationResult, error) {
+	dynamicScope := NewDynamicScope()
+	dynamicScope.assertFormat = s.assertFormat
+	state := &budgetState{ctx: ctx, compiler: s.compiler, start: s.compiler.Now()}
+	dynamicScope.budget = state
+	result := s.validate(instance, dynamicScope)
+
+	if state.canceled == nil {
+		return result, nil
+	}
+	result.AddError(NewEvaluationError("context", "validation_canceled", "Validation stopped: {reason}", map[string]interface{}{
+		"reason": state.canceled.Error(),
+	}))
+	return result, state.canceled
+}
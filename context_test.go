@@ -0,0 +1,88 @@
+package jsonschema
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type contextKey struct{}
+
+func TestCompileContext(t *testing.T) {
+	compiler := NewCompiler()
+	var received []interface{}
+	compiler.RegisterContextLoader("mem", func(ctx context.Context, url string) (io.ReadCloser, error) {
+		received = append(received, ctx.Value(contextKey{}))
+		return io.NopCloser(strings.NewReader(`{"type": "string"}`)), nil
+	})
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "request")
+	schema, err := compiler.CompileContext(ctx, []byte(`{"$id": "mem:///root.json", "properties": {"name": {"$ref": "mem:///name.json"}}}`))
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(map[string]interface{}{"name": 1}).IsValid())
+	assert.Equal(t, []interface{}{"request"}, received)
+
+	_, err = compiler.GetSchemaContext(ctx, "mem:///other.json")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"request", "request"}, received)
+
+	_, err = compiler.GetSchema("mem:///plain.json")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"request", "request", nil}, received, "other calls use a background context")
+
+	_, err = compiler.Loaders["mem"]("mem:///direct.json")
+	require.NoError(t, err)
+}
+
+func TestCompileContextCanceled(t *testing.T) {
+	compiler := NewCompiler()
+	ctx, cancel := context.WithCancel(context.Background())
+	compiler.RegisterContextLoader("mem", func(ctx context.Context, url string) (io.ReadCloser, error) {
+		cancel()
+		return nil, ctx.Err()
+	})
+
+	_, err := compiler.CompileContext(ctx, []byte(`{"$id": "mem:///root.json", "$ref": "mem:///name.json"}`))
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.NotContains(t, compiler.schemas, "mem:///root.json", "the incomplete schema is not cached")
+
+	_, err = compiler.GetSchemaContext(ctx, "mem:///name.json")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestRegisterLoaderReplacesContextLoader(t *testing.T) {
+	compiler := NewCompiler()
+	compiler.RegisterContextLoader("mem", func(context.Context, string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"type": "string"}`)), nil
+	})
+	compiler.RegisterLoader("mem", func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"type": "integer"}`)), nil
+	})
+
+	schema, err := compiler.GetSchema("mem:///value.json")
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(1).IsValid())
+}
+
+func TestValidateContext(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"type": "array", "items": {"type": "integer"}}`))
+	require.NoError(t, err)
+	instance := []interface{}{1, 2, "three"}
+
+	result, err := schema.ValidateContext(context.Background(), instance)
+	require.NoError(t, err)
+	assert.Equal(t, schema.Validate(instance).ToList(), result.ToList())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = schema.ValidateContext(ctx, instance)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, result.IsValid())
+	require.Contains(t, result.Errors, "context")
+	assert.Equal(t, "validation_canceled", result.Errors["context"].Code)
+}
@@ -0,0 +1,136 @@
+package jsonschema
+
+import (
+	"sync"
+)
+
+// Coverage records which subschemas of a schema are evaluated while validating a corpus of
+// instances, and whether each of them accepted and rejected at least one instance. It tells
+// whether contract tests exercise every branch of a schema: an anyOf alternative that is never
+// evaluated as valid, or a then clause that is never reached, shows up as uncovered.
+//
+// A Coverage is safe for concurrent use.
+type Coverage struct {
+	schema *Schema
+	order  []string           // JSON Pointers of the subschemas, in walk order
+	index  map[*Schema]string // JSON Pointer of each subschema
+	counts map[string]*SchemaCoverage
+	total  int
+	mu     sync.Mutex
+}
+
+// SchemaCoverage holds the evaluation counts of a single subschema.
+type SchemaCoverage struct {
+	Pointer   string `json:"pointer"`   // JSON Pointer of the subschema, relative to the root schema.
+	Evaluated int    `json:"evaluated"` // Number of evaluations of the subschema.
+	Valid     int    `json:"valid"`     // Number of evaluations that succeeded.
+	Invalid   int    `json:"invalid"`   // Number of evaluations that failed.
+}
+
+// Covered reports whether the subschema was evaluated at least once.
+func (c SchemaCoverage) Covered() bool {
+	return c.Evaluated > 0
+}
+
+// CoverageReport summarizes the coverage of a schema by a corpus of instances.
+type CoverageReport struct {
+	Instances int              `json:"instances"` // Number of instances validated.
+	Schemas   []SchemaCoverage `json:"schemas"`   // Every subschema, root first, in a deterministic order.
+}
+
+// Uncovered returns the JSON Pointers of the subschemas that were never evaluated.
+func (r *CoverageReport) Uncovered() []string {
+	var pointers []string
+	for _, schema := range r.Schemas {
+		if !schema.Covered() {
+			pointers = append(pointers, schema.Pointer)
+		}
+	}
+	return pointers
+}
+
+// OneSided returns the JSON Pointers of the subschemas that were evaluated but never both
+// accepted and rejected an instance, such as an anyOf alternative that always matched.
+func (r *CoverageReport) OneSided() []string {
+	var pointers []string
+	for _, schema := range r.Schemas {
+		if schema.Covered() && (schema.Valid == 0 || schema.Invalid == 0) {
+			pointers = append(pointers, schema.Pointer)
+		}
+	}
+	return pointers
+}
+
+// Ratio returns the fraction of subschemas that were evaluated at least once.
+func (r *CoverageReport) Ratio() float64 {
+	if len(r.Schemas) == 0 {
+		return 0
+	}
+	return float64(len(r.Schemas)-len(r.Uncovered())) / float64(len(r.Schemas))
+}
+
+// NewCoverage returns a Coverage for the compiled schema s.
+// Subschemas are addressed by their JSON Pointer in s; schemas reached through
+// references to other documents are evaluated but not reported.
+func NewCoverage(s *Schema) *Coverage {
+	c := &Coverage{
+		schema: s,
+		index:  make(map[*Schema]string),
+		counts: make(map[string]*SchemaCoverage),
+	}
+	walkSchema(s, func(pointer string, schema *Schema) bool {
+		if _, ok := c.index[schema]; ok {
+			return false
+		}
+		c.index[schema] = pointer
+		c.order = append(c.order, pointer)
+		c.counts[pointer] = &SchemaCoverage{Pointer: pointer}
+		return true
+	})
+	return c
+}
+
+// Validate validates instance against the schema like Schema.Validate and records the evaluated subschemas.
+func (c *Coverage) Validate(instance interface{}) *EvaluationResult {
+	dynamicScope := NewDynamicScope()
+	dynamicScope.coverage = c
+	dynamicScope.assertFormat = c.schema.assertFormat
+	result := c.schema.validate(instance, dynamicScope)
+
+	c.mu.Lock()
+	c.total++
+	c.mu.Unlock()
+
+	return result
+}
+
+// Report returns the coverage recorded so far.
+func (c *Coverage) Report() *CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &CoverageReport{Instances: c.total, Schemas: make([]SchemaCoverage, 0, len(c.order))}
+	for _, pointer := range c.order {
+		report.Schemas = append(report.Schemas, *c.counts[pointer])
+	}
+	return report
+}
+
+// record counts an evaluation of schema.
+func (c *Coverage) record(schema *Schema, valid bool) {
+	pointer, ok := c.index[schema]
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := c.counts[pointer]
+	counts.Evaluated++
+	if valid {
+		counts.Valid++
+	} else {
+		counts.Invalid++
+	}
+}
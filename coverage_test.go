@@ -0,0 +1,80 @@
+package jsonschema
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$defs": {
+			"positive": {"type": "integer", "minimum": 1},
+			"unused": {"type": "string"}
+		},
+		"type": "object",
+		"properties": {
+			"id": {"$ref": "#/$defs/positive"},
+			"contact": {
+				"anyOf": [
+					{"type": "string", "format": "email"},
+					{"type": "object", "required": ["phone"]}
+				]
+			},
+			"note": {"type": "string"}
+		},
+		"if": {"required": ["contact"]},
+		"then": {"required": ["id"]},
+		"else": {"maxProperties": 1}
+	}`))
+	require.NoError(t, err)
+
+	coverage := NewCoverage(schema)
+	assert.True(t, coverage.Validate(map[string]interface{}{"id": 1.0, "contact": "a@example.com"}).IsValid())
+	assert.False(t, coverage.Validate(map[string]interface{}{"id": 0.0, "contact": "a@example.com"}).IsValid())
+
+	report := coverage.Report()
+	assert.Equal(t, 2, report.Instances)
+	assert.Equal(t, []string{
+		"/$defs/unused",
+		"/else",
+		"/properties/note",
+	}, report.Uncovered())
+	assert.Equal(t, []string{
+		"/if",
+		"/then",
+		"/properties/contact",
+		"/properties/contact/anyOf/0",
+		"/properties/contact/anyOf/1",
+	}, report.OneSided())
+	assert.InDelta(t, 8.0/11.0, report.Ratio(), 1e-9)
+
+	for _, s := range report.Schemas {
+		if s.Pointer == "" {
+			assert.Equal(t, SchemaCoverage{Pointer: "", Evaluated: 2, Valid: 1, Invalid: 1}, s)
+		}
+	}
+}
+
+func TestCoverageConcurrent(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"items": {"type": "integer"}}`))
+	require.NoError(t, err)
+
+	coverage := NewCoverage(schema)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			coverage.Validate([]interface{}{1.0, "a"})
+		}()
+	}
+	wg.Wait()
+
+	report := coverage.Report()
+	assert.Equal(t, 8, report.Instances)
+	assert.Empty(t, report.Uncovered())
+	assert.Equal(t, 16, report.Schemas[1].Evaluated)
+}
@@ -0,0 +1,29 @@
+package jsonschema
+
+import (
+	"github.com/goccy/go-json"
+)
+
+// Decode validates the JSON document data against schema and decodes it into a value of T, so
+// that application code gets a concrete type instead of handling interface{} values. When the
+// document is not valid, the returned error is the *EvaluationResult and the value is the zero
+// value of T. Properties given more than once are handled with the DuplicateKeyPolicy of the
+// compiler, and data after the document is an error.
+func Decode[T any](schema *Schema, data []byte) (T, error) {
+	var value T
+	instance, err := schema.parseInstance(data)
+	if err != nil {
+		return value, err
+	}
+	if result := schema.Validate(instance); !result.IsValid() {
+		return value, result
+	}
+	if schema.jsonParser() == nil && schema.duplicateKeyPolicy() == DuplicateKeysFirstWins {
+		// Decode the occurrences that were validated rather than the last ones.
+		if data, err = json.Marshal(instance); err != nil {
+			return value, err
+		}
+	}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
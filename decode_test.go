@@ -0,0 +1,44 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"required": ["id"],
+		"properties": {"id": {"type": "integer", "minimum": 1}, "tags": {"type": "array", "items": {"type": "string"}}}
+	}`))
+	require.NoError(t, err)
+
+	type order struct {
+		ID   int64    `json:"id"`
+		Tags []string `json:"tags"`
+	}
+	value, err := Decode[order](schema, []byte(`{"id": 9007199254740993, "tags": ["a"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, order{ID: 9007199254740993, Tags: []string{"a"}}, value)
+
+	value, err = Decode[order](schema, []byte(`{"id": 0}`))
+	var result *EvaluationResult
+	require.ErrorAs(t, err, &result)
+	assert.Equal(t, order{}, value)
+
+	generic, err := Decode[map[string]interface{}](schema, []byte(`{"id": 1}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": 1.0}, generic)
+
+	_, err = Decode[order](schema, []byte(`{"id":`))
+	assert.ErrorIs(t, err, ErrJSONUnmarshalError)
+
+	_, err = Decode[order](schema, []byte(`{"id": 1} {"id": 0}`))
+	assert.ErrorIs(t, err, ErrJSONUnmarshalError, "data after the document")
+	_, err = Decode[order](schema, []byte("{\"id\": 1}\n\t"))
+	assert.NoError(t, err)
+	var defaulted order
+	assert.ErrorIs(t, schema.UnmarshalWithDefaults([]byte(`{"id": 1} x`), &defaulted), ErrJSONUnmarshalError)
+}
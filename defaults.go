@@ -0,0 +1,184 @@
+package jsonschema
+
+import (
+	"sort"
+
+	"github.com/goccy/go-json"
+)
+
+// UnmarshalWithDefaults decodes the JSON document data into v, like json.Unmarshal, after filling
+// in the defaults of the schema for absent properties and validating the result, so that loading
+// a configuration file validates and completes it in one step.
+//
+// Defaults are applied like ApplyDefaults applies them. When the defaulted document is not valid,
+// the returned error is the *EvaluationResult and v is left unchanged.
+func (s *Schema) UnmarshalWithDefaults(data []byte, v interface{}) error {
+	instance, err := s.parseInstance(data)
+	if err != nil {
+		return err
+	}
+
+	instance = s.applyDefaults(instance)
+	if result := s.Validate(instance); !result.IsValid() {
+		return result
+	}
+
+	defaulted, err := json.Marshal(instance)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(defaulted, v)
+}
+
+// ApplyDefaults fills in the absent properties of the objects of instance, and the absent items
+// at the end of its arrays, with the default keywords of their schemas, and returns the defaulted
+// instance. Objects and arrays are modified in place, but arrays that grow are new slices that
+// replace the original ones in their parents, hence the returned instance. The instance must be
+// a generic JSON value, such as decoded by json.Unmarshal into an interface{}.
+//
+// Defaults come from the properties and prefixItems schemas of each object and array, and are
+// applied to nested objects and arrays as well. An array only grows while the prefixItems schema
+// of its next position has a default. Besides the schema itself, the defaults of the schemas that
+// apply to the instance are applied: those reached through $ref and allOf, then or else depending
+// on whether the instance conforms to if, the dependentSchemas of its present properties, every
+// branch of anyOf it conforms to, and the only branch of oneOf it conforms to. The defaults of a
+// schema take precedence over those of the schemas it applies, earlier branches take precedence
+// over later ones, and defaults never replace present values.
+//
+// Conditions are evaluated on the instance as given, before any default is applied, so that the
+// branches taken do not depend on the order in which defaults are applied: {"if": {"properties":
+// {"mode": {"const": "safe"}}}} holds for an instance without mode even if the schema of mode has
+// a default, as it does in validation without defaults. Within a value added by a default, the
+// conditions of nested schemas are evaluated on that default.
+func (s *Schema) ApplyDefaults(instance interface{}) interface{} {
+	return s.applyDefaults(instance)
+}
+
+// ValidateAndApplyDefaults applies the defaults of the schema to the instance with ApplyDefaults,
+// replacing the instance with the defaulted one, and validates the result.
+func (s *Schema) ValidateAndApplyDefaults(instance *interface{}) *EvaluationResult {
+	*instance = s.applyDefaults(*instance)
+	return s.Validate(*instance)
+}
+
+// applyDefaults applies the defaults of the schema to instance, and returns the instance.
+func (s *Schema) applyDefaults(instance interface{}) interface{} {
+	return s.applyDefaultsTo(instance, copyJSON(instance), map[*Schema]bool{})
+}
+
+// applyDefaultsTo applies the defaults of s and the schemas it applies to instance, and returns
+// the defaulted instance. The conditions of s are evaluated on original, the instance before
+// defaults were applied. The visited schemas guard against recursive references.
+func (s *Schema) applyDefaultsTo(instance, original interface{}, visited map[*Schema]bool) interface{} {
+	if s == nil || s.Boolean != nil || visited[s] {
+		return instance
+	}
+	visited[s] = true
+	defer delete(visited, s)
+
+	// The defaults of s come first, so that they take precedence over those of the schemas it applies.
+	switch value := instance.(type) {
+	case map[string]interface{}:
+		originals, _ := original.(map[string]interface{})
+		if s.Properties != nil {
+			for name, schema := range *s.Properties {
+				if _, ok := value[name]; !ok && schema != nil && schema.Default != nil {
+					value[name] = copyJSON(schema.Default)
+				}
+				if property, ok := value[name]; ok {
+					value[name] = schema.applyDefaultsTo(property, originalOf(originals, name, property), visited)
+				}
+			}
+		}
+	case []interface{}:
+		originals, _ := original.([]interface{})
+		for i := len(value); i < len(s.PrefixItems) && s.PrefixItems[i] != nil && s.PrefixItems[i].Default != nil; i++ {
+			value = append(value, copyJSON(s.PrefixItems[i].Default))
+		}
+		for i, item := range value {
+			var prior interface{}
+			if i < len(originals) {
+				prior = originals[i]
+			} else {
+				prior = copyJSON(item)
+			}
+			if i < len(s.PrefixItems) {
+				value[i] = s.PrefixItems[i].applyDefaultsTo(item, prior, visited)
+			} else {
+				value[i] = s.Items.applyDefaultsTo(item, prior, visited)
+			}
+		}
+		instance = value
+	}
+
+	instance = s.ResolvedRef.applyDefaultsTo(instance, original, visited)
+	for _, schema := range s.AllOf {
+		instance = schema.applyDefaultsTo(instance, original, visited)
+	}
+	if s.If != nil {
+		if s.If.Validate(original).IsValid() {
+			instance = s.Then.applyDefaultsTo(instance, original, visited)
+		} else {
+			instance = s.Else.applyDefaultsTo(instance, original, visited)
+		}
+	}
+	if object, ok := original.(map[string]interface{}); ok && len(s.DependentSchemas) > 0 {
+		names := make([]string, 0, len(s.DependentSchemas))
+		for name := range s.DependentSchemas {
+			if _, ok := object[name]; ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			instance = s.DependentSchemas[name].applyDefaultsTo(instance, original, visited)
+		}
+	}
+	for _, schema := range s.AnyOf {
+		if schema.Validate(original).IsValid() {
+			instance = schema.applyDefaultsTo(instance, original, visited)
+		}
+	}
+	if len(s.OneOf) > 0 {
+		var matched *Schema
+		for _, schema := range s.OneOf {
+			if schema.Validate(original).IsValid() {
+				if matched != nil {
+					return instance // Ambiguous; no branch applies.
+				}
+				matched = schema
+			}
+		}
+		instance = matched.applyDefaultsTo(instance, original, visited)
+	}
+	return instance
+}
+
+// originalOf returns the value of the property name of originals, the object before defaults were
+// applied, or a copy of property, its current value, when the property was added by a default.
+func originalOf(originals map[string]interface{}, name string, property interface{}) interface{} {
+	if original, ok := originals[name]; ok {
+		return original
+	}
+	return copyJSON(property)
+}
+
+// copyJSON returns a deep copy of a generic JSON value, so that defaults are not shared between
+// instances.
+func copyJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = copyJSON(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyJSON(item)
+		}
+		return copied
+	}
+	return value
+}
@@ -0,0 +1,180 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const defaultsSchema = `{
+	"$defs": {
+		"TLS": {"type": "object", "properties": {"enabled": {"type": "boolean", "default": true}}}
+	},
+	"type": "object",
+	"required": ["name"],
+	"properties": {
+		"name": {"type": "string"},
+		"port": {"type": "integer", "minimum": 1, "default": 8080},
+		"tags": {"type": "array", "default": ["web"]},
+		"tls": {"$ref": "#/$defs/TLS", "default": {}}
+	}
+}`
+
+type defaultsConfig struct {
+	Name string   `json:"name"`
+	Port int      `json:"port"`
+	Tags []string `json:"tags"`
+	TLS  struct {
+		Enabled bool `json:"enabled"`
+	} `json:"tls"`
+}
+
+func TestUnmarshalWithDefaults(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(defaultsSchema))
+	require.NoError(t, err)
+
+	var config defaultsConfig
+	require.NoError(t, schema.UnmarshalWithDefaults([]byte(`{"name": "api"}`), &config))
+	assert.Equal(t, "api", config.Name)
+	assert.Equal(t, 8080, config.Port)
+	assert.Equal(t, []string{"web"}, config.Tags)
+	assert.True(t, config.TLS.Enabled)
+
+	config = defaultsConfig{}
+	require.NoError(t, schema.UnmarshalWithDefaults([]byte(`{"name": "api", "port": 9000, "tls": {"enabled": false}}`), &config))
+	assert.Equal(t, 9000, config.Port)
+	assert.False(t, config.TLS.Enabled)
+}
+
+func TestUnmarshalWithDefaultsInvalid(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(defaultsSchema))
+	require.NoError(t, err)
+
+	config := defaultsConfig{Name: "unchanged"}
+	err = schema.UnmarshalWithDefaults([]byte(`{"port": 0}`), &config)
+	var result *EvaluationResult
+	require.ErrorAs(t, err, &result)
+	assert.False(t, result.IsValid())
+	assert.Equal(t, "unchanged", config.Name)
+
+	err = schema.UnmarshalWithDefaults([]byte(`{`), &config)
+	assert.ErrorIs(t, err, ErrJSONUnmarshalError)
+}
+
+func TestApplyDefaults(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"mode": {"enum": ["fast", "safe"], "default": "fast"},
+			"point": {"prefixItems": [{"default": 0}, {"default": 0}, {"type": "number"}]},
+			"servers": {"items": {"properties": {"port": {"default": 80}}}}
+		},
+		"allOf": [{"properties": {"retries": {"default": 3}}}],
+		"if": {"properties": {"mode": {"const": "safe"}}},
+		"then": {"properties": {"timeout": {"default": 60}}},
+		"else": {"properties": {"timeout": {"default": 5}}},
+		"dependentSchemas": {"proxy": {"properties": {"proxyPort": {"default": 3128}}}},
+		"oneOf": [
+			{"required": ["file"], "properties": {"level": {"default": "debug"}}},
+			{"not": {"required": ["file"]}}
+		]
+	}`))
+	require.NoError(t, err)
+
+	var instance interface{} = map[string]interface{}{
+		"point":   []interface{}{1},
+		"servers": []interface{}{map[string]interface{}{}, map[string]interface{}{"port": 8080}},
+	}
+	result := schema.ValidateAndApplyDefaults(&instance)
+	assert.True(t, result.IsValid())
+	assert.Equal(t, map[string]interface{}{
+		"mode":    "fast",
+		"point":   []interface{}{1, 0.0},
+		"servers": []interface{}{map[string]interface{}{"port": 80.0}, map[string]interface{}{"port": 8080}},
+		"retries": 3.0,
+		"timeout": 60.0, // if holds for the instance without mode, before its default applies.
+	}, instance)
+
+	defaulted := schema.ApplyDefaults(map[string]interface{}{"mode": "safe", "proxy": "p", "file": "log.txt"})
+	assert.Equal(t, map[string]interface{}{
+		"mode":      "safe",
+		"proxy":     "p",
+		"proxyPort": 3128.0,
+		"file":      "log.txt",
+		"level":     "debug",
+		"retries":   3.0,
+		"timeout":   60.0,
+	}, defaulted)
+}
+
+func TestApplyDefaultsConditions(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"properties": {
+			"kind": {"default": "file"},
+			"output": {
+				"default": {"format": "json"},
+				"if": {"properties": {"format": {"const": "json"}}, "required": ["format"]},
+				"then": {"properties": {"indent": {"default": 2}}},
+				"else": {"properties": {"width": {"default": 80}}}
+			}
+		},
+		"if": {"required": ["kind"], "properties": {"kind": {"const": "file"}}},
+		"then": {
+			"properties": {"path": {"default": "out.log"}},
+			"if": {"required": ["path"]},
+			"then": {"properties": {"rotate": {"default": true}}},
+			"else": {"properties": {"rotate": {"default": false}}}
+		},
+		"else": {
+			"allOf": [{
+				"if": {"required": ["host"]},
+				"then": {"properties": {"port": {"default": 514}}}
+			}]
+		},
+		"anyOf": [
+			{"required": ["kind"], "properties": {"level": {"default": "info"}}},
+			{"properties": {"level": {"default": "warn"}, "buffer": {"default": 4096}}},
+			{"required": ["never"], "properties": {"flush": {"default": true}}}
+		]
+	}`))
+	require.NoError(t, err)
+
+	// kind is absent before defaults, so the else branches apply although kind defaults to file;
+	// the default output is then the instance of its own conditions.
+	assert.Equal(t, map[string]interface{}{
+		"kind":   "file",
+		"output": map[string]interface{}{"format": "json", "indent": 2.0},
+		"host":   "h",
+		"port":   514.0,
+		"level":  "warn",
+		"buffer": 4096.0,
+	}, schema.ApplyDefaults(map[string]interface{}{"host": "h"}))
+
+	// Nested conditions are evaluated on the instance before the defaults of their outer branch:
+	// path is absent, so rotate defaults to false even though then adds path.
+	assert.Equal(t, map[string]interface{}{
+		"kind":   "file",
+		"output": map[string]interface{}{"format": "text", "width": 80.0},
+		"path":   "out.log",
+		"rotate": false,
+		"level":  "info",
+		"buffer": 4096.0,
+	}, schema.ApplyDefaults(map[string]interface{}{"kind": "file", "output": map[string]interface{}{"format": "text"}}))
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, "info", schema.ApplyDefaults(map[string]interface{}{"kind": "file"}).(map[string]interface{})["level"],
+			"earlier branches of anyOf take precedence")
+	}
+}
+
+func TestApplyDefaultsCopies(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"properties": {"tags": {"default": ["a"]}}}`))
+	require.NoError(t, err)
+
+	first := schema.ApplyDefaults(map[string]interface{}{}).(map[string]interface{})
+	second := schema.ApplyDefaults(map[string]interface{}{}).(map[string]interface{})
+	first["tags"].([]interface{})[0] = "changed"
+	assert.Equal(t, []interface{}{"a"}, second["tags"], "defaults are not shared")
+	assert.Equal(t, "text", schema.ApplyDefaults("text"))
+}
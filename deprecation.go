@@ -0,0 +1,77 @@
+package jsonschema
+
+import (
+	"sort"
+)
+
+// DeprecationReport lists the subschemas of a set of schemas marked with deprecated: true, and how
+// often validations used them, so that deprecated fields still in active use are found before
+// they are removed.
+type DeprecationReport struct {
+	Validations int64             `json:"validations"` // Validations observed while Compiler.CollectFailures was enabled.
+	Schemas     []DeprecatedUsage `json:"schemas"`     // Deprecated subschemas, the most used first.
+}
+
+// DeprecatedUsage is a deprecated subschema with its observed use.
+type DeprecatedUsage struct {
+	Location string `json:"location"` // Canonical location of the subschema, such as https://example.com/user.json#/properties/nickname.
+	Uses     int64  `json:"uses"`     // Validations that evaluated the subschema.
+}
+
+// InUse returns the deprecated subschemas that at least one validation used.
+func (r *DeprecationReport) InUse() []DeprecatedUsage {
+	var used []DeprecatedUsage
+	for _, usage := range r.Schemas {
+		if usage.Uses > 0 {
+			used = append(used, usage)
+		}
+	}
+	return used
+}
+
+// DeprecationReport scans schemas, or every schema cached by c when none is given, for
+// subschemas marked with deprecated: true, and cross-references them with the validations
+// observed while CollectFailures was enabled, see SetCollectFailures. A deprecated subschema is
+// used by a validation that evaluates it, such as one whose instance has a deprecated property,
+// whichever schema the validation started from.
+func (c *Compiler) DeprecationReport(schemas ...*Schema) *DeprecationReport {
+	if len(schemas) == 0 {
+		for _, schema := range c.allSchemas() {
+			schemas = append(schemas, schema)
+		}
+	}
+
+	uses := make(map[*Schema]int64)
+	for _, schema := range schemas {
+		walkSchema(schema, func(_ string, subschema *Schema) bool {
+			if subschema.Deprecated != nil && *subschema.Deprecated {
+				uses[subschema] = 0
+			}
+			return true
+		})
+	}
+
+	report := &DeprecationReport{}
+	c.timings.mu.Lock()
+	for _, timings := range c.timings.schemas {
+		report.Validations += timings.validations
+		for subschema, count := range timings.deprecated {
+			if _, ok := uses[subschema]; ok {
+				uses[subschema] += count
+			}
+		}
+	}
+	c.timings.mu.Unlock()
+
+	report.Schemas = make([]DeprecatedUsage, 0, len(uses))
+	for subschema, count := range uses {
+		report.Schemas = append(report.Schemas, DeprecatedUsage{Location: subschema.Location(), Uses: count})
+	}
+	sort.Slice(report.Schemas, func(i, j int) bool {
+		if report.Schemas[i].Uses != report.Schemas[j].Uses {
+			return report.Schemas[i].Uses > report.Schemas[j].Uses
+		}
+		return report.Schemas[i].Location < report.Schemas[j].Location
+	})
+	return report
+}
@@ -0,0 +1,51 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecationReport(t *testing.T) {
+	compiler := NewCompiler().SetCollectFailures(true)
+	_, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/common.json",
+		"$defs": {"legacyId": {"type": "integer", "deprecated": true}}
+	}`))
+	require.NoError(t, err)
+	user, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/user.json",
+		"properties": {
+			"name": {"type": "string"},
+			"nickname": {"type": "string", "deprecated": true},
+			"fax": {"type": "string", "deprecated": true},
+			"ids": {"items": {"$ref": "common.json#/$defs/legacyId"}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	report := compiler.DeprecationReport()
+	assert.Equal(t, int64(0), report.Validations)
+	assert.Len(t, report.Schemas, 3)
+	assert.Empty(t, report.InUse())
+
+	user.Validate(map[string]interface{}{"name": "Ada", "nickname": "ada"})
+	user.Validate(map[string]interface{}{"nickname": "bob", "ids": []interface{}{1, 2}})
+	user.Validate(map[string]interface{}{"name": "Eve"})
+
+	report = compiler.DeprecationReport()
+	assert.Equal(t, int64(3), report.Validations)
+	assert.Equal(t, []DeprecatedUsage{
+		{Location: "https://example.com/user.json#/properties/nickname", Uses: 2},
+		{Location: "https://example.com/common.json#/$defs/legacyId", Uses: 1},
+		{Location: "https://example.com/user.json#/properties/fax", Uses: 0},
+	}, report.Schemas)
+	assert.Len(t, report.InUse(), 2)
+
+	report = compiler.DeprecationReport(user)
+	assert.Equal(t, []DeprecatedUsage{
+		{Location: "https://example.com/user.json#/properties/nickname", Uses: 2},
+		{Location: "https://example.com/user.json#/properties/fax", Uses: 0},
+	}, report.Schemas, "only the given schemas are scanned")
+}
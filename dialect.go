@@ -0,0 +1,197 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// Dialect is a custom dialect, selected by schemas whose $schema is its URI, that accepts a vetted
+// subset of the standard keywords, possibly under other names, for environments that must not
+// accept every schema the specification allows.
+type Dialect struct {
+	// URI is the value of $schema that selects the dialect.
+	URI string
+	// Aliases maps keywords of the dialect to the standard keywords they stand for, such as
+	// "definitions" to "$defs". JSON Pointers in $ref and $dynamicRef may use the aliases too.
+	Aliases map[string]string
+	// Removed lists the standard keywords the dialect does not allow; schemas using them are
+	// rejected when compiled. Listing a keyword that has an alias leaves only the alias.
+	Removed []string
+	// LocalRefs restricts $ref and $dynamicRef to the schemas already known to the compiler,
+	// such as those of the same document or compiled before, so that no document is ever fetched.
+	LocalRefs bool
+	// AssertFormat makes format an assertion in the schemas of the dialect.
+	AssertFormat bool
+}
+
+// RegisterDialect registers a custom dialect, used to compile the schemas whose $schema is the URI
+// of the dialect.
+func (c *Compiler) RegisterDialect(dialect Dialect) *Compiler {
+	if c.Dialects == nil {
+		c.Dialects = make(map[string]*Dialect)
+	}
+	c.Dialects[strings.TrimSuffix(dialect.URI, "#")] = &dialect
+	return c
+}
+
+// SetDefaultDialect sets the URI of the registered dialect used to compile the schemas that have
+// no $schema.
+func (c *Compiler) SetDefaultDialect(uri string) *Compiler {
+	c.DefaultDialect = uri
+	return c
+}
+
+// dialect returns the registered dialect with the given URI, or nil.
+func (c *Compiler) dialect(uri string) *Dialect {
+	return c.Dialects[strings.TrimSuffix(uri, "#")]
+}
+
+// Kinds of keywords whose values are subschemas.
+const (
+	subschemaSingle = iota + 1 // The value is a schema.
+	subschemaList              // The value is an array of schemas.
+	subschemaMap               // The value is an object of schemas.
+)
+
+// subschemaKeywords gives the kind of every standard keyword whose value holds subschemas.
+var subschemaKeywords = map[string]int{
+	"not": subschemaSingle, "if": subschemaSingle, "then": subschemaSingle, "else": subschemaSingle,
+	"items": subschemaSingle, "contains": subschemaSingle, "additionalProperties": subschemaSingle,
+	"propertyNames": subschemaSingle, "unevaluatedItems": subschemaSingle,
+	"unevaluatedProperties": subschemaSingle, "contentSchema": subschemaSingle,
+	"allOf": subschemaList, "anyOf": subschemaList, "oneOf": subschemaList, "prefixItems": subschemaList,
+	"$defs": subschemaMap, "dependentSchemas": subschemaMap, "properties": subschemaMap,
+	"patternProperties": subschemaMap,
+}
+
+// applyDialects rewrites the schemas of a document written in registered dialects to standard
+// keywords, and returns the dialect of the root schema, or nil.
+func (c *Compiler) applyDialects(jsonSchema []byte) ([]byte, *Dialect, error) {
+	if len(c.Dialects) == 0 {
+		return jsonSchema, nil, nil
+	}
+	var document interface{}
+	if err := decodeExactJSON(jsonSchema, &document); err != nil {
+		return nil, nil, err
+	}
+	root, ok := document.(map[string]interface{})
+	if !ok {
+		return jsonSchema, nil, nil
+	}
+
+	dialect := c.dialect(c.DefaultDialect)
+	if uri, ok := root["$schema"].(string); ok {
+		dialect = c.dialect(uri)
+	}
+	changed, err := c.rewriteSchema(root, dialect, "")
+	if err != nil || !changed {
+		return jsonSchema, dialect, err
+	}
+	data, err := json.Marshal(root)
+	return data, dialect, err
+}
+
+// rewriteSchema rewrites the keywords of the schema at pointer, and of its subschemas, written in
+// dialect, which is nil for standard dialects, and reports whether anything changed.
+func (c *Compiler) rewriteSchema(value interface{}, dialect *Dialect, pointer string) (bool, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return false, nil
+	}
+	if uri, ok := object["$schema"].(string); ok && pointer != "" {
+		dialect = c.dialect(uri)
+	}
+
+	changed := false
+	if dialect != nil {
+		for _, keyword := range dialect.Removed {
+			if _, ok := object[keyword]; ok {
+				return false, fmt.Errorf("%w: %s at '%s' in dialect %s", ErrKeywordNotAllowed, keyword, pointer, dialect.URI)
+			}
+		}
+		aliases := make([]string, 0, len(dialect.Aliases))
+		for alias := range dialect.Aliases {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+		for _, alias := range aliases {
+			aliased, ok := object[alias]
+			if !ok {
+				continue
+			}
+			keyword := dialect.Aliases[alias]
+			if _, ok := object[keyword]; ok {
+				return false, fmt.Errorf("%w: %s and %s at '%s'", ErrAliasedKeywordConflict, alias, keyword, pointer)
+			}
+			delete(object, alias)
+			object[keyword] = aliased
+			changed = true
+		}
+		for _, keyword := range []string{"$ref", "$dynamicRef"} {
+			if ref, ok := object[keyword].(string); ok {
+				if rewritten := dialect.aliasRef(ref); rewritten != ref {
+					object[keyword] = rewritten
+					changed = true
+				}
+			}
+		}
+	}
+
+	for keyword, value := range object {
+		var subschemas map[string]interface{}
+		switch subschemaKeywords[keyword] {
+		case subschemaSingle:
+			subschemas = map[string]interface{}{"": value}
+		case subschemaList:
+			if list, ok := value.([]interface{}); ok {
+				subschemas = make(map[string]interface{}, len(list))
+				for i, item := range list {
+					subschemas[fmt.Sprintf("/%d", i)] = item
+				}
+			}
+		case subschemaMap:
+			if values, ok := value.(map[string]interface{}); ok {
+				subschemas = make(map[string]interface{}, len(values))
+				for key, item := range values {
+					subschemas["/"+escapeJSONPointer(key)] = item
+				}
+			}
+		}
+		for suffix, subschema := range subschemas {
+			subschemaChanged, err := c.rewriteSchema(subschema, dialect, pointer+"/"+escapeJSONPointer(keyword)+suffix)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || subschemaChanged
+		}
+	}
+	return changed, nil
+}
+
+// aliasRef rewrites the aliased keywords of the JSON Pointer in the fragment of a reference, such
+// as #/definitions/name to #/$defs/name.
+func (d *Dialect) aliasRef(ref string) string {
+	base, fragment, ok := strings.Cut(ref, "#")
+	if !ok || !strings.HasPrefix(fragment, "/") || len(d.Aliases) == 0 {
+		return ref
+	}
+
+	segments := strings.Split(fragment[1:], "/")
+rewrite:
+	for i := 0; i < len(segments); i++ {
+		if keyword, ok := d.Aliases[unescapeJSONPointer(segments[i])]; ok {
+			segments[i] = escapeJSONPointer(keyword)
+		}
+		switch subschemaKeywords[unescapeJSONPointer(segments[i])] {
+		case subschemaSingle:
+		case subschemaList, subschemaMap:
+			i++ // Skip the index or name of the subschema.
+		default:
+			break rewrite // The rest of the pointer is not in a schema.
+		}
+	}
+	return base + "#/" + strings.Join(segments, "/")
+}
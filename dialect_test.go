@@ -0,0 +1,126 @@
+package jsonschema
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const vettedDialect = "https://example.com/dialects/vetted"
+
+func newVettedCompiler() *Compiler {
+	return NewCompiler().RegisterDialect(Dialect{
+		URI:          vettedDialect,
+		Aliases:      map[string]string{"definitions": "$defs"},
+		Removed:      []string{"$defs", "pattern", "$dynamicRef"},
+		LocalRefs:    true,
+		AssertFormat: true,
+	})
+}
+
+func TestDialectAliases(t *testing.T) {
+	compiler := newVettedCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"$schema": "https://example.com/dialects/vetted",
+		"definitions": {
+			"positive": {"type": "integer", "minimum": 1},
+			"list": {"type": "array", "items": {"$ref": "#/definitions/positive"}}
+		},
+		"properties": {
+			"count": {"$ref": "#/definitions/positive"},
+			"counts": {"$ref": "#/definitions/list"},
+			"definitions": {"type": "string"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	assert.True(t, schema.Validate(map[string]interface{}{"count": 2, "counts": []interface{}{1, 2}, "definitions": "x"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"count": 0}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"counts": []interface{}{1, -1}}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"definitions": 1}).IsValid(), "property names are not aliased")
+}
+
+func TestDialectRemovedKeywords(t *testing.T) {
+	compiler := newVettedCompiler()
+
+	_, err := compiler.Compile([]byte(`{
+		"$schema": "https://example.com/dialects/vetted",
+		"properties": {"name": {"type": "string", "pattern": "^a"}}
+	}`))
+	require.ErrorIs(t, err, ErrKeywordNotAllowed)
+	assert.Contains(t, err.Error(), "/properties/name")
+
+	_, err = compiler.Compile([]byte(`{"$schema": "https://example.com/dialects/vetted", "$defs": {}}`))
+	require.ErrorIs(t, err, ErrKeywordNotAllowed, "only the alias of $defs is allowed")
+
+	_, err = compiler.Compile([]byte(`{"$schema": "https://example.com/dialects/vetted#", "definitions": {}, "$defs": {}}`))
+	require.Error(t, err)
+
+	_, err = compiler.Compile([]byte(`{"properties": {"name": {"pattern": "^a"}}}`))
+	require.NoError(t, err, "schemas of other dialects are not affected")
+
+	schema, err := compiler.Compile([]byte(`{
+		"$schema": "https://example.com/dialects/vetted",
+		"properties": {
+			"code": {
+				"$id": "https://example.com/code.json",
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"pattern": "^[A-Z]+$"
+			}
+		}
+	}`))
+	require.NoError(t, err, "embedded resources may declare another dialect")
+	assert.False(t, schema.Validate(map[string]interface{}{"code": "abc"}).IsValid())
+}
+
+func TestDialectAliasConflict(t *testing.T) {
+	compiler := NewCompiler().RegisterDialect(Dialect{
+		URI:     vettedDialect,
+		Aliases: map[string]string{"definitions": "$defs"},
+	})
+	_, err := compiler.Compile([]byte(`{"$schema": "https://example.com/dialects/vetted", "definitions": {}, "$defs": {}}`))
+	require.ErrorIs(t, err, ErrAliasedKeywordConflict)
+}
+
+func TestDialectLocalRefs(t *testing.T) {
+	compiler := newVettedCompiler().SetUnresolvedRefPolicy(UnresolvedRefInvalid)
+	fetched := 0
+	compiler.RegisterLoader("https", func(url string) (io.ReadCloser, error) {
+		fetched++
+		return io.NopCloser(strings.NewReader(`{"type": "string"}`)), nil
+	})
+
+	_, err := compiler.Compile([]byte(`{"$id": "https://example.com/known.json", "type": "integer"}`))
+	require.NoError(t, err)
+	schema, err := compiler.Compile([]byte(`{
+		"$schema": "https://example.com/dialects/vetted",
+		"properties": {
+			"known": {"$ref": "https://example.com/known.json"},
+			"remote": {"$ref": "https://example.com/remote.json"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, fetched)
+	assert.True(t, schema.Validate(map[string]interface{}{"known": 1}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"known": "1"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"remote": "x"}).IsValid(), "remote documents are not fetched")
+}
+
+func TestDefaultDialect(t *testing.T) {
+	compiler := newVettedCompiler().SetDefaultDialect(vettedDialect)
+
+	schema, err := compiler.Compile([]byte(`{
+		"definitions": {"email": {"type": "string", "format": "email"}},
+		"$ref": "#/definitions/email"
+	}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("user@example.com").IsValid())
+	assert.False(t, schema.Validate("not an email").IsValid(), "the dialect asserts formats")
+
+	_, err = compiler.Compile([]byte(`{"pattern": "^a"}`))
+	require.ErrorIs(t, err, ErrKeywordNotAllowed)
+}
@@ -0,0 +1,414 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// Draft is a published version of JSON Schema, selected by the $schema of a schema. Schemas of
+// drafts before 2020-12 are compiled to their 2020-12 equivalents: keywords the draft does not
+// define are ignored, and keywords it defines differently, such as the boolean exclusiveMinimum
+// of draft-04, items arrays before 2020-12 or definitions before 2019-09, are rewritten.
+type Draft int
+
+// Supported drafts.
+const (
+	Draft4    Draft = 4
+	Draft6    Draft = 6
+	Draft7    Draft = 7
+	Draft2019 Draft = 2019
+	Draft2020 Draft = 2020
+)
+
+// draftMetaSchemas maps the URIs of the meta-schemas of the drafts, without scheme nor fragment,
+// to their draft.
+var draftMetaSchemas = map[string]Draft{
+	"json-schema.org/draft-04/schema":      Draft4,
+	"json-schema.org/draft-06/schema":      Draft6,
+	"json-schema.org/draft-07/schema":      Draft7,
+	"json-schema.org/draft/2019-09/schema": Draft2019,
+	"json-schema.org/draft/2020-12/schema": Draft2020,
+}
+
+// String returns the name of the draft, such as draft-07 or 2020-12.
+func (d Draft) String() string {
+	switch d {
+	case Draft4, Draft6, Draft7:
+		return "draft-0" + strconv.Itoa(int(d))
+	case Draft2019:
+		return "2019-09"
+	case Draft2020:
+		return "2020-12"
+	}
+	return "Draft(" + strconv.Itoa(int(d)) + ")"
+}
+
+// draftOf returns the draft whose meta-schema is at uri.
+func draftOf(uri string) (Draft, bool) {
+	uri = strings.TrimSuffix(uri, "#")
+	uri = strings.TrimPrefix(strings.TrimPrefix(uri, "https://"), "http://")
+	draft, ok := draftMetaSchemas[uri]
+	return draft, ok
+}
+
+// SetDefaultDraft sets the draft of the schemas that have no $schema, 2020-12 by default.
+func (c *Compiler) SetDefaultDraft(draft Draft) *Compiler {
+	c.DefaultDraft = draft
+	return c
+}
+
+// draftKeywords lists the keywords of 2020-12 introduced by each later draft, which the schemas of
+// earlier drafts ignore.
+var draftKeywords = []struct {
+	since    Draft
+	keywords []string
+}{
+	{Draft6, []string{"$id", "const", "contains", "propertyNames", "examples"}},
+	{Draft7, []string{"if", "then", "else", "$comment", "readOnly", "writeOnly", "contentEncoding", "contentMediaType"}},
+	{Draft2019, []string{
+		"$anchor", "$defs", "$vocabulary", "deprecated", "dependentRequired", "dependentSchemas",
+		"unevaluatedItems", "unevaluatedProperties", "minContains", "maxContains", "contentSchema",
+	}},
+	{Draft2020, []string{"$dynamicAnchor", "$dynamicRef", "prefixItems"}},
+}
+
+// recursiveAnchor is the name of the dynamic anchor standing for $recursiveAnchor of 2019-09.
+const recursiveAnchor = "recursiveAnchor"
+
+// applyDrafts rewrites the schemas of a document written in drafts before 2020-12 to 2020-12,
+// adding the keywords it ignores and the deprecated drafts it declares to warnings.
+func (c *Compiler) applyDrafts(jsonSchema []byte, warnings *compileWarnings) ([]byte, error) {
+	if c.DefaultDraft == 0 || c.DefaultDraft == Draft2020 {
+		if !bytes.Contains(jsonSchema, []byte("json-schema.org/draft-0")) && !bytes.Contains(jsonSchema, []byte("json-schema.org/draft/2019-09")) {
+			return jsonSchema, nil
+		}
+	}
+	var document interface{}
+	if err := decodeExactJSON(jsonSchema, &document); err != nil {
+		return nil, err
+	}
+	if _, ok := document.(map[string]interface{}); !ok {
+		return jsonSchema, nil
+	}
+
+	draft := c.DefaultDraft
+	if draft == 0 {
+		draft = Draft2020
+	}
+	if !c.rewriteDraft(document, draft, false, "", warnings) {
+		return jsonSchema, nil
+	}
+	return json.Marshal(document)
+}
+
+// MigrateDraft rewrites the schema document jsonSchema, written in the draft its $schema declares
+// or in the default draft, to its 2020-12 equivalent, as documents are rewritten before they are
+// compiled, and points the $schema of its schemas to the meta-schema of 2020-12. The keywords the
+// draft ignores are dropped and returned as ignored_keyword warnings. Object keys are sorted in
+// the result.
+func (c *Compiler) MigrateDraft(jsonSchema []byte) ([]byte, []CompileWarning, error) {
+	var document interface{}
+	if err := decodeExactJSON(jsonSchema, &document); err != nil {
+		return nil, nil, err
+	}
+	root, ok := document.(map[string]interface{})
+	if !ok {
+		return jsonSchema, nil, nil
+	}
+
+	draft := c.DefaultDraft
+	if draft == 0 {
+		draft = Draft2020
+	}
+	warnings := compileWarnings{}
+	c.rewriteDraft(root, draft, false, "", &warnings)
+	c.migrateSchemaURIs(root)
+	if _, ok := root["$schema"]; !ok && draft != Draft2020 {
+		root["$schema"] = defaultDialect
+	}
+
+	migrated, err := json.Marshal(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	ignored := make([]CompileWarning, 0, len(warnings))
+	for _, warning := range warnings {
+		if warning.Code == "ignored_keyword" {
+			ignored = append(ignored, warning)
+		}
+	}
+	return migrated, ignored, nil
+}
+
+// migrateSchemaURIs points the $schema of the schemas of value and its subschemas that declare a
+// draft before 2020-12 to the meta-schema of 2020-12.
+func (c *Compiler) migrateSchemaURIs(value interface{}) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if uri, ok := object["$schema"].(string); ok {
+		if draft, ok := draftOf(uri); ok && draft != Draft2020 && c.dialect(uri) == nil {
+			object["$schema"] = defaultDialect
+		}
+	}
+	for keyword, value := range object {
+		switch subschemaKeywords[keyword] {
+		case subschemaSingle:
+			c.migrateSchemaURIs(value)
+		case subschemaList:
+			for _, item := range toSlice(value) {
+				c.migrateSchemaURIs(item)
+			}
+		case subschemaMap:
+			if values, ok := value.(map[string]interface{}); ok {
+				for _, item := range values {
+					c.migrateSchemaURIs(item)
+				}
+			}
+		}
+	}
+}
+
+// rewriteDraft rewrites the keywords of the schema value at pointer written in draft, and of its
+// subschemas, and reports whether anything changed. recursive reports whether the resource of the
+// schema has a $recursiveAnchor, for 2019-09.
+func (c *Compiler) rewriteDraft(value interface{}, draft Draft, recursive bool, pointer string, warnings *compileWarnings) bool {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if uri, ok := object["$schema"].(string); ok {
+		if draft, ok = draftOf(uri); !ok || c.dialect(uri) != nil {
+			draft = Draft2020 // Custom dialects and meta-schemas extend 2020-12.
+		} else if draft != Draft2020 {
+			warnings.add(pointer+"/$schema", "deprecated_draft", fmt.Sprintf("Draft %s is deprecated; the schema is compiled as 2020-12", draft))
+		}
+	}
+
+	changed := false
+	if draft != Draft2020 {
+		changed = rewriteDraftKeywords(object, draft, func(keyword string) {
+			warnings.add(pointer+"/"+escapeJSONPointer(keyword), "ignored_keyword", fmt.Sprintf("Keyword %s is ignored by draft %s", keyword, draft))
+		})
+		if draft == Draft2019 {
+			if _, ok := object["$id"]; ok {
+				recursive = false
+			}
+			if anchor, ok := object["$recursiveAnchor"].(bool); ok {
+				delete(object, "$recursiveAnchor")
+				if anchor {
+					object["$dynamicAnchor"] = recursiveAnchor
+					recursive = true
+				}
+				changed = true
+			}
+			if ref, ok := object["$recursiveRef"].(string); ok {
+				delete(object, "$recursiveRef")
+				if recursive && ref == "#" {
+					object["$dynamicRef"] = "#" + recursiveAnchor
+				} else if _, ok := object["$ref"]; !ok {
+					object["$ref"] = ref
+				} else {
+					object["allOf"] = append([]interface{}{map[string]interface{}{"$ref": ref}}, toSlice(object["allOf"])...)
+				}
+				changed = true
+			}
+		}
+	}
+
+	for keyword, value := range object {
+		location := pointer + "/" + escapeJSONPointer(keyword)
+		switch subschemaKeywords[keyword] {
+		case subschemaSingle:
+			changed = c.rewriteDraft(value, draft, recursive, location, warnings) || changed
+		case subschemaList:
+			if list, ok := value.([]interface{}); ok {
+				for i, item := range list {
+					changed = c.rewriteDraft(item, draft, recursive, location+"/"+strconv.Itoa(i), warnings) || changed
+				}
+			}
+		case subschemaMap:
+			if values, ok := value.(map[string]interface{}); ok {
+				for key, item := range values {
+					changed = c.rewriteDraft(item, draft, recursive, location+"/"+escapeJSONPointer(key), warnings) || changed
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// rewriteDraftKeywords rewrites the keywords of a schema written in draft, before 2020-12, to
+// those of 2020-12, calls ignore with the assertions and applicators the draft ignores, and
+// reports whether anything changed.
+func rewriteDraftKeywords(object map[string]interface{}, draft Draft, ignore func(keyword string)) bool {
+	changed := false
+	rename := func(from, to string) {
+		if value, ok := object[from]; ok {
+			delete(object, from)
+			object[to] = value
+			changed = true
+		}
+	}
+
+	for _, keywords := range draftKeywords {
+		if draft < keywords.since {
+			for _, keyword := range keywords.keywords {
+				if _, ok := object[keyword]; ok {
+					delete(object, keyword)
+					if !slices.Contains(metadataKeywords, keyword) {
+						ignore(keyword)
+					}
+					changed = true
+				}
+			}
+		}
+	}
+
+	if draft <= Draft7 {
+		// Before 2019-09, the siblings of $ref are ignored.
+		if _, ok := object["$ref"]; ok {
+			for keyword := range object {
+				if keyword != "$ref" && keyword != "$schema" && keyword != "definitions" {
+					delete(object, keyword)
+					if !slices.Contains(metadataKeywords, keyword) {
+						ignore(keyword)
+					}
+					changed = true
+				}
+			}
+		}
+
+		idKeyword := "$id"
+		if draft == Draft4 {
+			idKeyword = "id"
+		}
+		if id, ok := object[idKeyword].(string); ok {
+			delete(object, idKeyword)
+			base, anchor, _ := strings.Cut(id, "#")
+			if base != "" {
+				object["$id"] = base
+			}
+			if anchor != "" {
+				object["$anchor"] = anchor
+			}
+			changed = true
+		}
+
+		rename("definitions", "$defs")
+		if ref, ok := object["$ref"].(string); ok {
+			if rewritten := draftRef(ref, draft); rewritten != ref {
+				object["$ref"] = rewritten
+				changed = true
+			}
+		}
+
+		if dependencies, ok := object["dependencies"].(map[string]interface{}); ok {
+			delete(object, "dependencies")
+			required := map[string]interface{}{}
+			schemas := map[string]interface{}{}
+			for name, dependency := range dependencies {
+				if _, ok := dependency.([]interface{}); ok {
+					required[name] = dependency
+				} else {
+					schemas[name] = dependency
+				}
+			}
+			if len(required) > 0 {
+				object["dependentRequired"] = required
+			}
+			if len(schemas) > 0 {
+				object["dependentSchemas"] = schemas
+			}
+			changed = true
+		}
+	}
+
+	if draft == Draft4 {
+		for bound, exclusive := range map[string]string{"minimum": "exclusiveMinimum", "maximum": "exclusiveMaximum"} {
+			if isExclusive, ok := object[exclusive].(bool); ok {
+				delete(object, exclusive)
+				if limit, ok := object[bound]; ok && isExclusive {
+					delete(object, bound)
+					object[exclusive] = limit
+				}
+				changed = true
+			}
+		}
+	}
+
+	if draft == Draft2019 {
+		if ref, ok := object["$ref"].(string); ok {
+			if rewritten := draftRef(ref, draft); rewritten != ref {
+				object["$ref"] = rewritten
+				changed = true
+			}
+		}
+	}
+
+	if items, ok := object["items"].([]interface{}); ok {
+		delete(object, "items")
+		object["prefixItems"] = items
+		rename("additionalItems", "items")
+		changed = true
+	} else if _, ok := object["additionalItems"]; ok {
+		delete(object, "additionalItems") // additionalItems applies only along items arrays.
+		ignore("additionalItems")
+		changed = true
+	}
+	return changed
+}
+
+// draftRef rewrites the JSON Pointer in the fragment of a reference written in draft, before
+// 2020-12, to the keywords of 2020-12, such as #/definitions/name to #/$defs/name.
+func draftRef(ref string, draft Draft) string {
+	base, fragment, ok := strings.Cut(ref, "#")
+	if !ok || !strings.HasPrefix(fragment, "/") {
+		return ref
+	}
+
+	segments := strings.Split(fragment[1:], "/")
+	for i := 0; i < len(segments); i++ {
+		next := ""
+		if i+1 < len(segments) {
+			next = segments[i+1]
+		}
+		switch segments[i] {
+		case "definitions":
+			if draft <= Draft7 {
+				segments[i] = "$defs"
+			}
+		case "dependencies":
+			if draft <= Draft7 {
+				segments[i] = "dependentSchemas"
+			}
+		case "additionalItems":
+			segments[i] = "items"
+			continue
+		case "items":
+			if _, err := strconv.Atoi(next); err == nil {
+				segments[i] = "prefixItems"
+			}
+		}
+		switch subschemaKeywords[unescapeJSONPointer(segments[i])] {
+		case subschemaSingle:
+		case subschemaList, subschemaMap:
+			i++ // Skip the index or name of the subschema.
+		default:
+			return base + "#/" + strings.Join(segments, "/") // The rest of the pointer is not in a schema.
+		}
+	}
+	return base + "#/" + strings.Join(segments, "/")
+}
+
+// toSlice returns value if it is a list, or nil.
+func toSlice(value interface{}) []interface{} {
+	list, _ := value.([]interface{})
+	return list
+}
@@ -0,0 +1,127 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraft4(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"id": "https://example.com/order.json",
+		"definitions": {"quantity": {"type": "integer", "minimum": 0, "exclusiveMinimum": true}},
+		"properties": {
+			"quantity": {"$ref": "#/definitions/quantity", "maximum": 1},
+			"price": {"minimum": 0, "exclusiveMinimum": false},
+			"kind": {"const": "book"}
+		},
+		"dependencies": {"price": ["quantity"], "discount": {"required": ["price"]}}
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://example.com/order.json", schema.ID)
+	assert.True(t, schema.Validate(map[string]interface{}{"quantity": 5}).IsValid(), "the siblings of $ref are ignored")
+	assert.False(t, schema.Validate(map[string]interface{}{"quantity": 0}).IsValid(), "the minimum is exclusive")
+	assert.True(t, schema.Validate(map[string]interface{}{"quantity": 1, "price": 0}).IsValid())
+	assert.True(t, schema.Validate(map[string]interface{}{"kind": "pen"}).IsValid(), "const is not a keyword of draft-04")
+	assert.False(t, schema.Validate(map[string]interface{}{"price": 1}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"discount": 1, "quantity": 1}).IsValid())
+}
+
+func TestDraft7Items(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"items": [{"type": "string"}, {"$ref": "#/items/0"}],
+		"additionalItems": {"type": "integer"},
+		"if": {"minItems": 3},
+		"then": {"maxItems": 4}
+	}`))
+	require.NoError(t, err)
+
+	assert.True(t, schema.Validate([]interface{}{"a", "b", 1}).IsValid())
+	assert.False(t, schema.Validate([]interface{}{"a", 1}).IsValid())
+	assert.False(t, schema.Validate([]interface{}{"a", "b", "c"}).IsValid())
+	assert.False(t, schema.Validate([]interface{}{"a", "b", 1, 2, 3}).IsValid())
+}
+
+func TestDraft2019RecursiveRef(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$id": "https://example.com/tree.json",
+		"$recursiveAnchor": true,
+		"type": "object",
+		"properties": {"children": {"type": "array", "items": {"$recursiveRef": "#"}}}
+	}`))
+	require.NoError(t, err)
+	schema, err := compiler.Compile([]byte(`{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$id": "https://example.com/strict-tree.json",
+		"$recursiveAnchor": true,
+		"$ref": "tree.json",
+		"unevaluatedProperties": false
+	}`))
+	require.NoError(t, err)
+
+	assert.True(t, schema.Validate(map[string]interface{}{"children": []interface{}{map[string]interface{}{}}}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"children": []interface{}{map[string]interface{}{"extra": 1}}}).IsValid())
+}
+
+func TestDefaultDraft(t *testing.T) {
+	compiler := NewCompiler().SetDefaultDraft(Draft4)
+	schema, err := compiler.Compile([]byte(`{"maximum": 10, "exclusiveMaximum": true}`))
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(10).IsValid())
+
+	schema, err = compiler.Compile([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "maximum": 10, "exclusiveMaximum": 10}`))
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(10).IsValid())
+
+	schema, err = NewCompiler().Compile([]byte(`{"$defs": {"a": {"const": 1}}, "$ref": "#/$defs/a", "minimum": 2}`))
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(1).IsValid(), "2020-12 applies the siblings of $ref")
+}
+
+func TestDraftString(t *testing.T) {
+	assert.Equal(t, "draft-04", Draft4.String())
+	assert.Equal(t, "2019-09", Draft2019.String())
+	assert.Equal(t, "2020-12", Draft2020.String())
+}
+
+func TestMigrateDraft(t *testing.T) {
+	migrated, warnings, err := NewCompiler().MigrateDraft([]byte(`{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"id": "https://example.com/order.json",
+		"definitions": {"quantity": {"type": "integer", "minimum": 0, "exclusiveMinimum": true}},
+		"properties": {
+			"quantity": {"$ref": "#/definitions/quantity", "maximum": 1},
+			"kind": {"const": "book"}
+		}
+	}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/order.json",
+		"$defs": {"quantity": {"type": "integer", "exclusiveMinimum": 0}},
+		"properties": {"quantity": {"$ref": "#/$defs/quantity"}, "kind": {}}
+	}`, string(migrated))
+	require.Len(t, warnings, 2)
+	assert.ElementsMatch(t, []string{"#/properties/quantity/maximum", "#/properties/kind/const"},
+		[]string{warnings[0].KeywordLocation, warnings[1].KeywordLocation})
+
+	schema, err := NewCompiler().Compile(migrated)
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(map[string]interface{}{"quantity": 0}).IsValid())
+
+	migrated, warnings, err = NewCompiler().SetDefaultDraft(Draft7).MigrateDraft([]byte(`{"items": [{"type": "string"}], "additionalItems": false}`))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.JSONEq(t, `{"$schema": "https://json-schema.org/draft/2020-12/schema", "prefixItems": [{"type": "string"}], "items": false}`, string(migrated))
+
+	current := []byte(`{"type": "string"}`)
+	migrated, _, err = NewCompiler().MigrateDraft(current)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(current), string(migrated))
+}
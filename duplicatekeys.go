@@ -0,0 +1,162 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// DuplicateKeyPolicy selects how properties given more than once in an object of a raw JSON
+// instance are handled. Parsers disagree on
+// which occurrence wins, so duplicate keys let a document mean one thing to the validator and
+// another to the service it protects.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysLastWins validates the last occurrence of a property, the one decoders such
+	// as encoding/json keep.
+	DuplicateKeysLastWins DuplicateKeyPolicy = iota
+	// DuplicateKeysFirstWins validates the first occurrence of a property and ignores the others.
+	DuplicateKeysFirstWins
+	// DuplicateKeysError rejects the instance with a DuplicateKeyError.
+	DuplicateKeysError
+)
+
+// SetDuplicateKeyPolicy sets how properties given more than once in raw JSON instances are
+// handled by ValidateJSON, ValidateReader, ValidateDecoder, TokenValidator, Decode, ValidateAs and
+// UnmarshalWithDefaults. The default is DuplicateKeysLastWins. A parser set with SetJSONParser
+// handles duplicate keys itself.
+func (c *Compiler) SetDuplicateKeyPolicy(policy DuplicateKeyPolicy) *Compiler {
+	c.DuplicateKeys = policy
+	return c
+}
+
+// DuplicateKeyError reports a property given more than once in an object of a raw JSON instance,
+// with DuplicateKeysError.
+type DuplicateKeyError struct {
+	Location string   // JSON Pointer of the property in the instance.
+	Position Position // Position of the key of the second occurrence, set by ValidateJSON only.
+}
+
+// Error returns the location of the duplicate key, with its position when known.
+func (e *DuplicateKeyError) Error() string {
+	if e.Position.Line > 0 {
+		return fmt.Sprintf("%s at '%s', line %d, column %d", ErrDuplicateKey, e.Location, e.Position.Line, e.Position.Column)
+	}
+	return fmt.Sprintf("%s at '%s'", ErrDuplicateKey, e.Location)
+}
+
+// Unwrap returns ErrDuplicateKey.
+func (e *DuplicateKeyError) Unwrap() error {
+	return ErrDuplicateKey
+}
+
+// duplicateKeyPolicy returns the policy of the compiler of s for duplicate keys.
+func (s *Schema) duplicateKeyPolicy() DuplicateKeyPolicy {
+	if s.compiler == nil {
+		return DuplicateKeysLastWins
+	}
+	return s.compiler.DuplicateKeys
+}
+
+// locateDuplicateKey sets the position of the key of the second occurrence of the property of err
+// in the raw JSON document data.
+func locateDuplicateKey(data []byte, err *DuplicateKeyError) {
+	index := strings.LastIndex(err.Location, "/")
+	objectOffset, _, locateErr := locateJSON(data, err.Location[:index])
+	if locateErr != nil {
+		return
+	}
+	name := unescapeJSONPointer(err.Location[index+1:])
+
+	scanner := &jsonScanner{data: data, pos: objectOffset}
+	if scanner.expect('{') != nil {
+		return
+	}
+	seen := false
+	for scanner.peek() == '"' {
+		start := scanner.pos
+		key, keyErr := scanner.readString()
+		if keyErr != nil {
+			return
+		}
+		if key == name {
+			if seen {
+				err.Position = positionAt(data, start)
+				return
+			}
+			seen = true
+		}
+		if scanner.expect(':') != nil || scanner.skipValue() != nil {
+			return
+		}
+		scanner.skipSpace()
+		if scanner.peek() != ',' || scanner.expect(',') != nil {
+			return
+		}
+	}
+}
+
+// parseJSONKeys parses a JSON document like parseJSON, handling properties given more than once
+// with policy.
+func parseJSONKeys(data []byte, policy DuplicateKeyPolicy) (interface{}, error) {
+	if policy == DuplicateKeysLastWins || !json.Valid(data) {
+		return parseJSON(data)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decodeKeys(decoder, policy, "")
+}
+
+// decodeKeys decodes the next value read from decoder, at location in the instance, handling
+// properties given more than once with policy. The syntax of the value must have been checked,
+// since the tokens of a json.Decoder do not check separators.
+func decodeKeys(decoder *json.Decoder, policy DuplicateKeyPolicy, location string) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		object := map[string]interface{}{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: key %v", ErrUnexpectedToken, key)
+			}
+			member := location + "/" + escapeJSONPointer(name)
+			_, duplicate := object[name]
+			if duplicate && policy == DuplicateKeysError {
+				return nil, &DuplicateKeyError{Location: member}
+			}
+			value, err := decodeKeys(decoder, policy, member)
+			if err != nil {
+				return nil, err
+			}
+			if !duplicate || policy != DuplicateKeysFirstWins {
+				object[name] = value
+			}
+		}
+		_, err = decoder.Token() // }
+		return object, err
+	case json.Delim('['):
+		array := []interface{}{}
+		for index := 0; decoder.More(); index++ {
+			item, err := decodeKeys(decoder, policy, location+"/"+strconv.Itoa(index))
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, item)
+		}
+		_, err = decoder.Token() // ]
+		return array, err
+	}
+	return token, nil
+}
@@ -0,0 +1,110 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const duplicateKeysSchema = `{
+	"type": "object",
+	"properties": {
+		"role": {"enum": ["user"]},
+		"profile": {"properties": {"admin": {"const": false}}}
+	}
+}`
+
+func TestDuplicateKeys(t *testing.T) {
+	instance := []byte(`{"role": "user", "profile": {"admin": true, "admin": false}, "role": "admin"}`)
+
+	schema, err := NewCompiler().Compile([]byte(duplicateKeysSchema))
+	require.NoError(t, err)
+	result, err := schema.ValidateJSON(instance)
+	require.NoError(t, err)
+	assert.False(t, result.IsValid(), "the last role wins")
+	var invalid []string
+	for _, detail := range result.ToList().Details {
+		if !detail.Valid {
+			invalid = append(invalid, detail.InstanceLocation)
+		}
+	}
+	assert.Equal(t, []string{"/role"}, invalid)
+
+	schema, err = NewCompiler().SetDuplicateKeyPolicy(DuplicateKeysFirstWins).Compile([]byte(duplicateKeysSchema))
+	require.NoError(t, err)
+	result, err = schema.ValidateJSON(instance)
+	require.NoError(t, err)
+	assert.False(t, result.IsValid(), "the first admin wins")
+	result, err = schema.ValidateJSON([]byte(`{"role": "user", "role": "admin", "profile": {"admin": false, "admin": {"nested": [1]}}}`))
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+}
+
+func TestDuplicateKeysError(t *testing.T) {
+	schema, err := NewCompiler().SetDuplicateKeyPolicy(DuplicateKeysError).Compile([]byte(duplicateKeysSchema))
+	require.NoError(t, err)
+
+	_, err = schema.ValidateJSON([]byte("{\n  \"profile\": {\"admin\": false,\n    \"admin\": true}\n}"))
+	require.ErrorIs(t, err, ErrDuplicateKey)
+	var duplicate *DuplicateKeyError
+	require.True(t, errors.As(err, &duplicate))
+	assert.Equal(t, "/profile/admin", duplicate.Location)
+	assert.Equal(t, Position{Offset: 36, Line: 3, Column: 5}, duplicate.Position)
+	assert.Equal(t, "duplicate key in JSON instance at '/profile/admin', line 3, column 5", err.Error())
+
+	_, err = schema.ValidateJSON([]byte(`{"a~b": 1, "a~b": 2}`))
+	require.True(t, errors.As(err, &duplicate))
+	assert.Equal(t, "/a~0b", duplicate.Location)
+	assert.Equal(t, 11, duplicate.Position.Offset)
+
+	result, err := schema.ValidateJSON([]byte(`{"role": "user", "profile": {"admin": false}}`))
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+}
+
+func TestDuplicateKeysDecode(t *testing.T) {
+	type account struct {
+		Role string `json:"role"`
+	}
+	instance := []byte(`{"role": "user", "role": "admin"}`)
+
+	schema, err := NewCompiler().Compile([]byte(duplicateKeysSchema))
+	require.NoError(t, err)
+	_, err = Decode[account](schema, instance)
+	var result *EvaluationResult
+	require.True(t, errors.As(err, &result), "the last role wins")
+
+	schema, err = NewCompiler().SetDuplicateKeyPolicy(DuplicateKeysFirstWins).Compile([]byte(duplicateKeysSchema))
+	require.NoError(t, err)
+	decoded, err := Decode[account](schema, instance)
+	require.NoError(t, err)
+	assert.Equal(t, account{Role: "user"}, decoded, "the validated role is decoded")
+
+	schema, err = NewCompiler().SetDuplicateKeyPolicy(DuplicateKeysError).Compile([]byte(duplicateKeysSchema))
+	require.NoError(t, err)
+	_, err = Decode[account](schema, []byte(`{"profile": {"admin": false, "admin": true}}`))
+	var duplicate *DuplicateKeyError
+	require.True(t, errors.As(err, &duplicate))
+	assert.Equal(t, "/profile/admin", duplicate.Location)
+	var target account
+	err = schema.UnmarshalWithDefaults([]byte(`[{}, {"a": 1, "a": 2}]`), &target)
+	require.True(t, errors.As(err, &duplicate))
+	assert.Equal(t, "/1/a", duplicate.Location)
+}
+
+func TestDuplicateKeysSuppressions(t *testing.T) {
+	compiler := NewCompiler().SetDuplicateKeyPolicy(DuplicateKeysError).
+		RegisterSuppression(SuppressionRule{InstancePath: "/role"})
+	schema, err := compiler.Compile([]byte(duplicateKeysSchema))
+	require.NoError(t, err)
+
+	_, err = schema.ValidateReader(strings.NewReader(`{"role": "user", "role": "admin"}`))
+	require.ErrorIs(t, err, ErrDuplicateKey)
+	_, err = schema.ValidateDecoder(json.NewDecoder(strings.NewReader(`{"role": "user", "role": "admin"}`)))
+	require.ErrorIs(t, err, ErrDuplicateKey)
+}
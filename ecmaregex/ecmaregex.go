@@ -0,0 +1,68 @@
+// Package ecmaregex provides a jsonschema.RegexEngine for the ECMA-262 regular expressions that
+// the JSON Schema specification uses, backed by github.com/dlclark/regexp2, so that patterns
+// written for JavaScript validators, with lookarounds, backreferences or \u{...} escapes, compile
+// and match as they do there:
+//
+//	compiler := jsonschema.NewCompiler().SetRegexEngine(ecmaregex.Engine{MatchTimeout: 50 * time.Millisecond})
+package ecmaregex
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dlclark/regexp2"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// DefaultMatchTimeout bounds the time of each match of an Engine without a MatchTimeout.
+const DefaultMatchTimeout = 100 * time.Millisecond
+
+// Engine compiles regular expressions with the ECMAScript and Unicode options of regexp2, the
+// equivalent of the u flag of JavaScript.
+type Engine struct {
+	// MatchTimeout bounds the time of each match, since backtracking makes some patterns take
+	// exponential time on crafted strings; a match that times out fails. DefaultMatchTimeout when
+	// 0; no bound when negative, for patterns and instances that are all trusted.
+	MatchTimeout time.Duration
+}
+
+var _ jsonschema.RegexEngine = Engine{}
+
+// Compile compiles pattern.
+func (e Engine) Compile(pattern string) (jsonschema.Regexp, error) {
+	re, err := regexp2.Compile(pattern, regexp2.ECMAScript|regexp2.Unicode)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case e.MatchTimeout > 0:
+		re.MatchTimeout = e.MatchTimeout
+	case e.MatchTimeout == 0:
+		re.MatchTimeout = DefaultMatchTimeout
+	}
+	return regex{re}, nil
+}
+
+// regex adapts a regexp2.Regexp to jsonschema.Regexp.
+type regex struct {
+	re *regexp2.Regexp
+}
+
+// MatchString reports whether s contains a match of the regular expression, false when the match
+// times out.
+func (r regex) MatchString(s string) bool {
+	matched, err := r.re.MatchString(s)
+	return err == nil && matched
+}
+
+// SubexpNames returns the names of the capture groups, with "" for the unnamed ones.
+func (r regex) SubexpNames() []string {
+	names := r.re.GetGroupNames()
+	for i, name := range names {
+		if _, err := strconv.Atoi(name); err == nil {
+			names[i] = ""
+		}
+	}
+	return names
+}
@@ -0,0 +1,81 @@
+package ecmaregex
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+func TestEngine(t *testing.T) {
+	compiler := jsonschema.NewCompiler().SetRegexEngine(Engine{}).SetAssertFormat(true)
+	schema, err := compiler.Compile([]byte(`{
+		"properties": {
+			"password": {"pattern": "^(?=.*\\d)(?=.*[a-z]).{8,}$"},
+			"pair": {"pattern": "^(\\w)\\1$"},
+			"emoji": {"pattern": "^\\u{1F600}$"},
+			"expression": {"format": "regex"}
+		},
+		"patternProperties": {"^(?<year>\\d{4})(?!-draft)": {"type": "integer"}}
+	}`))
+	require.NoError(t, err)
+
+	assert.True(t, schema.Validate(map[string]interface{}{
+		"password":   "secret123",
+		"pair":       "aa",
+		"emoji":      "😀",
+		"expression": "(?<=a)b",
+		"2024":       1,
+		"2024-draft": "pending",
+	}).IsValid())
+
+	result := schema.Validate(map[string]interface{}{
+		"password":   "secretsecret",
+		"pair":       "ab",
+		"expression": "(",
+		"2024":       "one",
+	})
+	assert.False(t, result.IsValid())
+	list := result.ToList()
+	assert.ElementsMatch(t, []string{
+		"/properties/password", "/properties/pair", "/properties/expression", "/patternProperties/^(?<year>\\d{4})(?!-draft)",
+	}, errorLocations(list))
+	assert.Equal(t, "'year'", result.Errors["properties"].Params["pattern"], "named groups label patterns")
+}
+
+func TestEngineCompileError(t *testing.T) {
+	_, err := Engine{}.Compile("[")
+	assert.Error(t, err)
+	re, err := Engine{}.Compile(`(a)(?<name>b)`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "", "name"}, re.SubexpNames())
+}
+
+// errorLocations returns the evaluation paths of the failed units of list.
+func errorLocations(list *jsonschema.List) []string {
+	var paths []string
+	for _, detail := range list.Details {
+		if !detail.Valid {
+			paths = append(paths, detail.EvaluationPath)
+		}
+	}
+	return paths
+}
+
+func TestEngineDefaultMatchTimeout(t *testing.T) {
+	re, err := Engine{}.Compile(`^(a+)+$`)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMatchTimeout, re.(regex).re.MatchTimeout)
+	start := time.Now()
+	assert.False(t, re.MatchString(strings.Repeat("a", 40)+"!"), "a match that times out fails")
+	assert.Less(t, time.Since(start), 10*DefaultMatchTimeout)
+
+	re, err = Engine{MatchTimeout: -1}.Compile(`^a$`)
+	require.NoError(t, err)
+	assert.True(t, re.MatchString("a"))
+	assert.NotEqual(t, DefaultMatchTimeout, re.(regex).re.MatchTimeout, "negative timeouts disable the bound")
+}
@@ -0,0 +1,146 @@
+package jsonschema
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EmailMode selects how strictly the email format checks addresses.
+type EmailMode int
+
+const (
+	// EmailRFC5321 accepts the ASCII addresses of RFC 5321 and the addr-spec of RFC 5322, including
+	// quoted local parts and address literals such as joe@[127.0.0.1], as IsEmail does.
+	EmailRFC5321 EmailMode = iota
+	// EmailSimple accepts any address of the form local@domain.tld without whitespace, as the
+	// validation of HTML email inputs does.
+	EmailSimple
+	// EmailSMTPUTF8 also accepts the internationalized addresses of RFC 6531, whose local part and
+	// domain may contain non-ASCII characters, as IsInternationalEmail does.
+	EmailSMTPUTF8
+)
+
+// simpleEmailPattern is the pattern of the addresses accepted by EmailSimple.
+var simpleEmailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@.]+(\.[^\s@.]+)+$`)
+
+// SetEmailMode sets how strictly the email format checks addresses for every schema of the
+// compiler. The default is EmailRFC5321.
+func (c *Compiler) SetEmailMode(mode EmailMode) *Compiler {
+	c.EmailMode = mode
+	return c
+}
+
+// SetEmailMode sets how strictly the email format checks addresses in s and its subschemas,
+// overriding the compiler. It modifies s in place, including for the schemas that reference it,
+// and must be called before s is validated concurrently.
+func (s *Schema) SetEmailMode(mode EmailMode) *Schema {
+	s.emailMode = &mode
+	return s
+}
+
+// emailFormat returns the function checking the email format in schema, or nil when the function
+// registered in Formats applies.
+func emailFormat(schema *Schema) func(interface{}) bool {
+	mode := EmailRFC5321
+	if schema.compiler != nil {
+		mode = schema.compiler.EmailMode
+	}
+	for s := schema; s != nil; s = s.parent {
+		if s.emailMode != nil {
+			mode = *s.emailMode
+			break
+		}
+	}
+
+	switch mode {
+	case EmailSimple:
+		return IsSimpleEmail
+	case EmailSMTPUTF8:
+		return IsInternationalEmail
+	}
+	return nil
+}
+
+// IsSimpleEmail tells whether given string has the form local@domain.tld
+// without whitespace.
+func IsSimpleEmail(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	return simpleEmailPattern.MatchString(s)
+}
+
+// IsInternationalEmail tells whether given string is a valid internationalized
+// email address as defined by RFC 6531, whose local part and domain may contain
+// UTF-8 characters.
+//
+// See https://datatracker.ietf.org/doc/html/rfc6531#section-3.3, for details.
+func IsInternationalEmail(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	if len(s) > 254 || !utf8.ValidString(s) {
+		return false
+	}
+
+	at := strings.LastIndexByte(s, '@')
+	if at < 1 {
+		return false
+	}
+	local := s[:at]
+	domain := s[at+1:]
+
+	if len(local) > 64 || !isInternationalLocalPart(local) {
+		return false
+	}
+
+	if len(domain) >= 2 && domain[0] == '[' && domain[len(domain)-1] == ']' {
+		ip := domain[1 : len(domain)-1]
+		if strings.HasPrefix(ip, "IPv6:") {
+			return IsIPV6(strings.TrimPrefix(ip, "IPv6:"))
+		}
+		return IsIPV4(ip)
+	}
+	return IsIDNHostname(domain)
+}
+
+// isInternationalLocalPart tells whether local is a dot-atom or a quoted string
+// whose characters may also be non-ASCII.
+func isInternationalLocalPart(local string) bool {
+	if len(local) >= 2 && local[0] == '"' && local[len(local)-1] == '"' {
+		quoted := local[1 : len(local)-1]
+		for i := 0; i < len(quoted); i++ {
+			switch c := quoted[i]; {
+			case c == '\\':
+				// quoted-pair: a backslash followed by a printable character or a space
+				if i++; i == len(quoted) || quoted[i] < ' ' || quoted[i] == 0x7f {
+					return false
+				}
+			case c == '"' || c < ' ' || c == 0x7f:
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, atom := range strings.Split(local, ".") {
+		if atom == "" {
+			return false
+		}
+		for _, r := range atom {
+			if r < utf8.RuneSelf && !isAtext(byte(r)) || r >= utf8.RuneSelf && !unicode.IsPrint(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isAtext tells whether c is an atext character of RFC 5322, section 3.2.3.
+func isAtext(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || strings.IndexByte("!#$%&'*+-/=?^_`{|}~", c) >= 0
+}
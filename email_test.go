@@ -0,0 +1,51 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailModes(t *testing.T) {
+	addresses := []string{
+		"joe.bloggs@example.com",
+		"\"joe bloggs\"@example.com",
+		"joe@[127.0.0.1]",
+		"joe@localhost",
+		"dörte@example.com",
+		"用户@例子.广告",
+		"joe..bloggs@example.com",
+		"joe bloggs@example.com",
+		"@example.com",
+	}
+	tests := []struct {
+		mode  EmailMode
+		valid []bool
+	}{
+		{EmailRFC5321, []bool{true, true, true, true, false, false, false, false, false}},
+		{EmailSimple, []bool{true, false, true, false, true, true, true, false, false}},
+		{EmailSMTPUTF8, []bool{true, true, true, true, true, true, false, false, false}},
+	}
+
+	for _, tt := range tests {
+		schema, err := NewCompiler().SetAssertFormat(true).SetEmailMode(tt.mode).Compile([]byte(`{"format": "email"}`))
+		require.NoError(t, err)
+		for i, address := range addresses {
+			assert.Equal(t, tt.valid[i], schema.Validate(address).IsValid(), "mode %d, %s", tt.mode, address)
+		}
+	}
+}
+
+func TestSchemaEmailMode(t *testing.T) {
+	schema, err := NewCompiler().SetAssertFormat(true).Compile([]byte(`{
+		"properties": {"email": {"type": "string", "format": "email"}}
+	}`))
+	require.NoError(t, err)
+
+	instance := map[string]interface{}{"email": "dörte@example.com"}
+	assert.False(t, schema.Validate(instance).IsValid())
+
+	schema.SetEmailMode(EmailSMTPUTF8)
+	assert.True(t, schema.Validate(instance).IsValid())
+}
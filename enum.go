@@ -1,7 +1,5 @@
 package jsonschema
 
-import "reflect"
-
 // EvaluateEnum checks if the data's value matches one of the enumerated values specified in the schema.
 // According to the JSON Schema Draft 2020-12:
 //   - The value of the "enum" keyword must be an array.
@@ -16,7 +14,7 @@ import "reflect"
 func evaluateEnum(schema *Schema, instance interface{}) *EvaluationError {
 	if schema.Enum != nil && len(schema.Enum) > 0 {
 		for _, enumValue := range schema.Enum {
-			if reflect.DeepEqual(instance, enumValue) {
+			if equalJSON(instance, enumValue) {
 				return nil // Match found.
 			}
 		}
@@ -0,0 +1,166 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// EnvironmentKeyword is the extension keyword conditioning a schema on the flags of the
+// environment. Its value is a flag, or a list of flags that must all be set, each optionally
+// negated with a leading "!", such as "production" or ["eu", "!staging"].
+const EnvironmentKeyword = "x-if-env"
+
+// SetEnvironment sets the flags of the environment the compiler compiles schemas for, so that one
+// schema source produces, say, a stricter production variant and a looser staging one. Subschemas
+// whose EnvironmentKeyword does not hold are stripped from the schema documents before they are
+// parsed: they are removed from the keywords and lists holding them, except from prefixItems, where
+// they are replaced with true to keep the positions of the other items. A root schema that does not
+// hold is replaced with true. References to stripped subschemas, or to the items following them in
+// a list, no longer resolve to them.
+func (c *Compiler) SetEnvironment(flags ...string) *Compiler {
+	c.Environment = make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		c.Environment[flag] = true
+	}
+	return c
+}
+
+// legacySubschemaKeywords are the keywords of drafts before 2020-12 whose values hold subschemas.
+var legacySubschemaKeywords = map[string]int{
+	"definitions": subschemaMap, "dependencies": subschemaMap, "additionalItems": subschemaSingle,
+}
+
+// applyEnvironment strips the subschemas of a document that are conditioned on flags of the
+// environment that do not hold.
+func (c *Compiler) applyEnvironment(jsonSchema []byte) ([]byte, error) {
+	if !bytes.Contains(jsonSchema, []byte(EnvironmentKeyword)) {
+		return jsonSchema, nil
+	}
+	var document interface{}
+	if err := decodeExactJSON(jsonSchema, &document); err != nil {
+		return nil, err
+	}
+
+	active, err := c.environmentHolds(document, "")
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return []byte("true"), nil
+	}
+	if err := c.stripEnvironment(document, ""); err != nil {
+		return nil, err
+	}
+	return json.Marshal(document)
+}
+
+// environmentHolds reports whether the EnvironmentKeyword of the schema value at pointer, if any,
+// holds, and removes the keyword.
+func (c *Compiler) environmentHolds(value interface{}, pointer string) (bool, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return true, nil
+	}
+	condition, ok := object[EnvironmentKeyword]
+	if !ok {
+		return true, nil
+	}
+	delete(object, EnvironmentKeyword)
+
+	var flags []interface{}
+	switch condition := condition.(type) {
+	case string:
+		flags = []interface{}{condition}
+	case []interface{}:
+		flags = condition
+	default:
+		return false, fmt.Errorf("%w at '%s'", ErrInvalidEnvironmentCondition, pointer)
+	}
+	holds := true
+	for _, flag := range flags {
+		name, ok := flag.(string)
+		if !ok {
+			return false, fmt.Errorf("%w at '%s'", ErrInvalidEnvironmentCondition, pointer)
+		}
+		negated := strings.HasPrefix(name, "!")
+		if c.Environment[strings.TrimPrefix(name, "!")] == negated {
+			holds = false
+		}
+	}
+	return holds, nil
+}
+
+// stripEnvironment strips the subschemas of the schema value at pointer whose conditions on the
+// environment do not hold.
+func (c *Compiler) stripEnvironment(value interface{}, pointer string) error {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for keyword, value := range object {
+		kind, ok := subschemaKeywords[keyword]
+		if !ok {
+			kind = legacySubschemaKeywords[keyword]
+		}
+		if kind == 0 {
+			continue
+		}
+		location := pointer + "/" + escapeJSONPointer(keyword)
+
+		switch value := value.(type) {
+		case []interface{}: // Lists of schemas, and items arrays before 2020-12.
+			kept := value[:0]
+			for i, subschema := range value {
+				active, err := c.environmentHolds(subschema, location+"/"+strconv.Itoa(i))
+				if err != nil {
+					return err
+				}
+				if !active {
+					if keyword == "prefixItems" || keyword == "items" {
+						kept = append(kept, true)
+					}
+					continue
+				}
+				if err := c.stripEnvironment(subschema, location+"/"+strconv.Itoa(i)); err != nil {
+					return err
+				}
+				kept = append(kept, subschema)
+			}
+			if len(kept) == 0 {
+				delete(object, keyword)
+			} else {
+				object[keyword] = kept
+			}
+		case map[string]interface{}:
+			if kind != subschemaMap {
+				active, err := c.environmentHolds(value, location)
+				if err != nil {
+					return err
+				}
+				if !active {
+					delete(object, keyword)
+				} else if err := c.stripEnvironment(value, location); err != nil {
+					return err
+				}
+				continue
+			}
+			for name, subschema := range value {
+				active, err := c.environmentHolds(subschema, location+"/"+escapeJSONPointer(name))
+				if err != nil {
+					return err
+				}
+				if !active {
+					delete(value, name)
+				} else if err := c.stripEnvironment(subschema, location+"/"+escapeJSONPointer(name)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
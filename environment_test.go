@@ -0,0 +1,70 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const environmentSchema = `{
+	"type": "object",
+	"properties": {
+		"password": {
+			"type": "string",
+			"allOf": [
+				{"x-if-env": "production", "minLength": 12},
+				{"x-if-env": "!production", "minLength": 4}
+			]
+		},
+		"debug": {"x-if-env": "production", "const": false},
+		"point": {"prefixItems": [{"x-if-env": ["eu", "production"], "type": "string"}, {"type": "integer"}]}
+	},
+	"additionalProperties": {"x-if-env": "production", "not": true}
+}`
+
+func TestEnvironment(t *testing.T) {
+	production, err := NewCompiler().SetEnvironment("production").Compile([]byte(environmentSchema))
+	require.NoError(t, err)
+	staging, err := NewCompiler().SetEnvironment("staging").Compile([]byte(environmentSchema))
+	require.NoError(t, err)
+
+	short := map[string]interface{}{"password": "secret"}
+	assert.False(t, production.Validate(short).IsValid())
+	assert.True(t, staging.Validate(short).IsValid())
+	assert.False(t, staging.Validate(map[string]interface{}{"password": "abc"}).IsValid())
+
+	debug := map[string]interface{}{"debug": true, "extra": 1}
+	assert.False(t, production.Validate(debug).IsValid())
+	assert.True(t, staging.Validate(debug).IsValid())
+
+	point := map[string]interface{}{"point": []interface{}{1, 2}}
+	assert.True(t, production.Validate(point).IsValid(), "the condition on the first item does not hold without eu")
+	assert.False(t, production.Validate(map[string]interface{}{"point": []interface{}{1, "2"}}).IsValid(), "the positions of the items are kept")
+
+	document, err := json.Marshal(staging)
+	require.NoError(t, err)
+	assert.NotContains(t, string(document), "x-if-env")
+	assert.NotContains(t, string(document), "additionalProperties")
+	assert.NotContains(t, string(document), "debug")
+}
+
+func TestEnvironmentRoot(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"x-if-env": "production", "type": "string"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(1).IsValid())
+
+	schema, err = NewCompiler().SetEnvironment("production").Compile([]byte(`{"x-if-env": "production", "type": "string"}`))
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(1).IsValid())
+}
+
+func TestEnvironmentInvalidCondition(t *testing.T) {
+	_, err := NewCompiler().Compile([]byte(`{"properties": {"a": {"x-if-env": 1}}}`))
+	require.ErrorIs(t, err, ErrInvalidEnvironmentCondition)
+	assert.Contains(t, err.Error(), "/properties/a")
+
+	_, err = NewCompiler().Compile([]byte(`{"anyOf": [{"x-if-env": ["production", true]}]}`))
+	require.ErrorIs(t, err, ErrInvalidEnvironmentCondition)
+}
@@ -0,0 +1,95 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// errorMessageKeyword is the extension keyword, as in ajv-errors, declaring the messages of the
+// errors of a schema.
+const errorMessageKeyword = "errorMessage"
+
+// compileErrorMessages returns the messages declared by the errorMessage keyword of s by keyword
+// name, with the empty name standing for every keyword when the value is a single string.
+//
+// A schema can replace the messages of all its errors with a string:
+//
+//	{"type": "string", "minLength": 1, "errorMessage": "name must be a non-empty string"}
+//
+// or the messages of the errors of some keywords with an object:
+//
+//	{"minimum": 18, "errorMessage": {"minimum": "age must be at least {minimum}, got {value}"}}
+//
+// Messages are templates like the built-in ones: {name} is replaced by the parameter name of the
+// error, and {value} by the instance when the error has no such parameter.
+func compileErrorMessages(s *Schema) (map[string]string, error) {
+	value, ok := s.Extensions[errorMessageKeyword]
+	if !ok {
+		return nil, nil
+	}
+	switch value := value.(type) {
+	case string:
+		return map[string]string{"": value}, nil
+	case map[string]interface{}:
+		messages := make(map[string]string, len(value))
+		for keyword, message := range value {
+			text, ok := message.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: message of %s is not a string", ErrInvalidErrorMessage, keyword)
+			}
+			messages[keyword] = text
+		}
+		return messages, nil
+	default:
+		return nil, ErrInvalidErrorMessage
+	}
+}
+
+// applyErrorMessages replaces the messages of the errors of the result with the messages declared
+// for their keywords, see compileErrorMessages.
+func (e *EvaluationResult) applyErrorMessages(messages map[string]string, instance interface{}) {
+	for keyword, err := range e.Errors {
+		message, ok := messages[keyword]
+		if !ok {
+			if message, ok = messages[""]; !ok {
+				continue
+			}
+		}
+		params := make(map[string]interface{}, len(err.Params)+1)
+		for key, value := range err.Params {
+			params[key] = value
+		}
+		if _, ok := params["value"]; !ok && strings.Contains(message, "{value}") {
+			params["value"] = formatInstance(instance)
+		}
+		e.Errors[keyword] = &EvaluationError{
+			Keyword:  err.Keyword,
+			Code:     err.Code,
+			Message:  message,
+			Params:   params,
+			Severity: err.Severity,
+			custom:   true,
+		}
+	}
+}
+
+// formatInstance returns instance as it appears in a message: strings and numbers as they are,
+// other values as JSON.
+func formatInstance(instance interface{}) string {
+	switch value := instance.(type) {
+	case string:
+		return value
+	case nil:
+		return "null"
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,59 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorMessage(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"age": {
+				"type": "integer",
+				"minimum": 18,
+				"errorMessage": {"minimum": "age must be at least {minimum}, got {value}"}
+			},
+			"name": {"type": "string", "minLength": 1, "errorMessage": "name must be a non-empty string, got {value}"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{"age": 16, "name": 3})
+	require.False(t, result.IsValid())
+	list := result.ToList()
+	messages := map[string]map[string]string{}
+	for _, detail := range list.Details {
+		messages[detail.InstanceLocation] = detail.Errors
+	}
+	assert.Equal(t, map[string]string{"minimum": "age must be at least 18, got 16"}, messages["/age"])
+	assert.Equal(t, map[string]string{"type": "name must be a non-empty string, got 3"}, messages["/name"])
+
+	result = schema.Validate(map[string]interface{}{"age": 20.5})
+	age := result.ToList().Details[0]
+	assert.Equal(t, "Value is number but should be integer", age.Errors["type"], "keywords without a message keep theirs")
+
+	i18n, err := GetI18n()
+	require.NoError(t, err)
+	localized := schema.Validate(map[string]interface{}{"age": 16}).ToLocalizeList(i18n.NewLocalizer("zh-Hans"))
+	assert.Equal(t, "age must be at least 18, got 16", localized.Details[0].Errors["minimum"])
+}
+
+func TestErrorMessageInstance(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"maxItems": 1, "errorMessage": "too many items in {value}"}`))
+	require.NoError(t, err)
+	result := schema.Validate([]interface{}{"a", "b"})
+	assert.Equal(t, `too many items in ["a","b"]`, result.Errors["maxItems"].Error())
+	assert.Equal(t, "items_too_long", result.Errors["maxItems"].Code)
+}
+
+func TestInvalidErrorMessage(t *testing.T) {
+	_, err := NewCompiler().Compile([]byte(`{"properties": {"a": {"errorMessage": {"type": 1}}}}`))
+	assert.ErrorIs(t, err, ErrInvalidErrorMessage)
+	assert.Contains(t, err.Error(), "/properties/a")
+
+	_, err = NewCompiler().Compile([]byte(`{"errorMessage": 1}`))
+	assert.ErrorIs(t, err, ErrInvalidErrorMessage)
+}
@@ -17,9 +17,33 @@ var ErrXMLUnmarshalError = errors.New("xml unmarshal error")
 // ErrYAMLUnmarshalError is returned when there is an error unmarshalling YAML.
 var ErrYAMLUnmarshalError = errors.New("yaml unmarshal error")
 
+// ErrYAMLNonFiniteNumber is returned when a YAML document contains .inf or .nan, which have no JSON representation.
+var ErrYAMLNonFiniteNumber = errors.New("yaml document contains a non-finite number")
+
+// ErrYAMLUnsupported is returned when a YAML document is compiled by a build with the jsonschema_core tag, which leaves out YAML.
+var ErrYAMLUnsupported = errors.New("yaml is not supported in this build")
+
+// ErrMultipartBoundaryNotFound is returned when multipart/form-data content has no boundary delimiter.
+var ErrMultipartBoundaryNotFound = errors.New("multipart boundary not found")
+
+// ErrInvalidBSON is returned when application/bson content is not a valid BSON document.
+var ErrInvalidBSON = errors.New("invalid bson document")
+
+// ErrUnsupportedContentEncoding is returned when a schema is served with a Content-Encoding that has no registered decompressor.
+var ErrUnsupportedContentEncoding = errors.New("unsupported content encoding")
+
+// ErrInvalidPunycode is returned when a label cannot be encoded to or decoded from Punycode.
+var ErrInvalidPunycode = errors.New("invalid punycode")
+
+// ErrInvalidHostname is returned when a host name cannot be converted between U-labels and A-labels because it is not valid.
+var ErrInvalidHostname = errors.New("invalid hostname")
+
 // ErrFailedToFetch is returned when there is an error fetching from the URL.
 var ErrFailedToFetch = errors.New("failed to fetch from URL")
 
+// ErrTooManyRedirects is returned when fetching a schema is redirected more often than the compiler allows.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
 // ErrInvalidHTTPStatusCode is returned when an invalid HTTP status code is returned.
 var ErrInvalidHTTPStatusCode = errors.New("invalid HTTP status code returned")
 
@@ -47,6 +71,12 @@ var ErrSegmentNotFoundForJSONPointer = errors.New("segment not found in the sche
 // ErrFailedToResolveReference is returned when a reference cannot be resolved.
 var ErrFailedToResolveReference = errors.New("failed to resolve reference")
 
+// ErrSubschemaNotFound is returned when no subschema exists at the given JSON Pointer or anchor.
+var ErrSubschemaNotFound = errors.New("subschema not found")
+
+// ErrSchemaNotRegistered is returned when no schema was set under a name that is not a URI.
+var ErrSchemaNotRegistered = errors.New("no schema registered under name")
+
 // ErrFailedToResolveDefinitions is returned when definitions in $defs cannot be resolved.
 var ErrFailedToResolveDefinitions = errors.New("failed to resolve definitions in $defs")
 
@@ -55,3 +85,123 @@ var ErrFailedToResolveItems = errors.New("failed to resolve items")
 
 // ErrInvalidJSONSchemaType is returned when the JSON schema type is invalid.
 var ErrInvalidJSONSchemaType = errors.New("invalid JSON schema type")
+
+// ErrInvalidEnum is returned when the value of the enum keyword is not an array.
+var ErrInvalidEnum = errors.New("enum must be an array")
+
+// ErrRemoteUnreachable is returned when a remote document referenced by a compiled schema cannot be fetched.
+var ErrRemoteUnreachable = errors.New("remote schema unreachable")
+
+// ErrRemoteChanged is returned when a remote document referenced by a compiled schema has changed since it was compiled.
+var ErrRemoteChanged = errors.New("remote schema changed")
+
+// ErrInvalidManifest is returned when a preload manifest is malformed.
+var ErrInvalidManifest = errors.New("invalid preload manifest")
+
+// ErrManifestHashMismatch is returned when a document listed in a preload manifest does not have the expected hash.
+var ErrManifestHashMismatch = errors.New("schema hash does not match manifest")
+
+// ErrInvalidStoreRef is returned when a schema store is given an invalid schema name, version or canary share.
+var ErrInvalidStoreRef = errors.New("invalid schema store reference")
+
+// ErrSchemaVersionExists is returned when a version is added to a schema store twice.
+var ErrSchemaVersionExists = errors.New("schema version already exists")
+
+// ErrSchemaVersionNotFound is returned when a schema store has no schema for a name and version.
+var ErrSchemaVersionNotFound = errors.New("schema version not found")
+
+// ErrTypeNotRegistered is returned when no schema is registered for a Go type.
+var ErrTypeNotRegistered = errors.New("no schema registered for type")
+
+// ErrGenerationFailed is returned when no instance valid against a schema could be generated.
+var ErrGenerationFailed = errors.New("failed to generate a valid instance")
+
+// ErrMutationSeedInvalid is returned when mutation testing is given an instance that is not valid against the schema.
+var ErrMutationSeedInvalid = errors.New("mutation seed instance is not valid")
+
+// ErrRecordingMissingSchema is returned when a validation recording does not contain a schema.
+var ErrRecordingMissingSchema = errors.New("recording does not contain a schema")
+
+// ErrRecordingMissingResource is returned when a replayed schema references a document that was not recorded.
+var ErrRecordingMissingResource = errors.New("resource not found in recording")
+
+// ErrRecordingRegexEngine is returned when replaying a recording made with a custom regex engine, which cannot be recorded.
+var ErrRecordingRegexEngine = errors.New("recording was made with a custom regex engine")
+
+// ErrInvalidStructTag is returned when a jsonschema struct tag is malformed.
+var ErrInvalidStructTag = errors.New("invalid jsonschema struct tag")
+
+// ErrUnknownStructTagKeyword is returned when a jsonschema struct tag uses a keyword it does not support.
+var ErrUnknownStructTagKeyword = errors.New("unknown keyword in jsonschema struct tag")
+
+// ErrUnsupportedValidatorTag is returned when a go-playground/validator tag has no JSON Schema equivalent, or the reverse.
+var ErrUnsupportedValidatorTag = errors.New("unsupported validator tag")
+
+// ErrUnexpectedToken is returned when a token validator is given a token that cannot follow the previous ones.
+var ErrUnexpectedToken = errors.New("unexpected token")
+
+// ErrIncompleteTokens is returned when the result of a token validator is requested before the instance is complete.
+var ErrIncompleteTokens = errors.New("incomplete token sequence")
+
+// ErrInvalidIR is returned when imported data is not a schema written by Schema.Export.
+var ErrInvalidIR = errors.New("invalid schema intermediate representation")
+
+// ErrUnsupportedIRVersion is returned when an exported schema was written with another version of the format.
+var ErrUnsupportedIRVersion = errors.New("unsupported schema intermediate representation version")
+
+// ErrKeywordNotAllowed is returned when a schema uses a keyword removed from its dialect.
+var ErrKeywordNotAllowed = errors.New("keyword not allowed by dialect")
+
+// ErrAliasedKeywordConflict is returned when a schema uses both a keyword and an alias of it.
+var ErrAliasedKeywordConflict = errors.New("keyword given both directly and through an alias")
+
+// ErrMiddleware is returned when a middleware fails to preprocess a schema document.
+var ErrMiddleware = errors.New("schema middleware failed")
+
+// ErrLocationNotFound is returned when a JSON Pointer does not point to a value of a JSON document.
+var ErrLocationNotFound = errors.New("location not found in JSON document")
+
+// ErrInvalidSchemaExtension is returned when the extension given to Schema.Extend is not a JSON object.
+var ErrInvalidSchemaExtension = errors.New("schema extension must be a JSON object")
+
+// ErrInvalidEnvironmentCondition is returned when the value of the x-if-env keyword is neither a flag nor a list of flags.
+var ErrInvalidEnvironmentCondition = errors.New("x-if-env must be a flag or a list of flags")
+
+// ErrInvalidKeyword is returned when the factory of a custom keyword rejects its value in a schema.
+var ErrInvalidKeyword = errors.New("invalid custom keyword")
+
+// ErrUnknownVocabulary is returned when the meta-schema of a schema requires a vocabulary the compiler does not know.
+var ErrUnknownVocabulary = errors.New("unknown required vocabulary")
+
+// ErrBudgetExceeded is matched by the error returned when a validation exceeds a limit of its budget.
+var ErrBudgetExceeded = errors.New("validation budget exceeded")
+
+// ErrUnsupportedGoType is returned when a schema is derived from a Go type without a JSON encoding, such as a channel or a function.
+var ErrUnsupportedGoType = errors.New("go type has no JSON schema")
+
+// ErrUnknownSeverity is returned when decoding the name of a severity other than error, warning and info.
+var ErrUnknownSeverity = errors.New("unknown severity")
+
+// ErrInvalidMirror is returned when the index of a schema mirror is not valid JSON.
+var ErrInvalidMirror = errors.New("invalid schema mirror index")
+
+// ErrMirrorCorrupted is returned when a mirrored document no longer matches the hash recorded in the index.
+var ErrMirrorCorrupted = errors.New("mirrored schema does not match its hash")
+
+// ErrInvalidErrorMessage is returned when the errorMessage keyword of a schema is neither a message nor an object of messages by keyword.
+var ErrInvalidErrorMessage = errors.New("errorMessage must be a string or an object of strings")
+
+// ErrInvalidSchemaLocation is returned when a JSON Pointer given to Compiler.KeywordsAt does not point to a schema.
+var ErrInvalidSchemaLocation = errors.New("location is not a schema")
+
+// ErrDuplicateKey is matched by the error returned when a raw JSON instance gives a property more than once, see DuplicateKeysError.
+var ErrDuplicateKey = errors.New("duplicate key in JSON instance")
+
+// ErrOffline is returned when an offline compiler would fetch a document over HTTP, see Compiler.SetOffline.
+var ErrOffline = errors.New("fetching over HTTP is disabled in offline mode")
+
+// ErrUnresolvedRemoteRefs is returned when an offline compiler compiles a schema with references to http and https URIs it cannot resolve.
+var ErrUnresolvedRemoteRefs = errors.New("unresolved remote references in offline mode")
+
+// ErrInvalidPattern is returned when a regular expression of pattern or patternProperties does not compile with the regex engine of the compiler.
+var ErrInvalidPattern = errors.New("invalid regular expression")
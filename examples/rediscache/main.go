@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+var (
+	errRedis         = errors.New("redis error")
+	errRedisResponse = errors.New("unexpected redis response")
+)
+
+// RedisCache is a jsonschema.Cache storing fetched schema documents in Redis, so that every
+// validator of a fleet fetches each remote schema once per time to live. It speaks the Redis
+// protocol over a single connection to stay dependency free; production code would typically
+// wrap the client of github.com/redis/go-redis instead.
+type RedisCache struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// DialRedisCache connects to the Redis server at addr.
+func DialRedisCache(addr string) (*RedisCache, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisCache{conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+// Get returns the value stored under key.
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, value != nil, nil
+}
+
+// Set stores value under key for ttl, or without expiration when ttl is 0.
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// Delete removes the value stored under key.
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	_, err := c.do(ctx, "DEL", key)
+	return err
+}
+
+// Close closes the connection.
+func (c *RedisCache) Close() error {
+	return c.conn.Close()
+}
+
+// do sends a command and returns its bulk string reply, nil for a nil or non-bulk reply.
+func (c *RedisCache) do(ctx context.Context, args ...string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := c.conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, command.String()); err != nil {
+		return nil, err
+	}
+
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errRedisResponse
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return nil, nil
+	case '-':
+		return nil, fmt.Errorf("%w: %s", errRedis, line[1:])
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, errRedisResponse
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		value := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, value); err != nil {
+			return nil, err
+		}
+		return value[:size], nil
+	}
+	return nil, fmt.Errorf("%w: %q", errRedisResponse, line)
+}
+
+func main() {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	cache, err := DialRedisCache(addr)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer cache.Close() //nolint:errcheck
+
+	compiler := jsonschema.NewCompiler().SetCache(cache, 10*time.Minute)
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"address": {"$ref": "https://json-schema.org/learn/examples/address.schema.json"}
+		}
+	}`))
+	if err != nil {
+		log.Printf("Failed to compile schema: %v", err)
+		return
+	}
+
+	instance := map[string]interface{}{
+		"address": map[string]interface{}{"locality": "Stockholm", "region": "Stockholm", "countryName": "Sweden"},
+	}
+	if result := schema.Validate(instance); result.IsValid() {
+		fmt.Println("The instance is valid")
+	} else {
+		fmt.Println("The instance is not valid")
+	}
+}
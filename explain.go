@@ -0,0 +1,168 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultDialect is the dialect applied to schemas that do not declare $schema.
+const defaultDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Explain describes how the compiled schema is evaluated, for debugging validation results and
+// performance. For every subschema, in a deterministic order, it lists the effective dialect when
+// it changes, the targets of $ref and $dynamicRef after resolution, unresolved references, and the
+// keywords in the order the evaluator applies them, noting whether format is asserted or only
+// annotated.
+//
+// The output is meant for humans and its layout may change between versions.
+func (s *Schema) Explain() string {
+	var out strings.Builder
+
+	uri := s.GetSchemaURI()
+	if uri == "" {
+		uri = "(anonymous)"
+	}
+	fmt.Fprintf(&out, "schema %s\n", uri)
+
+	dialects := map[*Schema]string{}
+	walkSchema(s, func(pointer string, schema *Schema) bool {
+		dialect := explainDialect(schema, dialects)
+		fmt.Fprintf(&out, "#%s\n", pointer)
+		if schema.parent == nil || dialect != dialects[schema.parent] {
+			fmt.Fprintf(&out, "  dialect: %s\n", dialect)
+		}
+
+		if schema.Boolean != nil {
+			if *schema.Boolean {
+				out.WriteString("  always valid\n")
+			} else {
+				out.WriteString("  always invalid\n")
+			}
+			return false
+		}
+
+		if schema.Ref != "" {
+			fmt.Fprintf(&out, "  $ref %s -> %s\n", schema.Ref, explainTarget(schema.ResolvedRef))
+		}
+		if schema.DynamicRef != "" {
+			fmt.Fprintf(&out, "  $dynamicRef %s -> %s (may be replaced through the dynamic scope)\n", schema.DynamicRef, explainTarget(schema.ResolvedDynamicRef))
+		}
+		if schema.Format != nil {
+			fmt.Fprintf(&out, "  format %q: %s\n", *schema.Format, explainFormat(schema))
+		}
+
+		keywords := evaluationOrder(schema)
+		if len(keywords) == 0 {
+			out.WriteString("  evaluates: nothing (accepts any instance)\n")
+		} else {
+			fmt.Fprintf(&out, "  evaluates: %s\n", strings.Join(keywords, ", "))
+		}
+		return true
+	})
+
+	return out.String()
+}
+
+// explainDialect returns the dialect of schema, inherited from its parent unless $schema is set.
+func explainDialect(schema *Schema, dialects map[*Schema]string) string {
+	dialect := schema.Schema
+	if dialect == "" {
+		if parent, ok := dialects[schema.parent]; ok {
+			dialect = parent
+		} else {
+			dialect = defaultDialect
+		}
+	}
+	dialects[schema] = dialect
+	return dialect
+}
+
+// explainTarget describes the schema a reference resolved to.
+func explainTarget(target *Schema) string {
+	if target == nil {
+		return "unresolved"
+	}
+
+	root := target.getRootSchema()
+	location := target.GetSchemaURI() + "#"
+	walkSchema(root, func(pointer string, schema *Schema) bool {
+		if schema == target {
+			location = root.GetSchemaURI() + "#" + pointer
+			return false
+		}
+		return true
+	})
+	return location
+}
+
+// explainFormat describes how the format keyword of schema is evaluated.
+func explainFormat(schema *Schema) string {
+	_, known := Formats[*schema.Format]
+	asserted := schema.assertsFormat()
+	switch {
+	case asserted && known:
+		return "asserted"
+	case asserted:
+		return "asserted, unknown format always fails"
+	case known:
+		return "annotation only"
+	default:
+		return "annotation only, unknown format"
+	}
+}
+
+// evaluationOrder lists the keywords of schema in the order Schema.evaluate applies them.
+func evaluationOrder(s *Schema) []string {
+	var keywords []string
+	add := func(present bool, keyword string) {
+		if present {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	add(s.ResolvedRef != nil, "$ref")
+	add(s.ResolvedDynamicRef != nil, "$dynamicRef")
+	add(s.Type != nil, "type")
+	add(s.Enum != nil, "enum")
+	add(s.Const != nil, "const")
+	add(s.AllOf != nil, "allOf")
+	add(s.AnyOf != nil, "anyOf")
+	add(s.OneOf != nil, "oneOf")
+	add(s.Not != nil, "not")
+	add(s.If != nil, "if")
+	add(s.If != nil && s.Then != nil, "then")
+	add(s.If != nil && s.Else != nil, "else")
+	add(len(s.PrefixItems) > 0, "prefixItems")
+	add(s.Items != nil, "items")
+	add(s.Contains != nil, "contains")
+	add(s.MaxContains != nil, "maxContains")
+	add(s.MinContains != nil, "minContains")
+	add(s.MaxItems != nil, "maxItems")
+	add(s.MinItems != nil, "minItems")
+	add(s.UniqueItems != nil, "uniqueItems")
+	add(s.MultipleOf != nil, "multipleOf")
+	add(s.Maximum != nil, "maximum")
+	add(s.ExclusiveMaximum != nil, "exclusiveMaximum")
+	add(s.Minimum != nil, "minimum")
+	add(s.ExclusiveMinimum != nil, "exclusiveMinimum")
+	add(s.MaxLength != nil, "maxLength")
+	add(s.MinLength != nil, "minLength")
+	add(s.Pattern != nil, "pattern")
+	add(s.Format != nil, "format")
+	add(s.Properties != nil, "properties")
+	add(s.PatternProperties != nil, "patternProperties")
+	add(s.AdditionalProperties != nil, "additionalProperties")
+	add(s.PropertyNames != nil, "propertyNames")
+	add(s.MaxProperties != nil, "maxProperties")
+	add(s.MinProperties != nil, "minProperties")
+	add(len(s.Required) > 0, "required")
+	add(len(s.DependentRequired) > 0, "dependentRequired")
+	add(s.DependentSchemas != nil, "dependentSchemas")
+	add(s.UnevaluatedProperties != nil, "unevaluatedProperties")
+	add(s.UnevaluatedItems != nil, "unevaluatedItems")
+	add(s.ContentEncoding != nil, "contentEncoding")
+	add(s.ContentMediaType != nil, "contentMediaType")
+	add(s.ContentSchema != nil, "contentSchema")
+
+	return keywords
+}
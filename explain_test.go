@@ -0,0 +1,57 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/user",
+		"$defs": {
+			"positive": {"type": "integer", "minimum": 1}
+		},
+		"type": "object",
+		"properties": {
+			"id": {"$ref": "#/$defs/positive"},
+			"email": {"type": "string", "format": "email"},
+			"extra": false
+		},
+		"required": ["id"]
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, `schema https://example.com/user
+#
+  dialect: https://json-schema.org/draft/2020-12/schema
+  evaluates: type, properties, required
+#/$defs/positive
+  evaluates: type, minimum
+#/properties/email
+  format "email": annotation only
+  evaluates: type, format
+#/properties/extra
+  always invalid
+#/properties/id
+  $ref #/$defs/positive -> https://example.com/user#/$defs/positive
+  evaluates: $ref
+`, schema.Explain())
+}
+
+func TestExplainUnresolvedAndAssertedFormat(t *testing.T) {
+	compiler := newFuzzCompiler().SetAssertFormat(true)
+	schema, err := compiler.Compile([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"allOf": [{"$ref": "https://example.com/missing.json"}, {"format": "custom"}, {}]
+	}`))
+	require.NoError(t, err)
+
+	explanation := schema.Explain()
+	assert.Contains(t, explanation, "schema (anonymous)\n")
+	assert.Contains(t, explanation, "$ref https://example.com/missing.json -> unresolved\n")
+	assert.Contains(t, explanation, `format "custom": asserted, unknown format always fails`)
+	assert.Contains(t, explanation, "evaluates: nothing (accepts any instance)\n")
+}
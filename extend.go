@@ -0,0 +1,72 @@
+package jsonschema
+
+import "github.com/goccy/go-json"
+
+// metadataKeywords are the keywords of a schema that Extend takes from the original schema when
+// the extension does not override them.
+var metadataKeywords = []string{"$schema", "title", "description", "default", "examples", "deprecated", "readOnly", "writeOnly", "$comment"}
+
+// Extend returns a new schema layering the schema document extension over s, for customizing a
+// shared contract per tenant. Instances must conform to both s and the constraints of the
+// extension, which can therefore only tighten s; the metadata of the extension, such as title,
+// description and default, overrides that of s, which is kept otherwise. The defaults of the
+// properties of the extension likewise take precedence over those of s.
+//
+// References of the extension are resolved against the URI of s. Like Normalize, the schema is
+// compiled with the compiler of s but is not registered with it, and s is left unchanged.
+func (s *Schema) Extend(extension []byte) (*Schema, error) {
+	var document interface{}
+	if err := decodeExactJSON(extension, &document); err != nil {
+		return nil, err
+	}
+	keywords, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, ErrInvalidSchemaExtension
+	}
+
+	original, err := genericSchema(s)
+	if err != nil {
+		return nil, err
+	}
+	if originalKeywords, ok := original.(map[string]interface{}); ok {
+		for _, keyword := range metadataKeywords {
+			if _, overridden := keywords[keyword]; !overridden {
+				if value, ok := originalKeywords[keyword]; ok {
+					keywords[keyword] = value
+				}
+			}
+		}
+	}
+
+	// The extension is identified by the URI of s while it is compiled, so that its relative
+	// references resolve like those of s; the $id is not kept, as it belongs to s.
+	_, identified := keywords["$id"]
+	if !identified && s.uri != "" {
+		keywords["$id"] = s.uri
+	}
+	data, err := json.Marshal(keywords)
+	if err != nil {
+		return nil, err
+	}
+	extended, err := newSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	compiler := s.compiler
+	if compiler == nil {
+		compiler = NewCompiler()
+	}
+	extended.initializeSchema(compiler, nil)
+	if err := compiler.compileKeywords(extended); err != nil {
+		return nil, err
+	}
+	if !identified {
+		extended.ID = ""
+	}
+
+	extended.AllOf = append([]*Schema{s}, extended.AllOf...)
+	extended.assertFormat = s.assertFormat
+	extended.emailMode = s.emailMode
+	extended.transforms = s.transforms
+	return extended, nil
+}
@@ -0,0 +1,80 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtend(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{"$id": "https://example.com/region.json", "enum": ["eu", "us"]}`))
+	require.NoError(t, err)
+	contract, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/order.json",
+		"title": "Order",
+		"description": "An order of the shared contract.",
+		"type": "object",
+		"properties": {
+			"quantity": {"type": "integer", "minimum": 1, "default": 1},
+			"region": {"$ref": "region.json"}
+		},
+		"required": ["quantity"]
+	}`))
+	require.NoError(t, err)
+	original, err := json.Marshal(contract)
+	require.NoError(t, err)
+
+	tenant, err := contract.Extend([]byte(`{
+		"title": "Acme order",
+		"properties": {
+			"quantity": {"maximum": 10, "default": 5},
+			"region": {"$ref": "region.json", "const": "eu"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Acme order", *tenant.Title)
+	assert.Equal(t, "An order of the shared contract.", *tenant.Description)
+	assert.True(t, tenant.Validate(map[string]interface{}{"quantity": 3, "region": "eu"}).IsValid())
+	assert.False(t, tenant.Validate(map[string]interface{}{"quantity": 11}).IsValid(), "the extension adds constraints")
+	assert.False(t, tenant.Validate(map[string]interface{}{"quantity": 0}).IsValid(), "the constraints of the original apply")
+	assert.False(t, tenant.Validate(map[string]interface{}{"quantity": 1, "region": "us"}).IsValid())
+	assert.False(t, tenant.Validate(map[string]interface{}{"region": "eu"}).IsValid())
+
+	var order struct {
+		Quantity int `json:"quantity"`
+	}
+	require.NoError(t, tenant.UnmarshalWithDefaults([]byte(`{}`), &order))
+	assert.Equal(t, 5, order.Quantity, "the defaults of the extension take precedence")
+	require.NoError(t, contract.UnmarshalWithDefaults([]byte(`{}`), &order))
+	assert.Equal(t, 1, order.Quantity)
+
+	unchanged, err := json.Marshal(contract)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(original), string(unchanged))
+	assert.True(t, contract.Validate(map[string]interface{}{"quantity": 11, "region": "us"}).IsValid())
+	cached, err := compiler.GetSchema("https://example.com/order.json")
+	require.NoError(t, err)
+	assert.Same(t, contract, cached)
+
+	assert.Empty(t, tenant.ID, "the extension does not claim the URI of the original")
+}
+
+func TestExtendInvalid(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"type": "string"}`))
+	require.NoError(t, err)
+
+	_, err = schema.Extend([]byte(`[]`))
+	require.ErrorIs(t, err, ErrInvalidSchemaExtension)
+	_, err = schema.Extend([]byte(`{"type": `))
+	require.Error(t, err)
+
+	extended, err := schema.Extend([]byte(`{"maxLength": 3}`))
+	require.NoError(t, err)
+	assert.True(t, extended.Validate("abc").IsValid())
+	assert.False(t, extended.Validate("abcd").IsValid())
+	assert.False(t, extended.Validate(1).IsValid())
+}
@@ -0,0 +1,193 @@
+package jsonschema
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Extract returns a self-contained schema document for the subschema of s at ref, such as
+// "#/$defs/Address" or "#address", for publishing a single model out of a large bundle.
+//
+// The document holds the subschema together with only the definitions it references, directly
+// or transitively. References to schemas of the same document are rewritten to point into the
+// $defs of the extracted document; referenced schemas are added under the last token of their
+// JSON Pointer. Relative references to other documents are made absolute, and the $schema of
+// the root is kept. Like Normalize, the document is compiled with the compiler of s but is not
+// registered with it.
+func (s *Schema) Extract(ref string) (*Schema, error) {
+	root := s.getRootSchema()
+	index := map[*Schema]string{}
+	byPointer := map[string]*Schema{}
+	walkSchema(root, func(pointer string, schema *Schema) bool {
+		if _, ok := index[schema]; !ok {
+			index[schema] = pointer
+		}
+		byPointer[pointer] = schema
+		return true
+	})
+
+	fragment := strings.TrimPrefix(ref, "#")
+	target, ok := byPointer[fragment]
+	if !ok && fragment != "" && !strings.HasPrefix(fragment, "/") {
+		target, ok = root.anchors[fragment]
+	}
+	if !ok || target == nil {
+		return nil, ErrSubschemaNotFound
+	}
+
+	node, err := genericSchema(target)
+	if err != nil {
+		return nil, err
+	}
+	document, ok := node.(map[string]interface{})
+	if !ok {
+		return s.compileDocument(node) // Boolean schema.
+	}
+	e := &extraction{index: index, byPointer: byPointer, names: map[string]bool{}}
+	if defs, ok := document["$defs"].(map[string]interface{}); ok {
+		for name := range defs {
+			e.names[name] = true
+		}
+	}
+
+	// Rewrite the references of the subschema, then of every definition added on the way.
+	e.included = []extractedSchema{{pointer: index[target], name: "", node: document}}
+	defs := map[string]interface{}{}
+	for i := 0; i < len(e.included); i++ {
+		included := e.included[i]
+		if err := e.rewrite(included); err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			defs[included.name] = included.node
+		}
+	}
+	if len(defs) > 0 {
+		existing, _ := document["$defs"].(map[string]interface{})
+		if existing == nil {
+			existing = map[string]interface{}{}
+			document["$defs"] = existing
+		}
+		for name, def := range defs {
+			existing[name] = def
+		}
+	}
+	if _, ok := document["$schema"]; !ok && root.Schema != "" {
+		document["$schema"] = root.Schema
+	}
+
+	return s.compileDocument(document)
+}
+
+// extractedSchema is a schema of the source document copied into the extracted document.
+type extractedSchema struct {
+	pointer string      // JSON Pointer of the schema in the source document.
+	name    string      // Name under $defs in the extracted document, empty for the extracted subschema.
+	node    interface{} // Generic JSON form of the copy.
+}
+
+// extraction builds the document returned by Extract.
+type extraction struct {
+	index     map[*Schema]string // JSON Pointer of every schema of the source document.
+	byPointer map[string]*Schema
+	included  []extractedSchema
+	names     map[string]bool // Names taken under $defs.
+}
+
+// rewrite points the references of an included schema into the extracted document, including
+// the schemas they refer to on the way.
+func (e *extraction) rewrite(included extractedSchema) error {
+	source := e.byPointer[included.pointer]
+	var err error
+	walkSchema(source, func(pointer string, schema *Schema) bool {
+		if err != nil || schema.Boolean != nil {
+			return false
+		}
+		node, ok := genericNode(included.node, pointer).(map[string]interface{})
+		if !ok {
+			return true
+		}
+
+		if schema.ResolvedDynamicRef != nil {
+			if _, inDocument := e.index[schema.ResolvedDynamicRef]; inDocument {
+				// The dynamic anchor must stay reachable; the reference itself is resolved by name.
+				if _, err = e.locate(schema.ResolvedDynamicRef); err != nil {
+					return false
+				}
+			}
+		}
+		if schema.ResolvedRef == nil {
+			return true
+		}
+		if _, inDocument := e.index[schema.ResolvedRef]; !inDocument {
+			if !isAbsoluteURI(schema.Ref) && schema.baseURI != "" {
+				node["$ref"] = resolveRelativeURI(schema.baseURI, schema.Ref)
+			}
+			return true
+		}
+
+		var location string
+		if location, err = e.locate(schema.ResolvedRef); err != nil {
+			return false
+		}
+		node["$ref"] = location
+		return true
+	})
+	return err
+}
+
+// locate returns the reference to schema in the extracted document, copying it under $defs if
+// neither the extracted subschema nor an included definition contains it.
+func (e *extraction) locate(schema *Schema) (string, error) {
+	pointer := e.index[schema]
+	for _, included := range e.included {
+		if pointer == included.pointer || strings.HasPrefix(pointer, included.pointer+"/") {
+			rest := strings.TrimPrefix(pointer, included.pointer)
+			if included.name == "" {
+				return "#" + rest, nil
+			}
+			return "#/$defs/" + escapeJSONPointer(included.name) + rest, nil
+		}
+	}
+
+	node, err := genericSchema(schema)
+	if err != nil {
+		return "", err
+	}
+	base := unescapeJSONPointer(pointer[strings.LastIndex(pointer, "/")+1:])
+	name := base
+	for i := 2; e.names[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	e.names[name] = true
+	e.included = append(e.included, extractedSchema{pointer: pointer, name: name, node: node})
+	return "#/$defs/" + escapeJSONPointer(name), nil
+}
+
+// genericNode returns the value at a JSON Pointer in a generic JSON document, or nil.
+func genericNode(document interface{}, pointer string) interface{} {
+	if pointer == "" {
+		return document
+	}
+	node := document
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		switch value := node.(type) {
+		case map[string]interface{}:
+			node = value[unescapeJSONPointer(token)]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(value) {
+				return nil
+			}
+			node = value[i]
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+// unescapeJSONPointer decodes a JSON Pointer reference token.
+func unescapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
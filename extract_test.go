@@ -0,0 +1,91 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const extractBundle = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://example.com/bundle.json",
+	"$defs": {
+		"Country": {"type": "string", "minLength": 2, "maxLength": 2},
+		"Street": {"type": "string"},
+		"Address": {
+			"$anchor": "address",
+			"type": "object",
+			"properties": {
+				"street": {"$ref": "#/$defs/Street"},
+				"country": {"$ref": "#/$defs/Country"},
+				"next": {"$ref": "#/$defs/Address"},
+				"owner": {"$ref": "people.json#/$defs/Person"}
+			}
+		},
+		"Order": {"type": "object", "properties": {"shipTo": {"$ref": "#address"}}},
+		"Unused": {"type": "integer"}
+	}
+}`
+
+func TestExtract(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{"$id": "https://example.com/people.json", "$defs": {"Person": {"type": "string"}}}`))
+	require.NoError(t, err)
+	bundle, err := compiler.Compile([]byte(extractBundle))
+	require.NoError(t, err)
+
+	address, err := bundle.Extract("#/$defs/Address")
+	require.NoError(t, err)
+	data, err := json.Marshal(address)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$anchor": "address",
+		"$defs": {
+			"Country": {"type": "string", "minLength": 2, "maxLength": 2},
+			"Street": {"type": "string"}
+		},
+		"type": "object",
+		"properties": {
+			"street": {"$ref": "#/$defs/Street"},
+			"country": {"$ref": "#/$defs/Country"},
+			"next": {"$ref": "#"},
+			"owner": {"$ref": "https://example.com/people.json#/$defs/Person"}
+		}
+	}`, string(data))
+
+	assert.True(t, address.Validate(map[string]interface{}{"country": "DE", "next": map[string]interface{}{"owner": "Ann"}}).IsValid())
+	assert.False(t, address.Validate(map[string]interface{}{"next": map[string]interface{}{"country": "DEU"}}).IsValid())
+}
+
+func TestExtractTransitive(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{"$id": "https://example.com/people.json", "$defs": {"Person": {"type": "string"}}}`))
+	require.NoError(t, err)
+	bundle, err := compiler.Compile([]byte(extractBundle))
+	require.NoError(t, err)
+
+	order, err := bundle.Extract("#/$defs/Order")
+	require.NoError(t, err)
+	require.Contains(t, order.Defs, "Address")
+	assert.ElementsMatch(t, []string{"Address", "Country", "Street"}, keysOf(order.Defs))
+	assert.Equal(t, "#/$defs/Address", (*order.Properties)["shipTo"].Ref)
+	assert.Equal(t, "#/$defs/Address", (*order.Defs["Address"].Properties)["next"].Ref)
+
+	byAnchor, err := bundle.Extract("#address")
+	require.NoError(t, err)
+	assert.Equal(t, "#/$defs/Street", (*byAnchor.Properties)["street"].Ref)
+
+	_, err = bundle.Extract("#/$defs/Missing")
+	assert.ErrorIs(t, err, ErrSubschemaNotFound)
+}
+
+func keysOf(defs map[string]*Schema) []string {
+	keys := make([]string, 0, len(defs))
+	for key := range defs {
+		keys = append(keys, key)
+	}
+	return keys
+}
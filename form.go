@@ -0,0 +1,216 @@
+package jsonschema
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// jsonNumberPattern matches the strings that are JSON numbers.
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// unmarshalFormURLEncoded is the handler of the application/x-www-form-urlencoded media type.
+func unmarshalFormURLEncoded(data []byte) (interface{}, error) {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return FormValuesInstance(values), nil
+}
+
+// FormValuesInstance maps form values, such as those of an application/x-www-form-urlencoded
+// body, to an object instance following the conventions of HTML forms:
+//
+//   - a[b]=1 sets the property b of the object a, and brackets can be nested, as in a[b][c]=1;
+//   - a[]=1&a[]=2 appends to the array a;
+//   - a repeated key, as in a=1&a=2, becomes an array, and a single one a string.
+//
+// Values are strings; validating the instance through a schema coerces them to the types the
+// schema expects.
+func FormValuesInstance(values url.Values) map[string]interface{} {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	instance := make(map[string]interface{}, len(values))
+	for _, key := range keys {
+		path := formKeyPath(key)
+		for _, value := range values[key] {
+			setFormValue(instance, path, value)
+		}
+	}
+	return instance
+}
+
+// formKeyPath splits a form key such as a[b][] into its segments a, b and "".
+func formKeyPath(key string) []string {
+	open := strings.IndexByte(key, '[')
+	if open <= 0 || !strings.HasSuffix(key, "]") {
+		return []string{key}
+	}
+
+	path := []string{key[:open]}
+	for _, segment := range strings.Split(key[open+1:len(key)-1], "][") {
+		if strings.ContainsAny(segment, "[]") {
+			return []string{key} // Not the bracket convention, keep the key as is.
+		}
+		path = append(path, segment)
+	}
+	return path
+}
+
+// setFormValue sets value at path in object. An empty segment appends to an array, and setting a
+// value twice at the same path turns it into an array.
+func setFormValue(object map[string]interface{}, path []string, value string) {
+	name := path[0]
+	if len(path) == 1 {
+		switch existing := object[name].(type) {
+		case nil:
+			object[name] = value
+		case []interface{}:
+			object[name] = append(existing, value)
+		case string:
+			object[name] = []interface{}{existing, value}
+		}
+		return
+	}
+
+	if path[1] == "" {
+		items, _ := object[name].([]interface{})
+		if object[name] != nil && items == nil {
+			return // Conflicts with a value set without brackets.
+		}
+		if len(path) == 2 {
+			object[name] = append(items, value)
+			return
+		}
+		child := map[string]interface{}{}
+		setFormValue(child, path[2:], value)
+		object[name] = append(items, child)
+		return
+	}
+
+	child, ok := object[name].(map[string]interface{})
+	if !ok {
+		if object[name] != nil {
+			return // Conflicts with a value set without brackets.
+		}
+		child = map[string]interface{}{}
+		object[name] = child
+	}
+	setFormValue(child, path[1:], value)
+}
+
+// coerceFormInstance converts the strings of a form instance to the types s expects: numbers,
+// integers, booleans (true, false and the on of checkboxes) and null (the empty string), and
+// wraps single values where s expects an array. Values that cannot be converted are left as is,
+// for validation to report them.
+func (s *Schema) coerceFormInstance(instance interface{}) interface{} {
+	return coerceFormValue(expandSchemas([]*Schema{s}), instance)
+}
+
+// coerceFormValue converts value according to schemas, which all apply to it.
+func coerceFormValue(schemas []*Schema, value interface{}) interface{} {
+	types := map[string]bool{}
+	for _, schema := range schemas {
+		for _, t := range schema.Type {
+			types[t] = true
+		}
+	}
+
+	if _, isArray := value.([]interface{}); types["array"] && !isArray && value != nil {
+		value = []interface{}{value}
+	}
+
+	switch v := value.(type) {
+	case string:
+		if len(types) == 0 || types["string"] {
+			return v
+		}
+		switch {
+		case (types["number"] || types["integer"]) && jsonNumberPattern.MatchString(v):
+			return json.Number(v)
+		case types["boolean"] && (v == "true" || v == "on"):
+			return true
+		case types["boolean"] && v == "false":
+			return false
+		case types["null"] && v == "":
+			return nil
+		}
+	case []interface{}:
+		for i, item := range v {
+			var itemSchemas []*Schema
+			for _, schema := range schemas {
+				if i < len(schema.PrefixItems) {
+					itemSchemas = append(itemSchemas, schema.PrefixItems[i])
+				} else if schema.Items != nil {
+					itemSchemas = append(itemSchemas, schema.Items)
+				}
+			}
+			v[i] = coerceFormValue(expandSchemas(itemSchemas), item)
+		}
+	case map[string]interface{}:
+		for name, property := range v {
+			var propertySchemas []*Schema
+			for _, schema := range schemas {
+				propertySchemas = append(propertySchemas, formPropertySchemas(schema, name)...)
+			}
+			v[name] = coerceFormValue(expandSchemas(propertySchemas), property)
+		}
+	}
+	return value
+}
+
+// formPropertySchemas returns the subschemas of s that apply to the property name.
+func formPropertySchemas(s *Schema, name string) []*Schema {
+	var schemas []*Schema
+	if s.Properties != nil {
+		if schema, ok := (*s.Properties)[name]; ok {
+			schemas = append(schemas, schema)
+		}
+	}
+	if s.PatternProperties != nil {
+		for pattern, schema := range *s.PatternProperties {
+			if regex, err := s.regex(pattern); err == nil && regex.MatchString(name) {
+				schemas = append(schemas, schema)
+			}
+		}
+	}
+	if len(schemas) == 0 && s.AdditionalProperties != nil {
+		schemas = append(schemas, s.AdditionalProperties)
+	}
+	return schemas
+}
+
+// expandSchemas returns schemas together with the schemas they apply through $ref and allOf.
+func expandSchemas(schemas []*Schema) []*Schema {
+	var expanded []*Schema
+	seen := map[*Schema]bool{}
+	var expand func(schema *Schema)
+	expand = func(schema *Schema) {
+		if schema == nil || schema.Boolean != nil || seen[schema] {
+			return
+		}
+		seen[schema] = true
+		expanded = append(expanded, schema)
+		expand(schema.ResolvedRef)
+		for _, member := range schema.AllOf {
+			expand(member)
+		}
+	}
+	for _, schema := range schemas {
+		expand(schema)
+	}
+	return expanded
+}
+
+// isFormMediaType reports whether mediaType is one of the form media types, whose values are
+// strings coerced according to the content schema.
+func isFormMediaType(mediaType *string) bool {
+	return mediaType != nil && (*mediaType == "application/x-www-form-urlencoded" || *mediaType == "multipart/form-data")
+}
@@ -0,0 +1,33 @@
+//go:build !jsonschema_core
+
+package jsonschema
+
+import (
+	"net/http"
+	"strings"
+)
+
+// defaultMaxFormMemory is the number of bytes of a request form kept in memory by ValidateForm,
+// the rest of the files being stored in temporary files, as with http.Request.FormValue.
+const defaultMaxFormMemory = 32 << 20
+
+// ValidateForm parses the form of a request, multipart/form-data or
+// application/x-www-form-urlencoded, and validates it as an object instance built by
+// FormInstance, with its strings coerced to the types the schema expects, such as numbers and
+// booleans. Query parameters are not part of the instance. The error reports a form that cannot
+// be parsed.
+func (s *Schema) ValidateForm(r *http.Request) (*EvaluationResult, error) {
+	var instance map[string]interface{}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(defaultMaxFormMemory); err != nil {
+			return nil, err
+		}
+		instance = FormInstance(r.MultipartForm.Value, r.MultipartForm.File)
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		instance = FormValuesInstance(r.PostForm)
+	}
+	return s.Validate(s.coerceFormInstance(instance)), nil
+}
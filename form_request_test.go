@@ -0,0 +1,54 @@
+//go:build !jsonschema_core
+
+package jsonschema
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateForm(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(uploadSchema))
+	require.NoError(t, err)
+
+	body, contentType := multipartBody(t, map[string][]string{"title": {"Logo"}, "tags": {"a", "b"}}, "image/png", make([]byte, 100))
+	request := httptest.NewRequest("POST", "/upload", body)
+	request.Header.Set("Content-Type", contentType)
+	result, err := schema.ValidateForm(request)
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+
+	body, contentType = multipartBody(t, map[string][]string{"title": {"Logo"}}, "image/gif", make([]byte, 2048))
+	request = httptest.NewRequest("POST", "/upload", body)
+	request.Header.Set("Content-Type", contentType)
+	result, err = schema.ValidateForm(request)
+	require.NoError(t, err)
+	assert.False(t, result.IsValid())
+
+	request = httptest.NewRequest("POST", "/upload", strings.NewReader("title=Logo"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	result, err = schema.ValidateForm(request)
+	require.NoError(t, err)
+	assert.False(t, result.IsValid(), "image is required")
+}
+
+func TestValidateFormURLEncoded(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(orderSchema))
+	require.NoError(t, err)
+
+	request := httptest.NewRequest("POST", "/orders", strings.NewReader("quantity=two"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	result, err := schema.ValidateForm(request)
+	require.NoError(t, err)
+	assert.False(t, result.IsValid(), "values that cannot be coerced are reported")
+
+	request = httptest.NewRequest("POST", "/orders", strings.NewReader("quantity=3&gift=true"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	result, err = schema.ValidateForm(request)
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+}
@@ -0,0 +1,79 @@
+package jsonschema
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormValuesInstance(t *testing.T) {
+	values, err := url.ParseQuery("name=John&tags=a&tags=b&address[city]=Oslo&address[geo][lat]=59.9&ids[]=1&ids[]=2&items[][sku]=x&weird]=1")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"name": "John",
+		"tags": []interface{}{"a", "b"},
+		"address": map[string]interface{}{
+			"city": "Oslo",
+			"geo":  map[string]interface{}{"lat": "59.9"},
+		},
+		"ids":    []interface{}{"1", "2"},
+		"items":  []interface{}{map[string]interface{}{"sku": "x"}},
+		"weird]": "1",
+	}, FormValuesInstance(values))
+}
+
+// orderSchema describes an order form whose values need coercion.
+const orderSchema = `{
+	"type": "object",
+	"properties": {
+		"quantity": {"type": "integer", "minimum": 1},
+		"price": {"$ref": "#/$defs/price"},
+		"gift": {"type": "boolean"},
+		"note": {"type": ["string", "null"]},
+		"coupon": {"type": ["null", "integer"]},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"sizes": {"type": "array", "items": {"type": "number"}},
+		"shipping": {
+			"type": "object",
+			"properties": {"express": {"type": "boolean"}}
+		}
+	},
+	"required": ["quantity"],
+	"$defs": {"price": {"type": "number"}}
+}`
+
+func TestFormCoercion(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(orderSchema))
+	require.NoError(t, err)
+
+	values, err := url.ParseQuery("quantity=2&price=9.50&gift=on&note=&coupon=&tags=red&sizes[]=1&sizes[]=1.5&shipping[express]=false")
+	require.NoError(t, err)
+	instance := schema.coerceFormInstance(FormValuesInstance(values))
+	assert.Equal(t, map[string]interface{}{
+		"quantity": json.Number("2"),
+		"price":    json.Number("9.50"),
+		"gift":     true,
+		"note":     "",
+		"coupon":   nil,
+		"tags":     []interface{}{"red"},
+		"sizes":    []interface{}{json.Number("1"), json.Number("1.5")},
+		"shipping": map[string]interface{}{"express": false},
+	}, instance)
+	assert.True(t, schema.Validate(instance).IsValid())
+}
+
+func TestFormURLEncodedMediaType(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"contentMediaType": "application/x-www-form-urlencoded",
+		"contentSchema": ` + orderSchema + `
+	}`))
+	require.NoError(t, err)
+
+	assert.True(t, schema.Validate("quantity=1&shipping[express]=true").IsValid())
+	assert.False(t, schema.Validate("quantity=0").IsValid())
+	assert.False(t, schema.Validate("quantity=1&shipping[express]=maybe").IsValid())
+}
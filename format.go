@@ -1,5 +1,10 @@
 package jsonschema
 
+import (
+	"net/url"
+	"strings"
+)
+
 // EvaluateFormat checks if the data conforms to the format specified in the schema.
 // According to the JSON Schema Draft 2020-12:
 //   - The "format" keyword defines the data format expected for a value.
@@ -11,14 +16,14 @@ package jsonschema
 // It handles formats as annotations by default, but can assert format validation if configured.
 //
 // Reference: https://json-schema.org/draft/2020-12/json-schema-validation#name-format
-func evaluateFormat(schema *Schema, value interface{}) *EvaluationError {
+func evaluateFormat(schema *Schema, value interface{}, dynamicScope *DynamicScope) *EvaluationError {
 	if schema.Format == nil {
 		return nil // No format to validate against.
 	}
 
 	formatFunc, exists := Formats[*schema.Format]
 	if !exists {
-		if schema.compiler != nil && schema.compiler.AssertFormat {
+		if assertsFormatIn(schema, dynamicScope) {
 			// If the format is not recognized, the behavior depends on the implementation
 			// configurations: it can ignore the unknown format (annotation behavior) or
 			// consider it an error (assertion behavior).
@@ -26,11 +31,34 @@ func evaluateFormat(schema *Schema, value interface{}) *EvaluationError {
 				"format": *schema.Format,
 			})
 		}
+		return nil
+	}
+
+	switch *schema.Format {
+	case "email":
+		if emailFunc := emailFormat(schema); emailFunc != nil {
+			formatFunc = emailFunc
+		}
+	case "hostname":
+		if schema.compiler != nil && schema.compiler.UnicodeHostnames {
+			formatFunc = IsIDNHostname
+		}
+	case "regex":
+		if schema.compiler != nil && schema.compiler.RegexEngine != nil {
+			formatFunc = isRegexWith(schema.compiler.RegexEngine)
+		}
+	case "uri", "iri", "uri-reference", "iri-reference":
+		if schema.compiler != nil && len(schema.compiler.URISchemes) > 0 {
+			uriFunc := formatFunc
+			formatFunc = func(v interface{}) bool {
+				return uriFunc(v) && hasAllowedScheme(v, schema.compiler.URISchemes)
+			}
+		}
 	}
 
 	// Execute the format validation function
-	if !formatFunc(value) {
-		if schema.compiler != nil && schema.compiler.AssertFormat {
+	if !formatFunc(value) && !isLocalTime(schema, value, formatFunc) || !inUTC(schema, value) {
+		if assertsFormatIn(schema, dynamicScope) {
 			return NewEvaluationError("format", "format_mismatch", "Value does not match format {format}", map[string]interface{}{
 				"format": *schema.Format,
 			})
@@ -39,3 +67,147 @@ func evaluateFormat(schema *Schema, value interface{}) *EvaluationError {
 
 	return nil
 }
+
+// timezoneFormats are the formats whose time zone is subject to the TimezonePolicy of the compiler.
+var timezoneFormats = map[string]bool{"date-time": true, "time": true}
+
+// isLocalTime reports whether value is a time without offset that formatFunc accepts with one,
+// when the compiler of schema allows local times.
+func isLocalTime(schema *Schema, value interface{}, formatFunc func(interface{}) bool) bool {
+	s, ok := value.(string)
+	if !ok || !timezoneFormats[*schema.Format] || schema.compiler == nil || schema.compiler.Timezones != TimezoneAllowLocal {
+		return false
+	}
+	return !hasTimezone(s) && formatFunc(s+"Z")
+}
+
+// inUTC reports whether value is written in UTC, or whether the compiler of schema does not
+// require it.
+func inUTC(schema *Schema, value interface{}) bool {
+	s, ok := value.(string)
+	if !ok || !timezoneFormats[*schema.Format] || schema.compiler == nil || schema.compiler.Timezones != TimezoneRequireUTC {
+		return true
+	}
+	return strings.HasSuffix(s, "Z") || strings.HasSuffix(s, "z")
+}
+
+// hasTimezone reports whether the time s ends with Z or a numeric offset.
+func hasTimezone(s string) bool {
+	if strings.HasSuffix(s, "Z") || strings.HasSuffix(s, "z") {
+		return true
+	}
+	return len(s) >= 6 && (s[len(s)-6] == '+' || s[len(s)-6] == '-') && s[len(s)-3] == ':'
+}
+
+// hasAllowedScheme reports whether the URI reference v has no scheme or one of schemes.
+func hasAllowedScheme(v interface{}, schemes []string) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return true
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// Vocabularies that make format an assertion when a meta-schema declares them in $vocabulary.
+const (
+	formatAssertionVocabulary = "https://json-schema.org/draft/2020-12/vocab/format-assertion"
+	format201909Vocabulary    = "https://json-schema.org/draft/2019-09/vocab/format"
+)
+
+// SetAssertFormat enables or disables format assertion when validating against s, overriding the
+// compiler and the dialect, for example to enforce formats on one path while another schema of the
+// same compiler keeps them as annotations. Schemas referenced from s are evaluated with the same
+// setting; ValidateWithAssertFormat overrides it for a single call.
+//
+// The setting is stored in s, the schema the compiler cached, and therefore also applies where
+// other schemas reference s. It is not synchronized with validations: call it before s is used
+// by other goroutines, or use ValidateWithAssertFormat on schemas already in use.
+func (s *Schema) SetAssertFormat(assert bool) *Schema {
+	s.assertFormat = &assert
+	return s
+}
+
+// assertsFormatIn reports whether the format keyword of schema is an assertion in an evaluation,
+// honoring the override of ValidateWithAssertFormat or of the validated schema.
+func assertsFormatIn(schema *Schema, dynamicScope *DynamicScope) bool {
+	if dynamicScope != nil && dynamicScope.assertFormat != nil {
+		return *dynamicScope.assertFormat
+	}
+	return schema.assertsFormat()
+}
+
+// assertsFormat reports whether the format keyword of s is an assertion. An override set with
+// Schema.SetAssertFormat on s or an enclosing schema comes first, then Compiler.SetAssertFormat and
+// Compiler.AssertFormat; otherwise the dialect of the nearest $schema, or the default dialect,
+// decides, and format is only an annotation in the 2019-09 and 2020-12 dialects.
+func (s *Schema) assertsFormat() bool {
+	for schema := s; schema != nil; schema = schema.parent {
+		if schema.assertFormat != nil {
+			return *schema.assertFormat
+		}
+	}
+	if s.compiler == nil {
+		return false
+	}
+	if s.compiler.AssertFormat || s.compiler.formatOverride {
+		return s.compiler.AssertFormat
+	}
+	for schema := s; schema != nil; schema = schema.parent {
+		if schema.Schema != "" {
+			return schema.formatAssertion
+		}
+	}
+	if dialect := s.compiler.dialect(s.compiler.DefaultDialect); dialect != nil {
+		return dialect.AssertFormat
+	}
+	return false
+}
+
+// dialectAssertsFormat reports whether the meta-schema at dialect declares the format-assertion
+// vocabulary, or, for 2019-09 meta-schemas, requires the format vocabulary. The standard
+// meta-schemas published at json-schema.org are known not to; other meta-schemas are loaded like
+// references, and self is used when it describes itself.
+func (c *Compiler) dialectAssertsFormat(dialect string, self *Schema) bool {
+	if custom := c.dialect(dialect); custom != nil {
+		return custom.AssertFormat
+	}
+	dialect = strings.TrimSuffix(dialect, "#")
+	if strings.HasPrefix(dialect, "https://json-schema.org/") || strings.HasPrefix(dialect, "http://json-schema.org/") {
+		return false
+	}
+	if c.formatDialects == nil {
+		c.formatDialects = make(map[string]bool)
+	}
+	if asserts, ok := c.formatDialects[dialect]; ok {
+		return asserts
+	}
+	c.formatDialects[dialect] = false // Guards against meta-schemas describing each other.
+
+	meta := self
+	if self.ID != dialect && self.uri != dialect {
+		var err error
+		if meta, err = c.getSchema(dialect); err != nil {
+			return false
+		}
+	}
+
+	vocabularies, _ := meta.Extensions["$vocabulary"].(map[string]interface{})
+	_, asserts := vocabularies[formatAssertionVocabulary]
+	if required, ok := vocabularies[format201909Vocabulary].(bool); ok && required {
+		asserts = true
+	}
+	c.formatDialects[dialect] = asserts
+	return asserts
+}
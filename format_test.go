@@ -0,0 +1,140 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAssertionByDialect(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/format-assertion",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/core": true,
+			"https://json-schema.org/draft/2020-12/vocab/format-assertion": false
+		}
+	}`))
+	require.NoError(t, err)
+
+	annotated, err := compiler.Compile([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "format": "ipv4"}`))
+	require.NoError(t, err)
+	assert.True(t, annotated.Validate("not an address").IsValid())
+
+	asserted, err := compiler.Compile([]byte(`{"$schema": "https://example.com/format-assertion", "properties": {"ip": {"format": "ipv4"}}}`))
+	require.NoError(t, err)
+	assert.False(t, asserted.Validate(map[string]interface{}{"ip": "not an address"}).IsValid())
+	assert.True(t, asserted.Validate(map[string]interface{}{"ip": "127.0.0.1"}).IsValid())
+
+	unknown, err := compiler.Compile([]byte(`{"$schema": "https://example.com/format-assertion", "format": "color"}`))
+	require.NoError(t, err)
+	assert.False(t, unknown.Validate("anything").IsValid())
+}
+
+func TestFormatAssertionOverride(t *testing.T) {
+	compiler := NewCompiler().SetAssertFormat(false)
+	_, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/format-assertion",
+		"$vocabulary": {"https://json-schema.org/draft/2020-12/vocab/format-assertion": true}
+	}`))
+	require.NoError(t, err)
+	schema, err := compiler.Compile([]byte(`{"$schema": "https://example.com/format-assertion", "format": "ipv4"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("not an address").IsValid())
+
+	schema, err = NewCompiler().SetAssertFormat(true).Compile([]byte(`{"format": "ipv4"}`))
+	require.NoError(t, err)
+	assert.False(t, schema.Validate("not an address").IsValid())
+
+	schema, err = NewCompiler().Compile([]byte(`{"format": "color"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("anything").IsValid())
+}
+
+func TestSchemaAssertFormat(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{"$id": "https://example.com/ip", "format": "ipv4"}`))
+	require.NoError(t, err)
+	ingestion, err := compiler.Compile([]byte(`{"properties": {"ip": {"$ref": "https://example.com/ip"}}}`))
+	require.NoError(t, err)
+	legacy, err := compiler.Compile([]byte(`{"$id": "https://example.com/legacy", "format": "ipv4"}`))
+	require.NoError(t, err)
+
+	instance := map[string]interface{}{"ip": "not an address"}
+	assert.True(t, ingestion.Validate(instance).IsValid())
+
+	ingestion.SetAssertFormat(true)
+	assert.False(t, ingestion.Validate(instance).IsValid())
+	assert.True(t, ingestion.ValidateWithAssertFormat(instance, false).IsValid())
+	assert.True(t, legacy.Validate("not an address").IsValid())
+	assert.False(t, legacy.ValidateWithAssertFormat("not an address", true).IsValid())
+}
+
+func TestTimezonePolicy(t *testing.T) {
+	values := []string{
+		"2024-03-01T10:00:00Z",
+		"2024-03-01T10:00:00.5+02:00",
+		"2024-03-01T10:00:00",
+		"10:00:00z",
+		"10:00:00-05:00",
+		"10:00:00.25",
+		"2024-03-01T25:00:00",
+	}
+	tests := []struct {
+		policy TimezonePolicy
+		valid  []bool
+	}{
+		{TimezoneRequireOffset, []bool{true, true, false, true, true, false, false}},
+		{TimezoneRequireUTC, []bool{true, false, false, true, false, false, false}},
+		{TimezoneAllowLocal, []bool{true, true, true, true, true, true, false}},
+	}
+
+	for _, tt := range tests {
+		compiler := NewCompiler().SetAssertFormat(true).SetTimezonePolicy(tt.policy)
+		dateTime, err := compiler.Compile([]byte(`{"format": "date-time"}`))
+		require.NoError(t, err)
+		timeOnly, err := compiler.Compile([]byte(`{"format": "time"}`))
+		require.NoError(t, err)
+
+		for i, value := range values {
+			schema := dateTime
+			if len(value) < 19 {
+				schema = timeOnly
+			}
+			assert.Equal(t, tt.valid[i], schema.Validate(value).IsValid(), "policy %d, %s", tt.policy, value)
+		}
+	}
+
+	// Other formats and annotations are not affected.
+	compiler := NewCompiler().SetTimezonePolicy(TimezoneRequireUTC)
+	schema, err := compiler.Compile([]byte(`{"format": "date-time"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("2024-03-01T10:00:00+02:00").IsValid())
+	schema, err = compiler.SetAssertFormat(true).Compile([]byte(`{"format": "date"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("2024-03-01").IsValid())
+}
+
+func TestURISchemes(t *testing.T) {
+	compiler := NewCompiler().SetAssertFormat(true).SetURISchemes("https", "mailto")
+	uri, err := compiler.Compile([]byte(`{"format": "uri"}`))
+	require.NoError(t, err)
+	reference, err := compiler.Compile([]byte(`{"format": "uri-reference"}`))
+	require.NoError(t, err)
+
+	assert.True(t, uri.Validate("https://example.com/a").IsValid())
+	assert.True(t, uri.Validate("HTTPS://example.com/a").IsValid())
+	assert.True(t, uri.Validate("mailto:joe@example.com").IsValid())
+	assert.False(t, uri.Validate("javascript:alert(1)").IsValid())
+	assert.False(t, uri.Validate("file:///etc/passwd").IsValid())
+	assert.False(t, uri.Validate("http://example.com").IsValid())
+
+	assert.True(t, reference.Validate("/relative/path").IsValid())
+	assert.True(t, reference.Validate("https://example.com").IsValid())
+	assert.False(t, reference.Validate("JavaScript:alert(1)").IsValid())
+
+	compiler.SetURISchemes()
+	assert.True(t, uri.Validate("file:///etc/passwd").IsValid())
+}
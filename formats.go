@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Formats is a registry of functions, which know how to validate
@@ -23,6 +24,7 @@ var Formats = map[string]func(interface{}) bool{
 	"duration":              IsDuration,
 	"period":                IsPeriod,
 	"hostname":              IsHostname,
+	"idn-hostname":          IsIDNHostname,
 	"email":                 IsEmail,
 	"ip-address":            IsIPV4,
 	"ipv4":                  IsIPV4,
@@ -268,45 +270,13 @@ func IsHostname(v interface{}) bool {
 	if !ok {
 		return true
 	}
-	// entire hostname (including the delimiting dots but not a trailing dot) has a maximum of 253 ASCII characters
-	s = strings.TrimSuffix(s, ".")
-	if len(s) > 253 {
-		return false
-	}
-
-	// Hostnames are composed of series of labels concatenated with dots, as are all domain names
-	for _, label := range strings.Split(s, ".") {
-		// Each label must be from 1 to 63 characters long
-		if labelLen := len(label); labelLen < 1 || labelLen > 63 {
-			return false
-		}
-
-		// labels must not start with a hyphen
-		// RFC 1123 section 2.1: restriction on the first character
-		// is relaxed to allow either a letter or a digit
-		if first := s[0]; first == '-' {
-			return false
-		}
-
-		// must not end with a hyphen
-		if label[len(label)-1] == '-' {
-			return false
-		}
-
-		// labels may contain only the ASCII letters 'a' through 'z' (in a case-insensitive manner),
-		// the digits '0' through '9', and the hyphen ('-')
-		for _, c := range label {
-			if valid := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || (c == '-'); !valid {
-				return false
-			}
-		}
-	}
-
-	return true
+	_, ok = hostnameToASCII(s, false)
+	return ok
 }
 
 // IsEmail tells whether given string is a valid Internet email address
-// as defined by RFC 5322, section 3.4.1.
+// as defined by RFC 5322, section 3.4.1, restricted to ASCII as RFC 5321 requires.
+// Internationalized addresses are checked by IsInternationalEmail.
 //
 // See https://en.wikipedia.org/wiki/Email_address, for details.
 func IsEmail(v interface{}) bool {
@@ -314,6 +284,11 @@ func IsEmail(v interface{}) bool {
 	if !ok {
 		return true
 	}
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
 	// entire email address to be no more than 254 characters long
 	if len(s) > 254 {
 		return false
@@ -0,0 +1,126 @@
+package jsonschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// FuzzSeed is a schema and instance pair used to seed FuzzValidate.
+type FuzzSeed struct {
+	Schema   []byte
+	Instance []byte
+}
+
+// FuzzCompile compiles data as a schema and exercises the parser and the reference resolver.
+// It is meant to be called from a native Go fuzz target. Remote references are never fetched.
+//
+// The return value follows the go-fuzz convention: 1 if the input compiled, 0 otherwise.
+// Panics are not recovered so the fuzzer can report them.
+func FuzzCompile(data []byte) int {
+	schema, err := newFuzzCompiler().Compile(data)
+	if err != nil {
+		return 0
+	}
+
+	if _, err := json.Marshal(schema); err != nil {
+		return 0
+	}
+	return 1
+}
+
+// FuzzValidate compiles schemaData, decodes instanceData as JSON and validates the instance,
+// exercising the evaluator and every output format. Remote references are never fetched.
+//
+// The return value follows the go-fuzz convention: 1 if both inputs were usable, 0 otherwise.
+// Panics are not recovered so the fuzzer can report them.
+func FuzzValidate(schemaData, instanceData []byte) int {
+	schema, err := newFuzzCompiler().Compile(schemaData)
+	if err != nil {
+		return 0
+	}
+
+	var instance interface{}
+	if err := json.Unmarshal(instanceData, &instance); err != nil {
+		return 0
+	}
+
+	result := schema.Validate(instance)
+	result.ToFlag()
+	result.ToList()
+	result.ToList(false)
+	return 1
+}
+
+// newFuzzCompiler returns a compiler without loaders so fuzzing never touches the network.
+func newFuzzCompiler() *Compiler {
+	compiler := NewCompiler()
+	compiler.Loaders = make(map[string]func(url string) (io.ReadCloser, error))
+	return compiler
+}
+
+// FuzzSeeds derives a seed corpus for FuzzValidate from the compiled schema.
+// Every seed pairs the schema document with an instance taken from the schema and its
+// subschemas: examples, defaults, const and enum values, plus a few values of each JSON type.
+func (s *Schema) FuzzSeeds() []FuzzSeed {
+	schemaData, err := json.Marshal(s)
+	if err != nil {
+		return nil
+	}
+
+	instances := []interface{}{nil, true, 0, 1.5, "", map[string]interface{}{}, []interface{}{}}
+	walkSchema(s, func(_ string, schema *Schema) bool {
+		instances = append(instances, schema.Examples...)
+		instances = append(instances, schema.Enum...)
+		if schema.Default != nil {
+			instances = append(instances, schema.Default)
+		}
+		if schema.Const != nil {
+			instances = append(instances, schema.Const.Value)
+		}
+		return true
+	})
+
+	seen := make(map[string]bool)
+	seeds := make([]FuzzSeed, 0, len(instances))
+	for _, instance := range instances {
+		instanceData, err := json.Marshal(instance)
+		if err != nil || seen[string(instanceData)] {
+			continue
+		}
+		seen[string(instanceData)] = true
+		seeds = append(seeds, FuzzSeed{Schema: schemaData, Instance: instanceData})
+	}
+
+	return seeds
+}
+
+// WriteFuzzCorpus writes seeds as corpus files for a fuzz target taking (schema, instance []byte)
+// arguments, using the file format of "go test -fuzz". Files are stored in dir, normally
+// testdata/fuzz/<FuzzTargetName>, and named after the hash of their content.
+func WriteFuzzCorpus(dir string, seeds []FuzzSeed) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	for _, seed := range seeds {
+		var content strings.Builder
+		content.WriteString("go test fuzz v1\n")
+		fmt.Fprintf(&content, "[]byte(%q)\n", seed.Schema)
+		fmt.Fprintf(&content, "[]byte(%q)\n", seed.Instance)
+
+		sum := sha256.Sum256([]byte(content.String()))
+		name := filepath.Join(dir, hex.EncodeToString(sum[:8]))
+		if err := os.WriteFile(name, []byte(content.String()), 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
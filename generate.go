@@ -0,0 +1,452 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxGenerateAttempts bounds the number of candidates Generate tries before giving up.
+const maxGenerateAttempts = 100
+
+// maxGenerateDepth bounds the nesting of generated objects and arrays. Below it only
+// required properties and the minimum number of items are generated.
+const maxGenerateDepth = 4
+
+// Generate returns a random instance that is valid against the schema, using r as the source
+// of randomness. Values have the same Go types as instances decoded from JSON: nil, bool,
+// float64, string, []interface{} and map[string]interface{}.
+//
+// Candidates are built from the schema's keywords (type, const, enum, examples, numeric and
+// length bounds, formats, properties, items and the applicators) and checked with Validate.
+// Keywords that cannot be generated directly, such as pattern or not, are only enforced by that
+// check, so a schema relying on them may exhaust the attempts and return ErrGenerationFailed.
+func (s *Schema) Generate(r *rand.Rand) (interface{}, error) {
+	g := &generator{rand: r}
+	for i := 0; i < maxGenerateAttempts; i++ {
+		value := g.value(s, 0)
+		if s.Validate(value).IsValid() {
+			return value, nil
+		}
+	}
+	return nil, ErrGenerationFailed
+}
+
+// QuickValues returns a function for the Values field of testing/quick's Config that fills every
+// argument of the checked function with an instance generated from the schema. Arguments are
+// expected to be of type interface{}. The function panics if no valid instance can be generated:
+//
+//	err := quick.Check(func(v interface{}) bool {
+//		return roundTrips(v)
+//	}, &quick.Config{Values: schema.QuickValues()})
+func (s *Schema) QuickValues() func(args []reflect.Value, r *rand.Rand) {
+	return func(args []reflect.Value, r *rand.Rand) {
+		for i := range args {
+			value, err := s.Generate(r)
+			if err != nil {
+				panic(fmt.Sprintf("jsonschema: %v", err))
+			}
+			arg := reflect.New(reflect.TypeOf((*interface{})(nil)).Elem()).Elem()
+			if value != nil {
+				arg.Set(reflect.ValueOf(value))
+			}
+			args[i] = arg
+		}
+	}
+}
+
+// generator builds candidate instances for a schema.
+type generator struct {
+	rand *rand.Rand
+}
+
+// value returns a candidate instance for s. The candidate is not guaranteed to be valid.
+func (g *generator) value(s *Schema, depth int) interface{} {
+	if s == nil {
+		return g.anyValue(depth)
+	}
+	if s.Boolean != nil {
+		if *s.Boolean {
+			return g.anyValue(depth)
+		}
+		return nil
+	}
+
+	if s.Const != nil && s.Const.IsSet {
+		return s.Const.Value
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[g.rand.Intn(len(s.Enum))]
+	}
+	if len(s.Examples) > 0 && (s.Pattern != nil || g.rand.Intn(4) == 0) {
+		return s.Examples[g.rand.Intn(len(s.Examples))]
+	}
+
+	if s.ResolvedRef != nil {
+		return g.value(s.ResolvedRef, depth)
+	}
+	if s.ResolvedDynamicRef != nil {
+		return g.value(s.ResolvedDynamicRef, depth)
+	}
+
+	if len(s.Type) == 0 {
+		if len(s.AllOf) > 0 {
+			return g.allOf(s.AllOf, depth)
+		}
+		if len(s.AnyOf) > 0 {
+			return g.value(s.AnyOf[g.rand.Intn(len(s.AnyOf))], depth)
+		}
+		if len(s.OneOf) > 0 {
+			return g.value(s.OneOf[g.rand.Intn(len(s.OneOf))], depth)
+		}
+	}
+
+	switch g.pickType(s) {
+	case "null":
+		return nil
+	case "boolean":
+		return g.rand.Intn(2) == 0
+	case "integer":
+		return g.number(s, true)
+	case "number":
+		return g.number(s, g.rand.Intn(2) == 0)
+	case "string":
+		return g.string(s)
+	case "array":
+		return g.array(s, depth)
+	case "object":
+		return g.object(s, depth)
+	}
+	return g.anyValue(depth)
+}
+
+// allOf generates a value for each schema and merges them when they are all objects.
+func (g *generator) allOf(schemas []*Schema, depth int) interface{} {
+	merged := map[string]interface{}{}
+	var first interface{}
+	for i, schema := range schemas {
+		value := g.value(schema, depth)
+		if i == 0 {
+			first = value
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return first
+		}
+		for key, v := range object {
+			merged[key] = v
+		}
+	}
+	return merged
+}
+
+// pickType chooses the type of the candidate, from the type keyword or else from the keywords present.
+func (g *generator) pickType(s *Schema) string {
+	if len(s.Type) > 0 {
+		return s.Type[g.rand.Intn(len(s.Type))]
+	}
+
+	switch {
+	case s.Properties != nil || s.PatternProperties != nil || len(s.Required) > 0 ||
+		s.MinProperties != nil || s.MaxProperties != nil || s.AdditionalProperties != nil:
+		return "object"
+	case s.Items != nil || len(s.PrefixItems) > 0 || s.Contains != nil || s.MinItems != nil || s.MaxItems != nil:
+		return "array"
+	case s.MinLength != nil || s.MaxLength != nil || s.Pattern != nil || s.Format != nil:
+		return "string"
+	case s.Minimum != nil || s.Maximum != nil || s.ExclusiveMinimum != nil || s.ExclusiveMaximum != nil || s.MultipleOf != nil:
+		return "number"
+	}
+	return ""
+}
+
+// anyValue returns a value of a random JSON type.
+func (g *generator) anyValue(depth int) interface{} {
+	types := []string{"null", "boolean", "number", "string"}
+	if depth < maxGenerateDepth {
+		types = append(types, "array", "object")
+	}
+
+	switch types[g.rand.Intn(len(types))] {
+	case "boolean":
+		return g.rand.Intn(2) == 0
+	case "number":
+		return float64(g.rand.Intn(2001) - 1000)
+	case "string":
+		return g.letters(g.rand.Intn(10))
+	case "array":
+		items := make([]interface{}, g.rand.Intn(3))
+		for i := range items {
+			items[i] = g.anyValue(depth + 1)
+		}
+		return items
+	case "object":
+		object := map[string]interface{}{}
+		for i := g.rand.Intn(3); i > 0; i-- {
+			object[g.letters(1+g.rand.Intn(6))] = g.anyValue(depth + 1)
+		}
+		return object
+	}
+	return nil
+}
+
+// number returns a number within the bounds of s, an integer if integer is true.
+func (g *generator) number(s *Schema, integer bool) interface{} {
+	lo, hasLo := math.Inf(-1), false
+	hi, hasHi := math.Inf(1), false
+	if s.Minimum != nil {
+		lo, _ = s.Minimum.Float64()
+		hasLo = true
+	}
+	if s.ExclusiveMinimum != nil {
+		if v, _ := s.ExclusiveMinimum.Float64(); !hasLo || v >= lo {
+			lo = math.Nextafter(v, math.Inf(1))
+			hasLo = true
+		}
+	}
+	if s.Maximum != nil {
+		hi, _ = s.Maximum.Float64()
+		hasHi = true
+	}
+	if s.ExclusiveMaximum != nil {
+		if v, _ := s.ExclusiveMaximum.Float64(); !hasHi || v <= hi {
+			hi = math.Nextafter(v, math.Inf(-1))
+			hasHi = true
+		}
+	}
+
+	switch {
+	case !hasLo && !hasHi:
+		lo, hi = -1000, 1000
+	case !hasLo:
+		lo = hi - 1000
+	case !hasHi:
+		hi = lo + 1000
+	}
+
+	if s.MultipleOf != nil {
+		step, _ := s.MultipleOf.Float64()
+		if step > 0 {
+			first, last := math.Ceil(lo/step), math.Floor(hi/step)
+			if first <= last {
+				return (first + math.Floor(g.rand.Float64()*(last-first+1))) * step
+			}
+		}
+	}
+
+	if integer {
+		first, last := math.Ceil(lo), math.Floor(hi)
+		if first > last {
+			return first
+		}
+		return first + math.Floor(g.rand.Float64()*(last-first+1))
+	}
+	return lo + g.rand.Float64()*(hi-lo)
+}
+
+// string returns a string of the schema's format, or random letters within its length bounds.
+func (g *generator) string(s *Schema) interface{} {
+	if s.Format != nil {
+		if value, ok := g.format(*s.Format); ok {
+			return value
+		}
+	}
+
+	minLength, maxLength := 0, -1
+	if s.MinLength != nil {
+		minLength = int(*s.MinLength)
+	}
+	if s.MaxLength != nil {
+		maxLength = int(*s.MaxLength)
+	}
+	if maxLength < minLength {
+		maxLength = minLength + 10
+	}
+	return g.letters(minLength + g.rand.Intn(maxLength-minLength+1))
+}
+
+// format returns a value of the named format, if the format is known to the generator.
+func (g *generator) format(name string) (string, bool) {
+	switch name {
+	case "date-time":
+		return g.date() + "T" + g.time(), true
+	case "date":
+		return g.date(), true
+	case "time":
+		return g.time(), true
+	case "email":
+		return g.letters(1+g.rand.Intn(8)) + "@example.com", true
+	case "hostname":
+		return g.letters(1+g.rand.Intn(8)) + ".example.com", true
+	case "ipv4", "ip-address":
+		return fmt.Sprintf("%d.%d.%d.%d", g.rand.Intn(256), g.rand.Intn(256), g.rand.Intn(256), g.rand.Intn(256)), true
+	case "ipv6":
+		parts := make([]string, 8)
+		for i := range parts {
+			parts[i] = strconv.FormatInt(int64(g.rand.Intn(0x10000)), 16)
+		}
+		return strings.Join(parts, ":"), true
+	case "uri", "iri", "uri-reference", "iri-reference", "uriref":
+		return "https://example.com/" + g.letters(g.rand.Intn(8)), true
+	case "uuid":
+		const hex = "0123456789abcdef"
+		b := make([]byte, 36)
+		for i := range b {
+			switch i {
+			case 8, 13, 18, 23:
+				b[i] = '-'
+			default:
+				b[i] = hex[g.rand.Intn(len(hex))]
+			}
+		}
+		return string(b), true
+	}
+	return "", false
+}
+
+// date returns a random RFC 3339 full-date.
+func (g *generator) date() string {
+	return fmt.Sprintf("%04d-%02d-%02d", 1970+g.rand.Intn(100), 1+g.rand.Intn(12), 1+g.rand.Intn(28))
+}
+
+// time returns a random RFC 3339 full-time in UTC.
+func (g *generator) time() string {
+	return fmt.Sprintf("%02d:%02d:%02dZ", g.rand.Intn(24), g.rand.Intn(60), g.rand.Intn(60))
+}
+
+// letters returns n random lowercase ASCII letters.
+func (g *generator) letters(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + g.rand.Intn(26))
+	}
+	return string(b)
+}
+
+// array returns an array honoring prefixItems, items, contains and the item count bounds of s.
+func (g *generator) array(s *Schema, depth int) interface{} {
+	minItems, maxItems := 0, -1
+	if s.MinItems != nil {
+		minItems = int(*s.MinItems)
+	}
+	if s.MaxItems != nil {
+		maxItems = int(*s.MaxItems)
+	}
+	if maxItems < minItems {
+		maxItems = minItems + 3
+	}
+	if depth >= maxGenerateDepth {
+		maxItems = minItems
+	}
+
+	count := minItems + g.rand.Intn(maxItems-minItems+1)
+	if count < len(s.PrefixItems) && maxItems >= len(s.PrefixItems) {
+		count = len(s.PrefixItems)
+	}
+	if s.Items != nil && s.Items.Boolean != nil && !*s.Items.Boolean && count > len(s.PrefixItems) {
+		count = len(s.PrefixItems)
+	}
+
+	contains := 0
+	if s.Contains != nil {
+		contains = 1
+		if s.MinContains != nil {
+			contains = int(*s.MinContains)
+		}
+	}
+
+	unique := s.UniqueItems != nil && *s.UniqueItems
+	items := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		itemSchema := s.Items
+		switch {
+		case i < len(s.PrefixItems):
+			itemSchema = s.PrefixItems[i]
+		case count-i <= contains:
+			itemSchema = s.Contains
+		}
+
+		item := g.value(itemSchema, depth+1)
+		for attempt := 0; unique && attempt < 10 && containsEqual(items, item); attempt++ {
+			item = g.value(itemSchema, depth+1)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// containsEqual reports whether items holds a value equal to item.
+func containsEqual(items []interface{}, item interface{}) bool {
+	for _, existing := range items {
+		if reflect.DeepEqual(existing, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// object returns an object with the required properties of s, a random subset of its other
+// properties and enough additional properties to reach minProperties.
+func (g *generator) object(s *Schema, depth int) interface{} {
+	object := map[string]interface{}{}
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	propertySchema := func(name string) *Schema {
+		if s.Properties != nil {
+			if schema, ok := (*s.Properties)[name]; ok {
+				return schema
+			}
+		}
+		return s.AdditionalProperties
+	}
+
+	if s.Properties != nil {
+		names := make([]string, 0, len(*s.Properties))
+		for name := range *s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if required[name] || (depth < maxGenerateDepth && g.rand.Intn(2) == 0) {
+				object[name] = g.value((*s.Properties)[name], depth+1)
+			}
+		}
+	}
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			object[name] = g.value(propertySchema(name), depth+1)
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for name, dependents := range s.DependentRequired {
+			if _, ok := object[name]; !ok {
+				continue
+			}
+			for _, dependent := range dependents {
+				if _, ok := object[dependent]; !ok {
+					object[dependent] = g.value(propertySchema(dependent), depth+1)
+					changed = true
+				}
+			}
+		}
+	}
+
+	if s.MinProperties != nil {
+		for i := 0; len(object) < int(*s.MinProperties) && i < 100; i++ {
+			name := g.letters(1 + g.rand.Intn(8))
+			if _, ok := object[name]; !ok {
+				object[name] = g.value(propertySchema(name), depth+1)
+			}
+		}
+	}
+	return object
+}
@@ -0,0 +1,96 @@
+package jsonschema
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "any", schema: `true`},
+		{name: "null", schema: `{"type": "null"}`},
+		{name: "integer range", schema: `{"type": "integer", "minimum": 3, "exclusiveMaximum": 7}`},
+		{name: "multipleOf", schema: `{"type": "number", "multipleOf": 0.5, "minimum": -2, "maximum": 2}`},
+		{name: "string length", schema: `{"type": "string", "minLength": 2, "maxLength": 4}`},
+		{name: "formats", schema: `{"type": "array", "prefixItems": [
+			{"format": "date-time"}, {"format": "email"}, {"format": "uuid"}, {"format": "ipv4"}, {"format": "ipv6"}
+		], "items": false}`},
+		{name: "enum", schema: `{"enum": ["red", "green", 3]}`},
+		{name: "const", schema: `{"const": {"a": [1, 2]}}`},
+		{name: "pattern with examples", schema: `{"type": "string", "pattern": "^[A-Z]{3}$", "examples": ["ABC", "XYZ"]}`},
+		{name: "unique items", schema: `{"type": "array", "items": {"type": "integer", "minimum": 0, "maximum": 5}, "minItems": 3, "uniqueItems": true}`},
+		{name: "contains", schema: `{"type": "array", "items": {"type": "integer"}, "contains": {"const": 42}, "minContains": 2}`},
+		{name: "object", schema: `{
+			"type": "object",
+			"properties": {
+				"id": {"type": "integer", "minimum": 1},
+				"name": {"type": "string", "minLength": 1},
+				"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 3}
+			},
+			"required": ["id", "name"],
+			"dependentRequired": {"tags": ["nickname"]},
+			"additionalProperties": {"type": "string"},
+			"minProperties": 3
+		}`},
+		{name: "oneOf and refs", schema: `{
+			"$defs": {
+				"circle": {"type": "object", "properties": {"kind": {"const": "circle"}, "radius": {"type": "number", "exclusiveMinimum": 0}}, "required": ["kind", "radius"]},
+				"square": {"type": "object", "properties": {"kind": {"const": "square"}, "side": {"type": "number", "exclusiveMinimum": 0}}, "required": ["kind", "side"]}
+			},
+			"oneOf": [{"$ref": "#/$defs/circle"}, {"$ref": "#/$defs/square"}]
+		}`},
+		{name: "allOf objects", schema: `{"allOf": [
+			{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"]},
+			{"type": "object", "properties": {"b": {"type": "boolean"}}, "required": ["b"]}
+		]}`},
+		{name: "recursive", schema: `{
+			"$defs": {"node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}}},
+			"$ref": "#/$defs/node"
+		}`},
+	}
+
+	compiler := NewCompiler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := compiler.Compile([]byte(tt.schema))
+			require.NoError(t, err)
+
+			r := rand.New(rand.NewSource(1)) //nolint:gosec
+			for i := 0; i < 50; i++ {
+				value, err := schema.Generate(r)
+				require.NoError(t, err)
+				assert.True(t, schema.Validate(value).IsValid(), "generated value %v is invalid", value)
+			}
+		})
+	}
+}
+
+func TestGenerateUnsatisfiable(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"type": "integer", "minimum": 5, "maximum": 1}`))
+	require.NoError(t, err)
+
+	_, err = schema.Generate(rand.New(rand.NewSource(1))) //nolint:gosec
+	assert.ErrorIs(t, err, ErrGenerationFailed)
+}
+
+func TestQuickValues(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {"age": {"type": "integer", "minimum": 0, "maximum": 150}},
+		"required": ["age"]
+	}`))
+	require.NoError(t, err)
+
+	property := func(v interface{}) bool {
+		age := v.(map[string]interface{})["age"].(float64)
+		return age >= 0 && age <= 150
+	}
+	assert.NoError(t, quick.Check(property, &quick.Config{Values: schema.QuickValues()}))
+}
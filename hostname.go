@@ -0,0 +1,263 @@
+package jsonschema
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SetUnicodeHostnames sets whether the hostname format also accepts internationalized host names
+// written with U-labels, such as bücher.example, checked as the idn-hostname format does. By
+// default, hostname only accepts ASCII labels, including the A-labels of IDNA such as
+// xn--bcher-kva.example.
+func (c *Compiler) SetUnicodeHostnames(accept bool) *Compiler {
+	c.UnicodeHostnames = accept
+	return c
+}
+
+// IsIDNHostname tells whether given string is a valid internationalized host
+// name as defined by RFC 5890, section 2.3.2.3: its labels are ASCII labels,
+// A-labels or U-labels, and respect the length limits of host names once the
+// U-labels are converted to A-labels.
+//
+// See https://datatracker.ietf.org/doc/html/rfc5890#section-2.3.2.3, for details.
+func IsIDNHostname(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, ok = hostnameToASCII(s, true)
+	return ok
+}
+
+// HostnameToASCII converts the U-labels of an internationalized host name to
+// A-labels with Punycode, as in bücher.example to xn--bcher-kva.example, after
+// checking it as IsIDNHostname does.
+func HostnameToASCII(host string) (string, error) {
+	ascii, ok := hostnameToASCII(host, true)
+	if !ok {
+		return "", ErrInvalidHostname
+	}
+	return ascii, nil
+}
+
+// HostnameToUnicode converts the A-labels of a host name to U-labels, as in
+// xn--bcher-kva.example to bücher.example, after checking it as IsIDNHostname
+// does.
+func HostnameToUnicode(host string) (string, error) {
+	ascii, ok := hostnameToASCII(host, true)
+	if !ok {
+		return "", ErrInvalidHostname
+	}
+	labels := strings.Split(ascii, ".")
+	for i, label := range labels {
+		if strings.HasPrefix(label, "xn--") {
+			labels[i], _ = punycodeDecode(label[4:])
+		}
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// hostnameSeparators are the label separators that IDNA treats as full stops.
+var hostnameSeparators = strings.NewReplacer("。", ".", "．", ".", "｡", ".")
+
+// hostnameToASCII returns the host name s with its labels in ASCII, lower-cased A-labels for
+// U-labels, and reports whether s is a valid host name. U-labels are only accepted when unicode is
+// true.
+func hostnameToASCII(s string, unicode bool) (string, bool) {
+	if unicode {
+		s = hostnameSeparators.Replace(s)
+	}
+	// entire hostname (including the delimiting dots but not a trailing dot) has a maximum of 253 ASCII characters
+	s = strings.TrimSuffix(s, ".")
+
+	labels := strings.Split(s, ".")
+	for i, label := range labels {
+		ascii, ok := labelToASCII(label, unicode)
+		if !ok {
+			return "", false
+		}
+		labels[i] = ascii
+	}
+	ascii := strings.Join(labels, ".")
+	return ascii, len(ascii) <= 253
+}
+
+// labelToASCII returns a host name label in ASCII, converting a U-label to an A-label when
+// unicode is true, and reports whether it is valid.
+func labelToASCII(label string, unicode bool) (string, bool) {
+	if !isASCII(label) {
+		if !unicode {
+			return "", false
+		}
+		label = strings.ToLower(label)
+		if !isULabel(label) {
+			return "", false
+		}
+		encoded, err := punycodeEncode(label)
+		if err != nil || len(encoded)+4 > 63 {
+			return "", false
+		}
+		return "xn--" + encoded, true
+	}
+
+	// Each label must be from 1 to 63 characters long, and must not start or end with a hyphen.
+	// RFC 1123 section 2.1: restriction on the first character is relaxed to allow either a
+	// letter or a digit
+	if len(label) < 1 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return "", false
+	}
+
+	// labels may contain only the ASCII letters 'a' through 'z' (in a case-insensitive manner),
+	// the digits '0' through '9', and the hyphen ('-')
+	for i := 0; i < len(label); i++ {
+		if c := label[i]; !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '-' {
+			return "", false
+		}
+	}
+
+	// A-labels must decode to a U-label that encodes back to the same A-label.
+	if lower := strings.ToLower(label); strings.HasPrefix(lower, "xn--") {
+		decoded, err := punycodeDecode(lower[4:])
+		if err != nil || isASCII(decoded) || !isULabel(decoded) {
+			return "", false
+		}
+		if encoded, err := punycodeEncode(decoded); err != nil || encoded != lower[4:] {
+			return "", false
+		}
+	}
+	return label, true
+}
+
+// isASCII tells whether s only contains ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Code points whose IDNA2008 property differs from the one of their general category, see
+// RFC 5892, section 2.6.
+var (
+	idnaPValid     = []rune{0x00DF, 0x03C2, 0x06FD, 0x06FE, 0x0F0B, 0x3007}
+	idnaDisallowed = []rune{0x0640, 0x07FA, 0x302E, 0x302F, 0x3031, 0x3032, 0x3033, 0x3034, 0x3035, 0x303B}
+)
+
+// idnaViramas are the code points of the Virama canonical combining class, which may precede
+// ZERO WIDTH JOINER and ZERO WIDTH NON-JOINER.
+var idnaViramas = []rune{
+	0x094D, 0x09CD, 0x0A4D, 0x0ACD, 0x0B4D, 0x0BCD, 0x0C4D, 0x0CCD, 0x0D3B, 0x0D3C, 0x0D4D, 0x0DCA,
+	0x0E3A, 0x0EBA, 0x0F84, 0x1039, 0x103A, 0x1714, 0x1734, 0x17D2, 0x1A60, 0x1B44, 0x1BAA, 0x1BAB,
+	0x1BF2, 0x1BF3, 0x2D7F, 0xA806, 0xA8C4, 0xA953, 0xA9C0, 0xAAF6, 0xABED,
+}
+
+// isULabel tells whether label, a lower-cased label, is a valid U-label as defined by RFC 5891,
+// section 5.4: a string in NFC of lower-case letters, digits, marks and hyphens that does not
+// start with a mark, with the contextual rules of RFC 5892, appendix A.
+func isULabel(label string) bool {
+	runes := []rune(label)
+	if len(runes) == 0 || runes[0] == '-' || runes[len(runes)-1] == '-' || unicode.IsMark(runes[0]) {
+		return false
+	}
+	// Hyphens in the third and fourth positions are reserved for A-labels.
+	if len(runes) >= 4 && runes[2] == '-' && runes[3] == '-' {
+		return false
+	}
+	if !isNFC(label) {
+		return false
+	}
+
+	arabicIndic, extendedArabicIndic := false, false
+	for i, r := range runes {
+		var prev, next rune
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case containsRune(idnaDisallowed, r):
+			return false
+		case containsRune(idnaPValid, r):
+		case r == 0x00B7: // MIDDLE DOT
+			if prev != 'l' || next != 'l' {
+				return false
+			}
+		case r == 0x0375: // GREEK LOWER NUMERAL SIGN (KERAIA)
+			if !unicode.Is(unicode.Greek, next) {
+				return false
+			}
+		case r == 0x05F3 || r == 0x05F4: // HEBREW PUNCTUATION GERESH and GERSHAYIM
+			if !unicode.Is(unicode.Hebrew, prev) {
+				return false
+			}
+		case r == 0x30FB: // KATAKANA MIDDLE DOT
+			if !containsJapanese(runes) {
+				return false
+			}
+		case r >= 0x0660 && r <= 0x0669: // ARABIC-INDIC DIGITS
+			arabicIndic = true
+		case r >= 0x06F0 && r <= 0x06F9: // EXTENDED ARABIC-INDIC DIGITS
+			extendedArabicIndic = true
+		case r == 0x200D: // ZERO WIDTH JOINER
+			if !containsRune(idnaViramas, prev) {
+				return false
+			}
+		case r == 0x200C: // ZERO WIDTH NON-JOINER
+			if !containsRune(idnaViramas, prev) && !(isJoining(runes[:i], true) && isJoining(runes[i+1:], false)) {
+				return false
+			}
+		case r < utf8.RuneSelf:
+			if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' {
+				return false
+			}
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r):
+			return false
+		case unicode.ToLower(r) != r || unicode.IsTitle(r):
+			return false // Unstable under case folding.
+		}
+	}
+	return !(arabicIndic && extendedArabicIndic)
+}
+
+// containsRune tells whether runes contains r.
+func containsRune(runes []rune, r rune) bool {
+	for _, candidate := range runes {
+		if candidate == r {
+			return true
+		}
+	}
+	return false
+}
+
+// containsJapanese tells whether runes contain a Hiragana, Katakana or Han character other than
+// KATAKANA MIDDLE DOT.
+func containsJapanese(runes []rune) bool {
+	for _, r := range runes {
+		if r != 0x30FB && unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return true
+		}
+	}
+	return false
+}
+
+// isJoining approximates the joining context of ZERO WIDTH NON-JOINER: ignoring marks, the
+// character before it, or after it when before is false, must be a letter of a joining script.
+func isJoining(runes []rune, before bool) bool {
+	for i := range runes {
+		r := runes[i]
+		if before {
+			r = runes[len(runes)-1-i]
+		}
+		if unicode.IsMark(r) {
+			continue
+		}
+		return unicode.IsLetter(r) && unicode.In(r, unicode.Arabic, unicode.Syriac, unicode.Nko, unicode.Mongolian, unicode.Mandaic)
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPunycode(t *testing.T) {
+	// Samples of RFC 3492, section 7.1, and of common domains.
+	tests := map[string]string{
+		"bücher":            "bcher-kva",
+		"他们为什么不说中文":         "ihqwcrb4cv8a8dqg056pqjye",
+		"ليهمابتكلموشعربي؟": "egbpdaj6bu4bxfgehfvwxn",
+		"3年b組金八先生":          "3b-ww4c5e180e575a65lsy2b",
+		"почемужеонинеговорятпорусски": "b1abfaaepdrnnbgefbadotcwatmq2g4l",
+		"example": "example-",
+	}
+	for label, encoded := range tests {
+		actual, err := punycodeEncode(label)
+		require.NoError(t, err, label)
+		assert.Equal(t, encoded, actual, label)
+
+		decoded, err := punycodeDecode(encoded)
+		require.NoError(t, err, encoded)
+		assert.Equal(t, label, decoded, encoded)
+	}
+
+	for _, encoded := range []string{"X", "ü-abc", "99999999999"} {
+		_, err := punycodeDecode(encoded)
+		assert.ErrorIs(t, err, ErrInvalidPunycode, encoded)
+	}
+}
+
+func TestHostnameConversion(t *testing.T) {
+	ascii, err := HostnameToASCII("Bücher.例子。广告.")
+	require.NoError(t, err)
+	assert.Equal(t, "xn--bcher-kva.xn--fsqu00a.xn--4rr70v", ascii)
+
+	unicode, err := HostnameToUnicode("xn--bcher-kva.Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "bücher.Example.com", unicode)
+
+	for _, host := range []string{"xn--X.com", "-bücher.com", "bü_cher.com", ""} {
+		_, err := HostnameToASCII(host)
+		assert.ErrorIs(t, err, ErrInvalidHostname, host)
+	}
+}
+
+func TestHostnameLengths(t *testing.T) {
+	label63 := "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijk"
+	assert.True(t, IsHostname(label63+".com"))
+	assert.False(t, IsHostname(label63+"l.com"))
+	assert.True(t, IsHostname(label63+"."+label63+"."+label63+"."+label63[:61]))
+	assert.False(t, IsHostname(label63+"."+label63+"."+label63+"."+label63[:62]))
+	assert.True(t, IsHostname("example.com."))
+	assert.False(t, IsHostname("example..com"))
+	assert.False(t, IsHostname("a.-b.com"))
+
+	// U-labels are limited by the length of their A-label.
+	assert.True(t, IsIDNHostname("실례.테스트"))
+	long := ""
+	for i := 0; i < 40; i++ {
+		long += string(rune(0xAC00 + i*37))
+	}
+	assert.False(t, IsIDNHostname(long+".com"))
+}
+
+func TestUnicodeHostnames(t *testing.T) {
+	compiler := NewCompiler().SetAssertFormat(true)
+	schema, err := compiler.Compile([]byte(`{"format": "hostname"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("xn--bcher-kva.example").IsValid())
+	assert.False(t, schema.Validate("bücher.example").IsValid())
+	assert.False(t, schema.Validate("xn--bcher-kvb.example").IsValid())
+
+	compiler.SetUnicodeHostnames(true)
+	assert.True(t, schema.Validate("bücher.example").IsValid())
+	assert.True(t, schema.Validate("xn--bcher-kva.example").IsValid())
+	assert.False(t, schema.Validate("bü cher.example").IsValid())
+}
@@ -0,0 +1,363 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// irMagic starts every exported schema.
+const irMagic = "JSIR"
+
+// IRVersion is the version of the format written by Schema.Export. Import reads this version only;
+// exported schemas must be exported again after upgrading to a release with another version.
+const IRVersion = 1
+
+// schemaIR is the intermediate representation of a compiled schema: the schemas reachable from the
+// exported one, referencing each other by index, so that nothing is resolved when it is imported.
+type schemaIR struct {
+	Root  int      `json:"root"`
+	Nodes []irNode `json:"nodes"`
+}
+
+// irNode is a schema of the intermediate representation. References to other nodes are their
+// index plus one, so that zero means none.
+type irNode struct {
+	Keywords        json.RawMessage `json:"keywords"` // The keywords of the schema, without its subschemas.
+	Children        []irChild       `json:"children,omitempty"`
+	Parent          int             `json:"parent,omitempty"`
+	ResolvedRef     int             `json:"ref,omitempty"`
+	ResolvedDynRef  int             `json:"dynamicRef,omitempty"`
+	URI             string          `json:"uri,omitempty"`
+	BaseURI         string          `json:"baseURI,omitempty"`
+	Anchors         map[string]int  `json:"anchors,omitempty"`
+	DynamicAnchors  map[string]int  `json:"dynamicAnchors,omitempty"`
+	Schemas         map[string]int  `json:"schemas,omitempty"`
+	FormatAssertion bool            `json:"formatAssertion,omitempty"`
+	AssertFormat    *bool           `json:"assertFormat,omitempty"`
+	EmailMode       *EmailMode      `json:"emailMode,omitempty"`
+}
+
+// irChild is a subschema of a node, at a JSON Pointer relative to it.
+type irChild struct {
+	Pointer string `json:"pointer"`
+	Node    int    `json:"node"`
+}
+
+// Export writes the compiled schema, with every schema it references, in a versioned intermediate
+// representation that Import reads back without parsing the schema documents again or resolving
+// their references. A build step can so compile schemas once, and ship the result to the
+// processes that validate with them.
+//
+// Compiler settings, custom formats, decoders and media types are not exported; the compiler
+// given to Compiler.Import must be configured like the one that compiled the schema.
+func (s *Schema) Export(w io.Writer) error {
+	index := map[*Schema]int{}
+	var order []*Schema
+	var visit func(schema *Schema)
+	visit = func(schema *Schema) {
+		if schema == nil {
+			return
+		}
+		if _, ok := index[schema]; ok {
+			return
+		}
+		index[schema] = len(order)
+		order = append(order, schema)
+
+		for _, child := range childSchemas(schema) {
+			visit(child.schema)
+		}
+		visit(schema.parent)
+		visit(schema.ResolvedRef)
+		visit(schema.ResolvedDynamicRef)
+		for _, schemas := range []map[string]*Schema{schema.anchors, schema.dynamicAnchors, schema.schemas} {
+			for _, key := range sortedSchemaKeys(schemas) {
+				visit(schemas[key])
+			}
+		}
+	}
+	visit(s)
+
+	ref := func(schema *Schema) int {
+		if schema == nil {
+			return 0
+		}
+		return index[schema] + 1
+	}
+	refs := func(schemas map[string]*Schema) map[string]int {
+		if len(schemas) == 0 {
+			return nil
+		}
+		refs := make(map[string]int, len(schemas))
+		for key, schema := range schemas {
+			refs[key] = ref(schema)
+		}
+		return refs
+	}
+
+	ir := schemaIR{Root: index[s], Nodes: make([]irNode, len(order))}
+	for i, schema := range order {
+		keywords, err := json.Marshal(withoutSubschemas(schema))
+		if err != nil {
+			return err
+		}
+		node := irNode{
+			Keywords:        keywords,
+			Parent:          ref(schema.parent),
+			ResolvedRef:     ref(schema.ResolvedRef),
+			ResolvedDynRef:  ref(schema.ResolvedDynamicRef),
+			URI:             schema.uri,
+			BaseURI:         schema.baseURI,
+			Anchors:         refs(schema.anchors),
+			DynamicAnchors:  refs(schema.dynamicAnchors),
+			Schemas:         refs(schema.schemas),
+			FormatAssertion: schema.formatAssertion,
+			AssertFormat:    schema.assertFormat,
+			EmailMode:       schema.emailMode,
+		}
+		for _, child := range childSchemas(schema) {
+			node.Children = append(node.Children, irChild{Pointer: child.pointer, Node: ref(child.schema)})
+		}
+		ir.Nodes[i] = node
+	}
+
+	data, err := json.Marshal(ir)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, len(irMagic)+2)
+	copy(header, irMagic)
+	binary.BigEndian.PutUint16(header[len(irMagic):], IRVersion)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Import reads a schema written by Schema.Export, with a new compiler.
+func Import(r io.Reader) (*Schema, error) {
+	return NewCompiler().Import(r)
+}
+
+// Import reads a schema written by Schema.Export. The schema documents it contains are cached by
+// the compiler under their URIs, as if they had been compiled with it.
+func (c *Compiler) Import(r io.Reader) (*Schema, error) {
+	c.compileMu.Lock()
+	defer c.compileMu.Unlock()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < len(irMagic)+2 || !bytes.HasPrefix(data, []byte(irMagic)) {
+		return nil, ErrInvalidIR
+	}
+	if version := binary.BigEndian.Uint16(data[len(irMagic):]); version != IRVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedIRVersion, version)
+	}
+
+	var ir schemaIR
+	if err := json.Unmarshal(data[len(irMagic)+2:], &ir); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIR, err)
+	}
+	if ir.Root < 0 || ir.Root >= len(ir.Nodes) {
+		return nil, fmt.Errorf("%w: root %d out of range", ErrInvalidIR, ir.Root)
+	}
+
+	schemas := make([]*Schema, len(ir.Nodes))
+	for i, node := range ir.Nodes {
+		if schemas[i], err = newSchema(node.Keywords); err != nil {
+			return nil, fmt.Errorf("%w: node %d: %w", ErrInvalidIR, i, err)
+		}
+	}
+	deref := func(ref int) (*Schema, error) {
+		if ref < 0 || ref > len(schemas) {
+			return nil, fmt.Errorf("%w: reference %d out of range", ErrInvalidIR, ref)
+		}
+		if ref == 0 {
+			return nil, nil
+		}
+		return schemas[ref-1], nil
+	}
+	derefs := func(refs map[string]int) (map[string]*Schema, error) {
+		if refs == nil {
+			return nil, nil
+		}
+		result := make(map[string]*Schema, len(refs))
+		for key, ref := range refs {
+			schema, err := deref(ref)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = schema
+		}
+		return result, nil
+	}
+
+	for i, node := range ir.Nodes {
+		schema := schemas[i]
+		schema.compiler = c
+		schema.uri = node.URI
+		schema.baseURI = node.BaseURI
+		schema.formatAssertion = node.FormatAssertion
+		schema.assertFormat = node.AssertFormat
+		schema.emailMode = node.EmailMode
+		if schema.parent, err = deref(node.Parent); err != nil {
+			return nil, err
+		}
+		if schema.ResolvedRef, err = deref(node.ResolvedRef); err != nil {
+			return nil, err
+		}
+		if schema.ResolvedDynamicRef, err = deref(node.ResolvedDynRef); err != nil {
+			return nil, err
+		}
+		if schema.anchors, err = derefs(node.Anchors); err != nil {
+			return nil, err
+		}
+		if schema.dynamicAnchors, err = derefs(node.DynamicAnchors); err != nil {
+			return nil, err
+		}
+		if schema.schemas, err = derefs(node.Schemas); err != nil {
+			return nil, err
+		}
+		for _, child := range node.Children {
+			subschema, err := deref(child.Node)
+			if err != nil {
+				return nil, err
+			}
+			if subschema == nil || !setSubschema(schema, child.Pointer, subschema) {
+				return nil, fmt.Errorf("%w: invalid subschema %s of node %d", ErrInvalidIR, child.Pointer, i)
+			}
+		}
+	}
+
+	for _, schema := range schemas {
+		if schema.parent == nil {
+			if err := c.compileKeywords(schema); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, schema := range schemas {
+		if schema.parent == nil && schema.uri != "" && isValidURI(schema.uri) {
+			c.SetSchema(schema.uri, schema)
+		}
+	}
+	return schemas[ir.Root], nil
+}
+
+// withoutSubschemas returns a copy of s without its subschemas, which Export writes as separate
+// nodes. Empty properties and patternProperties are kept, so that the keywords are preserved.
+func withoutSubschemas(s *Schema) *Schema {
+	copied := *s
+	copied.Defs = nil
+	copied.AllOf, copied.AnyOf, copied.OneOf = nil, nil, nil
+	copied.Not, copied.If, copied.Then, copied.Else = nil, nil, nil, nil
+	copied.DependentSchemas = nil
+	copied.PrefixItems, copied.Items, copied.Contains = nil, nil, nil
+	if s.Properties != nil {
+		copied.Properties = &SchemaMap{}
+	}
+	if s.PatternProperties != nil {
+		copied.PatternProperties = &SchemaMap{}
+	}
+	copied.AdditionalProperties, copied.PropertyNames = nil, nil
+	copied.UnevaluatedItems, copied.UnevaluatedProperties = nil, nil
+	copied.ContentSchema = nil
+	return &copied
+}
+
+// setSubschema sets the subschema of s at pointer, as returned by childSchemas, and reports
+// whether the pointer names a subschema.
+func setSubschema(s *Schema, pointer string, subschema *Schema) bool {
+	keyword, token, _ := strings.Cut(strings.TrimPrefix(pointer, "/"), "/")
+	key := unescapeJSONPointer(token)
+
+	setList := func(list *[]*Schema) bool {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 {
+			return false
+		}
+		for len(*list) <= i {
+			*list = append(*list, nil)
+		}
+		(*list)[i] = subschema
+		return true
+	}
+	setMap := func(schemas *map[string]*Schema) bool {
+		if *schemas == nil {
+			*schemas = make(map[string]*Schema)
+		}
+		(*schemas)[key] = subschema
+		return true
+	}
+	setSchemaMap := func(schemas **SchemaMap) bool {
+		if *schemas == nil {
+			*schemas = &SchemaMap{}
+		}
+		(**schemas)[key] = subschema
+		return true
+	}
+
+	switch keyword {
+	case "$defs":
+		return setMap(&s.Defs)
+	case "allOf":
+		return setList(&s.AllOf)
+	case "anyOf":
+		return setList(&s.AnyOf)
+	case "oneOf":
+		return setList(&s.OneOf)
+	case "not":
+		s.Not = subschema
+	case "if":
+		s.If = subschema
+	case "then":
+		s.Then = subschema
+	case "else":
+		s.Else = subschema
+	case "dependentSchemas":
+		return setMap(&s.DependentSchemas)
+	case "prefixItems":
+		return setList(&s.PrefixItems)
+	case "items":
+		s.Items = subschema
+	case "contains":
+		s.Contains = subschema
+	case "properties":
+		return setSchemaMap(&s.Properties)
+	case "patternProperties":
+		return setSchemaMap(&s.PatternProperties)
+	case "additionalProperties":
+		s.AdditionalProperties = subschema
+	case "propertyNames":
+		s.PropertyNames = subschema
+	case "unevaluatedItems":
+		s.UnevaluatedItems = subschema
+	case "unevaluatedProperties":
+		s.UnevaluatedProperties = subschema
+	case "contentSchema":
+		s.ContentSchema = subschema
+	default:
+		return false
+	}
+	return true
+}
+
+// sortedSchemaKeys returns the keys of schemas in order.
+func sortedSchemaKeys(schemas map[string]*Schema) []string {
+	keys := make([]string, 0, len(schemas))
+	for key := range schemas {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
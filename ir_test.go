@@ -0,0 +1,113 @@
+package jsonschema
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/address.json",
+		"type": "object",
+		"properties": {"city": {"type": "string", "minLength": 1}},
+		"required": ["city"]
+	}`))
+	require.NoError(t, err)
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/person.json",
+		"$defs": {
+			"name": {"$anchor": "name", "type": "string", "pattern": "^[A-Z]"}
+		},
+		"type": "object",
+		"properties": {
+			"name": {"$ref": "#name"},
+			"address": {"$ref": "address.json"},
+			"children": {"type": "array", "items": {"$ref": "#"}},
+			"id": {"enum": [12345678901234567890, "x"]},
+			"meta": {"properties": {}, "unevaluatedProperties": false}
+		},
+		"patternProperties": {"^x-": true},
+		"additionalProperties": false,
+		"x-owner": "team"
+	}`))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, schema.Export(&buf))
+
+	runtime := NewCompiler()
+	imported, err := runtime.Import(&buf)
+	require.NoError(t, err)
+
+	original, err := json.Marshal(schema)
+	require.NoError(t, err)
+	roundTripped, err := json.Marshal(imported)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(original), string(roundTripped))
+
+	instances := []string{
+		`{"name": "Ada", "address": {"city": "London"}, "children": [{"name": "Bo"}], "id": 12345678901234567890, "x-tag": 1}`,
+		`{"name": "ada"}`,
+		`{"address": {}}`,
+		`{"children": [{"name": 1}, {"extra": true}]}`,
+		`{"id": 12345678901234567891}`,
+		`{"meta": {"a": 1}, "other": true}`,
+	}
+	for _, data := range instances {
+		var instance interface{}
+		require.NoError(t, decodeExactJSON([]byte(data), &instance))
+		assert.Equal(t, sortedList(schema.Validate(instance).ToList()), sortedList(imported.Validate(instance).ToList()), data)
+	}
+
+	address, err := runtime.GetSchema("https://example.com/address.json")
+	require.NoError(t, err)
+	assert.False(t, address.Validate(map[string]interface{}{}).IsValid())
+}
+
+func TestImportDynamicRef(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/strict-tree.json",
+		"$dynamicAnchor": "node",
+		"$ref": "tree.json",
+		"unevaluatedProperties": false,
+		"$defs": {
+			"tree": {
+				"$id": "tree.json",
+				"$dynamicAnchor": "node",
+				"type": "object",
+				"properties": {
+					"data": true,
+					"children": {"type": "array", "items": {"$dynamicRef": "#node"}}
+				}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, schema.Export(&buf))
+	imported, err := Import(&buf)
+	require.NoError(t, err)
+
+	valid := map[string]interface{}{"children": []interface{}{map[string]interface{}{"data": 1}}}
+	invalid := map[string]interface{}{"children": []interface{}{map[string]interface{}{"daat": 1}}}
+	assert.True(t, imported.Validate(valid).IsValid())
+	assert.False(t, imported.Validate(invalid).IsValid())
+}
+
+func TestImportInvalid(t *testing.T) {
+	_, err := Import(bytes.NewReader([]byte(`{"type": "object"}`)))
+	require.ErrorIs(t, err, ErrInvalidIR)
+
+	_, err = Import(bytes.NewReader([]byte("JSIR\x00\x63{}")))
+	require.ErrorIs(t, err, ErrUnsupportedIRVersion)
+
+	_, err = Import(bytes.NewReader([]byte(`JSIR` + "\x00\x01" + `{"root": 0, "nodes": [{"keywords": {}, "children": [{"pointer": "/items", "node": 5}]}]}`)))
+	require.ErrorIs(t, err, ErrInvalidIR)
+}
@@ -33,7 +33,9 @@ func evaluateItems(schema *Schema, array []interface{}, evaluatedProps map[strin
 		// Ensure that we only access indices within the range of existing array elements
 		for i := startIndex; i < len(array); i++ {
 			item := array[i]
+			dynamicScope.pushIndex(i)
 			result, _, _ := schema.Items.evaluate(item, dynamicScope)
+			dynamicScope.popInstance()
 			if result != nil {
 				result.SetEvaluationPath(fmt.Sprintf("/items/%d", i)).
 					SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/items/%d", i))).
@@ -48,6 +50,12 @@ func evaluateItems(schema *Schema, array []interface{}, evaluatedProps map[strin
 		}
 	}
 
+	if schema.Items.IsFalse() {
+		if err := notAllowedItemsError("items", invalid_indexs); err != nil {
+			return results, err
+		}
+	}
+
 	if len(invalid_indexs) == 1 {
 		return results, NewEvaluationError("items", "item_mismatch", "Item at index {index} does not match the schema", map[string]interface{}{
 			"index": invalid_indexs[0],
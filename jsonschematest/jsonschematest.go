@@ -0,0 +1,244 @@
+// Package jsonschematest provides assertion helpers for tests that validate data against JSON schemas.
+//
+// The helpers report failures through the test's Errorf with one line per failing keyword,
+// and compare validation output against golden files with a line diff:
+//
+//	schema := jsonschematest.MustCompile(t, `{"type": "object", "required": ["name"]}`)
+//	jsonschematest.AssertValid(t, schema, map[string]interface{}{"name": "Ada"})
+//	jsonschematest.AssertInvalid(t, schema, map[string]interface{}{}, "required")
+//	jsonschematest.AssertGolden(t, schema.Validate(instance), "testdata/user.golden.json")
+//
+// Golden files are rewritten instead of compared when the test binary runs with
+// -jsonschematest.update or with JSONSCHEMATEST_UPDATE=1 in the environment.
+package jsonschematest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// update makes AssertGolden write golden files instead of comparing against them.
+var update = flag.Bool("jsonschematest.update", false, "update jsonschematest golden files")
+
+// T is the subset of testing.TB used by the helpers.
+type T interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// MustCompile compiles schemaJSON with a new compiler and stops the test if it fails.
+func MustCompile(t T, schemaJSON string) *jsonschema.Schema {
+	t.Helper()
+
+	schema, err := jsonschema.NewCompiler().Compile([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("Failed to compile schema: %v", err)
+		return nil
+	}
+	return schema
+}
+
+// AssertValid checks that instance is valid against schema and reports every failing keyword otherwise.
+func AssertValid(t T, schema *jsonschema.Schema, instance interface{}) bool {
+	t.Helper()
+
+	result := schema.Validate(instance)
+	if result.IsValid() {
+		return true
+	}
+
+	t.Errorf("Expected instance to be valid, but got errors:\n%s", FormatErrors(result))
+	return false
+}
+
+// AssertInvalid checks that instance is invalid against schema. When keywords are given,
+// each of them must be among the keywords that failed.
+func AssertInvalid(t T, schema *jsonschema.Schema, instance interface{}, keywords ...string) bool {
+	t.Helper()
+
+	result := schema.Validate(instance)
+	if result.IsValid() {
+		t.Errorf("Expected instance to be invalid, but it is valid")
+		return false
+	}
+
+	failed := make(map[string]bool)
+	for _, e := range collectErrors(result) {
+		failed[e.keyword] = true
+	}
+
+	var missing []string
+	for _, keyword := range keywords {
+		if !failed[keyword] {
+			missing = append(missing, keyword)
+		}
+	}
+	if len(missing) > 0 {
+		t.Errorf("Expected keywords %s to fail, got errors:\n%s", strings.Join(missing, ", "), FormatErrors(result))
+		return false
+	}
+	return true
+}
+
+// AssertGolden compares the list output of result with the golden file at path, reporting a line diff
+// on mismatch. Details are sorted so the output does not depend on map iteration order.
+func AssertGolden(t T, result *jsonschema.EvaluationResult, path string) bool {
+	t.Helper()
+
+	actual, err := marshalGolden(result)
+	if err != nil {
+		t.Fatalf("Failed to marshal result: %v", err)
+		return false
+	}
+
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("Failed to create golden file directory: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, actual, 0o600); err != nil {
+			t.Fatalf("Failed to write golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	expected, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run with -jsonschematest.update to create it): %v", err)
+		return false
+	}
+
+	if bytes.Equal(bytes.TrimSpace(expected), bytes.TrimSpace(actual)) {
+		return true
+	}
+
+	t.Errorf("Result does not match golden file %s (-want +got):\n%s", path, Diff(string(expected), string(actual)))
+	return false
+}
+
+// FormatErrors renders the errors of result one per line, as "instanceLocation [keyword] message".
+// Instance locations are absolute, starting from the root of the instance.
+func FormatErrors(result *jsonschema.EvaluationResult) string {
+	var lines []string
+	for _, e := range collectErrors(result) {
+		location := e.instanceLocation
+		if location == "" {
+			location = "/"
+		}
+		lines = append(lines, fmt.Sprintf("  %s [%s] %s", location, e.keyword, e.message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// resultError is an error found in an evaluation result, with its absolute instance location.
+type resultError struct {
+	instanceLocation string
+	keyword          string
+	message          string
+}
+
+// collectErrors flattens the errors of result and its details, sorted by location and keyword.
+func collectErrors(result *jsonschema.EvaluationResult) []resultError {
+	var errs []resultError
+	var collect func(r *jsonschema.EvaluationResult, location string)
+	collect = func(r *jsonschema.EvaluationResult, location string) {
+		location += r.InstanceLocation
+		for keyword, err := range r.Errors {
+			errs = append(errs, resultError{instanceLocation: location, keyword: keyword, message: err.Error()})
+		}
+		for _, detail := range r.Details {
+			collect(detail, location)
+		}
+	}
+	collect(result, "")
+
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].instanceLocation != errs[j].instanceLocation {
+			return errs[i].instanceLocation < errs[j].instanceLocation
+		}
+		return errs[i].keyword < errs[j].keyword
+	})
+	return errs
+}
+
+// marshalGolden renders the list output of result as indented JSON with deterministically ordered details.
+func marshalGolden(result *jsonschema.EvaluationResult) ([]byte, error) {
+	list := result.ToList()
+	sortDetails(list)
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// sortDetails orders the details of list recursively by evaluation path, then instance location.
+func sortDetails(list *jsonschema.List) {
+	sort.SliceStable(list.Details, func(i, j int) bool {
+		a, b := list.Details[i], list.Details[j]
+		if a.EvaluationPath != b.EvaluationPath {
+			return a.EvaluationPath < b.EvaluationPath
+		}
+		return a.InstanceLocation < b.InstanceLocation
+	})
+	for i := range list.Details {
+		sortDetails(&list.Details[i])
+	}
+}
+
+// updateGolden reports whether golden files should be rewritten.
+func updateGolden() bool {
+	return *update || os.Getenv("JSONSCHEMATEST_UPDATE") == "1"
+}
+
+// Diff returns a line diff of want and got. Removed lines are prefixed with "-",
+// added lines with "+" and unchanged lines with a space.
+func Diff(want, got string) string {
+	a := strings.Split(strings.TrimRight(want, "\n"), "\n")
+	b := strings.Split(strings.TrimRight(got, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			out.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return out.String()
+}
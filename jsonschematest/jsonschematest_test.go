@@ -0,0 +1,146 @@
+package jsonschematest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a T that records reported failures instead of failing the test.
+type recorder struct {
+	errors []string
+	fatals []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.fatals = append(r.fatals, fmt.Sprintf(format, args...))
+}
+
+const userSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string"},
+		"age": {"type": "integer", "minimum": 0}
+	},
+	"required": ["name"]
+}`
+
+func TestMustCompile(t *testing.T) {
+	rec := &recorder{}
+	assert.NotNil(t, MustCompile(rec, userSchema))
+	assert.Empty(t, rec.fatals)
+
+	assert.Nil(t, MustCompile(rec, `{"type": `))
+	assert.Len(t, rec.fatals, 1)
+}
+
+func TestAssertValid(t *testing.T) {
+	schema := MustCompile(t, userSchema)
+
+	rec := &recorder{}
+	assert.True(t, AssertValid(rec, schema, map[string]interface{}{"name": "Ada"}))
+	assert.Empty(t, rec.errors)
+
+	assert.False(t, AssertValid(rec, schema, map[string]interface{}{"age": -1}))
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], "/ [required]")
+	assert.Contains(t, rec.errors[0], "/age [minimum]")
+}
+
+func TestAssertInvalid(t *testing.T) {
+	schema := MustCompile(t, userSchema)
+
+	t.Run("valid instance", func(t *testing.T) {
+		rec := &recorder{}
+		assert.False(t, AssertInvalid(rec, schema, map[string]interface{}{"name": "Ada"}))
+		assert.Len(t, rec.errors, 1)
+	})
+
+	t.Run("expected keywords fail", func(t *testing.T) {
+		rec := &recorder{}
+		assert.True(t, AssertInvalid(rec, schema, map[string]interface{}{"age": -1}, "required", "minimum"))
+		assert.Empty(t, rec.errors)
+	})
+
+	t.Run("missing keyword", func(t *testing.T) {
+		rec := &recorder{}
+		assert.False(t, AssertInvalid(rec, schema, map[string]interface{}{}, "maximum"))
+		require.Len(t, rec.errors, 1)
+		assert.Contains(t, rec.errors[0], "Expected keywords maximum to fail")
+	})
+}
+
+func TestAssertGolden(t *testing.T) {
+	schema := MustCompile(t, userSchema)
+	path := filepath.Join(t.TempDir(), "golden", "user.json")
+	result := schema.Validate(map[string]interface{}{"name": "Ada", "age": -1})
+
+	rec := &recorder{}
+	assert.False(t, AssertGolden(rec, result, path))
+	assert.Len(t, rec.fatals, 1, "a missing golden file is fatal")
+
+	t.Setenv("JSONSCHEMATEST_UPDATE", "1")
+	rec = &recorder{}
+	assert.True(t, AssertGolden(rec, result, path))
+	assert.Empty(t, rec.fatals)
+
+	t.Setenv("JSONSCHEMATEST_UPDATE", "")
+	for i := 0; i < 5; i++ {
+		rec = &recorder{}
+		assert.True(t, AssertGolden(rec, schema.Validate(map[string]interface{}{"name": "Ada", "age": -1}), path))
+		assert.Empty(t, rec.errors, "golden output must not depend on map iteration order")
+	}
+
+	rec = &recorder{}
+	assert.False(t, AssertGolden(rec, schema.Validate(map[string]interface{}{"name": "Ada", "age": 1}), path))
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], "-want +got")
+
+	data, err := os.ReadFile(path) //nolint:gosec
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"valid": false`)
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     string
+		got      string
+		expected string
+	}{
+		{
+			name:     "equal",
+			want:     "a\nb\n",
+			got:      "a\nb\n",
+			expected: "  a\n  b\n",
+		},
+		{
+			name:     "changed line",
+			want:     "a\nb\nc",
+			got:      "a\nx\nc",
+			expected: "  a\n- b\n+ x\n  c\n",
+		},
+		{
+			name:     "added and removed lines",
+			want:     "a\nb",
+			got:      "b\nc",
+			expected: "- a\n  b\n+ c\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Diff(tt.want, tt.got))
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keyword is a compiled custom keyword of a schema, evaluated with the other keywords of the schema.
+type Keyword interface {
+	// Evaluate validates instance, returning an error when it does not conform to the keyword.
+	Evaluate(ctx *KeywordContext, instance interface{}) *EvaluationError
+}
+
+// KeywordFunc adapts a function to the Keyword interface.
+type KeywordFunc func(ctx *KeywordContext, instance interface{}) *EvaluationError
+
+// Evaluate calls f.
+func (f KeywordFunc) Evaluate(ctx *KeywordContext, instance interface{}) *EvaluationError {
+	return f(ctx, instance)
+}
+
+// KeywordFactory compiles the value of a custom keyword in schema, such as the currency code of an
+// x-currency keyword. It returns an error when the value is invalid, which fails the compilation
+// of the schema, or a nil Keyword to ignore the keyword in this schema.
+type KeywordFactory func(schema *Schema, value interface{}) (Keyword, error)
+
+// KeywordContext is the context of the evaluation of a custom keyword.
+type KeywordContext struct {
+	Schema  *Schema // Schema containing the keyword.
+	Keyword string  // Name of the keyword.
+
+	result *EvaluationResult
+}
+
+// Annotate reports annotation as the annotation of the keyword, which the output formats list
+// with the annotations of the schema.
+func (ctx *KeywordContext) Annotate(annotation interface{}) {
+	ctx.result.AddAnnotation(ctx.Keyword, annotation)
+}
+
+// Vocabulary is a custom vocabulary: a set of keywords enabled in the schemas whose meta-schema
+// declares the vocabulary in $vocabulary.
+type Vocabulary struct {
+	URI      string                    // URI of the vocabulary, as declared in $vocabulary.
+	Keywords map[string]KeywordFactory // Keywords of the vocabulary, by name.
+}
+
+// RegisterKeyword registers a custom keyword, such as x-currency, compiled with factory in every
+// schema where it appears and then evaluated with the other keywords of the schema.
+func (c *Compiler) RegisterKeyword(name string, factory KeywordFactory) *Compiler {
+	if c.Keywords == nil {
+		c.Keywords = make(map[string]KeywordFactory)
+	}
+	c.Keywords[name] = factory
+	return c
+}
+
+// RegisterVocabulary registers a custom vocabulary, whose keywords are enabled in the schemas whose
+// meta-schema, named by $schema, declares the vocabulary in $vocabulary. Schemas whose meta-schema
+// requires a vocabulary that is neither registered nor published at json-schema.org are rejected
+// with ErrUnknownVocabulary.
+func (c *Compiler) RegisterVocabulary(vocabulary Vocabulary) *Compiler {
+	if c.Vocabularies == nil {
+		c.Vocabularies = make(map[string]*Vocabulary)
+	}
+	c.Vocabularies[vocabulary.URI] = &vocabulary
+	return c
+}
+
+// compiledKeyword is a custom keyword compiled in a schema.
+type compiledKeyword struct {
+	name    string
+	keyword Keyword
+}
+
+// compileKeywords compiles the custom keywords of s and its subschemas, their errorMessage
+// keywords and the regular expressions of their patternProperties, and checks that the
+// vocabularies their meta-schemas require are known.
+func (c *Compiler) compileKeywords(s *Schema) error {
+	var err error
+	walkSchema(s, func(pointer string, schema *Schema) bool {
+		if err != nil {
+			return false
+		}
+		schema.keywords = nil
+		if patternsErr := schema.compilePatterns(); patternsErr != nil {
+			err = fmt.Errorf("%w at '%s'", patternsErr, pointer)
+			return false
+		}
+		if c.Keywords[errorMessageKeyword] == nil {
+			messages, messagesErr := compileErrorMessages(schema)
+			if messagesErr != nil {
+				err = fmt.Errorf("%w at '%s'", messagesErr, pointer)
+				return false
+			}
+			schema.errorMessages = messages
+		}
+		if schema.Schema != "" {
+			for uri, required := range c.metaSchemaVocabularies(schema.Schema, schema) {
+				if required && !strings.HasPrefix(uri, "https://json-schema.org/") && c.Vocabularies[uri] == nil {
+					err = fmt.Errorf("%w: %s required by %s", ErrUnknownVocabulary, uri, schema.Schema)
+					return false
+				}
+			}
+		}
+		if len(schema.Extensions) == 0 || len(c.Keywords) == 0 && len(c.Vocabularies) == 0 {
+			return true
+		}
+
+		var vocabularies map[string]bool
+		for _, name := range sortedKeys(schema.Extensions) {
+			factory := c.Keywords[name]
+			if factory == nil && len(c.Vocabularies) > 0 {
+				if vocabularies == nil {
+					vocabularies = c.schemaVocabularies(schema)
+				}
+				for uri := range vocabularies {
+					if vocabulary := c.Vocabularies[uri]; vocabulary != nil && vocabulary.Keywords[name] != nil {
+						factory = vocabulary.Keywords[name]
+						break
+					}
+				}
+			}
+			if factory == nil {
+				continue
+			}
+			keyword, factoryErr := factory(schema, schema.Extensions[name])
+			if factoryErr != nil {
+				err = fmt.Errorf("%w: %s at '%s': %w", ErrInvalidKeyword, name, pointer, factoryErr)
+				return false
+			}
+			if keyword != nil {
+				schema.keywords = append(schema.keywords, compiledKeyword{name: name, keyword: keyword})
+			}
+		}
+		return err == nil
+	})
+	return err
+}
+
+// schemaVocabularies returns the vocabularies declared by the meta-schema of s, named by the
+// $schema of s or of the nearest schema enclosing it.
+func (c *Compiler) schemaVocabularies(s *Schema) map[string]bool {
+	for schema := s; schema != nil; schema = schema.parent {
+		if schema.Schema != "" {
+			return c.metaSchemaVocabularies(schema.Schema, schema)
+		}
+	}
+	return nil
+}
+
+// metaSchemaVocabularies returns the $vocabulary of the meta-schema at dialect, mapping the URI
+// of each vocabulary to whether it is required. Custom dialects and the standard meta-schemas
+// published at json-schema.org declare no custom vocabulary; other meta-schemas are loaded like references, and
+// self is used when it describes itself.
+func (c *Compiler) metaSchemaVocabularies(dialect string, self *Schema) map[string]bool {
+	if c.dialect(dialect) != nil {
+		return nil
+	}
+	dialect = strings.TrimSuffix(dialect, "#")
+	if strings.HasPrefix(dialect, "https://json-schema.org/") || strings.HasPrefix(dialect, "http://json-schema.org/") {
+		return nil
+	}
+	if c.metaVocabularies == nil {
+		c.metaVocabularies = make(map[string]map[string]bool)
+	}
+	if vocabularies, ok := c.metaVocabularies[dialect]; ok {
+		return vocabularies
+	}
+	c.metaVocabularies[dialect] = nil // Guards against meta-schemas describing each other.
+
+	meta := self
+	if self.ID != dialect && self.uri != dialect {
+		var err error
+		if meta, err = c.getSchema(dialect); err != nil {
+			return nil
+		}
+	}
+	declared, _ := meta.Extensions["$vocabulary"].(map[string]interface{})
+	vocabularies := make(map[string]bool, len(declared))
+	for uri, required := range declared {
+		vocabularies[uri], _ = required.(bool)
+	}
+	c.metaVocabularies[dialect] = vocabularies
+	return vocabularies
+}
+
+// evaluateKeywords evaluates the custom keywords of s.
+func (s *Schema) evaluateKeywords(instance interface{}, result *EvaluationResult) {
+	for _, compiled := range s.keywords {
+		ctx := &KeywordContext{Schema: s, Keyword: compiled.name, result: result}
+		if err := compiled.keyword.Evaluate(ctx, instance); err != nil {
+			if err.Keyword == "" {
+				err.Keyword = compiled.name
+			}
+			result.AddError(err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// currencyKeyword compiles x-currency, requiring a string instance starting with its currency code.
+func currencyKeyword(schema *Schema, value interface{}) (Keyword, error) {
+	currency, ok := value.(string)
+	if !ok || len(currency) != 3 {
+		return nil, errors.New("x-currency must be a currency code")
+	}
+	return KeywordFunc(func(ctx *KeywordContext, instance interface{}) *EvaluationError {
+		amount, ok := instance.(string)
+		if !ok {
+			return nil
+		}
+		if len(amount) < 3 || amount[:3] != currency {
+			return NewEvaluationError("", "currency_mismatch", "Amount {amount} is not in {currency}", map[string]interface{}{
+				"amount": amount, "currency": currency,
+			})
+		}
+		ctx.Annotate(currency)
+		return nil
+	}), nil
+}
+
+func TestRegisterKeyword(t *testing.T) {
+	compiler := NewCompiler().RegisterKeyword("x-currency", currencyKeyword)
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"properties": {"price": {"type": "string", "x-currency": "EUR"}}
+	}`))
+	require.NoError(t, err)
+
+	valid := schema.Validate(map[string]interface{}{"price": "EUR 12.50"})
+	assert.True(t, valid.IsValid())
+	annotations := valid.ToBasic().Annotations
+	require.Len(t, annotations, 1)
+	assert.Equal(t, "/properties/price/x-currency", annotations[0].KeywordLocation)
+	assert.Equal(t, "EUR", annotations[0].Annotation)
+
+	result := schema.Validate(map[string]interface{}{"price": "USD 12.50"})
+	require.False(t, result.IsValid())
+	list := result.ToList()
+	require.Len(t, list.Details, 1)
+	assert.Equal(t, "Amount USD 12.50 is not in EUR", list.Details[0].Errors["x-currency"])
+
+	_, err = compiler.Compile([]byte(`{"properties": {"price": {"x-currency": 1}}}`))
+	require.ErrorIs(t, err, ErrInvalidKeyword)
+	assert.Contains(t, err.Error(), "/properties/price")
+
+	schema, err = NewCompiler().Compile([]byte(`{"x-currency": "EUR"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("USD 1").IsValid(), "unregistered keywords are annotations")
+}
+
+func TestRegisterVocabulary(t *testing.T) {
+	const vocabulary = "https://example.com/vocab/finance"
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/meta/finance",
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$vocabulary": {
+			"https://json-schema.org/draft/2020-12/vocab/core": true,
+			"https://json-schema.org/draft/2020-12/vocab/validation": true,
+			"https://example.com/vocab/finance": true
+		}
+	}`))
+	require.NoError(t, err)
+
+	source := []byte(`{"$schema": "https://example.com/meta/finance", "x-currency": "EUR"}`)
+	_, err = compiler.Compile(source)
+	require.ErrorIs(t, err, ErrUnknownVocabulary, "the meta-schema requires an unknown vocabulary")
+
+	compiler.RegisterVocabulary(Vocabulary{URI: vocabulary, Keywords: map[string]KeywordFactory{"x-currency": currencyKeyword}})
+	schema, err := compiler.Compile(source)
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("EUR 1").IsValid())
+	assert.False(t, schema.Validate("USD 1").IsValid())
+
+	schema, err = compiler.Compile([]byte(`{"x-currency": "EUR"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("USD 1").IsValid(), "the keywords of a vocabulary apply only where it is declared")
+}
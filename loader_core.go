@@ -0,0 +1,10 @@
+//go:build jsonschema_core
+
+package jsonschema
+
+// setupLoaders registers no HTTP loader in the core build, which leaves out net/http: schemas are
+// compiled from bytes, read from files, or fetched by loaders registered with RegisterLoader.
+func (c *Compiler) setupLoaders() {}
+
+// httpOptions is empty in the core build, which has no default HTTP loader to configure.
+type httpOptions struct{}
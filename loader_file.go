@@ -0,0 +1,120 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// fsMount is a file system serving the documents whose URIs start with prefix, see UseFS.
+type fsMount struct {
+	fsys   fs.FS
+	prefix string
+}
+
+// setupFileLoader registers the loader of file URIs, such as file:///etc/schemas/user.json, which
+// reads the documents from the local file system.
+func (c *Compiler) setupFileLoader() {
+	c.RegisterLoader("file", loadFile)
+}
+
+// loadFile opens the local file of a file URI.
+func loadFile(uri string) (io.ReadCloser, error) {
+	path, err := filePath(uri)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToFetch, err)
+	}
+	return file, nil
+}
+
+// filePath returns the local path of a file URI.
+func filePath(uri string) (string, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Host != "" && parsed.Host != "localhost" {
+		return "", fmt.Errorf("%w: file URI of remote host %s", ErrFailedToFetch, parsed.Host)
+	}
+	path := parsed.Path
+	if runtime.GOOS == "windows" {
+		path = filepath.FromSlash(strings.TrimPrefix(path, "/")) // file:///C:/schemas/user.json
+	}
+	return path, nil
+}
+
+// UseFS resolves the documents whose URIs start with prefix, such as
+// https://example.com/schemas/, from fsys, such as an embed.FS or os.DirFS, instead of fetching
+// them: the rest of the URI is the path of the document in fsys. This lets schemas that $ref each
+// other by their published URIs be shipped in the binary, or read from a local directory, without
+// a custom loader. Documents missing from fsys are not fetched either. When several file systems
+// match a URI, the one with the longest prefix is used.
+func (c *Compiler) UseFS(fsys fs.FS, prefix string) *Compiler {
+	c.mounts = append(c.mounts, fsMount{fsys: fsys, prefix: prefix})
+	return c
+}
+
+// openMounted opens the document at uri in the file system mounted with the longest prefix of uri
+// in c or the compilers it is layered on, and reports whether one is mounted.
+func (c *Compiler) openMounted(uri string) (io.ReadCloser, bool, error) {
+	mount, name, err := c.mounted(uri)
+	if mount == nil || err != nil {
+		return nil, mount != nil, err
+	}
+	file, err := mount.fsys.Open(name)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: %w", ErrFailedToFetch, err)
+	}
+	return file, true, nil
+}
+
+// mounted returns the file system mounted with the longest prefix of uri in c or the compilers it
+// is layered on, if any, and the path of the document of uri in it.
+func (c *Compiler) mounted(uri string) (*fsMount, string, error) {
+	var mount *fsMount
+	for compiler := c; compiler != nil; compiler = compiler.base {
+		for i, candidate := range compiler.mounts {
+			if strings.HasPrefix(uri, candidate.prefix) && (mount == nil || len(candidate.prefix) > len(mount.prefix)) {
+				mount = &compiler.mounts[i]
+			}
+		}
+	}
+	if mount == nil {
+		return nil, "", nil
+	}
+	name, err := url.PathUnescape(strings.TrimPrefix(uri, mount.prefix))
+	if err != nil || !fs.ValidPath(name) {
+		return mount, "", fmt.Errorf("%w: %s is not a path of the file system of %s", ErrFailedToFetch, uri, mount.prefix)
+	}
+	return mount, name, nil
+}
+
+// statLocal returns the file information of the document at uri when it is read from a local
+// file: one of a file system of UseFS, or of a file URI. It reports whether the document is local.
+func (c *Compiler) statLocal(uri string) (fs.FileInfo, bool, error) {
+	mount, name, err := c.mounted(uri)
+	switch {
+	case err != nil:
+		return nil, true, err
+	case mount != nil:
+		info, err := fs.Stat(mount.fsys, name)
+		return info, true, err
+	case !strings.HasPrefix(uri, "file:"):
+		return nil, false, nil
+	}
+	path, err := filePath(uri)
+	if err != nil {
+		return nil, true, err
+	}
+	info, err := os.Stat(path)
+	return info, true, err
+}
@@ -0,0 +1,54 @@
+package jsonschema
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLoader(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "name.json"), []byte(`{"type": "string", "minLength": 2}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user.json"), []byte(`{"properties": {"name": {"$ref": "name.json"}}}`), 0o600))
+
+	uri := (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, "user.json"))}).String()
+	schema, err := NewCompiler().GetSchema(uri)
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(map[string]interface{}{"name": "Ann"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"name": "A"}).IsValid())
+
+	_, err = NewCompiler().GetSchema(uri + ".missing")
+	assert.ErrorIs(t, err, ErrFailedToFetch)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestUseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"user.json":          {Data: []byte(`{"properties": {"name": {"$ref": "types/name.json"}, "tags": {"$ref": "https://example.com/other/tags.json"}}}`)},
+		"types/name.json":    {Data: []byte(`{"type": "string"}`)},
+		"other/tags.json":    {Data: []byte(`{"type": "array"}`)},
+		"specific/tags.json": {Data: []byte(`{"type": "array", "maxItems": 1}`)},
+	}
+	source := &reloadSource{documents: map[string]string{}}
+	compiler := NewCompiler().
+		UseFS(fsys, "https://example.com/schemas/").
+		UseFS(fstest.MapFS{"tags.json": fsys["specific/tags.json"]}, "https://example.com/other/").
+		RegisterLoader("https", source.load)
+
+	schema, err := compiler.GetSchema("https://example.com/schemas/user.json")
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(map[string]interface{}{"name": "Ann", "tags": []interface{}{"a"}}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"name": 1}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"tags": []interface{}{"a", "b"}}).IsValid(), "the longest prefix wins")
+
+	_, err = compiler.GetSchema("https://example.com/schemas/missing.json")
+	assert.ErrorIs(t, err, ErrFailedToFetch)
+	_, err = compiler.GetSchema("https://example.com/schemas/../secret.json")
+	assert.ErrorIs(t, err, ErrFailedToFetch)
+	assert.Zero(t, source.fetched, "mounted documents are never fetched")
+}
@@ -0,0 +1,239 @@
+//go:build !jsonschema_core
+
+package jsonschema
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// schemaAcceptHeader prefers JSON schema documents and accepts YAML ones.
+const schemaAcceptHeader = "application/schema+json, application/json;q=0.9, application/schema+yaml;q=0.8, application/yaml;q=0.8, */*;q=0.1"
+
+// defaultHTTPTimeout bounds the requests of the default HTTP loader without a client set with
+// SetHTTPClient.
+const defaultHTTPTimeout = 10 * time.Second
+
+// httpOptions configures the default HTTP loader.
+type httpOptions struct {
+	client    *http.Client                                       // Client of the requests, see SetHTTPClient.
+	headers   map[string]http.Header                             // Headers added to the requests by host, see SetHTTPHeader.
+	redirects func(req *http.Request, via []*http.Request) error // Redirect policy, see SetRedirectPolicy.
+	retry     HTTPRetryPolicy                                    // Retries of failed requests, see SetHTTPRetry.
+}
+
+// HTTPRetryPolicy configures how the default HTTP loader retries requests that fail with a network
+// error, a 429 Too Many Requests or a 5xx status.
+type HTTPRetryPolicy struct {
+	MaxRetries int           // Retries after the first attempt; 0 disables retries.
+	Backoff    time.Duration // Wait before the first retry, doubled before each following one.
+	MaxBackoff time.Duration // Longest wait between two attempts; unlimited when 0.
+}
+
+// SetHTTPClient sets the client of the default HTTP loader, for its transport, proxy, TLS
+// configuration or timeout. Its redirect policy applies unless SetRedirectPolicy is called; without
+// one, the loader follows MaxRedirects redirects. The default is a client with a 10 second timeout.
+func (c *Compiler) SetHTTPClient(client *http.Client) *Compiler {
+	c.http.client = client
+	return c
+}
+
+// SetHTTPHeader adds a header to the requests of the default HTTP loader to host, such as
+// registry.example.com or localhost:8080, for example the bearer token of a private schema
+// registry. The header is not sent with requests redirected to other hosts.
+func (c *Compiler) SetHTTPHeader(host, name, value string) *Compiler {
+	// The headers are copied, since overlays and reloaded compilers share them.
+	headers := maps.Clone(c.http.headers)
+	if headers == nil {
+		headers = make(map[string]http.Header)
+	}
+	header := headers[host].Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Add(name, value)
+	headers[host] = header
+	c.http.headers = headers
+	return c
+}
+
+// SetRedirectPolicy sets the policy of the default HTTP loader for redirects, with the signature
+// of http.Client.CheckRedirect, instead of following MaxRedirects redirects.
+func (c *Compiler) SetRedirectPolicy(policy func(req *http.Request, via []*http.Request) error) *Compiler {
+	c.http.redirects = policy
+	return c
+}
+
+// SetHTTPRetry sets how the default HTTP loader retries failed requests. By default they are not
+// retried.
+func (c *Compiler) SetHTTPRetry(policy HTTPRetryPolicy) *Compiler {
+	c.http.retry = policy
+	return c
+}
+
+// setupLoaders configures default loaders for fetching schemas via HTTP/HTTPS, which are canceled
+// with the context of CompileContext and GetSchemaContext. Responses served with a YAML media type
+// are converted to JSON, whatever the extension of the URL.
+func (c *Compiler) setupLoaders() {
+	c.RegisterContextLoader("http", c.loadHTTP)
+	c.RegisterContextLoader("https", c.loadHTTP)
+}
+
+// loadHTTP fetches the document at url with the options of the compiler, retrying failed requests.
+func (c *Compiler) loadHTTP(ctx context.Context, url string) (io.ReadCloser, error) {
+	client := c.httpClient()
+	backoff := c.http.retry.Backoff
+	for attempt := 0; ; attempt++ {
+		body, retry, err := c.fetchHTTP(ctx, client, url)
+		if !retry || attempt >= c.http.retry.MaxRetries {
+			return body, err
+		}
+		if c.http.retry.MaxBackoff > 0 {
+			backoff = min(backoff, c.http.retry.MaxBackoff)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrFailedToFetch, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// fetchHTTP makes a single request for the document at url, and reports whether it is worth
+// retrying when it fails.
+func (c *Compiler) fetchHTTP(ctx context.Context, client *http.Client, url string) (io.ReadCloser, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", schemaAcceptHeader)
+	if len(c.Decompressors) > 0 {
+		req.Header.Set("Accept-Encoding", c.acceptEncoding())
+	}
+	c.setHTTPHeaders(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrTooManyRedirects) {
+			return nil, false, ErrTooManyRedirects
+		}
+		if ctx.Err() != nil {
+			return nil, false, fmt.Errorf("%w: %w", ErrFailedToFetch, ctx.Err())
+		}
+		var redirectErr *redirectPolicyError
+		if errors.As(err, &redirectErr) {
+			return nil, false, fmt.Errorf("%w: %w", ErrFailedToFetch, redirectErr.err)
+		}
+		return nil, true, ErrFailedToFetch
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err = resp.Body.Close()
+		if err != nil {
+			return nil, false, err
+		}
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return nil, retry, ErrInvalidHTTPStatusCode
+	}
+
+	body, err := c.decompressBody(resp.Header.Get("Content-Encoding"), resp.Body)
+	if err != nil {
+		resp.Body.Close() //nolint:errcheck
+		return nil, false, err
+	}
+
+	if !isYAMLMediaType(resp.Header.Get("Content-Type")) {
+		return body, false, nil
+	}
+	defer body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, false, ErrFailedToReadData
+	}
+	if data, err = yamlToJSON(data); err != nil {
+		return nil, false, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), false, nil
+}
+
+// redirectPolicyError is an error of the redirect policy set with SetRedirectPolicy, which makes
+// the request fail without being retried.
+type redirectPolicyError struct {
+	err error
+}
+
+func (e *redirectPolicyError) Error() string {
+	return e.err.Error()
+}
+
+// httpClient returns the client of the default HTTP loader: the client set with SetHTTPClient, or
+// the default one, with the redirect policy of the compiler. Redirected requests carry the headers
+// set with SetHTTPHeader for their own host only.
+func (c *Compiler) httpClient() *http.Client {
+	client := http.Client{Timeout: defaultHTTPTimeout}
+	if c.http.client != nil {
+		client = *c.http.client
+	}
+	policy := client.CheckRedirect
+	if c.http.redirects != nil {
+		policy = c.http.redirects
+	}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		switch {
+		case policy == nil && len(via) > c.MaxRedirects:
+			return ErrTooManyRedirects
+		case policy != nil:
+			err := policy(req, via)
+			if err != nil && !errors.Is(err, ErrTooManyRedirects) && !errors.Is(err, http.ErrUseLastResponse) {
+				return &redirectPolicyError{err: err}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		c.setHTTPHeaders(req)
+		return nil
+	}
+	return &client
+}
+
+// setHTTPHeaders sets the headers of SetHTTPHeader for the host of req, removing those of other
+// hosts, which redirected requests inherit from the first one.
+func (c *Compiler) setHTTPHeaders(req *http.Request) {
+	for host, header := range c.http.headers {
+		if host == req.URL.Host {
+			continue
+		}
+		for name := range header {
+			req.Header.Del(name)
+		}
+	}
+	for name, values := range c.http.headers[req.URL.Host] {
+		req.Header[name] = slices.Clone(values)
+	}
+}
+
+// isYAMLMediaType reports whether a Content-Type header denotes a YAML document,
+// such as application/yaml, application/schema+yaml or text/yaml.
+func isYAMLMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+yaml")
+}
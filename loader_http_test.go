@@ -0,0 +1,189 @@
+//go:build !jsonschema_core
+
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	remoteSchemaURL = "https://json-schema.org/draft/2020-12/schema"
+)
+
+func TestValidateRemoteSchema(t *testing.T) {
+	compiler := NewCompiler()
+
+	// Load the meta-schema
+	metaSchema, err := compiler.GetSchema(remoteSchemaURL)
+	if err != nil {
+		t.Fatalf("Failed to load meta-schema: %v", err)
+	}
+
+	// Ensure that the schema is not nil
+	if metaSchema == nil {
+		t.Fatal("Meta-schema is nil")
+	}
+
+	// Verify the ID of the retrieved schema
+	expectedID := remoteSchemaURL
+	if metaSchema.ID != expectedID {
+		t.Errorf("Expected schema with ID %s, got %s", expectedID, metaSchema.ID)
+	}
+}
+
+func TestHTTPLoaderContentNegotiation(t *testing.T) {
+	var accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		switch r.URL.Path {
+		case "/user":
+			w.Header().Set("Content-Type", "application/schema+yaml; charset=utf-8")
+			fmt.Fprint(w, "type: object\nrequired: [name]\n")
+		case "/moved":
+			http.Redirect(w, r, "/user", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	compiler := NewCompiler()
+	schema, err := compiler.GetSchema(server.URL + "/moved")
+	if err != nil {
+		t.Fatalf("Failed to load YAML schema through a redirect: %v", err)
+	}
+	if accept != schemaAcceptHeader {
+		t.Errorf("Expected Accept header %q, got %q", schemaAcceptHeader, accept)
+	}
+	if schema.Validate(map[string]interface{}{}).IsValid() {
+		t.Error("Expected the YAML schema to require name")
+	}
+
+	_, err = NewCompiler().SetMaxRedirects(0).GetSchema(server.URL + "/moved")
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("Expected ErrTooManyRedirects, got %v", err)
+	}
+}
+
+func TestHTTPLoaderHeaders(t *testing.T) {
+	var other *httptest.Server
+	var leaked string
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Registry") != "private" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/elsewhere" {
+			http.Redirect(w, r, other.URL+"/schema", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, `{"type": "string"}`)
+	}))
+	defer registry.Close()
+	other = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Get("X-Registry")
+		fmt.Fprint(w, `{"type": "integer"}`)
+	}))
+	defer other.Close()
+
+	host := strings.TrimPrefix(registry.URL, "http://")
+	compiler := NewCompiler().
+		SetHTTPHeader(host, "Authorization", "Bearer secret").
+		SetHTTPHeader(host, "X-Registry", "private")
+	schema, err := compiler.GetSchema(registry.URL + "/user")
+	if err != nil {
+		t.Fatalf("Failed to load schema with headers: %v", err)
+	}
+	if !schema.Validate("name").IsValid() {
+		t.Error("Expected the schema of the registry")
+	}
+
+	if _, err = compiler.GetSchema(registry.URL + "/elsewhere"); err != nil {
+		t.Fatalf("Failed to follow redirect: %v", err)
+	}
+	if leaked != "" {
+		t.Errorf("Expected headers not to be sent to another host, got %q", leaked)
+	}
+
+	if _, err = NewCompiler().GetSchema(registry.URL + "/user"); !errors.Is(err, ErrInvalidHTTPStatusCode) {
+		t.Errorf("Expected ErrInvalidHTTPStatusCode without headers, got %v", err)
+	}
+}
+
+func TestHTTPLoaderRetry(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 || r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"type": "string"}`)
+	}))
+	defer server.Close()
+
+	_, err := NewCompiler().GetSchema(server.URL + "/flaky")
+	if !errors.Is(err, ErrInvalidHTTPStatusCode) || attempts != 1 {
+		t.Errorf("Expected a single failed attempt without retries, got %d and %v", attempts, err)
+	}
+
+	attempts = 0
+	compiler := NewCompiler().SetHTTPRetry(HTTPRetryPolicy{MaxRetries: 2, Backoff: time.Millisecond})
+	if _, err = compiler.GetSchema(server.URL + "/flaky"); err != nil {
+		t.Fatalf("Expected the third attempt to succeed: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+
+	attempts = 0
+	compiler = NewCompiler().SetHTTPRetry(HTTPRetryPolicy{MaxRetries: 1, Backoff: time.Millisecond})
+	if _, err = compiler.GetSchema(server.URL + "/down"); !errors.Is(err, ErrInvalidHTTPStatusCode) || attempts != 2 {
+		t.Errorf("Expected 2 failed attempts, got %d and %v", attempts, err)
+	}
+}
+
+func TestHTTPLoaderClientAndRedirectPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/schema", http.StatusFound)
+		default:
+			fmt.Fprint(w, `{"type": "string"}`)
+		}
+	}))
+	defer server.Close()
+
+	var requests int
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	compiler := NewCompiler().SetHTTPClient(client)
+	if _, err := compiler.GetSchema(server.URL + "/moved"); err != nil {
+		t.Fatalf("Failed to load schema with a custom client: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the client to make 2 requests, got %d", requests)
+	}
+
+	errCrossOrigin := errors.New("redirect refused")
+	compiler = NewCompiler().SetRedirectPolicy(func(*http.Request, []*http.Request) error {
+		return errCrossOrigin
+	})
+	if _, err := compiler.GetSchema(server.URL + "/moved"); !errors.Is(err, errCrossOrigin) || !errors.Is(err, ErrFailedToFetch) {
+		t.Errorf("Expected the error of the redirect policy, got %v", err)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
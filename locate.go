@@ -0,0 +1,256 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// Position is the position of a token in a JSON document.
+type Position struct {
+	Offset int `json:"offset"` // Offset in bytes from the start of the document.
+	Line   int `json:"line"`   // Line number, starting at 1.
+	Column int `json:"column"` // Column in bytes, starting at 1.
+}
+
+// LocateValue returns the position of the value at the JSON Pointer location in the raw JSON
+// document data, such as the instance location of an error. Properties given more than once are
+// located at their last occurrence, the one decoders keep.
+func LocateValue(data []byte, location string) (Position, error) {
+	offset, _, err := locateJSON(data, location)
+	if err != nil {
+		return Position{}, err
+	}
+	return positionAt(data, offset), nil
+}
+
+// LocateKey returns the position of the key of the property at the JSON Pointer location in the
+// raw JSON document data, such as the location of a property name rejected by propertyNames.
+func LocateKey(data []byte, location string) (Position, error) {
+	_, keyOffset, err := locateJSON(data, location)
+	if err != nil {
+		return Position{}, err
+	}
+	if keyOffset < 0 {
+		return Position{}, fmt.Errorf("%w: %s is not a property", ErrLocationNotFound, location)
+	}
+	return positionAt(data, keyOffset), nil
+}
+
+// positionAt returns the position of the byte at offset in data.
+func positionAt(data []byte, offset int) Position {
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	column := offset + 1
+	if i := bytes.LastIndexByte(data[:offset], '\n'); i >= 0 {
+		column = offset - i
+	}
+	return Position{Offset: offset, Line: line, Column: column}
+}
+
+// locateJSON returns the offsets of the value at location in data and of its key, or -1 when the
+// value is not a property.
+func locateJSON(data []byte, location string) (int, int, error) {
+	scanner := &jsonScanner{data: data}
+	scanner.skipSpace()
+	keyOffset := -1
+	if location == "" {
+		return scanner.pos, keyOffset, nil
+	}
+	if !strings.HasPrefix(location, "/") {
+		return 0, 0, fmt.Errorf("%w: %s is not a JSON Pointer", ErrLocationNotFound, location)
+	}
+
+	for _, token := range strings.Split(location[1:], "/") {
+		token = unescapeJSONPointer(token)
+		var err error
+		switch scanner.peek() {
+		case '{':
+			keyOffset, err = scanner.findProperty(token)
+		case '[':
+			keyOffset = -1
+			err = scanner.findItem(token)
+		default:
+			err = fmt.Errorf("%w: %s", ErrLocationNotFound, location)
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return scanner.pos, keyOffset, nil
+}
+
+// jsonScanner reads the raw tokens of a JSON document without decoding its values.
+type jsonScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *jsonScanner) peek() byte {
+	if s.pos < len(s.data) {
+		return s.data[s.pos]
+	}
+	return 0
+}
+
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// syntaxError returns the error of unexpected data at the position of the scanner.
+func (s *jsonScanner) syntaxError() error {
+	return fmt.Errorf("%w: invalid JSON at offset %d", ErrJSONUnmarshalError, s.pos)
+}
+
+// expect consumes the byte c, after white space.
+func (s *jsonScanner) expect(c byte) error {
+	s.skipSpace()
+	if s.peek() != c {
+		return s.syntaxError()
+	}
+	s.pos++
+	s.skipSpace()
+	return nil
+}
+
+// readString reads the string at the position of the scanner and returns it decoded.
+func (s *jsonScanner) readString() (string, error) {
+	start := s.pos
+	if s.peek() != '"' {
+		return "", s.syntaxError()
+	}
+	escaped := false
+	for s.pos++; s.pos < len(s.data); s.pos++ {
+		switch s.data[s.pos] {
+		case '\\':
+			escaped = true
+			s.pos++
+		case '"':
+			s.pos++
+			raw := s.data[start:s.pos]
+			if !escaped {
+				return string(raw[1 : len(raw)-1]), nil
+			}
+			var decoded string
+			if err := json.Unmarshal(raw, &decoded); err != nil {
+				return "", fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+			}
+			return decoded, nil
+		}
+	}
+	return "", s.syntaxError()
+}
+
+// skipValue moves the scanner past the value at its position.
+func (s *jsonScanner) skipValue() error {
+	switch s.peek() {
+	case '"':
+		_, err := s.readString()
+		return err
+	case '{', '[':
+		depth := 0
+		for s.pos < len(s.data) {
+			switch s.data[s.pos] {
+			case '"':
+				if _, err := s.readString(); err != nil {
+					return err
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					s.pos++
+					return nil
+				}
+			}
+			s.pos++
+		}
+		return s.syntaxError()
+	default:
+		start := s.pos
+		for s.pos < len(s.data) && !bytes.ContainsAny(s.data[s.pos:s.pos+1], ",}] \t\n\r") {
+			s.pos++
+		}
+		if s.pos == start {
+			return s.syntaxError()
+		}
+		return nil
+	}
+}
+
+// findProperty moves the scanner to the value of the last property named name of the object at
+// its position, and returns the offset of the key of that property.
+func (s *jsonScanner) findProperty(name string) (int, error) {
+	if err := s.expect('{'); err != nil {
+		return 0, err
+	}
+	valueOffset, keyOffset := -1, -1
+	for s.peek() != '}' {
+		start := s.pos
+		key, err := s.readString()
+		if err != nil {
+			return 0, err
+		}
+		if err := s.expect(':'); err != nil {
+			return 0, err
+		}
+		if key == name {
+			valueOffset, keyOffset = s.pos, start
+		}
+		if err := s.skipValue(); err != nil {
+			return 0, err
+		}
+		s.skipSpace()
+		if s.peek() == ',' {
+			if err := s.expect(','); err != nil {
+				return 0, err
+			}
+		} else if s.peek() != '}' {
+			return 0, s.syntaxError()
+		}
+	}
+	if valueOffset < 0 {
+		return 0, fmt.Errorf("%w: property %q", ErrLocationNotFound, name)
+	}
+	s.pos = valueOffset
+	return keyOffset, nil
+}
+
+// findItem moves the scanner to the item at index token of the array at its position.
+func (s *jsonScanner) findItem(token string) error {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return fmt.Errorf("%w: item %q", ErrLocationNotFound, token)
+	}
+	if err := s.expect('['); err != nil {
+		return err
+	}
+	for i := 0; s.peek() != ']'; i++ {
+		if i == index {
+			return nil
+		}
+		if err := s.skipValue(); err != nil {
+			return err
+		}
+		s.skipSpace()
+		if s.peek() == ',' {
+			if err := s.expect(','); err != nil {
+				return err
+			}
+		} else if s.peek() != ']' {
+			return s.syntaxError()
+		}
+	}
+	return fmt.Errorf("%w: item %d", ErrLocationNotFound, index)
+}
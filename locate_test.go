@@ -0,0 +1,58 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocate(t *testing.T) {
+	data := []byte(`{
+  "name": "Ada",
+  "tags": ["a", {"x\"y": [1, 2]}],
+  "a/b": {"c~d": null},
+  "name": "Bo"
+}`)
+
+	position, err := LocateValue(data, "")
+	require.NoError(t, err)
+	assert.Equal(t, Position{Offset: 0, Line: 1, Column: 1}, position)
+
+	position, err = LocateValue(data, "/tags/1/x\"y/1")
+	require.NoError(t, err)
+	assert.Equal(t, Position{Offset: 48, Line: 3, Column: 30}, position)
+	assert.Equal(t, byte('2'), data[position.Offset])
+
+	position, err = LocateKey(data, "/a~1b/c~0d")
+	require.NoError(t, err)
+	assert.Equal(t, Position{Offset: 64, Line: 4, Column: 11}, position)
+
+	position, err = LocateKey(data, "/name")
+	require.NoError(t, err)
+	assert.Equal(t, 5, position.Line, "duplicate properties are located at their last occurrence")
+
+	_, err = LocateValue(data, "/missing")
+	require.ErrorIs(t, err, ErrLocationNotFound)
+	_, err = LocateValue(data, "/tags/2")
+	require.ErrorIs(t, err, ErrLocationNotFound)
+	_, err = LocateKey(data, "/tags/0")
+	require.ErrorIs(t, err, ErrLocationNotFound)
+	_, err = LocateValue([]byte(`{"a": [1, }`), "/b")
+	require.ErrorIs(t, err, ErrJSONUnmarshalError)
+}
+
+func TestLocatePropertyNameErrors(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"properties": {"user": {"propertyNames": {"maxLength": 3}}}}`))
+	require.NoError(t, err)
+
+	data := []byte("{\n  \"user\": {\"id\": 2, \"label\": \"x\"}\n}")
+	var instance interface{}
+	require.NoError(t, decodeExactJSON(data, &instance))
+
+	errs := schema.Validate(instance).PropertyNameErrors()
+	require.Len(t, errs, 1)
+	position, err := LocateKey(data, errs[0].InstanceLocation)
+	require.NoError(t, err)
+	assert.Equal(t, Position{Offset: 22, Line: 2, Column: 21}, position)
+}
@@ -0,0 +1,119 @@
+package jsonschema
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// Manifest lists the schema documents a service depends on, for Compiler.Preload to fetch and
+// compile them at startup instead of on the first request. It is typically kept in a JSON file:
+//
+//	{"schemas": [{"uri": "https://example.com/user.json", "sha256": "9f86d0..."}]}
+type Manifest struct {
+	Schemas []ManifestEntry `json:"schemas"`
+}
+
+// ManifestEntry is a schema document of a Manifest.
+type ManifestEntry struct {
+	URI    string `json:"uri"`              // URI the document is loaded from.
+	SHA256 string `json:"sha256,omitempty"` // Expected hex SHA-256 of the JSON document, not checked when empty.
+}
+
+// LoadManifest reads a Manifest written as JSON.
+func LoadManifest(reader io.Reader) (*Manifest, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+	}
+	for i, entry := range manifest.Schemas {
+		if entry.URI == "" {
+			return nil, fmt.Errorf("%w: schema %d has no uri", ErrInvalidManifest, i)
+		}
+		if entry.SHA256 != "" {
+			if sum, err := hex.DecodeString(entry.SHA256); err != nil || len(sum) != sha256.Size {
+				return nil, fmt.Errorf("%w: invalid sha256 for %s", ErrInvalidManifest, entry.URI)
+			}
+		}
+	}
+	return &manifest, nil
+}
+
+// Preload fetches the documents of manifest in parallel with the loaders of the compiler, checks
+// their hashes and compiles them, so that later references to them are resolved from the cache.
+// It fails fast: the first missing or mismatching document cancels the other fetches and its error
+// is returned, wrapping ErrManifestHashMismatch when the hash differs.
+func (c *Compiler) Preload(ctx context.Context, manifest *Manifest) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	documents := make([][]byte, len(manifest.Schemas))
+	errs := make(chan error, len(manifest.Schemas))
+	var wg sync.WaitGroup
+	for i, entry := range manifest.Schemas {
+		id, _ := splitRef(entry.URI)
+		if _, exists := c.lookupSchema(id); exists {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, entry ManifestEntry) {
+			defer wg.Done()
+			data, err := fetchWithContext(ctx, func() ([]byte, error) { return c.fetchCachedDocument(ctx, entry.URI) })
+			if err == nil {
+				err = entry.verify(data)
+			}
+			if err != nil {
+				errs <- fmt.Errorf("%s: %w", entry.URI, err)
+				cancel()
+				return
+			}
+			documents[i] = data
+		}(i, entry)
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return err
+	}
+
+	// Compile with every document at hand, so that references between them are not fetched again.
+	c.compileMu.Lock()
+	defer c.compileMu.Unlock()
+	c.prefetched = make(map[string][]byte, len(documents))
+	defer func() { c.prefetched = nil }()
+	for i, entry := range manifest.Schemas {
+		if documents[i] != nil {
+			id, _ := splitRef(entry.URI)
+			c.prefetched[id] = documents[i]
+		}
+	}
+	for _, entry := range manifest.Schemas {
+		if _, err := c.getSchema(entry.URI); err != nil {
+			return fmt.Errorf("%s: %w", entry.URI, err)
+		}
+	}
+	return nil
+}
+
+// verify checks the hash of the document of the entry.
+func (e ManifestEntry) verify(data []byte) error {
+	if e.SHA256 == "" {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != strings.ToLower(e.SHA256) {
+		return fmt.Errorf("%w: expected %s, got %s", ErrManifestHashMismatch, strings.ToLower(e.SHA256), actual)
+	}
+	return nil
+}
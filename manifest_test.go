@@ -0,0 +1,86 @@
+package jsonschema
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Hex(document string) string {
+	sum := sha256.Sum256([]byte(document))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestLoadManifest(t *testing.T) {
+	manifest, err := LoadManifest(strings.NewReader(`{"schemas": [
+		{"uri": "https://example.com/user.json", "sha256": "` + sha256Hex("{}") + `"},
+		{"uri": "https://example.com/name.json"}
+	]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []ManifestEntry{
+		{URI: "https://example.com/user.json", SHA256: sha256Hex("{}")},
+		{URI: "https://example.com/name.json"},
+	}, manifest.Schemas)
+
+	_, err = LoadManifest(strings.NewReader(`{"schemas": [{"sha256": "` + sha256Hex("{}") + `"}]}`))
+	require.ErrorIs(t, err, ErrInvalidManifest)
+	_, err = LoadManifest(strings.NewReader(`{"schemas": [{"uri": "https://example.com/a.json", "sha256": "abc"}]}`))
+	require.ErrorIs(t, err, ErrInvalidManifest)
+	_, err = LoadManifest(strings.NewReader(`{"schemas": `))
+	require.ErrorIs(t, err, ErrJSONUnmarshalError)
+}
+
+func TestPreload(t *testing.T) {
+	user := `{"type": "object", "properties": {"name": {"$ref": "name.json"}}}`
+	name := `{"type": "string"}`
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/user.json": user,
+		"test://schemas/name.json": name,
+	}}
+	compiler := NewCompiler().RegisterLoader("test", source.load)
+
+	err := compiler.Preload(context.Background(), &Manifest{Schemas: []ManifestEntry{
+		{URI: "test://schemas/user.json", SHA256: sha256Hex(user)},
+		{URI: "test://schemas/name.json", SHA256: strings.ToUpper(sha256Hex(name))},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, source.fetched, "references between preloaded documents are not fetched again")
+
+	schema, err := compiler.GetSchema("test://schemas/user.json")
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(map[string]interface{}{"name": 1}).IsValid())
+	assert.Equal(t, 2, source.fetched)
+}
+
+func TestPreloadFailsFast(t *testing.T) {
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/name.json": `{"type": "string"}`,
+	}}
+	compiler := NewCompiler().RegisterLoader("test", source.load)
+	err := compiler.Preload(context.Background(), &Manifest{Schemas: []ManifestEntry{
+		{URI: "test://schemas/name.json", SHA256: sha256Hex(`{"type": "integer"}`)},
+	}})
+	require.ErrorIs(t, err, ErrManifestHashMismatch)
+	assert.NotContains(t, compiler.schemas, "test://schemas/name.json")
+
+	block := make(chan struct{})
+	defer close(block)
+	compiler = NewCompiler().RegisterLoader("test", func(url string) (io.ReadCloser, error) {
+		if strings.HasSuffix(url, "slow.json") {
+			<-block
+		}
+		return source.load(url)
+	})
+	err = compiler.Preload(context.Background(), &Manifest{Schemas: []ManifestEntry{
+		{URI: "test://schemas/slow.json"},
+		{URI: "test://schemas/missing.json"},
+	}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "test://schemas/missing.json")
+}
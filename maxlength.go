@@ -2,7 +2,6 @@ package jsonschema
 
 import (
 	"fmt"
-	"unicode/utf8"
 )
 
 // EvaluateMaxLength checks if the length of a string instance does not exceed the maxLength specified in the schema.
@@ -17,8 +16,8 @@ import (
 // Reference: https://json-schema.org/draft/2020-12/json-schema-validation#name-maxlength
 func evaluateMaxLength(schema *Schema, value string) *EvaluationError {
 	if schema.MaxLength != nil {
-		// Use utf8.RuneCountInString to correctly count the number of graphemes in the string
-		length := utf8.RuneCountInString(value)
+		// Count code points by default, or UTF-16 units or bytes, see Compiler.SetStringLength.
+		length := schema.stringLength(value)
 		if length > int(*schema.MaxLength) {
 			// String exceeds the maximum length.
 			return NewEvaluationError("maxLength", "string_too_long", "Value should be at most {max_length} characters", map[string]interface{}{
@@ -0,0 +1,506 @@
+package mediatypes
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goccy/go-json"
+)
+
+// ionBinaryVersionMarker starts every binary Ion stream.
+var ionBinaryVersionMarker = []byte{0xE0, 0x01, 0x00, 0xEA}
+
+// Patterns of the numeric and timestamp tokens of Ion text, underscores removed.
+var (
+	ionIntPattern       = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	ionHexPattern       = regexp.MustCompile(`^-?0[xX][0-9a-fA-F]+$`)
+	ionBinaryPattern    = regexp.MustCompile(`^-?0[bB][01]+$`)
+	ionDecimalPattern   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]*)?([dD][+-]?[0-9]+)?$`)
+	ionFloatPattern     = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]*)?[eE][+-]?[0-9]+$`)
+	ionTimestampPattern = regexp.MustCompile(`^[0-9]{4}(T|-[0-9]{2}T|-[0-9]{2}-[0-9]{2}(T([0-9]{2}:[0-9]{2}(:[0-9]{2}(\.[0-9]+)?)?(Z|[+-][0-9]{2}:[0-9]{2}))?)?)$`)
+)
+
+// UnmarshalIon reads Amazon Ion text, a superset of JSON, as an instance. Ion values without a
+// JSON counterpart are mapped as follows:
+//
+//   - typed nulls such as null.string become null, and annotations such as unit::12 are dropped;
+//   - integers and decimals become exact numbers, and floats become float64; nan and infinities,
+//     which JSON cannot represent, are rejected;
+//   - timestamps become strings as written, so that full ones match the date-time format;
+//   - symbols and clobs become strings, and blobs become base64 strings;
+//   - lists and s-expressions become arrays.
+//
+// A stream of a single top-level value is that value; a stream of several values is an array
+// of them. Binary Ion is reported with ErrIonBinaryUnsupported; applications reading it can
+// register a handler built on github.com/amazon-ion/ion-go instead.
+func UnmarshalIon(data []byte) (interface{}, error) {
+	if bytes.HasPrefix(data, ionBinaryVersionMarker) {
+		return nil, ErrIonBinaryUnsupported
+	}
+
+	p := &ionParser{data: data}
+	values := []interface{}{}
+	for {
+		p.skipSpace()
+		if p.offset == len(p.data) {
+			break
+		}
+		if p.versionMarker() {
+			continue
+		}
+		value, err := p.value(false)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	return values, nil
+}
+
+// ionParser parses Ion text from its offset.
+type ionParser struct {
+	data   []byte
+	offset int
+}
+
+// errorf returns an ErrInvalidIon error at the current offset.
+func (p *ionParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w at offset %d: %s", ErrInvalidIon, p.offset, fmt.Sprintf(format, args...))
+}
+
+// peek returns the byte at the offset plus i, or 0 past the end of the data.
+func (p *ionParser) peek(i int) byte {
+	if p.offset+i < len(p.data) {
+		return p.data[p.offset+i]
+	}
+	return 0
+}
+
+// versionMarker skips the $ion_1_0 symbol at the top level, which marks the version of the
+// stream and is not part of the data.
+func (p *ionParser) versionMarker() bool {
+	const marker = "$ion_1_0"
+	if !bytes.HasPrefix(p.data[p.offset:], []byte(marker)) || isIonIdentifierPart(p.peek(len(marker))) {
+		return false
+	}
+	save := p.offset
+	p.offset += len(marker)
+	p.skipSpace()
+	if p.peek(0) == ':' && p.peek(1) == ':' {
+		p.offset = save // An annotation.
+		return false
+	}
+	return true
+}
+
+// skipSpace skips whitespace and comments.
+func (p *ionParser) skipSpace() {
+	for p.offset < len(p.data) {
+		switch c := p.data[p.offset]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f':
+			p.offset++
+		case c == '/' && p.peek(1) == '/':
+			for p.offset < len(p.data) && p.data[p.offset] != '\n' {
+				p.offset++
+			}
+		case c == '/' && p.peek(1) == '*':
+			end := bytes.Index(p.data[p.offset+2:], []byte("*/"))
+			if end < 0 {
+				p.offset = len(p.data)
+				return
+			}
+			p.offset += end + 4
+		default:
+			return
+		}
+	}
+}
+
+// value parses a value with its annotations. In s-expressions, operator symbols such as + are
+// values too.
+func (p *ionParser) value(sexp bool) (interface{}, error) {
+	for {
+		p.skipSpace()
+		value, symbol, err := p.atom(sexp)
+		if err != nil {
+			return nil, err
+		}
+		save := p.offset
+		p.skipSpace()
+		if symbol && p.peek(0) == ':' && p.peek(1) == ':' {
+			p.offset += 2 // An annotation, the value follows.
+			continue
+		}
+		p.offset = save
+		return value, nil
+	}
+}
+
+// atom parses a value without annotations and reports whether it is a symbol, which may be an
+// annotation.
+func (p *ionParser) atom(sexp bool) (interface{}, bool, error) {
+	c := p.peek(0)
+	switch {
+	case p.offset == len(p.data):
+		return nil, false, p.errorf("unexpected end of data")
+	case c == '{' && p.peek(1) == '{':
+		value, err := p.lob()
+		return value, false, err
+	case c == '{':
+		value, err := p.structure()
+		return value, false, err
+	case c == '[':
+		value, err := p.sequence(']', false)
+		return value, false, err
+	case c == '(':
+		value, err := p.sequence(')', true)
+		return value, false, err
+	case c == '"':
+		value, err := p.quoted('"')
+		return value, false, err
+	case c == '\'' && p.peek(1) == '\'' && p.peek(2) == '\'':
+		value, err := p.longString()
+		return value, false, err
+	case c == '\'':
+		value, err := p.quoted('\'')
+		return value, true, err
+	case isIonDigit(c) || (c == '-' && isIonDigit(p.peek(1))):
+		value, err := p.number()
+		return value, false, err
+	case (c == '+' || c == '-') && bytes.HasPrefix(p.data[p.offset+1:], []byte("inf")) && !isIonIdentifierPart(p.peek(4)):
+		return nil, false, p.errorf("%cinf has no JSON representation", c)
+	case isIonIdentifierStart(c):
+		return p.identifier()
+	case sexp && isIonOperator(c):
+		start := p.offset
+		for isIonOperator(p.peek(0)) {
+			p.offset++
+		}
+		return string(p.data[start:p.offset]), false, nil
+	}
+	return nil, false, p.errorf("unexpected character %q", c)
+}
+
+// identifier parses a keyword or an identifier symbol.
+func (p *ionParser) identifier() (interface{}, bool, error) {
+	start := p.offset
+	for isIonIdentifierPart(p.peek(0)) {
+		p.offset++
+	}
+	name := string(p.data[start:p.offset])
+	switch name {
+	case "true":
+		return true, false, nil
+	case "false":
+		return false, false, nil
+	case "nan":
+		return nil, false, p.errorf("nan has no JSON representation")
+	case "null":
+		if p.peek(0) == '.' && isIonIdentifierStart(p.peek(1)) {
+			p.offset++ // A typed null, such as null.string.
+			for isIonIdentifierPart(p.peek(0)) {
+				p.offset++
+			}
+		}
+		return nil, false, nil
+	}
+	return name, true, nil
+}
+
+// number parses an integer, a decimal, a float or a timestamp.
+func (p *ionParser) number() (interface{}, error) {
+	start := p.offset
+	for c := p.peek(0); isIonIdentifierPart(c) || c == '-' || c == '+' || c == '.' || c == ':'; c = p.peek(0) {
+		if c == ':' && p.peek(1) == ':' {
+			break
+		}
+		p.offset++
+	}
+	token := string(p.data[start:p.offset])
+	if ionTimestampPattern.MatchString(token) {
+		return token, nil
+	}
+
+	digits := token
+	if strings.Contains(digits, "_") {
+		digits = strings.ReplaceAll(digits, "_", "")
+		if strings.HasPrefix(token, "_") || strings.HasSuffix(token, "_") || strings.Contains(token, "__") {
+			return nil, p.errorf("invalid number %s", token)
+		}
+	}
+
+	switch {
+	case ionIntPattern.MatchString(digits):
+		return json.Number(digits), nil
+	case ionHexPattern.MatchString(digits), ionBinaryPattern.MatchString(digits):
+		negative := strings.HasPrefix(digits, "-")
+		digits = strings.TrimPrefix(digits, "-")
+		n, ok := new(big.Int).SetString(digits[2:], map[byte]int{'x': 16, 'X': 16, 'b': 2, 'B': 2}[digits[1]])
+		if !ok {
+			return nil, p.errorf("invalid number %s", token)
+		}
+		if negative {
+			n.Neg(n)
+		}
+		return json.Number(n.String()), nil
+	case ionFloatPattern.MatchString(digits):
+		f, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %s", token)
+		}
+		return f, nil
+	case ionDecimalPattern.MatchString(digits):
+		digits = strings.NewReplacer("d", "e", "D", "e").Replace(digits)
+		digits = strings.Replace(digits, ".e", ".0e", 1)
+		if strings.HasSuffix(digits, ".") {
+			digits += "0"
+		}
+		return json.Number(digits), nil
+	}
+	return nil, p.errorf("invalid number %s", token)
+}
+
+// structure parses a struct.
+func (p *ionParser) structure() (interface{}, error) {
+	p.offset++
+	object := map[string]interface{}{}
+	for {
+		p.skipSpace()
+		if p.peek(0) == '}' {
+			p.offset++
+			return object, nil
+		}
+
+		var name string
+		var err error
+		switch c := p.peek(0); {
+		case c == '"':
+			name, err = p.quoted('"')
+		case c == '\'' && p.peek(1) == '\'' && p.peek(2) == '\'':
+			name, err = p.longString()
+		case c == '\'':
+			name, err = p.quoted('\'')
+		case isIonIdentifierStart(c):
+			start := p.offset
+			for isIonIdentifierPart(p.peek(0)) {
+				p.offset++
+			}
+			name = string(p.data[start:p.offset])
+		default:
+			return nil, p.errorf("expected a field name")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if p.peek(0) != ':' {
+			return nil, p.errorf("expected ':' after field %q", name)
+		}
+		p.offset++
+		if object[name], err = p.value(false); err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		switch p.peek(0) {
+		case ',':
+			p.offset++
+		case '}':
+		default:
+			return nil, p.errorf("expected ',' or '}' in struct")
+		}
+	}
+}
+
+// sequence parses a list, whose values are separated by commas, or an s-expression, whose values
+// are separated by whitespace.
+func (p *ionParser) sequence(end byte, sexp bool) (interface{}, error) {
+	p.offset++
+	items := []interface{}{}
+	for {
+		p.skipSpace()
+		if p.peek(0) == end {
+			p.offset++
+			return items, nil
+		}
+		item, err := p.value(sexp)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		if !sexp {
+			p.skipSpace()
+			switch p.peek(0) {
+			case ',':
+				p.offset++
+			case end:
+			default:
+				return nil, p.errorf("expected ',' or '%c' in list", end)
+			}
+		}
+	}
+}
+
+// lob parses a blob, as a base64 string, or a clob, as a string.
+func (p *ionParser) lob() (interface{}, error) {
+	p.offset += 2
+	p.skipSpace()
+
+	var value string
+	if p.peek(0) == '"' || p.peek(0) == '\'' {
+		var b strings.Builder
+		for p.peek(0) == '"' || p.peek(0) == '\'' {
+			var part string
+			var err error
+			if p.peek(0) == '"' {
+				part, err = p.quoted('"')
+			} else {
+				part, err = p.longString()
+			}
+			if err != nil {
+				return nil, err
+			}
+			b.WriteString(part)
+			p.skipSpace()
+		}
+		value = b.String()
+	} else {
+		end := bytes.Index(p.data[p.offset:], []byte("}}"))
+		if end < 0 {
+			return nil, p.errorf("unterminated blob")
+		}
+		encoded := strings.Join(strings.Fields(string(p.data[p.offset:p.offset+end])), "")
+		if _, err := base64.StdEncoding.DecodeString(encoded); err != nil {
+			return nil, p.errorf("invalid blob: %v", err)
+		}
+		p.offset += end
+		value = encoded
+	}
+
+	if p.peek(0) != '}' || p.peek(1) != '}' {
+		return nil, p.errorf("expected '}}'")
+	}
+	p.offset += 2
+	return value, nil
+}
+
+// longString parses consecutive long strings, delimited by three single quotes, which are
+// concatenated.
+func (p *ionParser) longString() (string, error) {
+	var b strings.Builder
+	for {
+		p.offset += 3
+		for {
+			if p.offset >= len(p.data) {
+				return "", p.errorf("unterminated long string")
+			}
+			if bytes.HasPrefix(p.data[p.offset:], []byte("'''")) {
+				p.offset += 3
+				break
+			}
+			if err := p.character(&b); err != nil {
+				return "", err
+			}
+		}
+
+		// The next long string continues this one, unless something else comes first.
+		save := p.offset
+		p.skipSpace()
+		if !bytes.HasPrefix(p.data[p.offset:], []byte("'''")) {
+			p.offset = save
+			return b.String(), nil
+		}
+	}
+}
+
+// quoted parses a string or a symbol delimited by quote.
+func (p *ionParser) quoted(quote byte) (string, error) {
+	var b strings.Builder
+	p.offset++
+	for {
+		switch c := p.peek(0); {
+		case p.offset >= len(p.data) || c == '\n':
+			return "", p.errorf("unterminated string")
+		case c == quote:
+			p.offset++
+			return b.String(), nil
+		default:
+			if err := p.character(&b); err != nil {
+				return "", err
+			}
+		}
+	}
+}
+
+// ionEscapes are the single character escapes of Ion strings.
+var ionEscapes = map[byte]string{
+	'a': "\a", 'b': "\b", 't': "\t", 'n': "\n", 'f': "\f", 'r': "\r", 'v': "\v",
+	'?': "?", '0': "\x00", '"': "\"", '\'': "'", '/': "/", '\\': "\\",
+}
+
+// character writes the character at the offset, or the one it escapes, to b.
+func (p *ionParser) character(b *strings.Builder) error {
+	c := p.data[p.offset]
+	if c != '\\' {
+		r, size := utf8.DecodeRune(p.data[p.offset:])
+		b.WriteRune(r)
+		p.offset += size
+		return nil
+	}
+
+	escape := p.peek(1)
+	p.offset += 2
+	if s, ok := ionEscapes[escape]; ok {
+		b.WriteString(s)
+		return nil
+	}
+	switch escape {
+	case '\n':
+		return nil // A line continuation.
+	case '\r':
+		if p.peek(0) == '\n' {
+			p.offset++
+		}
+		return nil
+	case 'x', 'u', 'U':
+		size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[escape]
+		if p.offset+size > len(p.data) {
+			return p.errorf("truncated escape")
+		}
+		code, err := strconv.ParseUint(string(p.data[p.offset:p.offset+size]), 16, 32)
+		if err != nil {
+			return p.errorf("invalid escape \\%c%s", escape, p.data[p.offset:p.offset+size])
+		}
+		p.offset += size
+		b.WriteRune(rune(code))
+		return nil
+	}
+	return p.errorf("invalid escape \\%c", escape)
+}
+
+func isIonDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIonIdentifierStart(c byte) bool {
+	return c == '$' || c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIonIdentifierPart(c byte) bool {
+	return isIonIdentifierStart(c) || isIonDigit(c)
+}
+
+func isIonOperator(c byte) bool {
+	return c != 0 && strings.IndexByte("!#%&*+-./;<=>?@^`|~", c) >= 0
+}
@@ -0,0 +1,87 @@
+package mediatypes
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalIon(t *testing.T) {
+	instance, err := UnmarshalIon([]byte(`$ion_1_0
+// An order, with most Ion types.
+order::{
+	id: 'ORD-1',
+	"customer": customer,
+	quantity: 1_000,
+	mask: 0x1F,
+	flags: -0b101,
+	price: 12.50,
+	discount: 5d-2,
+	whole: 3.,
+	ratio: 2.5e0,
+	placed: 2007-02-23T12:14:33.079-08:00,
+	day: 2007-02-23,
+	gift: true,
+	note: null.string,
+	tags: [fast, "free shipping", usd::9],
+	rule: (>= quantity 1),
+	memo: '''multi''' /* joined */ '''line''',
+	escaped: "tab\there é \x41",
+	thumbnail: {{ aGVs bG8= }},
+	raw: {{ "clob" }},
+}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"id":        "ORD-1",
+		"customer":  "customer",
+		"quantity":  json.Number("1000"),
+		"mask":      json.Number("31"),
+		"flags":     json.Number("-5"),
+		"price":     json.Number("12.50"),
+		"discount":  json.Number("5e-2"),
+		"whole":     json.Number("3.0"),
+		"ratio":     2.5,
+		"placed":    "2007-02-23T12:14:33.079-08:00",
+		"day":       "2007-02-23",
+		"gift":      true,
+		"note":      nil,
+		"tags":      []interface{}{"fast", "free shipping", json.Number("9")},
+		"rule":      []interface{}{">=", "quantity", json.Number("1")},
+		"memo":      "multiline",
+		"escaped":   "tab\there é A",
+		"thumbnail": "aGVsbG8=",
+		"raw":       "clob",
+	}, instance)
+}
+
+func TestUnmarshalIonStream(t *testing.T) {
+	instance, err := UnmarshalIon([]byte(`{a: 1} {a: 2}`))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"a": json.Number("1")},
+		map[string]interface{}{"a": json.Number("2")},
+	}, instance)
+}
+
+func TestUnmarshalIonInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{a: 1`,
+		`[1 2]`,
+		`"unterminated`,
+		`nan`,
+		`-inf`,
+		`1__0`,
+		`0x`,
+		`{{ not base64! }}`,
+		`{a 1}`,
+	} {
+		_, err := UnmarshalIon([]byte(data))
+		assert.ErrorIs(t, err, ErrInvalidIon, data)
+	}
+
+	_, err := UnmarshalIon([]byte{0xE0, 0x01, 0x00, 0xEA, 0x0F})
+	require.ErrorIs(t, err, ErrIonBinaryUnsupported)
+}
@@ -0,0 +1,43 @@
+// Package mediatypes provides contentMediaType handlers beyond the ones built into the compiler,
+// so that schemas can validate embedded Amazon Ion and newline-delimited JSON documents without
+// each application writing its own unmarshal glue:
+//
+//	compiler := mediatypes.Register(jsonschema.NewCompiler())
+//
+// Handlers can also be registered one by one with Compiler.RegisterMediaType, using the
+// functions of this package.
+package mediatypes
+
+import (
+	"errors"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// ErrInvalidNDJSON is returned when a line of newline-delimited JSON is not a JSON value.
+var ErrInvalidNDJSON = errors.New("invalid ndjson line")
+
+// ErrInvalidIon is returned when Ion text cannot be parsed.
+var ErrInvalidIon = errors.New("invalid ion text")
+
+// ErrIonBinaryUnsupported is returned for binary Ion data, which only the text handler reads.
+var ErrIonBinaryUnsupported = errors.New("binary ion is not supported")
+
+// Handlers returns the media type handlers of the package, by media type name.
+func Handlers() map[string]func([]byte) (interface{}, error) {
+	return map[string]func([]byte) (interface{}, error){
+		"application/x-ndjson":  UnmarshalNDJSON,
+		"application/jsonl":     UnmarshalNDJSON,
+		"application/jsonlines": UnmarshalNDJSON,
+		"application/ion":       UnmarshalIon,
+		"application/ion+text":  UnmarshalIon,
+	}
+}
+
+// Register adds every handler of the package to compiler and returns it.
+func Register(compiler *jsonschema.Compiler) *jsonschema.Compiler {
+	for name, unmarshal := range Handlers() {
+		compiler.RegisterMediaType(name, unmarshal)
+	}
+	return compiler
+}
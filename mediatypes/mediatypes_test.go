@@ -0,0 +1,47 @@
+package mediatypes
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+func TestRegister(t *testing.T) {
+	compiler := Register(jsonschema.NewCompiler())
+	for name := range Handlers() {
+		assert.Contains(t, compiler.MediaTypes, name)
+	}
+
+	schema, err := compiler.Compile([]byte(`{
+		"properties": {
+			"events": {
+				"contentMediaType": "application/x-ndjson",
+				"contentSchema": {"items": {"required": ["type"]}}
+			},
+			"record": {
+				"contentEncoding": "base64",
+				"contentMediaType": "application/ion",
+				"contentSchema": {"properties": {"amount": {"minimum": 0}}}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+
+	valid := map[string]interface{}{
+		"events": "{\"type\": \"click\"}\n{\"type\": \"view\"}\n",
+		"record": base64.StdEncoding.EncodeToString([]byte("{amount: 12.5d0}")),
+	}
+	assert.True(t, schema.Validate(valid).IsValid())
+
+	invalid := map[string]interface{}{
+		"events": "{\"type\": \"click\"}\n{\"id\": 2}\n",
+		"record": base64.StdEncoding.EncodeToString([]byte("{amount: -1}")),
+	}
+	result := schema.Validate(invalid)
+	assert.False(t, result.IsValid())
+	assert.Len(t, result.ToList().Details, 2)
+}
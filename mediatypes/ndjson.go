@@ -0,0 +1,39 @@
+package mediatypes
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+
+	"github.com/goccy/go-json"
+)
+
+// UnmarshalNDJSON reads newline-delimited JSON, also known as JSON Lines, as an array with one
+// instance per line, so that a content schema can describe the records with items. Blank lines
+// are skipped, and numbers are kept exact as json.Number.
+func UnmarshalNDJSON(data []byte) (interface{}, error) {
+	instances := []interface{}{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var instance interface{}
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.UseNumber()
+		if err := decoder.Decode(&instance); err != nil {
+			return nil, fmt.Errorf("%w %d: %v", ErrInvalidNDJSON, line, err)
+		}
+		if decoder.More() {
+			return nil, fmt.Errorf("%w %d: more than one value", ErrInvalidNDJSON, line)
+		}
+		instances = append(instances, instance)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
@@ -0,0 +1,32 @@
+package mediatypes
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalNDJSON(t *testing.T) {
+	instance, err := UnmarshalNDJSON([]byte("{\"id\": 1}\r\n\n  {\"id\": 9007199254740993}\n[]\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": json.Number("1")},
+		map[string]interface{}{"id": json.Number("9007199254740993")},
+		[]interface{}{},
+	}, instance)
+
+	instance, err = UnmarshalNDJSON(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{}, instance)
+}
+
+func TestUnmarshalNDJSONInvalid(t *testing.T) {
+	_, err := UnmarshalNDJSON([]byte("{\"id\": 1}\n{\"id\": \n"))
+	require.ErrorIs(t, err, ErrInvalidNDJSON)
+	assert.Contains(t, err.Error(), "line 2")
+
+	_, err = UnmarshalNDJSON([]byte("1 2\n"))
+	require.ErrorIs(t, err, ErrInvalidNDJSON)
+}
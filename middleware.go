@@ -0,0 +1,28 @@
+package jsonschema
+
+import "fmt"
+
+// Middleware preprocesses a schema document before it is parsed. It is given the document and the
+// URI it was loaded from, which is empty for documents compiled from bytes without one, and returns
+// the document to parse in its place.
+type Middleware func(document []byte, uri string) ([]byte, error)
+
+// Use appends middleware to the chain applied to every schema document the compiler parses, those
+// given to Compile as well as those fetched by loaders, in the order they were added. Middleware can
+// so strip proprietary keywords, inject $schema or rewrite references centrally. It runs before
+// custom dialects are applied.
+func (c *Compiler) Use(middleware ...Middleware) *Compiler {
+	c.Middlewares = append(c.Middlewares, middleware...)
+	return c
+}
+
+// applyMiddlewares passes the document loaded from uri through the middleware chain.
+func (c *Compiler) applyMiddlewares(document []byte, uri string) ([]byte, error) {
+	for _, middleware := range c.Middlewares {
+		var err error
+		if document, err = middleware(document, uri); err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrMiddleware, uri, err)
+		}
+	}
+	return document, nil
+}
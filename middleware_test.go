@@ -0,0 +1,83 @@
+package jsonschema
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilerUse(t *testing.T) {
+	var uris []string
+	compiler := NewCompiler().Use(
+		func(document []byte, uri string) ([]byte, error) {
+			uris = append(uris, uri)
+			return bytes.ReplaceAll(document, []byte(`"x-vendor-max"`), []byte(`"maximum"`)), nil
+		},
+		func(document []byte, uri string) ([]byte, error) {
+			return bytes.ReplaceAll(document, []byte("https://internal.example.com/"), []byte("https://example.com/")), nil
+		},
+	)
+	compiler.RegisterLoader("https", func(url string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"type": "integer", "x-vendor-max": 10}`)), nil
+	})
+
+	schema, err := compiler.Compile([]byte(`{
+		"properties": {"count": {"$ref": "https://internal.example.com/count.json"}}
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "https://example.com/count.json"}, uris)
+	assert.True(t, schema.Validate(map[string]interface{}{"count": 10}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"count": 11}).IsValid())
+}
+
+func TestCompilerUseInjectsSchema(t *testing.T) {
+	compiler := NewCompiler().Use(func(document []byte, uri string) ([]byte, error) {
+		var keywords map[string]interface{}
+		if err := json.Unmarshal(document, &keywords); err != nil {
+			return nil, err
+		}
+		if _, ok := keywords["$schema"]; !ok {
+			keywords["$schema"] = "https://example.com/dialects/vetted"
+		}
+		return json.Marshal(keywords)
+	}).RegisterDialect(Dialect{URI: "https://example.com/dialects/vetted", Removed: []string{"pattern"}})
+
+	_, err := compiler.Compile([]byte(`{"pattern": "^a"}`))
+	require.ErrorIs(t, err, ErrKeywordNotAllowed)
+}
+
+func TestCompilerUseError(t *testing.T) {
+	errRejected := errors.New("rejected")
+	compiler := NewCompiler().Use(func(document []byte, uri string) ([]byte, error) {
+		return nil, errRejected
+	})
+
+	_, err := compiler.Compile([]byte(`{}`), "https://example.com/rejected.json")
+	require.ErrorIs(t, err, ErrMiddleware)
+	require.ErrorIs(t, err, errRejected)
+	assert.Contains(t, err.Error(), "https://example.com/rejected.json")
+}
+
+func TestOverlayUse(t *testing.T) {
+	calls := 0
+	base := NewCompiler().Use(func(document []byte, uri string) ([]byte, error) {
+		calls++
+		return document, nil
+	})
+	overlay := base.Overlay().Use(func(document []byte, uri string) ([]byte, error) {
+		return nil, errors.New("tenant middleware")
+	})
+
+	_, err := base.Compile([]byte(`{}`))
+	require.NoError(t, err, "middleware added to an overlay does not apply to its base")
+	_, err = overlay.Compile([]byte(`{}`))
+	require.ErrorIs(t, err, ErrMiddleware)
+	assert.Equal(t, 2, calls)
+}
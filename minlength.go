@@ -1,9 +1,5 @@
 package jsonschema
 
-import (
-	"unicode/utf8"
-)
-
 // EvaluateMinLength checks if the length of a string instance meets or exceeds the minLength specified in the schema.
 // According to the JSON Schema Draft 2020-12:
 //   - The "minLength" keyword must be a non-negative integer.
@@ -17,8 +13,8 @@ import (
 // Reference: https://json-schema.org/draft/2020-12/json-schema-validation#name-minlength
 func evaluateMinLength(schema *Schema, value string) *EvaluationError {
 	if schema.MinLength != nil {
-		// Use utf8.RuneCountInString to correctly count the number of graphemes in the string
-		length := utf8.RuneCountInString(value)
+		// Count code points by default, or UTF-16 units or bytes, see Compiler.SetStringLength.
+		length := schema.stringLength(value)
 		if length < int(*schema.MinLength) {
 			// String does not meet the minimum length.
 			return NewEvaluationError("minLength", "string_too_short", "Value should be at least {min_length} characters", map[string]interface{}{
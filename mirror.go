@@ -0,0 +1,156 @@
+package jsonschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// mirrorIndexFile is the name of the index of a mirror directory.
+const mirrorIndexFile = "index.json"
+
+// MirrorEntry is a document stored in a mirror, see Compiler.Mirror.
+type MirrorEntry struct {
+	URI       string    `json:"uri"`       // URI the document was fetched from.
+	SHA256    string    `json:"sha256"`    // Hex SHA-256 of the JSON document, naming its file.
+	FetchedAt time.Time `json:"fetchedAt"` // When the document was fetched, from the clock of the compiler.
+}
+
+// schemaMirror is a directory holding the documents fetched by a compiler, see Compiler.Mirror.
+type schemaMirror struct {
+	dir     string
+	mu      sync.Mutex
+	entries map[string]MirrorEntry // Entries of the index, by URI.
+}
+
+// Mirror keeps a content-addressable copy of the documents the compiler fetches through its
+// loaders in dir, which is created if needed, for reproducible builds and an audit trail of the
+// external schemas a service depends on. Each document is stored once as sha256/<hash>.json, and
+// index.json maps the URIs to the hashes of their documents; the index has the format of a
+// Manifest, so it can be given to LoadManifest and Preload.
+//
+// Documents listed in the index are served from dir instead of being fetched again, after their
+// hash is checked, which fails with ErrMirrorCorrupted when the file was altered. Deleting an entry
+// from the index fetches the document again. The returned error reports an unreadable directory or
+// index, wrapping ErrInvalidMirror for an index that is not valid JSON. Failures to store a
+// fetched document make the fetch fail, so that no document escapes the mirror.
+func (c *Compiler) Mirror(dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, "sha256"), 0o755); err != nil {
+		return err
+	}
+	mirror := &schemaMirror{dir: dir, entries: map[string]MirrorEntry{}}
+
+	data, err := os.ReadFile(filepath.Join(dir, mirrorIndexFile))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		var index struct {
+			Schemas []MirrorEntry `json:"schemas"`
+		}
+		if err := json.Unmarshal(data, &index); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidMirror, err)
+		}
+		for _, entry := range index.Schemas {
+			mirror.entries[entry.URI] = entry
+		}
+	}
+
+	c.mirror = mirror
+	return nil
+}
+
+// MirroredSchemas returns the documents of the mirror of the compiler in order of URI, or nil
+// without a mirror.
+func (c *Compiler) MirroredSchemas() []MirrorEntry {
+	if c.mirror == nil {
+		return nil
+	}
+	c.mirror.mu.Lock()
+	defer c.mirror.mu.Unlock()
+	return c.mirror.sortedEntries()
+}
+
+// read returns the mirrored document of uri, and whether the mirror has one.
+func (m *schemaMirror) read(uri string) ([]byte, bool, error) {
+	m.mu.Lock()
+	entry, ok := m.entries[uri]
+	m.mu.Unlock()
+	if !ok {
+		return nil, false, nil
+	}
+
+	data, err := os.ReadFile(m.documentPath(entry.SHA256))
+	if err != nil {
+		return nil, false, err
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != entry.SHA256 {
+		return nil, false, fmt.Errorf("%w: %s", ErrMirrorCorrupted, uri)
+	}
+	return data, true, nil
+}
+
+// store adds the document of uri, fetched at fetchedAt, to the mirror.
+func (m *schemaMirror) store(uri string, data []byte, fetchedAt time.Time) error {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	path := m.documentPath(hash)
+	if _, err := os.Stat(path); err != nil {
+		if err := writeFileAtomic(path, data); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[uri] = MirrorEntry{URI: uri, SHA256: hash, FetchedAt: fetchedAt.UTC()}
+	index, err := json.MarshalIndent(map[string]interface{}{"schemas": m.sortedEntries()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(m.dir, mirrorIndexFile), append(index, '\n'))
+}
+
+// sortedEntries returns the entries of the mirror in order of URI. The caller holds the lock.
+func (m *schemaMirror) sortedEntries() []MirrorEntry {
+	entries := make([]MirrorEntry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].URI < entries[j].URI })
+	return entries
+}
+
+// documentPath returns the path of the document with the given hash.
+func (m *schemaMirror) documentPath(hash string) string {
+	return filepath.Join(m.dir, "sha256", hash+".json")
+}
+
+// writeFileAtomic writes data to path through a temporary file, so that readers never see a
+// partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()           //nolint:errcheck
+		os.Remove(file.Name()) //nolint:errcheck
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name()) //nolint:errcheck
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,73 @@
+package jsonschema
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirror(t *testing.T) {
+	dir := t.TempDir()
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/user.json": `{"type": "object", "properties": {"name": {"$ref": "name.json"}}}`,
+		"test://schemas/name.json": `{"type": "string"}`,
+	}}
+	fetchedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	compiler := NewCompiler().SetClock(NewManualClock(fetchedAt)).RegisterLoader("test", source.load)
+	require.NoError(t, compiler.Mirror(dir))
+
+	schema, err := compiler.GetSchema("test://schemas/user.json")
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(map[string]interface{}{"name": 1}).IsValid())
+
+	entries := compiler.MirroredSchemas()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "test://schemas/name.json", entries[0].URI)
+	assert.Equal(t, fetchedAt, entries[0].FetchedAt)
+	document, err := os.ReadFile(filepath.Join(dir, "sha256", entries[0].SHA256+".json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"type": "string"}`, string(document))
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	require.NoError(t, err)
+	manifest, err := LoadManifest(bytes.NewReader(index))
+	require.NoError(t, err, "the index is a manifest")
+	assert.Len(t, manifest.Schemas, 2)
+
+	// Another compiler, as in a later build, resolves the documents from the mirror.
+	source.set("test://schemas/name.json", `{"type": "integer"}`)
+	source.fetched = 0
+	mirrored := NewCompiler().RegisterLoader("test", source.load)
+	require.NoError(t, mirrored.Mirror(dir))
+	require.NoError(t, mirrored.Preload(context.Background(), manifest))
+	schema, err = mirrored.GetSchema("test://schemas/user.json")
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(map[string]interface{}{"name": "Ann"}).IsValid())
+	assert.Zero(t, source.fetched)
+}
+
+func TestMirrorCorrupted(t *testing.T) {
+	dir := t.TempDir()
+	source := &reloadSource{documents: map[string]string{"test://schemas/name.json": `{"type": "string"}`}}
+	compiler := NewCompiler().RegisterLoader("test", source.load)
+	require.NoError(t, compiler.Mirror(dir))
+	_, err := compiler.GetSchema("test://schemas/name.json")
+	require.NoError(t, err)
+
+	entry := compiler.MirroredSchemas()[0]
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sha256", entry.SHA256+".json"), []byte(`{}`), 0o600))
+
+	other := NewCompiler().RegisterLoader("test", source.load)
+	require.NoError(t, other.Mirror(dir))
+	_, err = other.GetSchema("test://schemas/name.json")
+	assert.ErrorIs(t, err, ErrMirrorCorrupted)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.json"), []byte(`{`), 0o600))
+	assert.ErrorIs(t, NewCompiler().Mirror(dir), ErrInvalidMirror)
+}
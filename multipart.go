@@ -0,0 +1,53 @@
+package jsonschema
+
+import (
+	"bytes"
+	"mime/multipart"
+	"strings"
+)
+
+// unmarshalMultipart is the handler of the multipart/form-data media type. The boundary is taken
+// from the first delimiter line of the data, since contentMediaType has no parameters.
+func unmarshalMultipart(data []byte) (interface{}, error) {
+	boundary := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimRight(line, "\r"); strings.HasPrefix(line, "--") && len(line) > 2 {
+			boundary = line[2:]
+			break
+		}
+	}
+	if boundary == "" {
+		return nil, ErrMultipartBoundaryNotFound
+	}
+
+	form, err := multipart.NewReader(bytes.NewReader(data), boundary).ReadForm(int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	defer form.RemoveAll() //nolint:errcheck
+
+	return FormInstance(form.Value, form.File), nil
+}
+
+// FormInstance maps the fields and files of a form to an object instance. Fields are mapped by
+// FormValuesInstance; each file becomes an object with its filename, contentType and size, or an
+// array of such objects when several files share a field.
+func FormInstance(values map[string][]string, files map[string][]*multipart.FileHeader) map[string]interface{} {
+	instance := FormValuesInstance(values)
+	for name, headers := range files {
+		items := make([]interface{}, len(headers))
+		for i, header := range headers {
+			items[i] = map[string]interface{}{
+				"filename":    header.Filename,
+				"contentType": header.Header.Get("Content-Type"),
+				"size":        header.Size,
+			}
+		}
+		if len(items) == 1 {
+			instance[name] = items[0]
+		} else {
+			instance[name] = items
+		}
+	}
+	return instance
+}
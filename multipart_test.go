@@ -0,0 +1,65 @@
+package jsonschema
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uploadSchema describes an upload form with a title, tags and a PNG image of at most 1 KiB.
+const uploadSchema = `{
+	"type": "object",
+	"properties": {
+		"title": {"type": "string", "minLength": 1},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"image": {
+			"type": "object",
+			"properties": {
+				"contentType": {"const": "image/png"},
+				"size": {"maximum": 1024}
+			}
+		}
+	},
+	"required": ["title", "image"]
+}`
+
+func multipartBody(t *testing.T, fields map[string][]string, contentType string, file []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, values := range fields {
+		for _, value := range values {
+			require.NoError(t, writer.WriteField(name, value))
+		}
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="image"; filename="logo.png"`)
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write(file)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+func TestMultipartMediaType(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"contentMediaType": "multipart/form-data",
+		"contentSchema": ` + uploadSchema + `
+	}`))
+	require.NoError(t, err)
+
+	body, _ := multipartBody(t, map[string][]string{"title": {"Logo"}}, "image/png", make([]byte, 10))
+	assert.True(t, schema.Validate(body.String()).IsValid())
+
+	body, _ = multipartBody(t, map[string][]string{"title": {""}}, "image/png", make([]byte, 10))
+	assert.False(t, schema.Validate(body.String()).IsValid())
+
+	_, err = unmarshalMultipart([]byte("title=Logo"))
+	require.ErrorIs(t, err, ErrMultipartBoundaryNotFound)
+}
@@ -0,0 +1,418 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxMutationDepth bounds how many schemas are followed without descending into the instance,
+// which protects against reference cycles that do not consume the instance.
+const maxMutationDepth = 64
+
+// Mutation is a systematic change to a valid instance that the schema is expected to reject.
+type Mutation struct {
+	InstanceLocation string      `json:"instanceLocation"` // JSON Pointer of the mutated value.
+	Keyword          string      `json:"keyword"`          // Keyword the mutation violates.
+	Description      string      `json:"description"`      // Human readable description of the change.
+	Instance         interface{} `json:"instance"`         // Mutated copy of the whole instance.
+}
+
+// MutationReport summarizes a mutation test.
+type MutationReport struct {
+	Killed    int        `json:"killed"`              // Number of mutations rejected by the schema.
+	Survivors []Mutation `json:"survivors,omitempty"` // Mutations the schema accepted.
+}
+
+// Total returns the number of mutations that were tried.
+func (r *MutationReport) Total() int {
+	return r.Killed + len(r.Survivors)
+}
+
+// MutationTest applies the Mutations of every instance and validates each mutated copy.
+// Mutations the schema accepts are reported as survivors: they point at constraints that are
+// missing or weaker than intended. Every instance must be valid against the schema, otherwise
+// ErrMutationSeedInvalid is returned.
+//
+// Survivors are not always defects. A mutation that breaks one anyOf alternative may still
+// match another one, for example; review them rather than asserting that none exist.
+func (s *Schema) MutationTest(instances ...interface{}) (*MutationReport, error) {
+	report := &MutationReport{}
+	for i, instance := range instances {
+		if !s.Validate(instance).IsValid() {
+			return nil, fmt.Errorf("%w: instance %d", ErrMutationSeedInvalid, i)
+		}
+
+		for _, mutation := range s.Mutations(instance) {
+			if s.Validate(mutation.Instance).IsValid() {
+				report.Survivors = append(report.Survivors, mutation)
+			} else {
+				report.Killed++
+			}
+		}
+	}
+	return report, nil
+}
+
+// Mutations returns changes to instance that each violate one constraint the schema applies to it:
+// dropping a required property, adding a property where additional ones are forbidden, crossing a
+// numeric, length, item or property count bound, breaking uniqueness, enum or const, and replacing
+// a value by one of a disallowed type.
+//
+// Constraints are looked up along the subschemas that apply to each value, following $ref, allOf,
+// the single matching anyOf or oneOf alternative, the taken if branch and dependentSchemas.
+// Keywords whose violation cannot be derived mechanically, such as pattern or format, are not mutated.
+func (s *Schema) Mutations(instance interface{}) []Mutation {
+	m := &mutator{root: instance}
+	m.collect(s, nil, instance, 0)
+	return m.mutations
+}
+
+// mutator collects the mutations of an instance.
+type mutator struct {
+	root      interface{}
+	mutations []Mutation
+}
+
+// collect adds the mutations of the schema s applied to value, found at path in the instance,
+// and descends into the subschemas that apply to value and its children.
+func (m *mutator) collect(s *Schema, path []string, value interface{}, depth int) {
+	if s == nil || s.Boolean != nil || depth > maxMutationDepth {
+		return
+	}
+
+	m.mutateValue(s, path, value)
+
+	if s.ResolvedRef != nil {
+		m.collect(s.ResolvedRef, path, value, depth+1)
+	}
+	if s.ResolvedDynamicRef != nil {
+		m.collect(s.ResolvedDynamicRef, path, value, depth+1)
+	}
+	for _, sub := range s.AllOf {
+		m.collect(sub, path, value, depth+1)
+	}
+	if sub := singleMatch(s.AnyOf, value); sub != nil {
+		m.collect(sub, path, value, depth+1)
+	}
+	if sub := singleMatch(s.OneOf, value); sub != nil {
+		m.collect(sub, path, value, depth+1)
+	}
+	if s.If != nil {
+		if s.If.Validate(value).IsValid() {
+			m.collect(s.Then, path, value, depth+1)
+		} else {
+			m.collect(s.Else, path, value, depth+1)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range sortedKeys(v) {
+			if _, ok := s.DependentSchemas[name]; ok {
+				m.collect(s.DependentSchemas[name], path, value, depth+1)
+			}
+		}
+		for _, name := range sortedKeys(v) {
+			for _, sub := range propertySchemas(s, name) {
+				m.collect(sub, append(path[:len(path):len(path)], name), v[name], 0)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			sub := s.Items
+			if i < len(s.PrefixItems) {
+				sub = s.PrefixItems[i]
+			}
+			m.collect(sub, append(path[:len(path):len(path)], strconv.Itoa(i)), item, 0)
+		}
+	}
+}
+
+// mutateValue adds the mutations of the keywords of s that constrain value itself.
+func (m *mutator) mutateValue(s *Schema, path []string, value interface{}) {
+	if len(s.Type) > 0 {
+		if wrong, ok := disallowedTypeValue(s.Type); ok {
+			m.add(path, "type", fmt.Sprintf("replace the value with %s", getDataType(wrong)), wrong)
+		}
+	}
+	if len(s.Enum) > 0 {
+		m.add(path, "enum", "replace the value with one outside the enum", "mutated value outside the enum")
+	}
+	if s.Const != nil && s.Const.IsSet {
+		m.add(path, "const", "replace the value with a different one", []interface{}{"mutated", s.Const.Value})
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m.mutateObject(s, path, v)
+	case []interface{}:
+		m.mutateArray(s, path, v)
+	case string:
+		m.mutateString(s, path, v)
+	default:
+		if number, ok := mutationNumber(value); ok {
+			m.mutateNumber(s, path, number)
+		}
+	}
+}
+
+func (m *mutator) mutateObject(s *Schema, path []string, object map[string]interface{}) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; ok {
+			m.add(path, "required", fmt.Sprintf("drop required property '%s'", name), withoutKey(object, name))
+		}
+	}
+	for _, name := range sortedKeys(object) {
+		for _, dependent := range s.DependentRequired[name] {
+			if _, ok := object[dependent]; ok {
+				m.add(path, "dependentRequired", fmt.Sprintf("drop property '%s' required by '%s'", dependent, name), withoutKey(object, dependent))
+			}
+		}
+	}
+
+	if forbidsExtraProperties(s.AdditionalProperties) {
+		m.add(path, "additionalProperties", "add an undeclared property", withKey(object, mutationPropertyName(object), "mutated"))
+	} else if forbidsExtraProperties(s.UnevaluatedProperties) {
+		m.add(path, "unevaluatedProperties", "add an undeclared property", withKey(object, mutationPropertyName(object), "mutated"))
+	}
+
+	if s.MaxProperties != nil {
+		grown := object
+		for len(grown) <= int(*s.MaxProperties) {
+			grown = withKey(grown, mutationPropertyName(grown), "mutated")
+		}
+		m.add(path, "maxProperties", "exceed maxProperties", grown)
+	}
+	if s.MinProperties != nil && *s.MinProperties > 0 {
+		shrunk := map[string]interface{}{}
+		for _, name := range sortedKeys(object)[:int(*s.MinProperties)-1] {
+			shrunk[name] = object[name]
+		}
+		m.add(path, "minProperties", "go below minProperties", shrunk)
+	}
+}
+
+func (m *mutator) mutateArray(s *Schema, path []string, items []interface{}) {
+	if s.MaxItems != nil {
+		grown := append([]interface{}{}, items...)
+		for len(grown) <= int(*s.MaxItems) {
+			var item interface{}
+			if len(items) > 0 {
+				item = items[len(items)-1]
+			}
+			grown = append(grown, item)
+		}
+		m.add(path, "maxItems", "exceed maxItems", grown)
+	}
+	if s.MinItems != nil && *s.MinItems > 0 && int(*s.MinItems) <= len(items) {
+		m.add(path, "minItems", "go below minItems", append([]interface{}{}, items[:int(*s.MinItems)-1]...))
+	}
+	if s.UniqueItems != nil && *s.UniqueItems && len(items) > 0 {
+		m.add(path, "uniqueItems", "duplicate the first item", append(append([]interface{}{}, items...), items[0]))
+	}
+}
+
+func (m *mutator) mutateString(s *Schema, path []string, str string) {
+	if s.MaxLength != nil {
+		m.add(path, "maxLength", "exceed maxLength", str+strings.Repeat("x", int(*s.MaxLength)+1))
+	}
+	if s.MinLength != nil && *s.MinLength > 0 {
+		runes := []rune(str)
+		if n := int(*s.MinLength) - 1; n < len(runes) {
+			m.add(path, "minLength", "go below minLength", string(runes[:n]))
+		}
+	}
+}
+
+func (m *mutator) mutateNumber(s *Schema, path []string, number float64) {
+	if s.Maximum != nil {
+		limit, _ := s.Maximum.Float64()
+		m.add(path, "maximum", "exceed maximum", limit+1)
+	}
+	if s.ExclusiveMaximum != nil {
+		limit, _ := s.ExclusiveMaximum.Float64()
+		m.add(path, "exclusiveMaximum", "reach exclusiveMaximum", limit)
+	}
+	if s.Minimum != nil {
+		limit, _ := s.Minimum.Float64()
+		m.add(path, "minimum", "go below minimum", limit-1)
+	}
+	if s.ExclusiveMinimum != nil {
+		limit, _ := s.ExclusiveMinimum.Float64()
+		m.add(path, "exclusiveMinimum", "reach exclusiveMinimum", limit)
+	}
+	if s.MultipleOf != nil {
+		step, _ := s.MultipleOf.Float64()
+		m.add(path, "multipleOf", "move off multipleOf", number+step/2)
+	}
+}
+
+// add records a mutation replacing the value at path with replacement.
+func (m *mutator) add(path []string, keyword, description string, replacement interface{}) {
+	m.mutations = append(m.mutations, Mutation{
+		InstanceLocation: jsonPointer(path),
+		Keyword:          keyword,
+		Description:      description,
+		Instance:         replaceAt(m.root, path, replacement),
+	})
+}
+
+// singleMatch returns the only schema of schemas that value is valid against, if exactly one is.
+func singleMatch(schemas []*Schema, value interface{}) *Schema {
+	var match *Schema
+	for _, schema := range schemas {
+		if schema.Validate(value).IsValid() {
+			if match != nil {
+				return nil
+			}
+			match = schema
+		}
+	}
+	return match
+}
+
+// propertySchemas returns the schemas that apply to the property name of an object validated by s.
+func propertySchemas(s *Schema, name string) []*Schema {
+	var schemas []*Schema
+	if s.Properties != nil {
+		if schema, ok := (*s.Properties)[name]; ok {
+			schemas = append(schemas, schema)
+		}
+	}
+	if s.PatternProperties != nil {
+		patterns := make([]string, 0, len(*s.PatternProperties))
+		for pattern := range *s.PatternProperties {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			if regex, err := s.regex(pattern); err == nil && regex.MatchString(name) {
+				schemas = append(schemas, (*s.PatternProperties)[pattern])
+			}
+		}
+	}
+	if len(schemas) == 0 && s.AdditionalProperties != nil {
+		schemas = append(schemas, s.AdditionalProperties)
+	}
+	return schemas
+}
+
+// forbidsExtraProperties reports whether schema is the false schema.
+func forbidsExtraProperties(schema *Schema) bool {
+	return schema.IsFalse()
+}
+
+// disallowedTypeValue returns a value whose type is not among types.
+func disallowedTypeValue(types SchemaType) (interface{}, bool) {
+	allowed := make(map[string]bool, len(types))
+	for _, t := range types {
+		allowed[t] = true
+	}
+	candidates := []struct {
+		typ   string
+		value interface{}
+	}{
+		{"string", "mutated"},
+		{"integer", 1.0},
+		{"number", 1.5},
+		{"boolean", true},
+		{"null", nil},
+		{"object", map[string]interface{}{}},
+		{"array", []interface{}{}},
+	}
+	for _, candidate := range candidates {
+		if allowed[candidate.typ] || (candidate.typ == "integer" && allowed["number"]) {
+			continue
+		}
+		return candidate.value, true
+	}
+	return nil, false
+}
+
+// mutationNumber converts a numeric value to float64.
+func mutationNumber(value interface{}) (float64, bool) {
+	switch getDataType(value) {
+	case "integer", "number":
+		rat := NewRat(value)
+		if rat == nil {
+			return 0, false
+		}
+		f, _ := rat.Float64()
+		return f, true
+	}
+	return 0, false
+}
+
+// mutationPropertyName returns a property name that object does not have.
+func mutationPropertyName(object map[string]interface{}) string {
+	name := "mutated"
+	for i := 1; ; i++ {
+		if _, ok := object[name]; !ok {
+			return name
+		}
+		name = "mutated" + strconv.Itoa(i)
+	}
+}
+
+// replaceAt returns a copy of root where the value at path is replaced. Only the containers along
+// path are copied.
+func replaceAt(root interface{}, path []string, replacement interface{}) interface{} {
+	if len(path) == 0 {
+		return replacement
+	}
+	switch v := root.(type) {
+	case map[string]interface{}:
+		return withKey(v, path[0], replaceAt(v[path[0]], path[1:], replacement))
+	case []interface{}:
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i < 0 || i >= len(v) {
+			return root
+		}
+		items := append([]interface{}{}, v...)
+		items[i] = replaceAt(v[i], path[1:], replacement)
+		return items
+	}
+	return root
+}
+
+// withKey returns a copy of object with name set to value.
+func withKey(object map[string]interface{}, name string, value interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(object)+1)
+	for k, v := range object {
+		result[k] = v
+	}
+	result[name] = value
+	return result
+}
+
+// withoutKey returns a copy of object without name.
+func withoutKey(object map[string]interface{}, name string) map[string]interface{} {
+	result := make(map[string]interface{}, len(object))
+	for k, v := range object {
+		if k != name {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// sortedKeys returns the keys of object in ascending order.
+func sortedKeys[V any](object map[string]V) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonPointer joins path into a JSON Pointer.
+func jsonPointer(path []string) string {
+	var pointer strings.Builder
+	for _, token := range path {
+		pointer.WriteString("/" + escapeJSONPointer(token))
+	}
+	return pointer.String()
+}
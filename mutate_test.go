@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutations(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "maxLength": 20},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"tags": {"type": "array", "items": {"enum": ["a", "b"]}, "uniqueItems": true}
+		},
+		"required": ["name"],
+		"additionalProperties": false
+	}`))
+	require.NoError(t, err)
+
+	instance := map[string]interface{}{"name": "Ada", "age": 36, "tags": []interface{}{"a"}}
+	mutations := schema.Mutations(instance)
+
+	var got []string
+	for _, mutation := range mutations {
+		got = append(got, mutation.InstanceLocation+" "+mutation.Keyword)
+	}
+	assert.Equal(t, []string{
+		" type",
+		" required",
+		" additionalProperties",
+		"/age type",
+		"/age maximum",
+		"/age minimum",
+		"/name type",
+		"/name maxLength",
+		"/name minLength",
+		"/tags type",
+		"/tags uniqueItems",
+		"/tags/0 enum",
+	}, got)
+
+	assert.Equal(t, map[string]interface{}{"name": "Ada", "age": 36, "tags": []interface{}{"a"}}, instance, "the instance must not be modified")
+	assert.Equal(t, map[string]interface{}{"name": "Ada", "age": 151.0, "tags": []interface{}{"a"}}, mutations[4].Instance)
+}
+
+func TestMutationTest(t *testing.T) {
+	t.Run("all mutations killed", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{
+			"type": "object",
+			"properties": {"age": {"type": "integer", "minimum": 0}},
+			"required": ["age"]
+		}`))
+		require.NoError(t, err)
+
+		report, err := schema.MutationTest(map[string]interface{}{"age": 1})
+		require.NoError(t, err)
+		assert.Equal(t, 4, report.Total())
+		assert.Empty(t, report.Survivors)
+	})
+
+	t.Run("permissive alternative survives", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{
+			"anyOf": [
+				{"type": "integer", "maximum": 10},
+				{"type": "number"}
+			]
+		}`))
+		require.NoError(t, err)
+
+		report, err := schema.MutationTest(5.5)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Total(), "only the number alternative matches 5.5")
+		assert.Empty(t, report.Survivors)
+
+		report, err = schema.MutationTest(5)
+		require.NoError(t, err)
+		assert.Equal(t, 0, report.Total(), "both alternatives match 5, so no constraint is mutated")
+	})
+
+	t.Run("invalid seed", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{"type": "string"}`))
+		require.NoError(t, err)
+
+		_, err = schema.MutationTest("ok", 1)
+		assert.ErrorIs(t, err, ErrMutationSeedInvalid)
+	})
+
+	t.Run("survivor reported", func(t *testing.T) {
+		schema, err := NewCompiler().Compile([]byte(`{
+			"oneOf": [
+				{"type": "integer", "maximum": 10},
+				{"type": "string"}
+			]
+		}`))
+		require.NoError(t, err)
+
+		report, err := schema.MutationTest(5)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Killed)
+		require.Len(t, report.Survivors, 1)
+		assert.Equal(t, "type", report.Survivors[0].Keyword)
+		assert.Equal(t, "mutated", report.Survivors[0].Instance)
+	})
+}
@@ -0,0 +1,21 @@
+//go:build !jsonschema_core
+
+package jsonschema
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeNFC normalizes strings to Unicode Normalization Form C. It is not part of the core
+// build, which leaves out the normalization tables.
+func NormalizeNFC(_ *Schema, value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok {
+		return norm.NFC.String(s), nil
+	}
+	return value, nil
+}
+
+// isNFC tells whether s is in Unicode Normalization Form C.
+func isNFC(s string) bool {
+	return norm.NFC.IsNormalString(s)
+}
@@ -0,0 +1,9 @@
+//go:build jsonschema_core
+
+package jsonschema
+
+// isNFC assumes that s is in Unicode Normalization Form C, which the core build cannot tell
+// without the normalization tables.
+func isNFC(string) bool {
+	return true
+}
@@ -0,0 +1,59 @@
+//go:build !jsonschema_core
+
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterTransform(t *testing.T) {
+	compiler := NewCompiler().SetAssertFormat(true).RegisterTransform(TrimSpace, NormalizeNFC, LowercaseEmails)
+	schema, err := compiler.Compile([]byte(`{
+		"properties": {
+			"code": {"type": "string", "pattern": "^[A-Z]{3}$"},
+			"name": {"const": "Café"},
+			"email": {"type": "string", "format": "email", "pattern": "^[a-z@.]+$"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	assert.True(t, schema.Validate(map[string]interface{}{
+		"code":  "  ABC\n",
+		"name":  "Cafe\u0301",
+		"email": " Ada@Example.COM ",
+	}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"code": " AB "}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"code": "ABCD"}).IsValid())
+}
+
+func TestSchemaSetTransforms(t *testing.T) {
+	compiler := NewCompiler().RegisterTransform(TrimSpace)
+	schema, err := compiler.Compile([]byte(`{
+		"properties": {
+			"trimmed": {"maxLength": 3},
+			"raw": {"maxLength": 3}
+		}
+	}`))
+	require.NoError(t, err)
+	(*schema.Properties)["raw"].SetTransforms()
+
+	assert.True(t, schema.Validate(map[string]interface{}{"trimmed": " abc "}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"raw": " abc "}).IsValid(), "transforms are disabled for raw")
+
+	schema.SetTransforms(NormalizeNFC)
+	assert.False(t, schema.Validate(map[string]interface{}{"trimmed": " abc "}).IsValid(), "the schema overrides the compiler")
+	assert.True(t, schema.Validate(map[string]interface{}{"trimmed": "éab"}).IsValid())
+}
+
+func TestNormalizeNFC(t *testing.T) {
+	compiler := NewCompiler().RegisterTransform(NormalizeNFC)
+	schema, err := compiler.Compile([]byte(`{"properties": {"name": {"const": "Café"}}}`))
+	require.NoError(t, err)
+
+	assert.True(t, schema.Validate(map[string]interface{}{"name": "Café"}).IsValid())
+	assert.False(t, IsIDNHostname("café.example"), "U-labels must be in NFC")
+	assert.True(t, IsIDNHostname("café.example"))
+}
@@ -0,0 +1,331 @@
+package jsonschema
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// Normalize returns a simplified copy of the schema in a canonical form, to make diffs, hashing and
+// reviews of schemas easier. The copy accepts exactly the same instances:
+//
+//   - allOf members that are true or {} and duplicated members are removed, and an allOf with a
+//     single member is merged into its parent when their keywords do not overlap;
+//   - enum values are deduplicated and sorted, and required and type lists are deduplicated and sorted;
+//   - a type list containing both integer and number keeps number only;
+//   - of minimum and exclusiveMinimum, and of maximum and exclusiveMaximum, only the tighter bound is kept;
+//   - constraints equal to their default (minLength, minItems, minProperties 0, uniqueItems false)
+//     are removed.
+//
+// Marshaling the copy writes keywords in a fixed order and property names sorted. The copy is
+// compiled with the compiler of s, but is not registered with it, so s stays the schema returned
+// for its URI. Normalize is meant to be called on root schemas.
+func (s *Schema) Normalize() (*Schema, error) {
+	document, err := genericSchema(s)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &normalizer{unwrap: !refersIntoAllOf(document)}
+	return s.compileDocument(n.normalize(document))
+}
+
+// genericSchema returns the generic JSON form of a schema, with numbers kept as json.Number.
+func genericSchema(schema *Schema) (interface{}, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// compileDocument compiles the generic JSON form of a schema document with the compiler of s,
+// without registering it.
+func (s *Schema) compileDocument(document interface{}) (*Schema, error) {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled, err := newSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	compiler := s.compiler
+	if compiler == nil {
+		compiler = NewCompiler()
+	}
+	compiled.initializeSchema(compiler, nil)
+	if err := compiler.compileKeywords(compiled); err != nil {
+		return nil, err
+	}
+	return compiled, nil
+}
+
+// schemaKeywords lists the keywords whose value is a schema, a list of schemas, or an object of
+// schemas, for the generic JSON form of a schema.
+var (
+	schemaKeywords = []string{
+		"not", "if", "then", "else", "items", "contains", "additionalProperties", "propertyNames",
+		"unevaluatedItems", "unevaluatedProperties", "contentSchema",
+	}
+	schemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+	schemaMapKeywords  = []string{"$defs", "properties", "patternProperties", "dependentSchemas"}
+)
+
+// identityKeywords change how references in a schema resolve, so a schema holding them is not
+// merged into its parent.
+var identityKeywords = []string{"$id", "$schema", "$anchor", "$dynamicAnchor", "$defs"}
+
+// normalizer simplifies the generic JSON form of a schema.
+type normalizer struct {
+	unwrap bool // Whether single allOf members may be merged, which changes JSON Pointers into allOf.
+}
+
+// normalize simplifies a schema bottom up: subschemas first, then the keywords of the schema itself.
+func (n *normalizer) normalize(value interface{}) interface{} {
+	schema, ok := value.(map[string]interface{})
+	if !ok {
+		return value // Boolean schema.
+	}
+
+	for _, keyword := range schemaKeywords {
+		if sub, ok := schema[keyword]; ok {
+			schema[keyword] = n.normalize(sub)
+		}
+	}
+	for _, keyword := range schemaListKeywords {
+		if list, ok := schema[keyword].([]interface{}); ok {
+			for i, sub := range list {
+				list[i] = n.normalize(sub)
+			}
+		}
+	}
+	for _, keyword := range schemaMapKeywords {
+		if subs, ok := schema[keyword].(map[string]interface{}); ok {
+			for name, sub := range subs {
+				subs[name] = n.normalize(sub)
+			}
+		}
+	}
+
+	n.simplifyAllOf(schema)
+	simplifyBounds(schema)
+	simplifyLists(schema)
+	for keyword, empty := range map[string]interface{}{
+		"minLength":     json.Number("0"),
+		"minItems":      json.Number("0"),
+		"minProperties": json.Number("0"),
+		"uniqueItems":   false,
+	} {
+		if value, ok := schema[keyword]; ok && canonicalJSON(value) == canonicalJSON(empty) {
+			delete(schema, keyword)
+		}
+	}
+	return schema
+}
+
+// simplifyAllOf removes trivial and duplicated allOf members and merges a single remaining member
+// into the schema when their keywords do not overlap.
+func (n *normalizer) simplifyAllOf(schema map[string]interface{}) {
+	members, ok := schema["allOf"].([]interface{})
+	if !ok {
+		return
+	}
+
+	seen := map[string]bool{}
+	kept := members[:0]
+	for _, member := range members {
+		key := canonicalJSON(member)
+		if key == "true" || key == "{}" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, member)
+	}
+	if len(kept) == 0 {
+		delete(schema, "allOf")
+		return
+	}
+	schema["allOf"] = kept
+
+	member, ok := kept[0].(map[string]interface{})
+	if !n.unwrap || len(kept) != 1 || !ok {
+		return
+	}
+	for _, keyword := range identityKeywords {
+		if _, ok := member[keyword]; ok {
+			return
+		}
+	}
+	for keyword := range member {
+		if _, ok := schema[keyword]; ok && keyword != "allOf" {
+			return
+		}
+	}
+
+	delete(schema, "allOf")
+	for keyword, value := range member {
+		schema[keyword] = value
+	}
+	n.simplifyAllOf(schema)
+}
+
+// simplifyBounds keeps only the tighter of an inclusive and an exclusive numeric bound.
+func simplifyBounds(schema map[string]interface{}) {
+	for _, pair := range [][2]string{{"minimum", "exclusiveMinimum"}, {"maximum", "exclusiveMaximum"}} {
+		inclusive, ok1 := jsonRat(schema[pair[0]])
+		exclusive, ok2 := jsonRat(schema[pair[1]])
+		if !ok1 || !ok2 {
+			continue
+		}
+		cmp := inclusive.Cmp(exclusive)
+		if pair[0] == "maximum" {
+			cmp = -cmp
+		}
+		if cmp > 0 {
+			delete(schema, pair[1])
+		} else {
+			delete(schema, pair[0])
+		}
+	}
+}
+
+// simplifyLists deduplicates and sorts enum, required and type.
+func simplifyLists(schema map[string]interface{}) {
+	if values, ok := schema["enum"].([]interface{}); ok {
+		schema["enum"] = sortedUnique(values)
+	}
+	if values, ok := schema["required"].([]interface{}); ok {
+		schema["required"] = sortedUnique(values)
+	}
+	if values, ok := schema["type"].([]interface{}); ok {
+		hasNumber := false
+		for _, value := range values {
+			hasNumber = hasNumber || value == "number"
+		}
+		types := values[:0]
+		for _, value := range values {
+			if !(hasNumber && value == "integer") {
+				types = append(types, value)
+			}
+		}
+		if types = sortedUnique(types); len(types) == 1 {
+			schema["type"] = types[0]
+		} else {
+			schema["type"] = types
+		}
+	}
+}
+
+// sortedUnique removes values that are equal as JSON and sorts the rest by their canonical form.
+func sortedUnique(values []interface{}) []interface{} {
+	keys := map[string]interface{}{}
+	for _, value := range values {
+		key := canonicalJSON(value)
+		if _, ok := keys[key]; !ok {
+			keys[key] = value
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	result := make([]interface{}, 0, len(sorted))
+	for _, key := range sorted {
+		result = append(result, keys[key])
+	}
+	return result
+}
+
+// canonicalJSON renders a generic JSON value with sorted object keys and numbers in lowest terms,
+// so that values equal under JSON Schema, such as 1 and 1.0, render the same.
+func canonicalJSON(value interface{}) string {
+	var out strings.Builder
+	writeCanonicalJSON(&out, value)
+	return out.String()
+}
+
+func writeCanonicalJSON(out *strings.Builder, value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		out.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			writeCanonicalJSON(out, key)
+			out.WriteByte(':')
+			writeCanonicalJSON(out, value[key])
+		}
+		out.WriteByte('}')
+	case []interface{}:
+		out.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			writeCanonicalJSON(out, item)
+		}
+		out.WriteByte(']')
+	case json.Number, float64, float32, int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
+		if r, ok := numberRat(value); ok {
+			out.WriteString(r.RatString())
+		} else {
+			data, _ := json.Marshal(value)
+			out.Write(data)
+		}
+	default:
+		data, _ := json.Marshal(value)
+		out.Write(data)
+	}
+}
+
+// jsonRat converts a generic JSON number to a rational.
+func jsonRat(value interface{}) (*big.Rat, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Rat).SetString(string(number))
+}
+
+// refersIntoAllOf reports whether a $ref or $dynamicRef of the document points into an allOf
+// member with a JSON Pointer, which merging the member into its parent would break.
+func refersIntoAllOf(value interface{}) bool {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if ref, ok := item.(string); ok && (key == "$ref" || key == "$dynamicRef") && strings.Contains(ref, "/allOf/") {
+				return true
+			}
+			if refersIntoAllOf(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if refersIntoAllOf(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected string
+	}{
+		{
+			name:     "single allOf member merged",
+			schema:   `{"title": "User", "allOf": [{"type": "object", "required": ["name"]}]}`,
+			expected: `{"type":"object","required":["name"],"title":"User"}`,
+		},
+		{
+			name:     "overlapping allOf member kept",
+			schema:   `{"type": "object", "allOf": [true, {"type": "object", "required": ["name"]}, {}]}`,
+			expected: `{"allOf":[{"type":"object","required":["name"]}],"type":"object"}`,
+		},
+		{
+			name:     "nested allOf unwrapped",
+			schema:   `{"properties": {"id": {"allOf": [{"allOf": [{"type": "integer"}, {"type": "integer"}]}]}}}`,
+			expected: `{"properties":{"id":{"type":"integer"}}}`,
+		},
+		{
+			name:     "enum and lists sorted",
+			schema:   `{"enum": ["b", 2, "a", 2.0, "b"], "required": ["z", "a", "z"], "type": ["string", "integer", "number"]}`,
+			expected: `{"type":["number","string"],"enum":["a","b",2],"required":["a","z"]}`,
+		},
+		{
+			name:     "redundant bounds removed",
+			schema:   `{"minimum": 1, "exclusiveMinimum": 1, "maximum": 5, "exclusiveMaximum": 10, "minLength": 0, "uniqueItems": false}`,
+			expected: `{"maximum":5,"exclusiveMinimum":1}`,
+		},
+		{
+			name:     "pointer into allOf",
+			schema:   `{"allOf": [{"$defs": {}, "type": "string"}], "properties": {"a": {"$ref": "#/allOf/0"}}}`,
+			expected: `{"allOf":[{"type":"string"}],"properties":{"a":{"$ref":"#/allOf/0"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewCompiler().Compile([]byte(tt.schema))
+			require.NoError(t, err)
+
+			normalized, err := schema.Normalize()
+			require.NoError(t, err)
+			data, err := json.Marshal(normalized)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestNormalizeValidatesAlike(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/order",
+		"$defs": {"amount": {"allOf": [{"type": "number", "minimum": 0, "exclusiveMinimum": 0}]}},
+		"type": "object",
+		"properties": {"total": {"$ref": "#/$defs/amount"}, "status": {"enum": ["open", "closed", "open"]}},
+		"required": ["total", "status"]
+	}`))
+	require.NoError(t, err)
+
+	normalized, err := schema.Normalize()
+	require.NoError(t, err)
+
+	for _, instance := range []interface{}{
+		map[string]interface{}{"total": 1.5, "status": "open"},
+		map[string]interface{}{"total": 0, "status": "open"},
+		map[string]interface{}{"total": 3, "status": "pending"},
+		map[string]interface{}{"total": 3},
+	} {
+		assert.Equal(t, schema.Validate(instance).IsValid(), normalized.Validate(instance).IsValid(), "instance %v", instance)
+	}
+
+	cached, err := compiler.GetSchema("https://example.com/order")
+	require.NoError(t, err)
+	assert.Same(t, schema, cached)
+}
@@ -18,8 +18,8 @@ func evaluateNot(schema *Schema, instance interface{}, evaluatedProps map[string
 	result, _, _ := schema.Not.evaluate(instance, dynamicScope)
 
 	if result != nil {
-		result.SetEvaluationPath("/oneOf").
-			SetSchemaLocation(schema.GetSchemaLocation("/oneOf")).
+		result.SetEvaluationPath("/not").
+			SetSchemaLocation(schema.GetSchemaLocation("/not")).
 			SetInstanceLocation("")
 
 		if result.IsValid() {
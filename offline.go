@@ -0,0 +1,121 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// SetOffline forbids fetching documents over HTTP, for production environments without outbound
+// network access. Documents must then come from the schemas already compiled, the local copies
+// registered with AddResource or PreloadDir, the file systems of UseFS, or loaders of other
+// schemes; compiling a schema whose references to http and https URIs cannot be resolved from
+// them fails with ErrUnresolvedRemoteRefs, listing the references.
+func (c *Compiler) SetOffline(offline bool) *Compiler {
+	c.Offline = offline
+	return c
+}
+
+// AddResource registers data, a JSON document, as the local copy of the document at uri, which
+// references resolve to instead of fetching it. The document is compiled when first referenced.
+func (c *Compiler) AddResource(uri string, data []byte) error {
+	if !json.Valid(data) {
+		return fmt.Errorf("%w: %s", ErrJSONUnmarshalError, uri)
+	}
+	id, _ := splitRef(uri)
+	if c.resources == nil {
+		c.resources = make(map[string][]byte)
+	}
+	c.resources[id] = data
+	return nil
+}
+
+// PreloadDir registers the JSON and YAML documents of the directory dir and its subdirectories
+// as local copies of remote documents, like AddResource: each one under its $id, or under its file
+// URI when it has none. Documents that cannot be read or parsed make PreloadDir fail, naming them.
+func (c *Compiler) PreloadDir(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	fsys := os.DirFS(dir)
+	return fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		extension := strings.ToLower(filepath.Ext(name))
+		if entry.IsDir() || extension != ".json" && !isYAMLPath(name) {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err == nil && isYAMLPath(name) {
+			data, err = yamlToJSON(data)
+		}
+		var document struct {
+			ID string `json:"$id"`
+		}
+		if err == nil {
+			err = json.Unmarshal(data, &document)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		uri := document.ID
+		if !isAbsoluteURI(uri) {
+			uri = (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, name))}).String()
+		}
+		return c.AddResource(uri, data)
+	})
+}
+
+// resource returns the local copy of the document at uri registered in c or the compilers it is
+// layered on, and whether there is one.
+func (c *Compiler) resource(uri string) ([]byte, bool) {
+	for ; c != nil; c = c.base {
+		if data, ok := c.resources[uri]; ok {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// isNetworkURI reports whether uri is fetched over the network, with HTTP.
+func isNetworkURI(uri string) bool {
+	scheme := getURLScheme(uri)
+	return scheme == "http" || scheme == "https"
+}
+
+// checkOfflineRefs returns an error listing the references to http and https URIs of schema and
+// its subschemas that could not be resolved.
+func checkOfflineRefs(schema *Schema) error {
+	unresolved := map[string]bool{}
+	walkSchema(schema, func(_ string, subschema *Schema) bool {
+		for _, reference := range []struct {
+			ref      string
+			resolved *Schema
+		}{{subschema.Ref, subschema.ResolvedRef}, {subschema.DynamicRef, subschema.ResolvedDynamicRef}} {
+			ref := reference.ref
+			if ref == "" || reference.resolved != nil {
+				continue
+			}
+			if !isAbsoluteURI(ref) {
+				ref = resolveRelativeURI(subschema.baseURI, ref)
+			}
+			if isNetworkURI(ref) {
+				unresolved[ref] = true
+			}
+		}
+		return true
+	})
+	if len(unresolved) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnresolvedRemoteRefs, strings.Join(sortedKeys(unresolved), ", "))
+}
@@ -0,0 +1,67 @@
+package jsonschema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffline(t *testing.T) {
+	source := &reloadSource{documents: map[string]string{
+		"https://example.com/name.json": `{"type": "string"}`,
+	}}
+	compiler := NewCompiler().SetOffline(true).RegisterLoader("https", source.load)
+
+	_, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/user.json",
+		"properties": {
+			"name": {"$ref": "name.json"},
+			"tags": {"$ref": "https://example.org/tags.json#/$defs/tag"},
+			"self": {"$ref": "#"}
+		}
+	}`))
+	require.ErrorIs(t, err, ErrUnresolvedRemoteRefs)
+	assert.Contains(t, err.Error(), ": https://example.com/name.json, https://example.org/tags.json#/$defs/tag")
+	assert.Zero(t, source.fetched)
+	_, err = compiler.GetSchema("https://example.com/name.json")
+	assert.ErrorIs(t, err, ErrOffline)
+
+	require.NoError(t, compiler.AddResource("https://example.com/name.json", []byte(`{"type": "string"}`)))
+	require.NoError(t, compiler.AddResource("https://example.org/tags.json", []byte(`{"$defs": {"tag": {"type": "string"}}}`)))
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/user.json",
+		"properties": {"name": {"$ref": "name.json"}, "tags": {"items": {"$ref": "https://example.org/tags.json#/$defs/tag"}}}
+	}`))
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(map[string]interface{}{"tags": []interface{}{1}}).IsValid())
+	assert.Zero(t, source.fetched)
+	assert.Empty(t, compiler.loaded, "local copies are not checked by CheckRemotes")
+
+	assert.ErrorIs(t, compiler.AddResource("https://example.com/bad.json", []byte(`{`)), ErrJSONUnmarshalError)
+}
+
+func TestPreloadDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "vendor"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vendor", "address.json"), []byte(`{"$id": "https://example.com/address.json", "required": ["city"], "properties": {"owner": {"$ref": "person.json"}}}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "person.json"), []byte(`{"$id": "https://example.com/person.json", "properties": {"address": {"$ref": "address.json"}}}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "local.json"), []byte(`{"type": "integer"}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`not a schema`), 0o600))
+
+	compiler := NewCompiler().SetOffline(true)
+	require.NoError(t, compiler.PreloadDir(dir))
+	schema, err := compiler.GetSchema("https://example.com/person.json")
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(map[string]interface{}{"address": map[string]interface{}{}}).IsValid())
+	assert.True(t, schema.Validate(map[string]interface{}{"address": map[string]interface{}{"city": "Oslo"}}).IsValid())
+
+	require.Contains(t, compiler.resources, "file://"+filepath.ToSlash(filepath.Join(dir, "local.json")))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{`), 0o600))
+	err = NewCompiler().PreloadDir(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken.json")
+}
@@ -0,0 +1,206 @@
+package jsonschema
+
+import "sort"
+
+// OutputUnit is a unit of the basic, detailed and verbose output formats of the JSON Schema
+// specification, which other tooling reads. KeywordLocation follows the evaluation path from the
+// root schema, including the $ref crossed, and AbsoluteKeywordLocation is the location of the
+// keyword in the schema resource defining it, when that resource has a URI.
+type OutputUnit struct {
+	Valid                   bool          `json:"valid"`
+	KeywordLocation         string        `json:"keywordLocation"`
+	AbsoluteKeywordLocation string        `json:"absoluteKeywordLocation,omitempty"`
+	InstanceLocation        string        `json:"instanceLocation"`
+	Error                   string        `json:"error,omitempty"`
+	Errors                  []*OutputUnit `json:"errors,omitempty"`
+	Annotation              interface{}   `json:"annotation,omitempty"`
+	Annotations             []*OutputUnit `json:"annotations,omitempty"`
+}
+
+// ToBasic converts the evaluation result to the basic output format: a flat list of the errors, or
+// of the annotations of a valid result, each with the locations of its keyword and instance.
+func (e *EvaluationResult) ToBasic() *OutputUnit {
+	tree := newOutputBuilder().unit(e, "", "")
+	basic := &OutputUnit{Valid: e.Valid}
+	var flatten func(unit *OutputUnit)
+	flatten = func(unit *OutputUnit) {
+		children := unit.Errors
+		if e.Valid {
+			children = unit.Annotations
+		}
+		for _, child := range children {
+			switch {
+			case !child.isKeyword():
+				flatten(child)
+			case e.Valid:
+				basic.Annotations = append(basic.Annotations, child)
+			default:
+				basic.Errors = append(basic.Errors, child)
+			}
+		}
+	}
+	flatten(tree)
+	return basic
+}
+
+// ToDetailed converts the evaluation result to the detailed output format: the units of the
+// verbose format leading to errors, or to the annotations of a valid result, where the units with
+// a single child are replaced with that child.
+func (e *EvaluationResult) ToDetailed() *OutputUnit {
+	root := newOutputBuilder().unit(e, "", "")
+	var condense func(unit *OutputUnit) *OutputUnit
+	condense = func(unit *OutputUnit) *OutputUnit {
+		if unit.isKeyword() {
+			return unit
+		}
+		children := unit.Errors
+		if unit.Valid {
+			children = unit.Annotations
+		}
+		condensed := make([]*OutputUnit, 0, len(children))
+		for _, child := range children {
+			if child = condense(child); child != nil {
+				condensed = append(condensed, child)
+			}
+		}
+		switch len(condensed) {
+		case 0:
+			return nil
+		case 1:
+			if unit != root {
+				return condensed[0]
+			}
+		}
+		detailed := *unit
+		detailed.Errors, detailed.Annotations = nil, nil
+		if unit.Valid {
+			detailed.Annotations = condensed
+		} else {
+			detailed.Errors = condensed
+		}
+		return &detailed
+	}
+	if detailed := condense(root); detailed != nil {
+		return detailed
+	}
+	return &OutputUnit{Valid: root.Valid, KeywordLocation: root.KeywordLocation, AbsoluteKeywordLocation: root.AbsoluteKeywordLocation}
+}
+
+// ToVerbose converts the evaluation result to the verbose output format, a unit for every schema
+// evaluated, mirroring the evaluation: the units of invalid schemas list their errors and invalid
+// subschemas under errors, and their annotations and valid subschemas under annotations.
+func (e *EvaluationResult) ToVerbose() *OutputUnit {
+	return newOutputBuilder().unit(e, "", "")
+}
+
+// isKeyword reports whether u is the error or annotation of a keyword, rather than the unit of a schema.
+func (u *OutputUnit) isKeyword() bool {
+	return u.Error != "" || u.Annotation != nil
+}
+
+// outputBuilder converts evaluation results to output units.
+type outputBuilder struct {
+	locations map[*Schema]string // Pointers of the schemas in their resources.
+}
+
+func newOutputBuilder() *outputBuilder {
+	return &outputBuilder{locations: map[*Schema]string{}}
+}
+
+// unit returns the verbose unit of result, evaluated at keywordLocation for the instance at
+// instanceLocation.
+func (b *outputBuilder) unit(result *EvaluationResult, keywordLocation, instanceLocation string) *OutputUnit {
+	unit := &OutputUnit{
+		Valid:                   result.Valid,
+		KeywordLocation:         keywordLocation,
+		AbsoluteKeywordLocation: b.location(result.schema),
+		InstanceLocation:        instanceLocation,
+	}
+	keyword := func(name string) (string, string) {
+		if result.schema.IsBoolean() {
+			return keywordLocation, unit.AbsoluteKeywordLocation // The error of the schema itself.
+		}
+		absolute := ""
+		if unit.AbsoluteKeywordLocation != "" {
+			absolute = unit.AbsoluteKeywordLocation + "/" + escapeJSONPointer(name)
+		}
+		return keywordLocation + "/" + escapeJSONPointer(name), absolute
+	}
+
+	for _, name := range sortedKeys(result.Errors) {
+		location, absolute := keyword(name)
+		unit.Errors = append(unit.Errors, &OutputUnit{
+			KeywordLocation:         location,
+			AbsoluteKeywordLocation: absolute,
+			InstanceLocation:        instanceLocation,
+			Error:                   result.Errors[name].Error(),
+		})
+	}
+	for _, name := range sortedKeys(result.Annotations) {
+		location, absolute := keyword(name)
+		unit.Annotations = append(unit.Annotations, &OutputUnit{
+			Valid:                   true,
+			KeywordLocation:         location,
+			AbsoluteKeywordLocation: absolute,
+			InstanceLocation:        instanceLocation,
+			Annotation:              result.Annotations[name],
+		})
+	}
+
+	// Subschemas are evaluated in any order, such as the properties of an object; their units are
+	// listed in order of location instead.
+	details := make([]*OutputUnit, 0, len(result.Details))
+	for _, detail := range result.Details {
+		location := keywordLocation + detail.EvaluationPath
+		if detail.EvaluationPath == "" && result.schema != nil && detail.schema != nil {
+			switch detail.schema {
+			case result.schema.ResolvedRef:
+				location += "/$ref"
+			case result.schema.ResolvedDynamicRef:
+				location += "/$dynamicRef"
+			}
+		}
+		details = append(details, b.unit(detail, location, instanceLocation+detail.InstanceLocation))
+	}
+	sort.SliceStable(details, func(i, j int) bool {
+		if details[i].KeywordLocation != details[j].KeywordLocation {
+			return details[i].KeywordLocation < details[j].KeywordLocation
+		}
+		return details[i].InstanceLocation < details[j].InstanceLocation
+	})
+	for _, child := range details {
+		if child.Valid {
+			unit.Annotations = append(unit.Annotations, child)
+		} else {
+			unit.Errors = append(unit.Errors, child)
+		}
+	}
+	return unit
+}
+
+// location returns the absolute location of schema in the nearest enclosing resource with a URI,
+// or an empty string.
+func (b *outputBuilder) location(schema *Schema) string {
+	if schema == nil {
+		return ""
+	}
+	resource := schema.resource()
+	if resource.uri == "" {
+		return ""
+	}
+
+	pointer, ok := b.locations[schema]
+	if !ok {
+		walkSchema(resource, func(pointer string, subschema *Schema) bool {
+			if subschema != resource && subschema.uri != "" {
+				return false // Another resource.
+			}
+			if _, seen := b.locations[subschema]; !seen {
+				b.locations[subschema] = pointer
+			}
+			return true
+		})
+		pointer = b.locations[schema]
+	}
+	return resource.uri + "#" + pointer
+}
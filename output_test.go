@@ -0,0 +1,127 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const outputSchema = `{
+	"$id": "https://example.com/person.json",
+	"$defs": {"name": {"type": "string", "minLength": 2}},
+	"type": "object",
+	"properties": {
+		"name": {"$ref": "#/$defs/name"},
+		"age": {"title": "Age", "allOf": [{"minimum": 0}, {"maximum": 150}]}
+	}
+}`
+
+func compileOutputSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema, err := NewCompiler().Compile([]byte(outputSchema))
+	require.NoError(t, err)
+	return schema
+}
+
+func TestToBasic(t *testing.T) {
+	schema := compileOutputSchema(t)
+	basic := schema.Validate(map[string]interface{}{"name": "A", "age": 200}).ToBasic()
+	require.Len(t, basic.Errors, 5)
+	assert.Equal(t, "/properties", basic.Errors[0].KeywordLocation)
+	basic.Errors = basic.Errors[1:] // The message of properties names the properties in any order.
+
+	data, err := json.Marshal(basic)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"valid": false,
+		"keywordLocation": "",
+		"instanceLocation": "",
+		"errors": [
+			{
+				"valid": false,
+				"keywordLocation": "/properties/age/allOf",
+				"absoluteKeywordLocation": "https://example.com/person.json#/properties/age/allOf",
+				"instanceLocation": "/age",
+				"error": "Value does not match the allOf schema at index 1"
+			},
+			{
+				"valid": false,
+				"keywordLocation": "/properties/age/allOf/1/maximum",
+				"absoluteKeywordLocation": "https://example.com/person.json#/properties/age/allOf/1/maximum",
+				"instanceLocation": "/age",
+				"error": "200 should be at most 150"
+			},
+			{
+				"valid": false,
+				"keywordLocation": "/properties/name/$ref",
+				"absoluteKeywordLocation": "https://example.com/person.json#/properties/name/$ref",
+				"instanceLocation": "/name",
+				"error": "Value does not match the reference schema"
+			},
+			{
+				"valid": false,
+				"keywordLocation": "/properties/name/$ref/minLength",
+				"absoluteKeywordLocation": "https://example.com/person.json#/$defs/name/minLength",
+				"instanceLocation": "/name",
+				"error": "Value should be at least 2 characters"
+			}
+		]
+	}`, string(data))
+
+	valid := schema.Validate(map[string]interface{}{"age": 20}).ToBasic()
+	assert.True(t, valid.Valid)
+	assert.Empty(t, valid.Errors)
+	require.Len(t, valid.Annotations, 1)
+	assert.Equal(t, "/properties/age/title", valid.Annotations[0].KeywordLocation)
+	assert.Equal(t, "/age", valid.Annotations[0].InstanceLocation)
+}
+
+func TestToDetailed(t *testing.T) {
+	schema := compileOutputSchema(t)
+	detailed := schema.Validate(map[string]interface{}{"name": "A", "age": 20}).ToDetailed()
+
+	assert.False(t, detailed.Valid)
+	assert.Equal(t, "", detailed.KeywordLocation)
+	require.Len(t, detailed.Errors, 2)
+	assert.Equal(t, "/properties", detailed.Errors[0].KeywordLocation)
+	name := detailed.Errors[1]
+	assert.Equal(t, "/properties/name", name.KeywordLocation)
+	assert.Equal(t, "/name", name.InstanceLocation)
+	require.Len(t, name.Errors, 2)
+	assert.Equal(t, "/properties/name/$ref", name.Errors[0].KeywordLocation)
+	assert.Equal(t, "/properties/name/$ref/minLength", name.Errors[1].KeywordLocation, "the unit of the referenced schema is condensed")
+	assert.Equal(t, "Value should be at least 2 characters", name.Errors[1].Error)
+}
+
+func TestToVerbose(t *testing.T) {
+	schema := compileOutputSchema(t)
+	verbose := schema.Validate(map[string]interface{}{"name": "A", "age": 20}).ToVerbose()
+
+	assert.Equal(t, "https://example.com/person.json#", verbose.AbsoluteKeywordLocation)
+	require.Len(t, verbose.Errors, 2)
+	require.Len(t, verbose.Annotations, 1, "the valid age is kept")
+	age := verbose.Annotations[0]
+	assert.True(t, age.Valid)
+	assert.Equal(t, "/properties/age", age.KeywordLocation)
+	require.Len(t, age.Annotations, 3)
+	assert.Equal(t, "/properties/age/title", age.Annotations[0].KeywordLocation)
+	assert.Equal(t, "Age", *age.Annotations[0].Annotation.(*string))
+	assert.Equal(t, "/properties/age/allOf/0", age.Annotations[1].KeywordLocation)
+	assert.Equal(t, "https://example.com/person.json#/properties/age/allOf/0", age.Annotations[1].AbsoluteKeywordLocation)
+
+	_, err := json.Marshal(verbose)
+	require.NoError(t, err)
+}
+
+func TestOutputWithoutURI(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"items": {"type": "string"}, "minItems": 2}`))
+	require.NoError(t, err)
+	basic := schema.Validate([]interface{}{1}).ToBasic()
+	require.NotEmpty(t, basic.Errors)
+	for _, unit := range basic.Errors {
+		assert.Empty(t, unit.AbsoluteKeywordLocation)
+	}
+	assert.True(t, schema.Validate([]interface{}{"a", "b"}).ToFlag().Valid)
+}
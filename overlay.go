@@ -0,0 +1,169 @@
+package jsonschema
+
+import (
+	"context"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Overlay returns a compiler layered on c, for multi-tenant validation platforms: c holds the
+// schemas, loaders, decoders, media types, type adapters and registered types shared by every
+// tenant, and each tenant compiles into its own overlay. Schemas compiled or set in an overlay,
+// and everything registered in it, shadow those of c for that overlay only; lookups fall back to
+// c otherwise. The overlay starts with a copy of the settings of c, so that setting severities,
+// dialects, keywords, vocabularies or the environment of an overlay leaves c and the other
+// overlays unchanged.
+//
+// Overlays do not lock c, which must therefore not be modified once overlays are in use.
+// Overlays can be layered on other overlays.
+func (c *Compiler) Overlay() *Compiler {
+	overlay := c.clone()
+	overlay.base = c
+	overlay.Decoders = make(map[string]func(string) ([]byte, error))
+	overlay.MediaTypes = make(map[string]func([]byte) (interface{}, error))
+	overlay.Loaders = make(map[string]func(url string) (io.ReadCloser, error))
+	overlay.ContextLoaders = make(map[string]ContextLoader)
+	overlay.mounts, overlay.resources, overlay.types = nil, nil, nil
+	overlay.TypeAdapters, overlay.Decompressors = nil, nil
+	return overlay
+}
+
+// clone returns a compiler with the settings and registrations of c and no schemas. The maps are
+// copied and the slices clipped, so that configuring the clone leaves c unchanged. Every field
+// configuring a compiler must be copied here, which Overlay and reloadCompiler rely on.
+func (c *Compiler) clone() *Compiler {
+	return &Compiler{
+		schemas:              make(map[string]*Schema),
+		formatOverride:       c.formatOverride,
+		types:                maps.Clone(c.types),
+		mirror:               c.mirror,
+		mounts:               slices.Clip(c.mounts),
+		resources:            maps.Clone(c.resources),
+		http:                 c.http,
+		Decoders:             maps.Clone(c.Decoders),
+		MediaTypes:           maps.Clone(c.MediaTypes),
+		Loaders:              maps.Clone(c.Loaders),
+		ContextLoaders:       maps.Clone(c.ContextLoaders),
+		DefaultBaseURI:       c.DefaultBaseURI,
+		AssertFormat:         c.AssertFormat,
+		AnnotateExtensions:   c.AnnotateExtensions,
+		MaxRedirects:         c.MaxRedirects,
+		CollectStats:         c.CollectStats,
+		CollectFailures:      c.CollectFailures,
+		AllowRemoteChanges:   c.AllowRemoteChanges,
+		Offline:              c.Offline,
+		UnresolvedRefs:       c.UnresolvedRefs,
+		Timezones:            c.Timezones,
+		StringLength:         c.StringLength,
+		EmailMode:            c.EmailMode,
+		URISchemes:           slices.Clip(c.URISchemes),
+		UnicodeHostnames:     c.UnicodeHostnames,
+		SuggestPropertyNames: c.SuggestPropertyNames,
+		AnchorPatterns:       c.AnchorPatterns,
+		DuplicateKeys:        c.DuplicateKeys,
+		Parser:               c.Parser,
+		RegexEngine:          c.RegexEngine,
+		Dialects:             maps.Clone(c.Dialects),
+		DefaultDialect:       c.DefaultDialect,
+		DefaultDraft:         c.DefaultDraft,
+		Environment:          maps.Clone(c.Environment),
+		Keywords:             maps.Clone(c.Keywords),
+		Vocabularies:         maps.Clone(c.Vocabularies),
+		WarningHandler:       c.WarningHandler,
+		Severities:           maps.Clone(c.Severities),
+		Middlewares:          slices.Clip(c.Middlewares),
+		Suppressions:         slices.Clip(c.Suppressions),
+		Transforms:           slices.Clip(c.Transforms),
+		Clock:                c.Clock,
+		TypeAdapters:         maps.Clone(c.TypeAdapters),
+		Decompressors:        maps.Clone(c.Decompressors),
+		Cache:                c.Cache,
+		CacheTTL:             c.CacheTTL,
+		CachePolicy:          c.CachePolicy,
+	}
+}
+
+// lookupSchema returns the schema cached under uri in c or the compilers it is layered on.
+func (c *Compiler) lookupSchema(uri string) (*Schema, bool) {
+	for ; c != nil; c = c.base {
+		c.mu.RLock()
+		schema, ok := c.cachedSchema(uri)
+		c.mu.RUnlock()
+		if ok {
+			return schema, true
+		}
+	}
+	return nil, false
+}
+
+// allSchemas returns a copy of the schemas cached in c and the compilers it is layered on, by URI.
+func (c *Compiler) allSchemas() map[string]*Schema {
+	schemas := make(map[string]*Schema)
+	if c.base != nil {
+		schemas = c.base.allSchemas()
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for uri, schema := range c.schemas {
+		schemas[uri] = schema
+	}
+	return schemas
+}
+
+// loader returns the loader registered for scheme in c or the compilers it is layered on.
+func (c *Compiler) loader(scheme string) (ContextLoader, bool) {
+	for ; c != nil; c = c.base {
+		loader, ok := c.Loaders[scheme]
+		if !ok {
+			continue
+		}
+		if contextLoader, ok := c.ContextLoaders[scheme]; ok {
+			return contextLoader, true
+		}
+		return func(_ context.Context, url string) (io.ReadCloser, error) { return loader(url) }, true
+	}
+	return nil, false
+}
+
+// decoder returns the decoder registered for encoding in c or the compilers it is layered on.
+func (c *Compiler) decoder(encoding string) (func(string) ([]byte, error), bool) {
+	for ; c != nil; c = c.base {
+		if decoder, ok := c.Decoders[encoding]; ok {
+			return decoder, true
+		}
+	}
+	return nil, false
+}
+
+// mediaType returns the handler registered for mediaType in c or the compilers it is layered on.
+func (c *Compiler) mediaType(mediaType string) (func([]byte) (interface{}, error), bool) {
+	for ; c != nil; c = c.base {
+		if unmarshal, ok := c.MediaTypes[mediaType]; ok {
+			return unmarshal, true
+		}
+	}
+	return nil, false
+}
+
+// typeAdapter returns the type adapter registered for t in c or the compilers it is layered on.
+// c may be nil.
+func (c *Compiler) typeAdapter(t reflect.Type) (func(interface{}) (interface{}, error), bool) {
+	for ; c != nil; c = c.base {
+		if adapter, ok := c.TypeAdapters[t]; ok {
+			return adapter, true
+		}
+	}
+	return nil, false
+}
+
+// typeSchemaURI returns the schema URI registered for t in c or the compilers it is layered on.
+func (c *Compiler) typeSchemaURI(t reflect.Type) (string, bool) {
+	for ; c != nil; c = c.base {
+		if uri, ok := c.types[t]; ok {
+			return uri, true
+		}
+	}
+	return "", false
+}
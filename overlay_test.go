@@ -0,0 +1,160 @@
+package jsonschema
+
+import (
+	"encoding/base32"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlaySharesBase(t *testing.T) {
+	base := NewCompiler()
+	_, err := base.Compile([]byte(`{"type": "string", "format": "email"}`), "https://example.com/email.json")
+	require.NoError(t, err)
+
+	tenantA := base.Overlay()
+	tenantB := base.Overlay()
+	_, err = tenantB.Compile([]byte(`{"type": "string", "maxLength": 5}`), "https://example.com/email.json")
+	require.NoError(t, err)
+
+	schemaJSON := []byte(`{"properties": {"email": {"$ref": "https://example.com/email.json"}}}`)
+	schemaA, err := tenantA.Compile(schemaJSON)
+	require.NoError(t, err)
+	schemaB, err := tenantB.Compile(schemaJSON)
+	require.NoError(t, err)
+
+	instance := map[string]interface{}{"email": "someone@example.com"}
+	assert.True(t, schemaA.Validate(instance).IsValid(), "tenant A uses the base schema")
+	assert.False(t, schemaB.Validate(instance).IsValid(), "tenant B shadows the base schema")
+
+	shared, err := base.GetSchema("https://example.com/email.json")
+	require.NoError(t, err)
+	assert.True(t, shared.Validate("someone@example.com").IsValid(), "the base is unchanged")
+	_, exists := base.schemas["https://example.com/email.json"]
+	assert.True(t, exists)
+	assert.Len(t, base.schemas, 1)
+}
+
+func TestOverlayFallsBackToBaseRegistrations(t *testing.T) {
+	base := NewCompiler().RegisterLoader("test", func(url string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"type": "integer"}`)), nil
+	})
+	base.RegisterDecoder("base32", base32.StdEncoding.DecodeString)
+	base.RegisterTypeAdapter(reflect.TypeOf(time.Duration(0)), func(v interface{}) (interface{}, error) {
+		return v.(time.Duration).Seconds(), nil
+	})
+	RegisterType[time.Duration](base, "test://schemas/duration.json")
+
+	tenant := base.Overlay()
+	schema, err := tenant.Compile([]byte(`{
+		"properties": {
+			"count": {"$ref": "test://schemas/count.json"},
+			"data": {"contentEncoding": "base32", "contentMediaType": "application/json"},
+			"timeout": {"type": "number", "maximum": 10}
+		}
+	}`))
+	require.NoError(t, err)
+	assert.False(t, schema.Validate(map[string]interface{}{"count": "1"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"data": "not base32"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"timeout": time.Minute}).IsValid())
+	assert.True(t, schema.Validate(map[string]interface{}{"timeout": time.Second}).IsValid())
+
+	result, err := tenant.ValidateValue(time.Second)
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+	assert.NotContains(t, base.schemas, "test://schemas/count.json", "documents loaded by a tenant stay in its overlay")
+}
+
+func TestOverlayCopiesSettings(t *testing.T) {
+	base := NewCompiler().
+		SetSeverity("maxLength", SeverityWarning).
+		SetEnvironment("production").
+		RegisterDialect(Dialect{URI: "https://example.com/base-dialect"}).
+		RegisterVocabulary(Vocabulary{URI: "https://example.com/vocab/base"})
+	tenant := base.Overlay()
+	sibling := base.Overlay()
+
+	tenant.SetSeverity("minLength", SeverityInfo).
+		SetSeverity("maxLength", SeverityError).
+		RegisterDialect(Dialect{URI: "https://example.com/tenant-dialect"}).
+		RegisterVocabulary(Vocabulary{URI: "https://example.com/vocab/tenant"}).
+		RegisterKeyword("x-tenant", nil)
+	tenant.Environment["staging"] = true
+
+	for _, c := range []*Compiler{base, sibling} {
+		assert.Equal(t, map[string]Severity{"maxLength": SeverityWarning}, c.Severities)
+		assert.Equal(t, map[string]bool{"production": true}, c.Environment)
+		assert.Len(t, c.Dialects, 1)
+		assert.Len(t, c.Vocabularies, 1)
+		assert.Empty(t, c.Keywords)
+	}
+	assert.Len(t, tenant.Severities, 2)
+	assert.Len(t, tenant.Dialects, 2)
+
+	schema, err := sibling.Compile([]byte(`{"type": "string", "maxLength": 2}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate("long").IsValid(), "the sibling keeps the severities of the base")
+}
+
+func TestOverlayConcurrentTenants(t *testing.T) {
+	base := NewCompiler()
+	_, err := base.Compile([]byte(`{"type": "string"}`), "https://example.com/name.json")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tenant := base.Overlay()
+			schema, err := tenant.Compile([]byte(`{"$ref": "https://example.com/name.json"}`))
+			assert.NoError(t, err)
+			assert.True(t, schema.Validate("John").IsValid())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCloneCopiesEveryExportedField(t *testing.T) {
+	c := NewCompiler()
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		switch value.Kind() {
+		case reflect.Bool:
+			value.SetBool(true)
+		case reflect.Int, reflect.Int64:
+			value.SetInt(1)
+		case reflect.String:
+			value.SetString("x")
+		case reflect.Slice:
+			value.Set(reflect.MakeSlice(value.Type(), 1, 1))
+		case reflect.Map:
+			value.Set(reflect.MakeMap(value.Type()))
+			value.SetMapIndex(reflect.New(value.Type().Key()).Elem(), reflect.New(value.Type().Elem()).Elem())
+		case reflect.Func:
+			value.Set(reflect.MakeFunc(value.Type(), func([]reflect.Value) []reflect.Value { return nil }))
+		case reflect.Struct:
+			value.Field(0).SetInt(1)
+		case reflect.Interface:
+			continue // Left as set by NewCompiler.
+		}
+		require.False(t, value.IsZero(), field.Name)
+	}
+
+	clone := reflect.ValueOf(c.clone()).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if field := v.Type().Field(i); field.IsExported() {
+			assert.Equal(t, v.Field(i).IsZero(), clone.Field(i).IsZero(), "clone copies %s", field.Name)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package jsonschema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/goccy/go-json"
+)
+
+// JSONParser parses raw JSON documents into instances: map[string]interface{} for objects,
+// []interface{} for arrays, and string, bool, nil and json.Number or other numeric types for
+// values. It lets applications validate raw bytes with the JSON library they already use, such
+// as encoding/json or json-iterator, or with a parser of their own.
+type JSONParser interface {
+	Parse(data []byte) (interface{}, error)
+}
+
+// JSONParserFunc adapts a function to the JSONParser interface.
+type JSONParserFunc func(data []byte) (interface{}, error)
+
+// Parse calls f(data).
+func (f JSONParserFunc) Parse(data []byte) (interface{}, error) {
+	return f(data)
+}
+
+// DefaultJSONParser is the parser of compilers without one set with SetJSONParser. It parses with
+// goccy/go-json and keeps numbers exact, as json.Number.
+var DefaultJSONParser JSONParser = JSONParserFunc(parseJSON)
+
+// parseJSON parses a JSON document with goccy/go-json, keeping numbers exact. Data after the
+// document is an error.
+func parseJSON(data []byte) (interface{}, error) {
+	var instance interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&instance); err != nil {
+		return nil, err
+	}
+	if err := expectEnd(decoder); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// expectEnd returns an error unless decoder has nothing left to read but white space.
+func expectEnd(decoder *json.Decoder) error {
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: data after the document", ErrUnexpectedToken)
+	}
+	return nil
+}
+
+// SetJSONParser sets the parser of the raw JSON instances of ValidateJSON, ValidateReader, Decode,
+// ValidateAs and UnmarshalWithDefaults. With a parser other than DefaultJSONParser, ValidateJSON
+// and ValidateReader parse the whole document before validating it instead of validating its
+// tokens as they are read, and duplicate keys are handled by the parser rather than by the
+// DuplicateKeyPolicy of the compiler.
+func (c *Compiler) SetJSONParser(parser JSONParser) *Compiler {
+	c.Parser = parser
+	return c
+}
+
+// jsonParser returns the parser of the compiler of s, or nil when it uses the default one.
+func (s *Schema) jsonParser() JSONParser {
+	if s.compiler == nil {
+		return nil
+	}
+	return s.compiler.Parser
+}
+
+// parseInstance parses the raw JSON document data with the parser of the compiler of s. The
+// default parser handles duplicate keys with the DuplicateKeyPolicy of the compiler.
+func (s *Schema) parseInstance(data []byte) (interface{}, error) {
+	var instance interface{}
+	var err error
+	switch parser := s.jsonParser(); {
+	case parser != nil:
+		instance, err = parser.Parse(data)
+	case s.duplicateKeyPolicy() != DuplicateKeysLastWins:
+		instance, err = parseJSONKeys(data, s.duplicateKeyPolicy())
+	default:
+		instance, err = DefaultJSONParser.Parse(data)
+	}
+	if err != nil && !errors.Is(err, ErrDuplicateKey) {
+		err = fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+	}
+	return instance, err
+}
+
+// validateParsed validates the JSON document read from r by parsing it whole first.
+func (s *Schema) validateParsed(r io.Reader) (*EvaluationResult, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+	}
+	instance, err := s.parseInstance(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.Validate(instance), nil
+}
@@ -0,0 +1,75 @@
+package jsonschema
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stdParser parses with encoding/json, counting the documents it parsed.
+type stdParser struct {
+	parsed int
+}
+
+func (p *stdParser) Parse(data []byte) (interface{}, error) {
+	p.parsed++
+	var instance interface{}
+	decoder := stdjson.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&instance); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+func TestJSONParser(t *testing.T) {
+	parser := &stdParser{}
+	schema, err := NewCompiler().SetJSONParser(parser).Compile([]byte(`{
+		"type": "object",
+		"properties": {"count": {"type": "integer", "maximum": 9007199254740993}},
+		"required": ["count"]
+	}`))
+	require.NoError(t, err)
+
+	result, err := schema.ValidateJSON([]byte(`{"count": 9007199254740993}`))
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+	result, err = schema.ValidateReader(strings.NewReader(`{"count": 9007199254740994}`))
+	require.NoError(t, err)
+	assert.False(t, result.IsValid(), "numbers are kept exact")
+	assert.Equal(t, 2, parser.parsed)
+
+	_, err = schema.ValidateJSON([]byte(`{"count": `))
+	assert.True(t, errors.Is(err, ErrJSONUnmarshalError))
+
+	type document struct {
+		Count int64 `json:"count"`
+	}
+	value, err := Decode[document](schema, []byte(`{"count": 3}`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), value.Count)
+	assert.Equal(t, 4, parser.parsed)
+
+	var defaulted document
+	assert.Error(t, schema.UnmarshalWithDefaults([]byte(`{}`), &defaulted))
+	assert.Equal(t, 5, parser.parsed)
+}
+
+func TestJSONParserFunc(t *testing.T) {
+	custom := JSONParserFunc(func([]byte) (interface{}, error) {
+		return map[string]interface{}{"name": "fixed"}, nil
+	})
+	schema, err := NewCompiler().SetJSONParser(custom).Compile([]byte(`{"properties": {"name": {"const": "fixed"}}}`))
+	require.NoError(t, err)
+	result, err := schema.ValidateJSON([]byte(`{"name": "other"}`))
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+
+	overlay := schema.compiler.Overlay()
+	assert.NotNil(t, overlay.Parser)
+}
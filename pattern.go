@@ -1,6 +1,19 @@
 package jsonschema
 
-import "regexp"
+import (
+	"regexp/syntax"
+	"sort"
+)
+
+// SetAnchorPatterns sets whether pattern and patternProperties must match whole strings, as if
+// their regular expressions started with ^ and ended with $. The specification matches them
+// anywhere in the string, which surprises schemas written for validators that anchor them, such
+// as XML Schema or OpenAPI generators; enabling it eases their migration. See UnanchoredPatterns
+// to find the patterns whose meaning depends on the setting.
+func (c *Compiler) SetAnchorPatterns(anchor bool) *Compiler {
+	c.AnchorPatterns = anchor
+	return c
+}
 
 // EvaluatePattern checks if the string data matches the regular expression specified in the "pattern" schema attribute.
 // According to the JSON Schema Draft 2020-12:
@@ -14,8 +27,8 @@ import "regexp"
 // Reference: https://json-schema.org/draft/2020-12/json-schema-validation#name-pattern
 func evaluatePattern(schema *Schema, instance string) *EvaluationError {
 	if schema.Pattern != nil {
-		// Compile the regular expression from the pattern.
-		regExp, err := regexp.Compile(*schema.Pattern)
+		// Get the regular expression compiled with the schema.
+		regExp, err := schema.regex(*schema.Pattern)
 		if err != nil {
 			// Handle regular expression compilation errors.
 			return NewEvaluationError("pattern", "invalid_pattern", "Invalid regular expression pattern {pattern}", map[string]interface{}{
@@ -34,3 +47,104 @@ func evaluatePattern(schema *Schema, instance string) *EvaluationError {
 	}
 	return nil
 }
+
+// compileRegex compiles a regular expression of pattern or patternProperties, anchored at both
+// ends when the compiler anchors patterns.
+func (s *Schema) compileRegex(pattern string) (Regexp, error) {
+	engine := s.compiler.regexEngine()
+	if s.compiler != nil && s.compiler.AnchorPatterns {
+		return engine.Compile("^(?:" + pattern + ")$")
+	}
+	return engine.Compile(pattern)
+}
+
+// regex returns the regular expression of the pattern or a patternProperties key of s, as
+// compiled with the schema, or compiles it when s was not compiled by a compiler. It never
+// modifies s, so that concurrent validations can share it.
+func (s *Schema) regex(pattern string) (Regexp, error) {
+	if regex, ok := s.compiledPatterns[pattern]; ok {
+		return regex, nil
+	}
+	return s.compileRegex(pattern)
+}
+
+// UnanchoredPattern describes a regular expression of pattern or patternProperties that is not
+// anchored at both ends.
+type UnanchoredPattern struct {
+	// KeywordLocation is the JSON Pointer of the keyword, relative to the analyzed schema.
+	KeywordLocation string `json:"keywordLocation"`
+
+	// Pattern is the regular expression.
+	Pattern string `json:"pattern"`
+}
+
+// UnanchoredPatterns reports the regular expressions of pattern and patternProperties in s that
+// do not start with ^ and end with $, in every alternative. They match anywhere in the string,
+// so "[0-9]+" accepts "abc1", which is rarely intended and differs between validators that
+// anchor patterns and those that do not. Invalid regular expressions are not reported.
+func (s *Schema) UnanchoredPatterns() []UnanchoredPattern {
+	var findings []UnanchoredPattern
+	walkSchema(s, func(pointer string, schema *Schema) bool {
+		if schema.Pattern != nil && !isAnchoredPattern(*schema.Pattern) {
+			findings = append(findings, UnanchoredPattern{
+				KeywordLocation: "#" + pointer + "/pattern",
+				Pattern:         *schema.Pattern,
+			})
+		}
+		if schema.PatternProperties != nil {
+			patterns := make([]string, 0, len(*schema.PatternProperties))
+			for pattern := range *schema.PatternProperties {
+				patterns = append(patterns, pattern)
+			}
+			sort.Strings(patterns)
+			for _, pattern := range patterns {
+				if !isAnchoredPattern(pattern) {
+					findings = append(findings, UnanchoredPattern{
+						KeywordLocation: "#" + pointer + "/patternProperties/" + escapeJSONPointer(pattern),
+						Pattern:         pattern,
+					})
+				}
+			}
+		}
+		return true
+	})
+	return findings
+}
+
+// isAnchoredPattern reports whether every match of pattern spans the whole string, or whether
+// pattern is not a valid regular expression.
+func isAnchoredPattern(pattern string) bool {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return true
+	}
+	return anchoredAt(re, true) && anchoredAt(re, false)
+}
+
+// anchoredAt reports whether re starts, or ends, with an anchor at the start, or end, of text.
+func anchoredAt(re *syntax.Regexp, start bool) bool {
+	switch re.Op {
+	case syntax.OpBeginText:
+		return start
+	case syntax.OpEndText:
+		return !start
+	case syntax.OpCapture:
+		return anchoredAt(re.Sub[0], start)
+	case syntax.OpConcat:
+		if len(re.Sub) == 0 {
+			return false
+		}
+		if start {
+			return anchoredAt(re.Sub[0], start)
+		}
+		return anchoredAt(re.Sub[len(re.Sub)-1], start)
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !anchoredAt(sub, start) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
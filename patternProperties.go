@@ -2,25 +2,40 @@ package jsonschema
 
 import (
 	"fmt"
-	"regexp"
 	"slices"
+	"sort"
 	"strings"
 )
 
-// Initialize or Load Schema
-func (s *Schema) compilePatterns() {
-	if s.PatternProperties == nil {
-		return // No patterns to compile if the map is nil
+// compilePatterns compiles the regular expressions of pattern and patternProperties once, when
+// the schema is compiled, so that validations only read them and a schema can be validated
+// concurrently. It returns an ErrInvalidPattern error for the first one the regex engine of the
+// compiler rejects.
+func (s *Schema) compilePatterns() error {
+	s.compiledPatterns = nil
+	if s.Pattern == nil && s.PatternProperties == nil {
+		return nil
 	}
 
-	s.compiledPatterns = make(map[string]*regexp.Regexp)
-	// Since s.PatternProperties is a pointer to a SchemaMap, we dereference it here
-	for pattern := range *s.PatternProperties {
-		regex, err := regexp.Compile(pattern)
-		if err == nil {
-			s.compiledPatterns[pattern] = regex
+	compiled := make(map[string]Regexp)
+	if s.Pattern != nil {
+		regex, err := s.compileRegex(*s.Pattern)
+		if err != nil {
+			return fmt.Errorf("%w in pattern %q: %v", ErrInvalidPattern, *s.Pattern, err)
 		}
+		compiled[*s.Pattern] = regex
 	}
+	if s.PatternProperties != nil {
+		for _, pattern := range sortedKeys(*s.PatternProperties) {
+			regex, err := s.compileRegex(pattern)
+			if err != nil {
+				return fmt.Errorf("%w in patternProperties %q: %v", ErrInvalidPattern, pattern, err)
+			}
+			compiled[pattern] = regex
+		}
+	}
+	s.compiledPatterns = compiled
+	return nil
 }
 
 // EvaluatePatternProperties checks if properties in the data object that match regex patterns conform to the schemas specified in the schema's patternProperties attribute.
@@ -38,20 +53,22 @@ func evaluatePatternProperties(schema *Schema, object map[string]interface{}, ev
 
 	// invalid_regex  := []string{}
 	invalid_properties := []string{}
+	failedPatterns := map[string][]string{} // Labels of the patterns each invalid property failed.
 	results := []*EvaluationResult{}
 
+	patterns := make([]string, 0, len(*schema.PatternProperties))
+	for patternKey := range *schema.PatternProperties {
+		patterns = append(patterns, patternKey)
+	}
+	sort.Strings(patterns)
+
 	// Loop over each pattern in the PatternProperties map.
-	for patternKey, patternSchema := range *schema.PatternProperties {
-		// Get from the compiled patterns map, if not found, compile the pattern
-		regex, ok := schema.compiledPatterns[patternKey]
-		if !ok {
-			var err error
-			regex, err = regexp.Compile(patternKey)
-			if err != nil {
-				// invalid_regex = append(invalid_regex, patternKey)
-				continue
-			}
-			schema.compiledPatterns[patternKey] = regex
+	for _, patternKey := range patterns {
+		patternSchema := (*schema.PatternProperties)[patternKey]
+		regex, err := schema.regex(patternKey)
+		if err != nil {
+			// invalid_regex = append(invalid_regex, patternKey)
+			continue
 		}
 
 		// Check each property in the object against the compiled regex.
@@ -60,16 +77,21 @@ func evaluatePatternProperties(schema *Schema, object map[string]interface{}, ev
 				evaluatedProps[propName] = true
 
 				// Evaluate the property value directly using the associated schema or boolean.
+				dynamicScope.pushInstance(propName)
 				result, _, _ := patternSchema.evaluate(propValue, dynamicScope)
+				dynamicScope.popInstance()
 				if result != nil {
-					result.SetEvaluationPath(fmt.Sprintf("/patternProperties/%s", propName)).
-						SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/patternProperties/%s", propName))).
+					result.SetEvaluationPath(fmt.Sprintf("/patternProperties/%s", escapeJSONPointer(patternKey))).
+						SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/patternProperties/%s", escapeJSONPointer(patternKey)))).
 						SetInstanceLocation(fmt.Sprintf("/%s", propName))
 
 					results = append(results, result)
 
-					if !result.IsValid() && !slices.Contains(invalid_properties, propName) {
-						invalid_properties = append(invalid_properties, propName)
+					if !result.IsValid() {
+						if !slices.Contains(invalid_properties, propName) {
+							invalid_properties = append(invalid_properties, propName)
+						}
+						failedPatterns[propName] = append(failedPatterns[propName], fmt.Sprintf("'%s'", patternLabel(regex, patternKey)))
 					}
 				}
 			}
@@ -77,13 +99,15 @@ func evaluatePatternProperties(schema *Schema, object map[string]interface{}, ev
 	}
 
 	if len(invalid_properties) == 1 {
-		return results, NewEvaluationError("properties", "pattern_property_mismatch", "Property {property} does not match the pattern schema", map[string]interface{}{
+		return results, NewEvaluationError("properties", "pattern_property_mismatch", "Property {property} does not match the schema of pattern {pattern}", map[string]interface{}{
 			"property": fmt.Sprintf("'%s'", invalid_properties[0]),
+			"pattern":  strings.Join(failedPatterns[invalid_properties[0]], ", "),
 		})
 	} else if len(invalid_properties) > 1 {
+		sort.Strings(invalid_properties)
 		quotedProperties := make([]string, len(invalid_properties))
 		for i, prop := range invalid_properties {
-			quotedProperties[i] = fmt.Sprintf("'%s'", prop)
+			quotedProperties[i] = fmt.Sprintf("'%s' (%s)", prop, strings.Join(failedPatterns[prop], ", "))
 		}
 		return results, NewEvaluationError("properties", "pattern_properties_mismatch", "Properties {properties} do not match their pattern schemas", map[string]interface{}{
 			"properties": strings.Join(quotedProperties, ", "),
@@ -92,3 +116,14 @@ func evaluatePatternProperties(schema *Schema, object map[string]interface{}, ev
 
 	return results, nil
 }
+
+// patternLabel names a pattern in error messages: the name of its first named capture group,
+// such as "locale" for (?P<locale>^[a-z]{2}$), or else the pattern itself.
+func patternLabel(regex Regexp, pattern string) string {
+	for _, name := range regex.SubexpNames() {
+		if name != "" {
+			return name
+		}
+	}
+	return pattern
+}
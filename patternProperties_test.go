@@ -0,0 +1,27 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternPropertiesErrorNamesPattern(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"patternProperties": {
+			"^x-": {"type": "string"},
+			"(?P<locale>^[a-z]{2}-[A-Z]{2}$)": {"type": "string", "minLength": 1}
+		}
+	}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{"x-owner": 1})
+	require.Contains(t, result.Errors, "properties")
+	assert.Equal(t, "Property 'x-owner' does not match the schema of pattern '^x-'", result.Errors["properties"].Error())
+	require.Len(t, result.Details, 1)
+	assert.Equal(t, "/patternProperties/^x-", result.Details[0].EvaluationPath)
+
+	result = schema.Validate(map[string]interface{}{"x-owner": 1, "de-DE": ""})
+	assert.Equal(t, "Properties 'de-DE' ('locale'), 'x-owner' ('^x-') do not match their pattern schemas", result.Errors["properties"].Error())
+}
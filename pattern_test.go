@@ -0,0 +1,76 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnchorPatterns(t *testing.T) {
+	source := []byte(`{
+		"properties": {"code": {"type": "string", "pattern": "[0-9]+|x"}},
+		"patternProperties": {"^x-[a-z]+$": {"type": "string"}, "[A-Z]{2}": {"type": "integer"}},
+		"additionalProperties": false
+	}`)
+
+	schema, err := NewCompiler().Compile(source)
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(map[string]interface{}{"code": "abc1"}).IsValid(), "patterns match anywhere by default")
+	assert.True(t, schema.Validate(map[string]interface{}{"abCD": 1}).IsValid())
+
+	anchored, err := NewCompiler().SetAnchorPatterns(true).Compile(source)
+	require.NoError(t, err)
+	assert.True(t, anchored.Validate(map[string]interface{}{"code": "123"}).IsValid())
+	assert.True(t, anchored.Validate(map[string]interface{}{"code": "x"}).IsValid(), "every alternative is anchored")
+	assert.False(t, anchored.Validate(map[string]interface{}{"code": "abc1"}).IsValid())
+	assert.False(t, anchored.Validate(map[string]interface{}{"code": "1x"}).IsValid())
+	assert.True(t, anchored.Validate(map[string]interface{}{"CD": 1, "x-owner": "team"}).IsValid())
+	assert.False(t, anchored.Validate(map[string]interface{}{"abCD": 1}).IsValid())
+	assert.False(t, anchored.Validate(map[string]interface{}{"x-owner": 1}).IsValid(), "patterns anchored at both ends are unaffected")
+	assert.False(t, anchored.Validate(map[string]interface{}{"x-": "team"}).IsValid())
+
+	overlay, err := NewCompiler().SetAnchorPatterns(true).Overlay().Compile(source)
+	require.NoError(t, err)
+	assert.False(t, overlay.Validate(map[string]interface{}{"code": "abc1"}).IsValid())
+}
+
+func TestPatternCompiledOnce(t *testing.T) {
+	compiled := 0
+	engine := RegexEngineFunc(func(pattern string) (Regexp, error) {
+		compiled++
+		return DefaultRegexEngine.Compile(pattern)
+	})
+	schema, err := NewCompiler().SetRegexEngine(engine).Compile([]byte(`{
+		"properties": {"code": {"type": "string", "pattern": "^[0-9]+$"}}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, 1, compiled)
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, schema.Validate(map[string]interface{}{"code": "123"}).IsValid())
+		assert.False(t, schema.Validate(map[string]interface{}{"code": "abc"}).IsValid())
+	}
+	assert.Equal(t, 1, compiled, "validations reuse the pattern compiled with the schema")
+}
+
+func TestUnanchoredPatterns(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"properties": {
+			"code": {"pattern": "[0-9]+"},
+			"name": {"pattern": "^[A-Z][a-z]*$"},
+			"tag": {"pattern": "^a$|b$"},
+			"id": {"pattern": "^(?:[a-f0-9]{8}|[A-F0-9]{8})$"},
+			"prefix": {"pattern": "^x-"}
+		},
+		"patternProperties": {"^x-[a-z]+$": true, "a/b": true}
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []UnanchoredPattern{
+		{KeywordLocation: "#/patternProperties/a~1b", Pattern: "a/b"},
+		{KeywordLocation: "#/properties/code/pattern", Pattern: "[0-9]+"},
+		{KeywordLocation: "#/properties/prefix/pattern", Pattern: "^x-"},
+		{KeywordLocation: "#/properties/tag/pattern", Pattern: "^a$|b$"},
+	}, schema.UnanchoredPatterns())
+}
@@ -28,7 +28,9 @@ func evaluatePrefixItems(schema *Schema, array []interface{}, evaluatedProps map
 			break // Stop validation if there are more schemas than array items.
 		}
 
+		dynamicScope.pushIndex(i)
 		result, _, _ := itemSchema.evaluate(array[i], dynamicScope)
+		dynamicScope.popInstance()
 		if result != nil {
 			results = append(results, result.SetEvaluationPath(fmt.Sprintf("/prefixItems/%d", i)).
 				SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/prefixItems/%d", i))).
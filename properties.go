@@ -28,7 +28,9 @@ func evaluateProperties(schema *Schema, object map[string]interface{}, evaluated
 		propValue, exists := object[propName]
 
 		if exists {
+			dynamicScope.pushInstance(propName)
 			result, _, _ := propSchema.evaluate(propValue, dynamicScope)
+			dynamicScope.popInstance()
 			if result != nil {
 				result.SetEvaluationPath(fmt.Sprintf("/properties/%s", propName)).
 					SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/properties/%s", propName))).
@@ -42,7 +44,9 @@ func evaluateProperties(schema *Schema, object map[string]interface{}, evaluated
 			}
 		} else if isRequired(schema, propName) && !defaultIsSpecified(propSchema) {
 			// Handle properties that are expected but not provided
+			dynamicScope.pushInstance(propName)
 			result, _, _ := propSchema.evaluate(nil, dynamicScope)
+			dynamicScope.popInstance()
 
 			if result != nil {
 				result.SetEvaluationPath(fmt.Sprintf("/properties/%s", propName)).
@@ -58,6 +62,14 @@ func evaluateProperties(schema *Schema, object map[string]interface{}, evaluated
 		}
 	}
 
+	forbidden := len(invalid_properties) > 0
+	for _, propName := range invalid_properties {
+		forbidden = forbidden && (*schema.Properties)[propName].IsFalse()
+	}
+	if forbidden {
+		return results, notAllowedPropertiesError("properties", "property_not_allowed", "Property {property} is not allowed", "properties_not_allowed", "Properties {properties} are not allowed", invalid_properties)
+	}
+
 	if len(invalid_properties) == 1 {
 		return results, NewEvaluationError("properties", "property_mismatch", "Property {property} does not match the schema", map[string]interface{}{
 			"property": fmt.Sprintf("'%s'", invalid_properties[0]),
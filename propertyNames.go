@@ -2,9 +2,20 @@ package jsonschema
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"unicode"
 )
 
+// SetSuggestPropertyNames sets whether property names rejected by propertyNames come with a
+// suggested name: the first of their snake_case, kebab-case, camelCase, PascalCase, lowercase or
+// SCREAMING_SNAKE_CASE forms that conforms to the schema, reported at the location of the
+// property with the property_name_suggestion error.
+func (c *Compiler) SetSuggestPropertyNames(suggest bool) *Compiler {
+	c.SuggestPropertyNames = suggest
+	return c
+}
+
 // EvaluatePropertyNames checks if every property name in the object conforms to the schema specified by the propertyNames attribute.
 // According to the JSON Schema Draft 2020-12:
 //   - The "propertyNames" keyword must be a valid JSON Schema.
@@ -25,20 +36,36 @@ func evaluatePropertyNames(schema *Schema, object map[string]interface{}, evalua
 	invalid_properties := []string{}
 	results := []*EvaluationResult{}
 
-	if schema.PropertyNames != nil {
-		for propName := range object {
-			result, _, _ := schema.PropertyNames.evaluate(propName, dynamicScope)
+	// Property names are evaluated in order, so that errors list them in a stable order.
+	propNames := make([]string, 0, len(object))
+	for propName := range object {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
 
-			if result != nil {
-				result.SetEvaluationPath(fmt.Sprintf("/propertyNames/%s", propName)).
-					SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/propertyNames/%s", propName))).
-					SetInstanceLocation(fmt.Sprintf("/%s", propName))
-			}
+	for _, propName := range propNames {
+		dynamicScope.pushInstance(propName)
+		result, _, _ := schema.PropertyNames.evaluate(propName, dynamicScope)
+		dynamicScope.popInstance()
 
-			results = append(results, result)
+		if result != nil {
+			result.SetEvaluationPath(fmt.Sprintf("/propertyNames/%s", escapeJSONPointer(propName))).
+				SetSchemaLocation(schema.GetSchemaLocation("/propertyNames")).
+				SetInstanceLocation(fmt.Sprintf("/%s", escapeJSONPointer(propName)))
+		}
+
+		results = append(results, result)
 
-			if !result.IsValid() {
-				invalid_properties = append(invalid_properties, propName)
+		if !result.IsValid() {
+			invalid_properties = append(invalid_properties, propName)
+			if schema.compiler != nil && schema.compiler.SuggestPropertyNames {
+				if suggestion, ok := suggestPropertyName(schema.PropertyNames, propName, object); ok {
+					result.AddError(NewEvaluationError("propertyNames", "property_name_suggestion", "Property name {property} does not match the schema, did you mean {suggestion}?", map[string]interface{}{
+						"property":   fmt.Sprintf("'%s'", propName),
+						"suggestion": fmt.Sprintf("'%s'", suggestion),
+						"name":       suggestion,
+					}))
+				}
 			}
 		}
 	}
@@ -59,3 +86,117 @@ func evaluatePropertyNames(schema *Schema, object map[string]interface{}, evalua
 
 	return results, nil
 }
+
+// suggestPropertyName returns the first normalization of the invalid property name, such as its
+// snake_case or camelCase form, that conforms to the propertyNames schema and is not a property
+// of the object already.
+func suggestPropertyName(propertyNames *Schema, propName string, object map[string]interface{}) (string, bool) {
+	for _, candidate := range propertyNameCandidates(propName) {
+		if candidate == propName || candidate == "" {
+			continue
+		}
+		if _, exists := object[candidate]; exists {
+			continue
+		}
+		if result, _, _ := propertyNames.evaluate(candidate, NewDynamicScope()); result != nil && result.IsValid() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// propertyNameCandidates returns normalizations of a property name, in order of preference: the
+// name without surrounding white space, then its words in snake_case, kebab-case, camelCase,
+// PascalCase, lowercase and SCREAMING_SNAKE_CASE.
+func propertyNameCandidates(propName string) []string {
+	words := splitWords(propName)
+	lower := make([]string, len(words))
+	title := make([]string, len(words))
+	for i, word := range words {
+		lower[i] = strings.ToLower(word)
+		runes := []rune(lower[i])
+		title[i] = string(unicode.ToUpper(runes[0])) + string(runes[1:])
+	}
+	camel := ""
+	if len(lower) > 0 {
+		camel = lower[0] + strings.Join(title[1:], "")
+	}
+	return []string{
+		strings.TrimSpace(propName),
+		strings.Join(lower, "_"),
+		strings.Join(lower, "-"),
+		camel,
+		strings.Join(title, ""),
+		strings.Join(lower, ""),
+		strings.ToUpper(strings.Join(lower, "_")),
+	}
+}
+
+// splitWords splits a property name into words at characters other than letters and digits, and
+// at changes from lowercase to uppercase, such as in "userID" or "first-name".
+func splitWords(propName string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(propName)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+// PropertyNameError is a property whose name does not conform to a propertyNames schema.
+type PropertyNameError struct {
+	InstanceLocation string                      `json:"instanceLocation"`     // JSON Pointer of the property in the instance, whose key LocateKey finds in raw JSON.
+	Property         string                      `json:"property"`             // Name of the property.
+	Errors           map[string]*EvaluationError `json:"errors"`               // Errors of the propertyNames schema for the name.
+	Suggestion       string                      `json:"suggestion,omitempty"` // Conforming name, see Compiler.SetSuggestPropertyNames.
+}
+
+// PropertyNameErrors returns the properties of the instance whose names were rejected by
+// propertyNames, with their absolute instance locations.
+func (e *EvaluationResult) PropertyNameErrors() []PropertyNameError {
+	var errs []PropertyNameError
+	var visit func(result *EvaluationResult, location string)
+	visit = func(result *EvaluationResult, location string) {
+		location += result.InstanceLocation
+		for _, detail := range result.Details {
+			if !detail.IsValid() && strings.HasPrefix(detail.EvaluationPath, "/propertyNames/") {
+				nameError := PropertyNameError{
+					InstanceLocation: location + detail.InstanceLocation,
+					Property:         unescapeJSONPointer(strings.TrimPrefix(detail.InstanceLocation, "/")),
+					Errors:           make(map[string]*EvaluationError, len(detail.Errors)),
+				}
+				for keyword, err := range detail.Errors {
+					if err.Code == "property_name_suggestion" {
+						nameError.Suggestion, _ = err.Params["name"].(string)
+						continue
+					}
+					nameError.Errors[keyword] = err
+				}
+				errs = append(errs, nameError)
+				continue
+			}
+			visit(detail, location)
+		}
+	}
+	visit(e, "")
+	return errs
+}
@@ -0,0 +1,68 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyNameErrors(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"properties": {
+			"settings": {"propertyNames": {"pattern": "^[a-z_]+$", "maxLength": 12}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{
+		"settings": map[string]interface{}{"ok": 1, "Dark/Mode": true, "fontSize": 12},
+	})
+	require.False(t, result.IsValid())
+
+	settings := result.Details[0]
+	assert.Equal(t, "Property names 'Dark/Mode', 'fontSize' do not match the schema", settings.Errors["propertyNames"].Error())
+	require.Len(t, settings.Details, 3)
+	assert.Equal(t, "/Dark~1Mode", settings.Details[0].InstanceLocation)
+	assert.Equal(t, "#/propertyNames", settings.Details[0].SchemaLocation)
+
+	errs := result.PropertyNameErrors()
+	require.Len(t, errs, 2)
+	assert.Equal(t, "/settings/Dark~1Mode", errs[0].InstanceLocation)
+	assert.Equal(t, "Dark/Mode", errs[0].Property)
+	assert.Contains(t, errs[0].Errors, "pattern")
+	assert.Empty(t, errs[0].Suggestion)
+	assert.Equal(t, "/settings/fontSize", errs[1].InstanceLocation)
+}
+
+func TestSuggestPropertyNames(t *testing.T) {
+	compiler := NewCompiler().SetSuggestPropertyNames(true)
+	schema, err := compiler.Compile([]byte(`{"propertyNames": {"pattern": "^[a-z]+(_[a-z]+)*$"}}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{"firstName": "Ada", "Last-Name": "Lovelace", "userID": 1, "user_id": 2, "?": 0})
+	errs := result.PropertyNameErrors()
+	require.Len(t, errs, 4)
+	assert.Equal(t, "?", errs[0].Property)
+	assert.Empty(t, errs[0].Suggestion)
+	assert.Equal(t, "last_name", errs[1].Suggestion)
+	assert.Equal(t, "first_name", errs[2].Suggestion)
+	assert.Equal(t, "userid", errs[3].Suggestion, "user_id is taken")
+
+	detail := result.Details[1]
+	assert.Equal(t, "Property name 'Last-Name' does not match the schema, did you mean 'last_name'?", detail.Errors["propertyNames"].Error())
+
+	camel, err := compiler.Compile([]byte(`{"propertyNames": {"pattern": "^[a-z]+([A-Z][a-z]+)*$"}}`))
+	require.NoError(t, err)
+	errs = camel.Validate(map[string]interface{}{"first_name": 1, " last name ": 2}).PropertyNameErrors()
+	require.Len(t, errs, 2)
+	assert.Equal(t, "lastName", errs[0].Suggestion)
+	assert.Equal(t, "firstName", errs[1].Suggestion)
+}
+
+func TestSplitWords(t *testing.T) {
+	assert.Equal(t, []string{"user", "ID"}, splitWords("userID"))
+	assert.Equal(t, []string{"HTTP", "Server2"}, splitWords("HTTPServer2"))
+	assert.Equal(t, []string{"first", "name"}, splitWords(" first-name "))
+	assert.Equal(t, []string{"Ärger", "Frei"}, splitWords("ÄrgerFrei"))
+}
@@ -0,0 +1,113 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+// UnusedDefs returns the JSON Pointers of the $defs entries of s that are never referenced, in a
+// deterministic order. A definition is used when a $ref or $dynamicRef reachable from the root
+// of s points to it or into it; definitions that declare a $dynamicAnchor are considered used
+// whenever the schema contains a $dynamicRef. References from other documents are not known, so
+// definitions meant to be referenced from outside are reported as unused.
+//
+// When a definition is unused, its nested definitions are not reported separately.
+func (s *Schema) UnusedDefs() []string {
+	used := reachableSchemas(s)
+
+	var unused []string
+	var collect func(pointer string, schema *Schema)
+	collect = func(pointer string, schema *Schema) {
+		for _, child := range childSchemas(schema) {
+			if strings.HasPrefix(child.pointer, "/$defs/") && !subtreeUsed(child.schema, used) {
+				unused = append(unused, pointer+child.pointer)
+				continue
+			}
+			collect(pointer+child.pointer, child.schema)
+		}
+	}
+	collect("", s)
+	return unused
+}
+
+// subtreeUsed reports whether schema or any schema nested in it is used.
+func subtreeUsed(schema *Schema, used map[*Schema]bool) bool {
+	inUse := false
+	walkSchema(schema, func(_ string, nested *Schema) bool {
+		inUse = inUse || used[nested]
+		return !inUse
+	})
+	return inUse
+}
+
+// PruneUnusedDefs returns a copy of s without the definitions reported by UnusedDefs, together
+// with the JSON Pointers of the removed definitions, to keep generated bundles small. Like
+// Normalize, the copy is compiled with the compiler of s but is not registered with it.
+func (s *Schema) PruneUnusedDefs() (*Schema, []string, error) {
+	unused := s.UnusedDefs()
+	document, err := genericSchema(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Remove the deepest definitions first, so that pointers into a $defs stay valid until then.
+	for i := len(unused) - 1; i >= 0; i-- {
+		pointer := unused[i]
+		cut := strings.LastIndex(pointer, "/$defs/")
+		parent, ok := genericNode(document, pointer[:cut]).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		defs, ok := parent["$defs"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		delete(defs, unescapeJSONPointer(pointer[cut+len("/$defs/"):]))
+		if len(defs) == 0 {
+			delete(parent, "$defs")
+		}
+	}
+
+	pruned, err := s.compileDocument(document)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pruned, unused, nil
+}
+
+// reachableSchemas returns the schemas of the document of s that are evaluated when validating
+// against s: every subschema except $defs entries, and the targets of references, transitively.
+func reachableSchemas(s *Schema) map[*Schema]bool {
+	inDocument := map[*Schema]bool{}
+	dynamic := false
+	walkSchema(s, func(_ string, schema *Schema) bool {
+		inDocument[schema] = true
+		dynamic = dynamic || schema.DynamicRef != ""
+		return true
+	})
+
+	reached := map[*Schema]bool{}
+	var visit func(schema *Schema)
+	visit = func(schema *Schema) {
+		if schema == nil || reached[schema] || !inDocument[schema] {
+			return
+		}
+		reached[schema] = true
+		for _, child := range childSchemas(schema) {
+			if !strings.HasPrefix(child.pointer, "/$defs/") {
+				visit(child.schema)
+			}
+		}
+		visit(schema.ResolvedRef)
+		visit(schema.ResolvedDynamicRef)
+	}
+	visit(s)
+
+	if dynamic {
+		for schema := range inDocument {
+			if schema.DynamicAnchor != "" {
+				visit(schema)
+			}
+		}
+	}
+	return reached
+}
@@ -0,0 +1,64 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnusedDefs(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected []string
+	}{
+		{
+			name:   "all used",
+			schema: `{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"type": "string"}}, "properties": {"x": {"$ref": "#/$defs/a"}}}`,
+		},
+		{
+			name:     "unused definitions",
+			schema:   `{"$defs": {"a": {"type": "string"}, "b": {"$ref": "#/$defs/c"}, "c": {}}, "$ref": "#/$defs/a"}`,
+			expected: []string{"/$defs/b", "/$defs/c"},
+		},
+		{
+			name:     "nested definitions",
+			schema:   `{"$defs": {"outer": {"$defs": {"inner": {}, "spare": {}}, "$ref": "#/$defs/outer/$defs/inner"}, "gone": {"$defs": {"deep": {}}}}, "$ref": "#/$defs/outer"}`,
+			expected: []string{"/$defs/gone", "/$defs/outer/$defs/spare"},
+		},
+		{
+			name:   "reference into a definition",
+			schema: `{"$defs": {"shapes": {"properties": {"circle": {"type": "object"}}}}, "items": {"$ref": "#/$defs/shapes/properties/circle"}}`,
+		},
+		{
+			name:     "anchors and self references",
+			schema:   `{"$defs": {"node": {"$anchor": "node", "properties": {"next": {"$ref": "#node"}}}, "loop": {"$ref": "#/$defs/loop"}}, "$ref": "#node"}`,
+			expected: []string{"/$defs/loop"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewCompiler().Compile([]byte(tt.schema))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, schema.UnusedDefs())
+		})
+	}
+}
+
+func TestPruneUnusedDefs(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"$defs": {"name": {"type": "string"}, "legacy": {"type": "integer"}, "old": {"$defs": {"older": {}}}},
+		"properties": {"name": {"$ref": "#/$defs/name"}}
+	}`))
+	require.NoError(t, err)
+
+	pruned, removed, err := schema.PruneUnusedDefs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/$defs/legacy", "/$defs/old"}, removed)
+	assert.Equal(t, []string{"name"}, keysOf(pruned.Defs))
+	assert.False(t, pruned.Validate(map[string]interface{}{"name": 1}).IsValid())
+	assert.Len(t, schema.Defs, 3)
+}
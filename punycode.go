@@ -0,0 +1,178 @@
+package jsonschema
+
+import (
+	"math"
+	"strings"
+)
+
+// Parameters of the Punycode encoding of IDNA, see RFC 3492, section 5.
+const (
+	punycodeBase        = 36
+	punycodeTMin        = 1
+	punycodeTMax        = 26
+	punycodeSkew        = 38
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+)
+
+// punycodeEncode encodes a label to Punycode, without the xn-- prefix.
+//
+// See https://datatracker.ietf.org/doc/html/rfc3492#section-6.3, for details.
+func punycodeEncode(label string) (string, error) {
+	input := []rune(label)
+	var output strings.Builder
+	for _, r := range input {
+		if r < punycodeInitialN {
+			output.WriteRune(r)
+		}
+	}
+	basic := output.Len()
+	handled := basic
+	if basic > 0 {
+		output.WriteByte('-')
+	}
+
+	n, delta, bias := rune(punycodeInitialN), 0, punycodeInitialBias
+	for handled < len(input) {
+		m := rune(math.MaxInt32)
+		for _, r := range input {
+			if r >= n && r < m {
+				m = r
+			}
+		}
+		if int(m-n) > (math.MaxInt32-delta)/(handled+1) {
+			return "", ErrInvalidPunycode
+		}
+		delta += int(m-n) * (handled + 1)
+		n = m
+
+		for _, r := range input {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := punycodeBase; ; k += punycodeBase {
+				t := punycodeThreshold(k, bias)
+				if q < t {
+					break
+				}
+				output.WriteByte(punycodeDigit(t + (q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+			output.WriteByte(punycodeDigit(q))
+			bias = punycodeAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+	return output.String(), nil
+}
+
+// punycodeDecode decodes a label from Punycode, without the xn-- prefix.
+//
+// See https://datatracker.ietf.org/doc/html/rfc3492#section-6.2, for details.
+func punycodeDecode(encoded string) (string, error) {
+	var output []rune
+	pos := 0
+	if delimiter := strings.LastIndexByte(encoded, '-'); delimiter >= 0 {
+		for _, r := range encoded[:delimiter] {
+			if r >= punycodeInitialN {
+				return "", ErrInvalidPunycode
+			}
+			output = append(output, r)
+		}
+		pos = delimiter + 1
+	}
+
+	n, i, bias := rune(punycodeInitialN), 0, punycodeInitialBias
+	for pos < len(encoded) {
+		oldi, w := i, 1
+		for k := punycodeBase; ; k += punycodeBase {
+			if pos == len(encoded) {
+				return "", ErrInvalidPunycode
+			}
+			digit, ok := punycodeDigitValue(encoded[pos])
+			pos++
+			if !ok || digit > (math.MaxInt32-i)/w {
+				return "", ErrInvalidPunycode
+			}
+			i += digit * w
+			t := punycodeThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			if w > math.MaxInt32/(punycodeBase-t) {
+				return "", ErrInvalidPunycode
+			}
+			w *= punycodeBase - t
+		}
+
+		length := len(output) + 1
+		bias = punycodeAdapt(i-oldi, length, oldi == 0)
+		if i/length > math.MaxInt32-int(n) {
+			return "", ErrInvalidPunycode
+		}
+		n += rune(i / length)
+		i %= length
+		if n < punycodeInitialN || n > 0x10FFFF || (n >= 0xD800 && n <= 0xDFFF) {
+			return "", ErrInvalidPunycode
+		}
+		output = append(output[:i], append([]rune{n}, output[i:]...)...)
+		i++
+	}
+	return string(output), nil
+}
+
+// punycodeThreshold returns the threshold of the digit at position k.
+func punycodeThreshold(k, bias int) int {
+	switch {
+	case k <= bias:
+		return punycodeTMin
+	case k >= bias+punycodeTMax:
+		return punycodeTMax
+	}
+	return k - bias
+}
+
+// punycodeAdapt returns the bias after a code point is encoded, see RFC 3492, section 6.1.
+func punycodeAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punycodeDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+	k := 0
+	for delta > (punycodeBase-punycodeTMin)*punycodeTMax/2 {
+		delta /= punycodeBase - punycodeTMin
+		k += punycodeBase
+	}
+	return k + (punycodeBase-punycodeTMin+1)*delta/(delta+punycodeSkew)
+}
+
+// punycodeDigit returns the character of a digit: a to z for 0 to 25, and 0 to 9 for 26 to 35.
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+// punycodeDigitValue returns the digit of a character, in either case.
+func punycodeDigitValue(c byte) (int, bool) {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A'), true
+	case c >= '0' && c <= '9':
+		return int(c-'0') + 26, true
+	}
+	return 0, false
+}
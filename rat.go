@@ -1,6 +1,7 @@
 package jsonschema
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"strings"
@@ -13,10 +14,13 @@ type Rat struct {
 	*big.Rat
 }
 
-// UnmarshalJSON implements the json.Unmarshaler interface for Rat.
+// UnmarshalJSON implements the json.Unmarshaler interface for Rat. Numbers are parsed from their
+// literal, so that bounds such as 9007199254740993 are kept exactly.
 func (r *Rat) UnmarshalJSON(data []byte) error {
 	var tmp interface{}
-	if err := json.Unmarshal(data, &tmp); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&tmp); err != nil {
 		return err
 	}
 
@@ -46,6 +50,8 @@ func convertToBigRat(data interface{}) (*big.Rat, error) {
 	switch v := data.(type) {
 	case float64, float32, int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
 		str = fmt.Sprint(v)
+	case json.Number:
+		str = string(v)
 	case string:
 		str = v
 	default:
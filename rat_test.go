@@ -0,0 +1,51 @@
+package jsonschema
+
+import (
+	"math"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExactNumbers(t *testing.T) {
+	compiler := NewCompiler()
+
+	maximum, err := compiler.Compile([]byte(`{"maximum": 9007199254740993}`))
+	require.NoError(t, err)
+	assert.True(t, maximum.Validate(json.Number("9007199254740993")).IsValid())
+	assert.False(t, maximum.Validate(json.Number("9007199254740994")).IsValid())
+	assert.True(t, maximum.Validate(int64(9007199254740993)).IsValid())
+	assert.False(t, maximum.Validate(uint64(math.MaxUint64)).IsValid())
+
+	constant, err := compiler.Compile([]byte(`{"const": 9007199254740993}`))
+	require.NoError(t, err)
+	assert.True(t, constant.Validate(int64(9007199254740993)).IsValid())
+	assert.False(t, constant.Validate(int64(9007199254740992)).IsValid())
+	assert.False(t, constant.Validate(float64(9007199254740992)).IsValid())
+
+	enum, err := compiler.Compile([]byte(`{"enum": [1, "a", [2, {"b": 3}]]}`))
+	require.NoError(t, err)
+	assert.True(t, enum.Validate(int(1)).IsValid())
+	assert.True(t, enum.Validate(json.Number("1.0")).IsValid())
+	assert.True(t, enum.Validate([]interface{}{uint8(2), map[string]interface{}{"b": int64(3)}}).IsValid())
+	assert.False(t, enum.Validate(true).IsValid())
+}
+
+func TestExactIntegerType(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"type": "integer"}`))
+	require.NoError(t, err)
+	assert.True(t, schema.Validate(json.Number("9007199254740993")).IsValid())
+	assert.True(t, schema.Validate(json.Number("1.0e3")).IsValid())
+	assert.False(t, schema.Validate(json.Number("9007199254740993.5")).IsValid())
+	assert.True(t, schema.Validate(uint64(math.MaxUint64)).IsValid())
+}
+
+func TestExactUniqueItems(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"uniqueItems": true}`))
+	require.NoError(t, err)
+	assert.False(t, schema.Validate([]interface{}{json.Number("1"), 1.0}).IsValid())
+	assert.False(t, schema.Validate([]interface{}{int64(2), json.Number("2.0")}).IsValid())
+	assert.True(t, schema.Validate([]interface{}{json.Number("9007199254740993"), json.Number("9007199254740992")}).IsValid())
+}
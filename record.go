@@ -0,0 +1,234 @@
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// Recording is a self-contained capture of a validation: the schema, every other schema document
+// known to its compiler (including remote references that were fetched), the compiler settings
+// that affect evaluation, the instance and the result. It is meant to be written to a file where
+// a discrepancy is observed, such as in production, and replayed later without network access.
+//
+// Custom formats, keywords, decoders, media types, loaders and regex engines are code and cannot
+// be recorded; a replay uses the ones registered in the replaying process. RegexEngine names the
+// recorded engine, which must be set on the compiler of the replay.
+type Recording struct {
+	RecordedAt       time.Time                  `json:"recordedAt"`
+	URI              string                     `json:"uri,omitempty"`              // URI of the root schema, if it has one.
+	Schema           json.RawMessage            `json:"schema"`                     // Root schema document.
+	Resources        map[string]json.RawMessage `json:"resources,omitempty"`        // Other schema documents, by URI.
+	DefaultBaseURI   string                     `json:"defaultBaseURI,omitempty"`   // Compiler.DefaultBaseURI at recording time.
+	AssertFormat     bool                       `json:"assertFormat,omitempty"`     // Compiler.AssertFormat or Schema.SetAssertFormat(true) at recording time.
+	Timezones        TimezonePolicy             `json:"timezones,omitempty"`        // Compiler.Timezones at recording time.
+	EmailMode        EmailMode                  `json:"emailMode,omitempty"`        // Compiler.EmailMode or Schema.SetEmailMode at recording time.
+	UnicodeHostnames bool                       `json:"unicodeHostnames,omitempty"` // Compiler.UnicodeHostnames at recording time.
+	URISchemes       []string                   `json:"uriSchemes,omitempty"`       // Compiler.URISchemes at recording time.
+	StringLength     StringLength               `json:"stringLength,omitempty"`     // Compiler.StringLength at recording time.
+	AnchorPatterns   bool                       `json:"anchorPatterns,omitempty"`   // Compiler.AnchorPatterns at recording time.
+	DefaultDraft     Draft                      `json:"defaultDraft,omitempty"`     // Compiler.DefaultDraft at recording time.
+	Environment      []string                   `json:"environment,omitempty"`      // Flags of Compiler.Environment at recording time, sorted.
+	DuplicateKeys    DuplicateKeyPolicy         `json:"duplicateKeys,omitempty"`    // Compiler.DuplicateKeys at recording time.
+	RegexEngine      string                     `json:"regexEngine,omitempty"`      // Go type of Compiler.RegexEngine, when not the default.
+	Instance         interface{}                `json:"instance"`
+	Result           *List                      `json:"result"` // Hierarchical output of the recorded validation.
+}
+
+// Record validates instance like Validate and captures the validation into a Recording. The
+// documents of the schemas are marshaled once per compiler and reused by later recordings.
+func (s *Schema) Record(instance interface{}) (*EvaluationResult, *Recording, error) {
+	result := s.Validate(instance)
+
+	schemaJSON, err := s.compiler.snapshot(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recording := &Recording{
+		RecordedAt: s.compiler.Now().UTC(),
+		URI:        s.uri,
+		Schema:     schemaJSON,
+		Instance:   instance,
+		Result:     result.ToList(),
+	}
+
+	if compiler := s.compiler; compiler != nil {
+		recording.DefaultBaseURI = compiler.DefaultBaseURI
+		recording.AssertFormat = compiler.AssertFormat || (s.assertFormat != nil && *s.assertFormat)
+		recording.Timezones = compiler.Timezones
+		recording.EmailMode = compiler.EmailMode
+		if s.emailMode != nil {
+			recording.EmailMode = *s.emailMode
+		}
+		recording.UnicodeHostnames = compiler.UnicodeHostnames
+		recording.URISchemes = compiler.URISchemes
+		recording.StringLength = compiler.StringLength
+		recording.AnchorPatterns = compiler.AnchorPatterns
+		recording.DefaultDraft = compiler.DefaultDraft
+		recording.DuplicateKeys = compiler.DuplicateKeys
+		for flag, set := range compiler.Environment {
+			if set {
+				recording.Environment = append(recording.Environment, flag)
+			}
+		}
+		sort.Strings(recording.Environment)
+		if compiler.RegexEngine != nil {
+			recording.RegexEngine = fmt.Sprintf("%T", compiler.RegexEngine)
+		}
+
+		for uri, resource := range compiler.allSchemas() {
+			if resource == s {
+				continue
+			}
+			data, err := compiler.snapshot(resource)
+			if err != nil {
+				return nil, nil, err
+			}
+			if recording.Resources == nil {
+				recording.Resources = make(map[string]json.RawMessage)
+			}
+			recording.Resources[uri] = data
+		}
+	}
+
+	return result, recording, nil
+}
+
+// snapshot returns the document of schema, marshaled on first use and kept by c, which may be
+// nil, since compiled schemas do not change.
+func (c *Compiler) snapshot(schema *Schema) (json.RawMessage, error) {
+	if c != nil {
+		if data, ok := c.snapshots.Load(schema); ok {
+			return data.(json.RawMessage), nil
+		}
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		c.snapshots.Store(schema, json.RawMessage(data))
+	}
+	return data, nil
+}
+
+// Save writes the recording as indented JSON.
+func (r *Recording) Save(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// LoadRecording reads a recording written by Recording.Save.
+func LoadRecording(reader io.Reader) (*Recording, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var recording Recording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+	}
+	if len(recording.Schema) == 0 {
+		return nil, ErrRecordingMissingSchema
+	}
+	return &recording, nil
+}
+
+// Compiler returns a compiler configured like the recorded one, which resolves references only
+// from the recorded resources and never fetches them. The regex engine named by RegexEngine is
+// not restored: set it on the compiler before compiling the recorded schema.
+func (r *Recording) Compiler() *Compiler {
+	compiler := NewCompiler()
+	compiler.Loaders = make(map[string]func(url string) (io.ReadCloser, error))
+	compiler.ContextLoaders = make(map[string]ContextLoader)
+	compiler.SetDefaultBaseURI(r.DefaultBaseURI)
+	if r.AssertFormat {
+		compiler.SetAssertFormat(true)
+	}
+	compiler.SetTimezonePolicy(r.Timezones).
+		SetEmailMode(r.EmailMode).
+		SetUnicodeHostnames(r.UnicodeHostnames).
+		SetURISchemes(r.URISchemes...).
+		SetStringLength(r.StringLength).
+		SetAnchorPatterns(r.AnchorPatterns).
+		SetDefaultDraft(r.DefaultDraft).
+		SetEnvironment(r.Environment...).
+		SetDuplicateKeyPolicy(r.DuplicateKeys)
+
+	loader := func(url string) (io.ReadCloser, error) {
+		id, _ := splitRef(url)
+		data, ok := r.Resources[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrRecordingMissingResource, id)
+		}
+		return io.NopCloser(strings.NewReader(string(data))), nil
+	}
+	for uri := range r.Resources {
+		compiler.RegisterLoader(getURLScheme(uri), loader)
+	}
+	return compiler
+}
+
+// Replay compiles the recorded schema with Compiler and validates the recorded instance again. It
+// returns ErrRecordingRegexEngine for recordings made with a custom regex engine, which must be
+// replayed with a compiler from Compiler that has the engine set.
+func (r *Recording) Replay() (*EvaluationResult, error) {
+	if r.RegexEngine != "" {
+		return nil, fmt.Errorf("%w: %s", ErrRecordingRegexEngine, r.RegexEngine)
+	}
+	schema, err := r.Compiler().Compile(r.Schema, r.URI)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Validate(r.Instance), nil
+}
+
+// Reproduces reports whether result has the same validity and fails the same keywords, at the
+// same evaluation paths and instance locations, as the recorded result. Error messages are not
+// compared, so a replay in another locale still reproduces.
+func (r *Recording) Reproduces(result *EvaluationResult) bool {
+	if r.Result == nil {
+		return false
+	}
+
+	want, got := listErrors(r.Result), listErrors(result.ToList())
+	if r.Result.Valid != result.IsValid() || len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// listErrors flattens the errors of a hierarchical list into sorted
+// "evaluationPath instanceLocation keyword" lines.
+func listErrors(list *List) []string {
+	var errs []string
+	var collect func(l *List, evaluationPath, instanceLocation string)
+	collect = func(l *List, evaluationPath, instanceLocation string) {
+		evaluationPath += l.EvaluationPath
+		instanceLocation += l.InstanceLocation
+		for keyword := range l.Errors {
+			errs = append(errs, evaluationPath+" "+instanceLocation+" "+keyword)
+		}
+		for i := range l.Details {
+			collect(&l.Details[i], evaluationPath, instanceLocation)
+		}
+	}
+	collect(list, "", "")
+	sort.Strings(errs)
+	return errs
+}
@@ -0,0 +1,148 @@
+package jsonschema
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	fetched := 0
+	compiler := NewCompiler().SetAssertFormat(true)
+	compiler.RegisterLoader("https", func(url string) (io.ReadCloser, error) {
+		fetched++
+		return io.NopCloser(strings.NewReader(`{"type": "string", "format": "email"}`)), nil
+	})
+
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"email": {"$ref": "https://example.com/email.json"},
+			"age": {"type": "integer", "minimum": 0}
+		}
+	}`))
+	require.NoError(t, err)
+
+	result, recording, err := schema.Record(map[string]interface{}{"email": "not an email", "age": -1})
+	require.NoError(t, err)
+	assert.False(t, result.IsValid())
+	assert.Contains(t, recording.Resources, "https://example.com/email.json")
+	assert.True(t, recording.AssertFormat)
+
+	var buf bytes.Buffer
+	require.NoError(t, recording.Save(&buf))
+
+	loaded, err := LoadRecording(&buf)
+	require.NoError(t, err)
+
+	replayed, err := loaded.Replay()
+	require.NoError(t, err)
+	assert.False(t, replayed.IsValid())
+	assert.True(t, loaded.Reproduces(replayed))
+	assert.Equal(t, 1, fetched, "replaying must not fetch remote references")
+
+	valid, err := loaded.Compiler().Compile(loaded.Schema)
+	require.NoError(t, err)
+	assert.False(t, loaded.Reproduces(valid.Validate(map[string]interface{}{"email": "a@example.com"})))
+}
+
+func TestRecordSettings(t *testing.T) {
+	compiler := NewCompiler().
+		SetTimezonePolicy(TimezoneAllowLocal).
+		SetEmailMode(EmailSimple).
+		SetUnicodeHostnames(true).
+		SetURISchemes("https").
+		SetStringLength(LengthBytes).
+		SetAnchorPatterns(true).
+		SetDefaultDraft(Draft7).
+		SetEnvironment("production", "eu").
+		SetDuplicateKeyPolicy(DuplicateKeysError)
+	schema, err := compiler.Compile([]byte(`{"properties": {"code": {"pattern": "[0-9]+"}, "name": {"maxLength": 3}}}`))
+	require.NoError(t, err)
+
+	instance := map[string]interface{}{"code": "a1", "name": "héé"}
+	result, recording, err := schema.Record(instance)
+	require.NoError(t, err)
+	assert.False(t, result.IsValid())
+
+	var buf bytes.Buffer
+	require.NoError(t, recording.Save(&buf))
+	loaded, err := LoadRecording(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eu", "production"}, loaded.Environment)
+
+	replay := loaded.Compiler()
+	assert.Equal(t, TimezoneAllowLocal, replay.Timezones)
+	assert.Equal(t, EmailSimple, replay.EmailMode)
+	assert.True(t, replay.UnicodeHostnames)
+	assert.Equal(t, []string{"https"}, replay.URISchemes)
+	assert.Equal(t, LengthBytes, replay.StringLength)
+	assert.True(t, replay.AnchorPatterns)
+	assert.Equal(t, Draft7, replay.DefaultDraft)
+	assert.Equal(t, map[string]bool{"production": true, "eu": true}, replay.Environment)
+	assert.Equal(t, DuplicateKeysError, replay.DuplicateKeys)
+
+	replayed, err := loaded.Replay()
+	require.NoError(t, err)
+	assert.True(t, loaded.Reproduces(replayed), "anchored patterns and byte lengths are replayed")
+}
+
+func TestRecordSnapshotsSchemas(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{"type": "string"}`), "https://example.com/name.json")
+	require.NoError(t, err)
+	schema, err := compiler.Compile([]byte(`{"properties": {"name": {"$ref": "https://example.com/name.json"}}}`))
+	require.NoError(t, err)
+
+	_, first, err := schema.Record(map[string]interface{}{"name": 1})
+	require.NoError(t, err)
+	_, second, err := schema.Record(map[string]interface{}{"name": "Ada"})
+	require.NoError(t, err)
+	assert.Same(t, &first.Schema[0], &second.Schema[0], "documents are marshaled once per compiler")
+	name := second.Resources["https://example.com/name.json"]
+	assert.Same(t, &first.Resources["https://example.com/name.json"][0], &name[0])
+}
+
+func TestReplayCustomRegexEngine(t *testing.T) {
+	schema, err := NewCompiler().SetRegexEngine(DefaultRegexEngine).Compile([]byte(`{"pattern": "^a"}`))
+	require.NoError(t, err)
+	_, recording, err := schema.Record("b")
+	require.NoError(t, err)
+	assert.Equal(t, "RegexEngineFunc", strings.TrimPrefix(recording.RegexEngine, "jsonschema."))
+
+	_, err = recording.Replay()
+	assert.ErrorIs(t, err, ErrRecordingRegexEngine)
+
+	replayed, err := recording.Compiler().SetRegexEngine(DefaultRegexEngine).Compile(recording.Schema)
+	require.NoError(t, err)
+	assert.True(t, recording.Reproduces(replayed.Validate(recording.Instance)))
+}
+
+func TestReplayMissingResource(t *testing.T) {
+	recording := &Recording{
+		Schema: []byte(`{"$ref": "https://example.com/missing.json"}`),
+		Resources: map[string]json.RawMessage{
+			"https://example.com/other.json": []byte(`{"type": "string"}`),
+		},
+	}
+	compiler := recording.Compiler()
+
+	_, err := compiler.GetSchema("https://example.com/missing.json")
+	assert.ErrorIs(t, err, ErrRecordingMissingResource)
+
+	_, err = compiler.GetSchema("http://example.com/other.json")
+	assert.ErrorIs(t, err, ErrNoLoaderRegistered)
+}
+
+func TestLoadRecordingErrors(t *testing.T) {
+	_, err := LoadRecording(strings.NewReader(`{"instance": 1}`))
+	assert.ErrorIs(t, err, ErrRecordingMissingSchema)
+
+	_, err = LoadRecording(strings.NewReader(`{`))
+	assert.ErrorIs(t, err, ErrJSONUnmarshalError)
+}
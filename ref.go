@@ -16,6 +16,15 @@ func (s *Schema) resolveRef(ref string) (*Schema, error) {
 		return s.resolveAnchor(ref[1:])
 	}
 
+	// Names given to SetSchema that are not URIs resolve as they are, not against the base URI.
+	if s.compiler != nil {
+		if name, _ := splitRef(ref); name != "" && !isValidURI(name) {
+			if _, ok := s.compiler.lookupSchema(name); ok {
+				return s.compiler.getSchema(ref)
+			}
+		}
+	}
+
 	// Resolve the full URL if ref is a relative URL
 	if !isAbsoluteURI(ref) && s.baseURI != "" {
 		ref = resolveRelativeURI(s.baseURI, ref)
@@ -55,8 +64,20 @@ func (s *Schema) resolveRefWithFullURL(ref string) (*Schema, error) {
 		return resolved, nil
 	}
 
+	if root.localRefs {
+		baseURI, anchor := splitRef(ref)
+		resolved, ok := s.compiler.lookupSchema(baseURI)
+		if !ok {
+			return nil, ErrFailedToResolveGlobalReference
+		}
+		if baseURI == ref {
+			return resolved, nil
+		}
+		return resolved.resolveAnchor(anchor)
+	}
+
 	// If not found in the current schema or its parents, look for the reference in the compiler
-	if resolved, err := s.compiler.GetSchema(ref); err != nil {
+	if resolved, err := s.compiler.getSchema(ref); err != nil {
 		return nil, ErrFailedToResolveGlobalReference
 	} else {
 		return resolved, nil
@@ -189,3 +210,22 @@ func resolveSubschemaList(schemas []*Schema) {
 		}
 	}
 }
+
+// evaluateUnresolvedRef applies the UnresolvedRefPolicy of the compiler to the reference ref of
+// keyword, which could not be resolved.
+func (s *Schema) evaluateUnresolvedRef(keyword string, ref string, result *EvaluationResult) {
+	if s.compiler == nil {
+		return
+	}
+
+	switch s.compiler.UnresolvedRefs {
+	case UnresolvedRefIgnore:
+	case UnresolvedRefWarn:
+		refs, _ := result.Annotations["unresolvedRefs"].([]string)
+		result.Annotations["unresolvedRefs"] = append(refs, ref)
+	case UnresolvedRefInvalid:
+		result.AddError(NewEvaluationError(keyword, "ref_unresolved", "Reference {ref} could not be resolved", map[string]interface{}{
+			"ref": ref,
+		}))
+	}
+}
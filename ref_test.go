@@ -0,0 +1,55 @@
+package jsonschema
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnresolvedRefPolicy(t *testing.T) {
+	schemaJSON := []byte(`{
+		"properties": {
+			"address": {"$ref": "test://schemas/address.json"},
+			"name": {"type": "string"}
+		}
+	}`)
+	instance := map[string]interface{}{"address": "unknown", "name": "John"}
+	unavailable := func(url string) (io.ReadCloser, error) {
+		return nil, errors.New("service unavailable")
+	}
+
+	compile := func(policy UnresolvedRefPolicy) *Schema {
+		compiler := NewCompiler().RegisterLoader("test", unavailable).SetUnresolvedRefPolicy(policy)
+		schema, err := compiler.Compile(schemaJSON)
+		require.NoError(t, err)
+		return schema
+	}
+
+	result := compile(UnresolvedRefIgnore).Validate(instance)
+	assert.True(t, result.IsValid())
+
+	result = compile(UnresolvedRefWarn).Validate(instance)
+	assert.True(t, result.IsValid())
+	list := result.ToList()
+	require.Len(t, list.Details, 2)
+	for _, detail := range list.Details {
+		if detail.InstanceLocation == "/address" {
+			assert.Equal(t, []string{"test://schemas/address.json"}, detail.Annotations["unresolvedRefs"])
+		}
+	}
+
+	result = compile(UnresolvedRefInvalid).Validate(instance)
+	assert.False(t, result.IsValid())
+	list = result.ToList()
+	found := false
+	for _, detail := range list.Details {
+		if detail.InstanceLocation == "/address" {
+			found = true
+			assert.Equal(t, "Reference test://schemas/address.json could not be resolved", detail.Errors["$ref"])
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,382 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// maxReflectDepth bounds the nesting of the Go values converted by reflectValue, which stops
+// cyclic pointers like encoding/json does.
+const maxReflectDepth = 1000
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// reflectValue converts a Go value without a generic JSON form, such as a struct, a pointer or a
+// typed map or slice, located at the JSON Pointer location of the instance, to the generic value
+// its JSON encoding would decode to. It walks the value with reflection instead of encoding it, so
+// validating structs allocates little more than the maps and slices of the converted value.
+//
+// The conversion follows encoding/json: json struct tags name fields and their omitempty and
+// string options are honored, embedded structs are promoted, []byte becomes a base64 string, and
+// types implementing json.Marshaler or encoding.TextMarshaler are converted with them. time.Time
+// becomes its RFC 3339 string. Values with a registered type adapter are converted by it.
+func reflectValue(compiler *Compiler, v reflect.Value, location string, depth int) (interface{}, *EvaluationError) {
+	if depth > maxReflectDepth {
+		return nil, conversionError(location, fmt.Errorf("json: unsupported value: encountered a cycle via %s", v.Type()))
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		return adaptReflected(compiler, v.Elem(), location)
+	}
+	if v.CanInterface() {
+		// The adapters of the value itself, at depth 0, were applied by adaptValue.
+		if _, ok := compiler.typeAdapter(v.Type()); ok && depth > 0 {
+			return adaptReflected(compiler, v, location)
+		}
+		if v.Type() == timeType {
+			return v.Interface().(time.Time).Format(time.RFC3339Nano), nil
+		}
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return nil, nil
+		}
+		if marshaler, ok := reflectMarshaler(v, marshalerType); ok {
+			wire, err := marshalInstance(marshaler)
+			if err != nil {
+				return nil, conversionError(location, err)
+			}
+			return wire, nil
+		}
+		if marshaler, ok := reflectMarshaler(v, textMarshalerType); ok {
+			text, err := marshaler.(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return nil, conversionError(location, err)
+			}
+			return string(text), nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, conversionError(location, fmt.Errorf("json: unsupported value: %s", strconv.FormatFloat(f, 'g', -1, 64)))
+		}
+		if v.Kind() == reflect.Float32 {
+			// The shortest representation of the float32, as encoded, rather than its float64 value.
+			return json.Number(strconv.FormatFloat(f, 'g', -1, 32)), nil
+		}
+		return f, nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return reflectValue(compiler, v.Elem(), location, depth+1)
+	case reflect.Struct:
+		return reflectStruct(compiler, v, location, depth)
+	case reflect.Map:
+		return reflectMap(compiler, v, location, depth)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 && !isMarshaler(reflect.PointerTo(v.Type().Elem())) {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		return reflectList(compiler, v, location, depth)
+	case reflect.Array:
+		return reflectList(compiler, v, location, depth)
+	}
+	return nil, conversionError(location, fmt.Errorf("json: unsupported type: %s", v.Type()))
+}
+
+// adaptReflected converts v with adaptValue, which applies the type adapters and walks the
+// generic maps and slices.
+func adaptReflected(compiler *Compiler, v reflect.Value, location string) (interface{}, *EvaluationError) {
+	adapted, _, err := adaptValue(compiler, v.Interface(), location)
+	return adapted, err
+}
+
+// reflectMarshaler returns v, or its address when only pointers implement the interface t, as a
+// marshaler of t.
+func reflectMarshaler(v reflect.Value, t reflect.Type) (interface{}, bool) {
+	if v.Type().Implements(t) {
+		return v.Interface(), true
+	}
+	if v.Kind() != reflect.Pointer && v.CanAddr() && reflect.PointerTo(v.Type()).Implements(t) {
+		return v.Addr().Interface(), true
+	}
+	return nil, false
+}
+
+// isMarshaler reports whether values of type t marshal themselves.
+func isMarshaler(t reflect.Type) bool {
+	return t.Implements(marshalerType) || t.Implements(textMarshalerType)
+}
+
+// reflectList converts a slice or an array.
+func reflectList(compiler *Compiler, v reflect.Value, location string, depth int) (interface{}, *EvaluationError) {
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		item, err := reflectValue(compiler, v.Index(i), location+"/"+strconv.Itoa(i), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = item
+	}
+	return list, nil
+}
+
+// reflectMap converts a map, whose keys must be strings, integers or encoding.TextMarshaler.
+func reflectMap(compiler *Compiler, v reflect.Value, location string, depth int) (interface{}, *EvaluationError) {
+	if v.IsNil() {
+		return nil, nil
+	}
+	object := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := reflectMapKey(iter.Key())
+		if err != nil {
+			return nil, conversionError(location, err)
+		}
+		item, evalErr := reflectValue(compiler, iter.Value(), location+"/"+escapeJSONPointer(key), depth+1)
+		if evalErr != nil {
+			return nil, evalErr
+		}
+		object[key] = item
+	}
+	return object, nil
+}
+
+// reflectMapKey returns the property name of a map key.
+func reflectMapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if key.CanInterface() {
+		if marshaler, ok := reflectMarshaler(key, textMarshalerType); ok {
+			if key.Kind() == reflect.Pointer && key.IsNil() {
+				return "", nil
+			}
+			text, err := marshaler.(encoding.TextMarshaler).MarshalText()
+			return string(text), err
+		}
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("json: unsupported type: %s", key.Type())
+}
+
+// reflectStruct converts a struct to an object of its JSON fields.
+func reflectStruct(compiler *Compiler, v reflect.Value, location string, depth int) (interface{}, *EvaluationError) {
+	fields := reflectFields(v.Type())
+	object := make(map[string]interface{}, len(fields))
+fields:
+	for _, field := range fields {
+		value := v
+		for i, index := range field.index {
+			if i > 0 && value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue fields // Fields of nil embedded structs are omitted.
+				}
+				value = value.Elem()
+			}
+			value = value.Field(index)
+		}
+		if field.omitEmpty && isEmptyValue(value) {
+			continue
+		}
+
+		item, err := reflectValue(compiler, value, location+"/"+escapeJSONPointer(field.name), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if field.quoted && item != nil {
+			data, marshalErr := json.Marshal(item)
+			if marshalErr != nil {
+				return nil, conversionError(location+"/"+escapeJSONPointer(field.name), marshalErr)
+			}
+			item = string(data)
+		}
+		object[field.name] = item
+	}
+	return object, nil
+}
+
+// isEmptyValue reports whether v is empty for the omitempty option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// reflectField is a JSON field of a struct type.
+type reflectField struct {
+	name      string
+	index     []int // Indexes of the field and of the embedded structs it is promoted from.
+	omitEmpty bool
+	quoted    bool // Whether the string option encodes the value as a JSON string.
+	tagged    bool
+}
+
+// reflectFieldCache holds the fields of the struct types converted so far.
+var reflectFieldCache sync.Map // map[reflect.Type][]reflectField
+
+// reflectFields returns the JSON fields of the struct type t, with the fields of embedded structs
+// promoted following the rules of encoding/json: the shallowest field of a name wins, then the
+// tagged one, and conflicting fields are omitted.
+func reflectFields(t reflect.Type) []reflectField {
+	if fields, ok := reflectFieldCache.Load(t); ok {
+		return fields.([]reflectField)
+	}
+
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	var candidates []reflectField
+	visited := map[reflect.Type]bool{}
+	for current := []embedded{{typ: t}}; len(current) > 0; {
+		var next []embedded
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, options, _ := strings.Cut(tag, ",")
+				index := append(append(make([]int, 0, len(e.index)+1), e.index...), i)
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, embedded{typ: ft, index: index})
+					continue
+				}
+
+				field := reflectField{name: name, index: index, tagged: name != ""}
+				if name == "" {
+					field.name = sf.Name
+				}
+				for _, option := range strings.Split(options, ",") {
+					switch option {
+					case "omitempty":
+						field.omitEmpty = true
+					case "string":
+						switch ft.Kind() {
+						case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64, reflect.String:
+							field.quoted = true
+						}
+					}
+				}
+				candidates = append(candidates, field)
+			}
+		}
+		current = next
+	}
+
+	byName := make(map[string][]reflectField, len(candidates))
+	var names []string
+	for _, field := range candidates {
+		if _, ok := byName[field.name]; !ok {
+			names = append(names, field.name)
+		}
+		byName[field.name] = append(byName[field.name], field)
+	}
+	fields := make([]reflectField, 0, len(names))
+	for _, name := range names {
+		if field, ok := dominantField(byName[name]); ok {
+			fields = append(fields, field)
+		}
+	}
+
+	actual, _ := reflectFieldCache.LoadOrStore(t, fields)
+	return actual.([]reflectField)
+}
+
+// dominantField returns the field that wins among fields of the same name, if any.
+func dominantField(fields []reflectField) (reflectField, bool) {
+	depth := len(fields[0].index)
+	var shallowest []reflectField
+	for _, field := range fields {
+		switch {
+		case len(field.index) < depth:
+			depth, shallowest = len(field.index), []reflectField{field}
+		case len(field.index) == depth:
+			shallowest = append(shallowest, field)
+		}
+	}
+	if len(shallowest) == 1 {
+		return shallowest[0], true
+	}
+	var tagged []reflectField
+	for _, field := range shallowest {
+		if field.tagged {
+			tagged = append(tagged, field)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return reflectField{}, false
+}
+
+// conversionError returns the error of a value at location that could not be converted.
+func conversionError(location string, err error) *EvaluationError {
+	return NewEvaluationError("type", "type_adapter_failed", "Value at {location} could not be converted: {error}", map[string]interface{}{
+		"location": "'" + location + "'",
+		"error":    err.Error(),
+	})
+}
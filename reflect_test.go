@@ -0,0 +1,173 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+}
+
+type testAudit struct {
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	Version   int       `json:"version"`
+}
+
+type testPerson struct {
+	testAudit
+	*testAddress
+	Name     string            `json:"name"`
+	Nickname *string           `json:"nickname"`
+	Age      uint8             `json:"age,omitempty"`
+	Score    float32           `json:"score"`
+	ID       int64             `json:"id,string"`
+	Version  string            `json:"version"` // Shadows the version of the audit.
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Counts   map[int]int       `json:"counts"`
+	Avatar   []byte            `json:"avatar"`
+	Color    testColor         `json:"color"`
+	Extra    interface{}       `json:"extra"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func TestReflectValueMatchesEncodingJSON(t *testing.T) {
+	values := []interface{}{
+		testPerson{
+			testAudit:   testAudit{CreatedBy: "ann", CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC), Version: 3},
+			testAddress: &testAddress{Street: "Main St"},
+			Name:        "Bob",
+			Score:       0.1,
+			ID:          12345678901234,
+			Version:     "v2",
+			Tags:        []string{"a", "b"},
+			Counts:      map[int]int{1: 2},
+			Avatar:      []byte{0xff, 0x00},
+			Color:       testColor(1),
+			Extra:       map[string]interface{}{"list": []int{1, 2}},
+			Ignored:     "x",
+			internal:    "y",
+		},
+		&testPerson{Name: "Ann", Labels: map[string]string{"k": "v"}},
+		[]testAddress{{Street: "a"}, {City: "b"}},
+		map[string][]float64{"x": {1.5, -2}},
+		[2]bool{true, false},
+		(*testPerson)(nil),
+	}
+
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+		expected, err := decodeInstance(data)
+		require.NoError(t, err)
+
+		converted, evalErr := reflectValue(nil, reflect.ValueOf(value), "", 0)
+		require.Nil(t, evalErr)
+		actual, err := json.Marshal(converted)
+		require.NoError(t, err)
+		expectedData, err := json.Marshal(expected)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expectedData), string(actual), "%T", value)
+	}
+}
+
+func TestValidateStruct(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"street": {"type": "string"},
+			"createdAt": {"type": "string", "format": "date-time"},
+			"score": {"type": "number", "maximum": 1},
+			"id": {"type": "string", "pattern": "^[0-9]+$"},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"nickname": {"type": ["string", "null"]}
+		},
+		"required": ["name", "tags"]
+	}`))
+	require.NoError(t, err)
+
+	person := testPerson{Name: "Ann", Tags: []string{"a"}, Score: 0.5, ID: 7}
+	assert.True(t, schema.Validate(person).IsValid())
+	assert.True(t, schema.Validate(&person).IsValid())
+
+	result := schema.Validate(testPerson{Tags: []string{"a", "a"}, Score: 2})
+	assert.False(t, result.IsValid())
+	list := result.ToList()
+	var locations []string
+	for _, detail := range list.Details {
+		if !detail.Valid {
+			locations = append(locations, detail.InstanceLocation)
+		}
+	}
+	assert.ElementsMatch(t, []string{"/name", "/score", "/tags"}, locations)
+
+	assert.False(t, schema.Validate(testPerson{Name: "Ann"}).IsValid(), "nil slices are null")
+}
+
+func TestValidateStructErrors(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(struct {
+		Values []float64 `json:"values"`
+	}{Values: []float64{1, math.Inf(1)}})
+	require.False(t, result.IsValid())
+	assert.Equal(t, "type_adapter_failed", result.Errors["type"].Code)
+	assert.Equal(t, "Value at '/values/1' could not be converted: json: unsupported value: +Inf", result.Errors["type"].Error())
+
+	result = schema.Validate(map[string]interface{}{"callback": func() {}})
+	assert.Equal(t, "Value at '/callback' could not be converted: json: unsupported type: func()", result.Errors["type"].Error())
+
+	type node struct {
+		Next *node `json:"next"`
+	}
+	cycle := &node{}
+	cycle.Next = cycle
+	assert.False(t, schema.Validate(cycle).IsValid())
+}
+
+func TestValidateStructTypeAdapters(t *testing.T) {
+	compiler := NewCompiler().RegisterTypeAdapter(reflect.TypeOf(testLevel(0)), func(v interface{}) (interface{}, error) {
+		if v.(testLevel) > 1 {
+			return nil, errors.New("unknown level")
+		}
+		return v.(testLevel).String(), nil
+	})
+	schema, err := compiler.Compile([]byte(`{"properties": {"levels": {"items": {"enum": ["low", "high"]}}}}`))
+	require.NoError(t, err)
+
+	type config struct {
+		Levels []testLevel `json:"levels"`
+	}
+	assert.True(t, schema.Validate(config{Levels: []testLevel{0, 1}}).IsValid())
+	result := schema.Validate(config{Levels: []testLevel{0, 3}})
+	assert.Equal(t, "Value at '/levels/1' could not be converted: unknown level", result.Errors["type"].Error())
+}
+
+func TestValidateStructAllocations(t *testing.T) {
+	person := testPerson{
+		Name:   "Ann",
+		Tags:   []string{"a", "b", "c"},
+		Labels: map[string]string{"team": "core"},
+		Score:  0.5,
+	}
+	reflected := testing.AllocsPerRun(100, func() {
+		_, _ = reflectValue(nil, reflect.ValueOf(person), "", 0)
+	})
+	marshaled := testing.AllocsPerRun(100, func() {
+		_, _ = marshalInstance(person)
+	})
+	assert.Less(t, reflected, marshaled)
+}
@@ -0,0 +1,71 @@
+package jsonschema
+
+import (
+	"regexp"
+)
+
+// RegexEngine compiles the regular expressions of pattern, patternProperties and the regex format.
+// The specification uses the ECMA-262 dialect, while the default engine, DefaultRegexEngine, uses
+// the RE2 syntax of the regexp package, which rejects lookarounds and backreferences and reads
+// some escapes differently. The ecmaregex package provides an ECMA-262 engine.
+type RegexEngine interface {
+	Compile(pattern string) (Regexp, error)
+}
+
+// Regexp is a regular expression compiled by a RegexEngine. *regexp.Regexp implements it.
+type Regexp interface {
+	// MatchString reports whether s contains a match of the regular expression.
+	MatchString(s string) bool
+	// SubexpNames returns the names of the capture groups, with "" for the whole match and the
+	// unnamed groups, like regexp.Regexp.SubexpNames.
+	SubexpNames() []string
+}
+
+// RegexEngineFunc adapts a function to a RegexEngine.
+type RegexEngineFunc func(pattern string) (Regexp, error)
+
+// Compile calls f(pattern).
+func (f RegexEngineFunc) Compile(pattern string) (Regexp, error) {
+	return f(pattern)
+}
+
+// DefaultRegexEngine compiles regular expressions with regexp.Compile.
+var DefaultRegexEngine RegexEngine = RegexEngineFunc(compileRE2)
+
+// compileRE2 compiles pattern with regexp.Compile.
+func compileRE2(pattern string) (Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re, nil
+}
+
+// SetRegexEngine sets the engine compiling the regular expressions of pattern, patternProperties
+// and the regex format, such as an ECMA-262 engine for schemas written for JavaScript validators.
+// The regular expressions are compiled along with each schema, with the engine set at that time,
+// and compilations fail with ErrInvalidPattern on those the engine rejects.
+func (c *Compiler) SetRegexEngine(engine RegexEngine) *Compiler {
+	c.RegexEngine = engine
+	return c
+}
+
+// regexEngine returns the regular expression engine of the compiler.
+func (c *Compiler) regexEngine() RegexEngine {
+	if c == nil || c.RegexEngine == nil {
+		return DefaultRegexEngine
+	}
+	return c.RegexEngine
+}
+
+// isRegexWith returns the regex format checker of engine.
+func isRegexWith(engine RegexEngine) func(interface{}) bool {
+	return func(v interface{}) bool {
+		pattern, ok := v.(string)
+		if !ok {
+			return true
+		}
+		_, err := engine.Compile(pattern)
+		return err == nil
+	}
+}
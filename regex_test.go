@@ -0,0 +1,56 @@
+package jsonschema
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexEngine(t *testing.T) {
+	var compiled []string
+	engine := RegexEngineFunc(func(pattern string) (Regexp, error) {
+		compiled = append(compiled, pattern)
+		return regexp.Compile(strings.ReplaceAll(pattern, `\d`, `[0-9]`))
+	})
+	compiler := NewCompiler().SetRegexEngine(engine).SetAssertFormat(true).SetAnchorPatterns(true)
+	schema, err := compiler.Compile([]byte(`{
+		"properties": {"code": {"pattern": "\\d+"}, "expression": {"format": "regex"}},
+		"patternProperties": {"x-\\d": {"type": "string"}}
+	}`))
+	require.NoError(t, err)
+
+	assert.True(t, schema.Validate(map[string]interface{}{"code": "12", "expression": "a+", "x-1": "one"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"code": "12a"}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"expression": "("}).IsValid())
+	assert.Contains(t, compiled, `^(?:\d+)$`, "anchoring wraps the pattern given to the engine")
+	assert.Contains(t, compiled, `^(?:x-\d)$`)
+	assert.Contains(t, compiled, "(")
+
+	assert.NotNil(t, compiler.Overlay().RegexEngine)
+}
+
+func TestDefaultRegexEngine(t *testing.T) {
+	re, err := DefaultRegexEngine.Compile(`(?P<name>a)`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "name"}, re.SubexpNames())
+
+	re, err = DefaultRegexEngine.Compile(`(?=a)`)
+	assert.Error(t, err)
+	assert.Nil(t, re, "failures return a nil interface")
+}
+
+func TestInvalidPattern(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{"$id": "https://example.com/lookahead.json", "properties": {"password": {"pattern": "^(?=.*\\d).{8,}$"}}}`))
+	require.ErrorIs(t, err, ErrInvalidPattern)
+	assert.Contains(t, err.Error(), "/properties/password")
+	_, err = compiler.GetSchema("https://example.com/lookahead.json")
+	assert.Error(t, err, "schemas that fail to compile are not cached")
+
+	_, err = compiler.Compile([]byte(`{"patternProperties": {"[": {"type": "string"}}}`))
+	require.ErrorIs(t, err, ErrInvalidPattern)
+	assert.Contains(t, err.Error(), `patternProperties "["`)
+}
@@ -0,0 +1,64 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/goccy/go-json"
+)
+
+// RegisterType associates the Go type T with the schema at uri, so that values of T can be
+// validated without naming their schema, with Compiler.ValidateValue and ValidateAs. The schema is
+// looked up when it is first needed, so it may be compiled or loaded after registration.
+// Pointers to T use the same schema.
+func RegisterType[T any](c *Compiler, uri string) *Compiler {
+	if c.types == nil {
+		c.types = make(map[reflect.Type]string)
+	}
+	c.types[reflect.TypeOf((*T)(nil)).Elem()] = uri
+	return c
+}
+
+// SchemaForType returns the schema registered for t, or for the type t points to.
+func (c *Compiler) SchemaForType(t reflect.Type) (*Schema, error) {
+	for t != nil {
+		if uri, ok := c.typeSchemaURI(t); ok {
+			return c.GetSchema(uri)
+		}
+		if t.Kind() != reflect.Ptr {
+			break
+		}
+		t = t.Elem()
+	}
+	return nil, fmt.Errorf("%w: %v", ErrTypeNotRegistered, t)
+}
+
+// ValidateValue validates a value of a registered type against its schema. The value is
+// converted to its JSON form first, so json struct tags and json.Marshaler implementations
+// apply. The error reports an unregistered type or a value that cannot be converted.
+func (c *Compiler) ValidateValue(value interface{}) (*EvaluationResult, error) {
+	schema, err := c.SchemaForType(reflect.TypeOf(value))
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := decodeInstance(data)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Validate(instance), nil
+}
+
+// ValidateAs validates the JSON document data against the schema registered for T and decodes it
+// into a value of T. When the document is not valid, the returned error is the *EvaluationResult.
+func ValidateAs[T any](c *Compiler, data []byte) (T, error) {
+	schema, err := c.SchemaForType(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		var value T
+		return value, err
+	}
+	return Decode[T](schema, data)
+}
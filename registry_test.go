@@ -0,0 +1,52 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registryUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestRegisterType(t *testing.T) {
+	compiler := RegisterType[registryUser](NewCompiler(), "https://example.com/user.json")
+	_, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/user.json",
+		"type": "object",
+		"properties": {"name": {"minLength": 1}, "age": {"minimum": 0}}
+	}`))
+	require.NoError(t, err)
+
+	result, err := compiler.ValidateValue(registryUser{Name: "Ann", Age: 30})
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+
+	result, err = compiler.ValidateValue(&registryUser{Age: -1})
+	require.NoError(t, err)
+	assert.False(t, result.IsValid())
+
+	_, err = compiler.ValidateValue(struct{}{})
+	assert.ErrorIs(t, err, ErrTypeNotRegistered)
+}
+
+func TestValidateAs(t *testing.T) {
+	compiler := RegisterType[registryUser](NewCompiler(), "https://example.com/user.json")
+	_, err := compiler.Compile([]byte(`{"$id": "https://example.com/user.json", "required": ["name"]}`))
+	require.NoError(t, err)
+
+	user, err := ValidateAs[registryUser](compiler, []byte(`{"name": "Ann", "age": 30}`))
+	require.NoError(t, err)
+	assert.Equal(t, registryUser{Name: "Ann", Age: 30}, user)
+
+	_, err = ValidateAs[registryUser](compiler, []byte(`{"age": 30}`))
+	var result *EvaluationResult
+	require.ErrorAs(t, err, &result)
+	assert.False(t, result.IsValid())
+
+	_, err = ValidateAs[string](compiler, []byte(`"x"`))
+	assert.ErrorIs(t, err, ErrTypeNotRegistered)
+}
@@ -0,0 +1,253 @@
+package jsonschema
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reloader keeps a schema loaded from a URI up to date with its source, for long-running services
+// that should pick up schema changes without a restart. Each reload fetches the schema and every
+// document it references through the loaders of the compiler again, and swaps the compiled schema
+// atomically when any of them changed, so Schema can be called concurrently with reloads.
+//
+// Reloads happen on demand with Reload, periodically with Start, when local files the schema was
+// read from change with Watch, or in the background when the schema is older than the maximum age
+// set with SetMaxAge. The Set and On methods must be called before the Reloader is used
+// concurrently.
+//
+// The schemas of reloads are measured by the compiler given to NewReloader, whose Stats report
+// the current schema; the measurements of the schemas replaced by a reload are removed.
+type Reloader struct {
+	compiler *Compiler
+	uri      string
+	current  atomic.Pointer[Schema]
+
+	mu        sync.Mutex        // Serializes reloads.
+	documents map[string][]byte // Documents fetched by the last successful reload, by URI.
+	fetched   []*Schema         // Schemas compiled from documents, whose measurements a reload replaces.
+
+	maxAge     time.Duration
+	onChange   func(previous, current *Schema)
+	onError    func(error)
+	checkedAt  atomic.Int64 // Unix nanoseconds of the start of the last reload.
+	refreshing atomic.Bool  // Whether a background reload is running.
+}
+
+// NewReloader loads the schema at uri with the loaders and settings of compiler and returns a
+// Reloader serving it. Schemas compiled into compiler from bytes stay shared; only documents
+// fetched by loaders are fetched again on reload, from the Cache of the compiler when it has one,
+// so that changes are seen once they expire from it. The compiler must not be modified while a
+// reload runs.
+func NewReloader(compiler *Compiler, uri string) (*Reloader, error) {
+	r := &Reloader{compiler: compiler, uri: uri}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SetMaxAge enables stale-while-revalidate: once the last reload is older than maxAge, Schema
+// still returns the current schema immediately but starts a reload in the background, so that
+// validation never waits for the source. Errors of background reloads are passed to the OnError
+// callback, and the next attempt waits for maxAge again. Zero disables background reloads.
+func (r *Reloader) SetMaxAge(maxAge time.Duration) *Reloader {
+	r.maxAge = maxAge
+	return r
+}
+
+// OnChange sets a function called with the previous and the new schema after every reload that
+// swapped the schema, whichever way it was started.
+func (r *Reloader) OnChange(fn func(previous, current *Schema)) *Reloader {
+	r.onChange = fn
+	return r
+}
+
+// OnError sets a function called with the errors of background reloads.
+func (r *Reloader) OnError(fn func(error)) *Reloader {
+	r.onError = fn
+	return r
+}
+
+// Schema returns the most recently loaded schema, starting a background reload when it is older
+// than the maximum age.
+func (r *Reloader) Schema() *Schema {
+	if r.maxAge > 0 && r.compiler.Now().Sub(time.Unix(0, r.checkedAt.Load())) > r.maxAge && r.refreshing.CompareAndSwap(false, true) {
+		go func() {
+			defer r.refreshing.Store(false)
+			if _, err := r.Reload(); err != nil && r.onError != nil {
+				r.onError(err)
+			}
+		}()
+	}
+	return r.current.Load()
+}
+
+// Reload fetches the schema and the documents it references again and reports whether any of them
+// changed. On error, the current schema is kept.
+func (r *Reloader) Reload() (bool, error) {
+	previous, schema, err := r.reload()
+	if err != nil || schema == nil {
+		return false, err
+	}
+	if previous != nil && r.onChange != nil {
+		r.onChange(previous, schema)
+	}
+	return true, nil
+}
+
+// reload performs a reload and returns the previous and the new schema, or a nil new schema when
+// nothing changed.
+func (r *Reloader) reload() (*Schema, *Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkedAt.Store(r.compiler.Now().UnixNano())
+	fresh := r.compiler.reloadCompiler()
+	schema, err := fresh.GetSchema(r.uri)
+	if err != nil {
+		r.forget(fetchedSchemas(fresh))
+		return nil, nil, err
+	}
+	previous := r.current.Load()
+	if previous != nil && sameDocuments(r.documents, fresh.loaded) {
+		r.forget(fetchedSchemas(fresh))
+		return previous, nil, nil
+	}
+	r.forget(r.fetched)
+	r.documents = fresh.loaded
+	r.fetched = fetchedSchemas(fresh)
+	r.current.Store(schema)
+	return previous, schema, nil
+}
+
+// forget removes the measurements of schemas from the compiler of r.
+func (r *Reloader) forget(schemas []*Schema) {
+	for _, schema := range schemas {
+		r.compiler.registry().forget(schema)
+	}
+}
+
+// fetchedSchemas returns the schemas c compiled from the documents its loaders fetched.
+func fetchedSchemas(c *Compiler) []*Schema {
+	schemas := make([]*Schema, 0, len(c.loaded))
+	for uri := range c.loaded {
+		if schema, ok := c.schemas[uri]; ok {
+			schemas = append(schemas, schema)
+		}
+	}
+	return schemas
+}
+
+// Start reloads the schema every interval until ctx is done, in a new goroutine. Errors are
+// passed to onError, which may be nil; the previous schema stays in use until a reload succeeds.
+func (r *Reloader) Start(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := r.Reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// Watch reloads the schema when the local files of the documents it was read from change, until
+// ctx is done, in a new goroutine: those of file URIs and those served from the file systems of
+// UseFS, such as an os.DirFS. Their modification times and sizes are checked every interval, which
+// costs a stat call per file rather than reading them, without depending on the file notification
+// APIs of each platform. Documents fetched from elsewhere are not watched; Start and SetMaxAge
+// reload them. Errors are passed to onError, which may be nil.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	watched := r.localVersions()
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current := r.localVersions()
+				if maps.Equal(watched, current) {
+					continue
+				}
+				if _, err := r.Reload(); err != nil {
+					if onError != nil {
+						onError(err)
+					}
+					continue // Retried at the next tick, until the files are valid again.
+				}
+				watched = r.localVersions()
+			}
+		}
+	}()
+}
+
+// localVersions returns the modification time and size of the local file of every document of the
+// current schema, by URI, or "" for a file that cannot be read.
+func (r *Reloader) localVersions() map[string]string {
+	r.mu.Lock()
+	uris := make([]string, 0, len(r.documents))
+	for uri := range r.documents {
+		uris = append(uris, uri)
+	}
+	r.mu.Unlock()
+
+	versions := make(map[string]string, len(uris))
+	for _, uri := range uris {
+		info, local, err := r.compiler.statLocal(uri)
+		switch {
+		case !local:
+		case err != nil:
+			versions[uri] = ""
+		default:
+			versions[uri] = fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	return versions
+}
+
+// reloadCompiler returns a compiler with the settings, loaders and compiled schemas of c, except
+// the documents that loaders fetched, so that they are fetched again. Its measurements are
+// recorded by c.
+func (c *Compiler) reloadCompiler() *Compiler {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	fresh := c.clone()
+	fresh.base = c.base
+	fresh.measuredBy = c
+	if c.measuredBy != nil {
+		fresh.measuredBy = c.measuredBy
+	}
+	for uri, schema := range c.schemas {
+		if _, loaded := c.loaded[uri]; !loaded {
+			fresh.schemas[uri] = schema
+		}
+	}
+	return fresh
+}
+
+// sameDocuments reports whether two sets of fetched documents are identical.
+func sameDocuments(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for uri, data := range a {
+		other, ok := b[uri]
+		if !ok || !bytes.Equal(data, other) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,212 @@
+package jsonschema
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reloadSource serves mutable documents to a compiler loader.
+type reloadSource struct {
+	mu        sync.Mutex
+	documents map[string]string
+	fetched   int
+}
+
+func (s *reloadSource) set(url, document string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.documents[url] = document
+}
+
+func (s *reloadSource) load(url string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetched++
+	id, _ := splitRef(url)
+	document, ok := s.documents[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(strings.NewReader(document)), nil
+}
+
+func TestReloader(t *testing.T) {
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/user.json": `{"type": "object", "properties": {"name": {"$ref": "name.json"}}}`,
+		"test://schemas/name.json": `{"type": "string"}`,
+	}}
+	compiler := NewCompiler().RegisterLoader("test", source.load)
+
+	reloader, err := NewReloader(compiler, "test://schemas/user.json")
+	require.NoError(t, err)
+	first := reloader.Schema()
+	assert.True(t, first.Validate(map[string]interface{}{"name": "ab"}).IsValid())
+
+	changed, err := reloader.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Same(t, first, reloader.Schema())
+
+	source.set("test://schemas/name.json", `{"type": "string", "minLength": 3}`)
+	changed, err = reloader.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.False(t, reloader.Schema().Validate(map[string]interface{}{"name": "ab"}).IsValid())
+	assert.True(t, first.Validate(map[string]interface{}{"name": "ab"}).IsValid(), "previous schema is unchanged")
+
+	current := reloader.Schema()
+	source.set("test://schemas/user.json", `{"type": `)
+	_, err = reloader.Reload()
+	require.Error(t, err)
+	assert.Same(t, current, reloader.Schema(), "schema is kept on error")
+}
+
+func TestReloaderKeepsCompiledSchemas(t *testing.T) {
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/user.json": `{"$ref": "https://example.com/id.json"}`,
+	}}
+	compiler := NewCompiler().RegisterLoader("test", source.load)
+	_, err := compiler.Compile([]byte(`{"type": "integer"}`), "https://example.com/id.json")
+	require.NoError(t, err)
+
+	reloader, err := NewReloader(compiler, "test://schemas/user.json")
+	require.NoError(t, err)
+	assert.False(t, reloader.Schema().Validate("1").IsValid())
+	assert.Equal(t, 1, source.fetched)
+}
+
+func TestReloaderStart(t *testing.T) {
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/value.json": `{"type": "string"}`,
+	}}
+	reloader, err := NewReloader(NewCompiler().RegisterLoader("test", source.load), "test://schemas/value.json")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloader.Start(ctx, time.Millisecond, nil)
+
+	source.set("test://schemas/value.json", `{"type": "integer"}`)
+	assert.Eventually(t, func() bool {
+		return reloader.Schema().Validate(1).IsValid()
+	}, time.Second, time.Millisecond)
+}
+
+func TestReloaderWatch(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user.json"), []byte(`{"properties": {"name": {"$ref": "name.json"}}}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "name.json"), []byte(`{"type": "string"}`), 0o600))
+
+	for name, compiler := range map[string]*Compiler{
+		"file URIs": NewCompiler(),
+		"UseFS":     NewCompiler().UseFS(os.DirFS(dir), "https://example.com/schemas/"),
+	} {
+		uri := "file://" + filepath.ToSlash(filepath.Join(dir, "user.json"))
+		if name == "UseFS" {
+			uri = "https://example.com/schemas/user.json"
+		}
+		reloader, err := NewReloader(compiler, uri)
+		require.NoError(t, err, name)
+		require.Len(t, reloader.localVersions(), 2, name)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 100)
+		reloader.Watch(ctx, time.Millisecond, func(err error) { errs <- err })
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "name.json"), []byte(`{"type": "integer"}`), 0o600))
+		assert.Eventually(t, func() bool {
+			return reloader.Schema().Validate(map[string]interface{}{"name": 1}).IsValid()
+		}, time.Second, time.Millisecond, name)
+		cancel()
+		assert.Empty(t, errs, name)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "name.json"), []byte(`{"type": "string"}`), 0o600))
+	}
+}
+
+func TestReloaderStats(t *testing.T) {
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/value.json": `{"type": "string"}`,
+	}}
+	compiler := NewCompiler().SetCollectStats(true).RegisterLoader("test", source.load)
+	reloader, err := NewReloader(compiler, "test://schemas/value.json")
+	require.NoError(t, err)
+
+	reloader.Schema().Validate("a")
+	stats := compiler.Stats()
+	require.Len(t, stats, 1, "reloaded schemas are measured by the compiler")
+	assert.Equal(t, "test://schemas/value.json", stats[0].URI)
+	assert.Equal(t, int64(1), stats[0].Validations)
+
+	for i := 0; i < 3; i++ {
+		_, err = reloader.Reload()
+		require.NoError(t, err)
+	}
+	source.set("test://schemas/value.json", `{"type": "integer"}`)
+	_, err = reloader.Reload()
+	require.NoError(t, err)
+	reloader.Schema().Validate(1)
+	reloader.Schema().Validate("a")
+	stats = compiler.Stats()
+	require.Len(t, stats, 1, "the measurements of replaced schemas are removed")
+	assert.Equal(t, int64(2), stats[0].Validations)
+	assert.Equal(t, int64(1), stats[0].Invalid)
+}
+
+func TestReloaderStaleWhileRevalidate(t *testing.T) {
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/value.json": `{"type": "string"}`,
+	}}
+	reloader, err := NewReloader(NewCompiler().RegisterLoader("test", source.load), "test://schemas/value.json")
+	require.NoError(t, err)
+
+	changes := make(chan [2]*Schema, 1)
+	errs := make(chan error, 1)
+	reloader.SetMaxAge(time.Millisecond).
+		OnChange(func(previous, current *Schema) {
+			select {
+			case changes <- [2]*Schema{previous, current}:
+			default:
+			}
+		}).
+		OnError(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+
+	stale := reloader.Schema()
+	source.set("test://schemas/value.json", `{"type": "integer"}`)
+	time.Sleep(2 * time.Millisecond)
+
+	assert.Same(t, stale, reloader.Schema(), "the stale schema is served while revalidating")
+	select {
+	case change := <-changes:
+		assert.Same(t, stale, change[0])
+		assert.True(t, change[1].Validate(1).IsValid())
+	case <-time.After(time.Second):
+		t.Fatal("schema was not refreshed")
+	}
+	assert.True(t, reloader.Schema().Validate(1).IsValid())
+
+	source.set("test://schemas/value.json", `{"type": `)
+	time.Sleep(2 * time.Millisecond)
+	reloader.Schema()
+	select {
+	case err := <-errs:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("refresh error was not reported")
+	}
+	assert.True(t, reloader.Schema().Validate(1).IsValid(), "the schema is kept on error")
+}
@@ -0,0 +1,103 @@
+package jsonschema
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+)
+
+// RemoteStatus is the result of checking one remote document referenced by the compiled schemas.
+type RemoteStatus struct {
+	URI     string // URI the document was loaded from.
+	Changed bool   // Whether the document differs from the compiled one.
+	Err     error  // Why the check failed, nil if it passed.
+}
+
+// SetAllowRemoteChanges sets whether CheckRemotes accepts remote documents that changed since
+// they were compiled. By default, a changed document fails the check.
+func (c *Compiler) SetAllowRemoteChanges(allow bool) *Compiler {
+	c.AllowRemoteChanges = allow
+	return c
+}
+
+// CheckRemotes fetches again every document the compiler loaded through its loaders, such as the
+// targets of remote $ref, and reports whether each one is still reachable and unchanged, for
+// readiness probes of validation services. The documents are fetched concurrently, bypassing the
+// Cache, from which changed documents are removed; those not fetched before ctx is done fail
+// with the context error.
+//
+// The returned error joins the errors of the failed checks, wrapping ErrRemoteUnreachable or
+// ErrRemoteChanged, and is nil when every check passed.
+func (c *Compiler) CheckRemotes(ctx context.Context) ([]RemoteStatus, error) {
+	c.mu.RLock()
+	loaded := maps.Clone(c.loaded)
+	c.mu.RUnlock()
+	uris := make([]string, 0, len(loaded))
+	for uri := range loaded {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	statuses := make([]RemoteStatus, len(uris))
+	var wg sync.WaitGroup
+	for i, uri := range uris {
+		wg.Add(1)
+		go func(i int, uri string, compiled []byte) {
+			defer wg.Done()
+			statuses[i] = c.checkRemote(ctx, uri, compiled)
+		}(i, uri, loaded[uri])
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, status := range statuses {
+		if status.Err != nil {
+			errs = append(errs, status.Err)
+		}
+	}
+	return statuses, errors.Join(errs...)
+}
+
+// checkRemote fetches the document at uri and compares it with the compiled one.
+func (c *Compiler) checkRemote(ctx context.Context, uri string, compiled []byte) RemoteStatus {
+	status := RemoteStatus{URI: uri}
+	data, err := fetchWithContext(ctx, func() ([]byte, error) { return c.fetchDocument(ctx, uri) })
+	switch {
+	case err != nil:
+		status.Err = fmt.Errorf("%w: %s: %w", ErrRemoteUnreachable, uri, err)
+	case !bytes.Equal(data, compiled):
+		status.Changed = true
+		if c.Cache != nil {
+			_ = c.Cache.Delete(ctx, documentCacheKey(uri))
+		}
+		if !c.AllowRemoteChanges {
+			status.Err = fmt.Errorf("%w: %s", ErrRemoteChanged, uri)
+		}
+	}
+	return status
+}
+
+// fetchWithContext runs fetch, which loaders cannot cancel, and returns the context error instead
+// of waiting for it when ctx is done first.
+func fetchWithContext(ctx context.Context, fetch func() ([]byte, error)) ([]byte, error) {
+	type fetched struct {
+		data []byte
+		err  error
+	}
+	done := make(chan fetched, 1)
+	go func() {
+		data, err := fetch()
+		done <- fetched{data, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-done:
+		return result.data, result.err
+	}
+}
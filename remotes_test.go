@@ -0,0 +1,69 @@
+package jsonschema
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRemotes(t *testing.T) {
+	source := &reloadSource{documents: map[string]string{
+		"test://schemas/name.json": `{"type": "string"}`,
+		"test://schemas/age.json":  `{"type": "integer"}`,
+	}}
+	compiler := NewCompiler().RegisterLoader("test", source.load)
+	_, err := compiler.Compile([]byte(`{
+		"properties": {
+			"name": {"$ref": "test://schemas/name.json"},
+			"age": {"$ref": "test://schemas/age.json"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	statuses, err := compiler.CheckRemotes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []RemoteStatus{
+		{URI: "test://schemas/age.json"},
+		{URI: "test://schemas/name.json"},
+	}, statuses)
+
+	source.set("test://schemas/name.json", `{"type": "string", "minLength": 1}`)
+	delete(source.documents, "test://schemas/age.json")
+	statuses, err = compiler.CheckRemotes(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrRemoteUnreachable)
+	assert.ErrorIs(t, err, ErrRemoteChanged)
+	require.Len(t, statuses, 2)
+	assert.ErrorIs(t, statuses[0].Err, ErrRemoteUnreachable)
+	assert.True(t, statuses[1].Changed)
+
+	compiler.SetAllowRemoteChanges(true)
+	source.set("test://schemas/age.json", `{"type": "integer"}`)
+	statuses, err = compiler.CheckRemotes(context.Background())
+	require.NoError(t, err)
+	assert.True(t, statuses[1].Changed)
+	assert.NoError(t, statuses[1].Err)
+}
+
+func TestCheckRemotesContext(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	source := &reloadSource{documents: map[string]string{"test://schemas/slow.json": `{}`}}
+	compiler := NewCompiler().RegisterLoader("test", source.load)
+	_, err := compiler.Compile([]byte(`{"$ref": "test://schemas/slow.json"}`))
+	require.NoError(t, err)
+
+	compiler.RegisterLoader("test", func(url string) (io.ReadCloser, error) {
+		<-block
+		return source.load(url)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	statuses, err := compiler.CheckRemotes(ctx)
+	assert.ErrorIs(t, err, ErrRemoteUnreachable)
+	assert.ErrorIs(t, err, context.Canceled)
+	require.Len(t, statuses, 1)
+}
@@ -7,6 +7,10 @@ type EvaluationError struct {
 	Code    string                 `json:"code"`
 	Message string                 `json:"message"`
 	Params  map[string]interface{} `json:"params"`
+
+	Severity Severity `json:"severity,omitempty"` // Severity of the keyword, below SeverityError for warnings, see Compiler.SetSeverity.
+
+	custom bool // Whether Message was declared by the errorMessage keyword of the schema, which is not localized.
 }
 
 func NewEvaluationError(keyword string, code string, message string, params ...map[string]interface{}) *EvaluationError {
@@ -31,7 +35,7 @@ func (e *EvaluationError) Error() string {
 }
 
 func (e *EvaluationError) Localize(localizer *i18n.Localizer) string {
-	if localizer != nil {
+	if localizer != nil && !e.custom {
 		return localizer.Get(e.Code, i18n.Vars(e.Params))
 	} else {
 		return e.Error()
@@ -49,6 +53,7 @@ type List struct {
 	InstanceLocation string                 `json:"instanceLocation"`
 	Annotations      map[string]interface{} `json:"annotations,omitempty"`
 	Errors           map[string]string      `json:"errors,omitempty"`
+	Warnings         map[string]string      `json:"warnings,omitempty"`
 	Details          []List                 `json:"details,omitempty"`
 }
 
@@ -59,7 +64,8 @@ type EvaluationResult struct {
 	SchemaLocation   string                      `json:"schemaLocation"`
 	InstanceLocation string                      `json:"instanceLocation"`
 	Annotations      map[string]interface{}      `json:"annotations,omitempty"`
-	Errors           map[string]*EvaluationError `json:"errors,omitempty"` // Store error messages here
+	Errors           map[string]*EvaluationError `json:"errors,omitempty"`   // Store error messages here
+	Warnings         map[string]*EvaluationError `json:"warnings,omitempty"` // Errors of keywords below SeverityError, see Compiler.SetSeverity.
 	Details          []*EvaluationResult         `json:"details,omitempty"`
 }
 
@@ -163,6 +169,11 @@ func (e *EvaluationResult) CollectAnnotations() *EvaluationResult {
 	if e.schema.Examples != nil {
 		e.Annotations["examples"] = e.schema.Examples
 	}
+	if e.schema.compiler != nil && e.schema.compiler.AnnotateExtensions {
+		for keyword, value := range e.schema.Extensions {
+			e.Annotations[keyword] = value
+		}
+	}
 
 	return e
 }
@@ -202,6 +213,7 @@ func (e *EvaluationResult) ToLocalizeList(localizer *i18n.Localizer, includeHier
 		InstanceLocation: e.InstanceLocation,
 		Annotations:      e.Annotations,
 		Errors:           e.convertErrors(localizer),
+		Warnings:         e.convertWarnings(localizer),
 		Details:          make([]List, 0),
 	}
 
@@ -226,6 +238,7 @@ func (e *EvaluationResult) flattenDetailsToList(localizer *i18n.Localizer, list
 			InstanceLocation: detail.InstanceLocation,
 			Annotations:      detail.Annotations,
 			Errors:           detail.convertErrors(localizer),
+			Warnings:         detail.convertWarnings(localizer),
 		}
 		list.Details = append(list.Details, flatDetail)
 
@@ -236,8 +249,21 @@ func (e *EvaluationResult) flattenDetailsToList(localizer *i18n.Localizer, list
 }
 
 func (e *EvaluationResult) convertErrors(localizer *i18n.Localizer) map[string]string {
+	return localizeErrors(e.Errors, localizer)
+}
+
+// convertWarnings converts the warnings of the result like convertErrors, see Compiler.SetSeverity.
+func (e *EvaluationResult) convertWarnings(localizer *i18n.Localizer) map[string]string {
+	if len(e.Warnings) == 0 {
+		return nil
+	}
+	return localizeErrors(e.Warnings, localizer)
+}
+
+// localizeErrors returns the messages of errs by keyword, localized when localizer is not nil.
+func localizeErrors(errs map[string]*EvaluationError, localizer *i18n.Localizer) map[string]string {
 	errors := make(map[string]string)
-	for key, err := range e.Errors {
+	for key, err := range errs {
 		if localizer != nil {
 			errors[key] = err.Localize(localizer)
 		} else {
@@ -0,0 +1,481 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// UnsatisfiableSchema describes a subschema that rejects every instance.
+type UnsatisfiableSchema struct {
+	// KeywordLocation is the JSON Pointer of the subschema, relative to the analyzed schema.
+	KeywordLocation string `json:"keywordLocation"`
+
+	// Reason explains why no instance can be valid.
+	Reason string `json:"reason"`
+}
+
+// Unsatisfiable reports the subschemas of s that no instance can satisfy, such as an allOf
+// combining conflicting types, enumerated values that fail the other keywords, or a required
+// property whose schema is false. Such schemas are usually mistakes that otherwise only show up
+// as data being rejected.
+//
+// The analysis follows $ref, allOf, anyOf, oneOf and not, and merges the numeric, length, item
+// and property count bounds of schemas that apply together. It only reports what it can prove:
+// keywords such as pattern, if or dependentSchemas are not analyzed, so a schema made impossible
+// by them is not reported. Boolean false subschemas are intentional and are not reported.
+func (s *Schema) Unsatisfiable() []UnsatisfiableSchema {
+	a := &satisfiability{results: make(map[*Schema]*typeDomain)}
+
+	var findings []UnsatisfiableSchema
+	walkSchema(s, func(pointer string, schema *Schema) bool {
+		if schema.Boolean != nil {
+			return false
+		}
+		if domain := a.analyze(schema); domain.empty() {
+			findings = append(findings, UnsatisfiableSchema{
+				KeywordLocation: "#" + pointer,
+				Reason:          strings.Join(domain.reasons, "; "),
+			})
+		}
+		return true
+	})
+	return findings
+}
+
+// satisfiableTypes lists the instance types tracked by the analysis. Integers and numbers with a
+// fractional part are tracked separately, like getDataType does.
+var satisfiableTypes = []string{"null", "boolean", "integer", "number", "string", "array", "object"}
+
+// typeDomain over-approximates the types of the instances a schema accepts.
+type typeDomain struct {
+	types   map[string]bool
+	reasons []string // Why types were ruled out.
+}
+
+// anyType returns a domain accepting instances of every type.
+func anyType() *typeDomain {
+	d := &typeDomain{types: make(map[string]bool, len(satisfiableTypes))}
+	for _, t := range satisfiableTypes {
+		d.types[t] = true
+	}
+	return d
+}
+
+func (d *typeDomain) empty() bool {
+	return len(d.types) == 0
+}
+
+// exclude rules out types for the given reason, if any of them is still possible.
+func (d *typeDomain) exclude(reason string, types ...string) {
+	excluded := false
+	for _, t := range types {
+		if d.types[t] {
+			delete(d.types, t)
+			excluded = true
+		}
+	}
+	if excluded {
+		d.reasons = append(d.reasons, reason)
+	}
+}
+
+// sortedTypes returns the possible types in a deterministic order.
+func (d *typeDomain) sortedTypes() []string {
+	var types []string
+	for _, t := range satisfiableTypes {
+		if d.types[t] {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// satisfiability memoizes the analysis of the subschemas of a schema.
+type satisfiability struct {
+	results map[*Schema]*typeDomain
+}
+
+// analyze returns the types of the instances s may accept. A schema whose analysis is in
+// progress, through a recursive reference, is assumed to accept every type.
+func (a *satisfiability) analyze(s *Schema) *typeDomain {
+	if s == nil {
+		return anyType()
+	}
+	if d, ok := a.results[s]; ok {
+		if d == nil {
+			return anyType()
+		}
+		return d
+	}
+	a.results[s] = nil
+
+	d := a.analyzeSchema(s)
+	a.results[s] = d
+	return d
+}
+
+// satisfiable reports whether s may accept some instance.
+func (a *satisfiability) satisfiable(s *Schema) bool {
+	return !a.analyze(s).empty()
+}
+
+func (a *satisfiability) analyzeSchema(s *Schema) *typeDomain {
+	d := anyType()
+	if s.Boolean != nil {
+		if !*s.Boolean {
+			d.exclude("the schema is false", satisfiableTypes...)
+		}
+		return d
+	}
+
+	conjuncts := a.conjuncts(s, nil, map[*Schema]bool{})
+
+	// A schema that is satisfiable only if one of its applied schemas is.
+	for _, c := range conjuncts {
+		if c.schema != s && !a.satisfiable(c.schema) {
+			d.exclude(fmt.Sprintf("%s is unsatisfiable", c.path), satisfiableTypes...)
+			return d
+		}
+	}
+
+	// Enumerated values are checked exactly against the whole schema.
+	if values, keyword, ok := enumeratedConjunct(conjuncts); ok {
+		var valid []interface{}
+		for _, value := range values {
+			if s.Validate(value).IsValid() {
+				valid = append(valid, value)
+			}
+		}
+		if len(valid) == 0 {
+			d.exclude(fmt.Sprintf("no value allowed by %s satisfies the other keywords", keyword), satisfiableTypes...)
+			return d
+		}
+		d.types = map[string]bool{}
+		for _, value := range valid {
+			d.types[getDataType(value)] = true
+		}
+		return d
+	}
+
+	a.analyzeTypes(d, conjuncts)
+	a.analyzeApplicators(d, conjuncts)
+	if d.types["integer"] || d.types["number"] {
+		analyzeNumbers(d, conjuncts)
+	}
+	if d.types["string"] {
+		analyzeStrings(d, conjuncts)
+	}
+	if d.types["array"] {
+		a.analyzeArrays(d, conjuncts)
+	}
+	if d.types["object"] {
+		a.analyzeObjects(d, conjuncts)
+	}
+	return d
+}
+
+// conjunct is a schema that applies to the same instance as the analyzed schema.
+type conjunct struct {
+	schema *Schema
+	path   string // Keyword path from the analyzed schema, used in reasons.
+}
+
+// conjuncts returns s followed by the schemas it applies unconditionally through $ref and allOf.
+func (a *satisfiability) conjuncts(s *Schema, path []string, seen map[*Schema]bool) []conjunct {
+	if s == nil || seen[s] {
+		return nil
+	}
+	seen[s] = true
+
+	result := []conjunct{{schema: s, path: strings.Join(path, "/")}}
+	if s.Boolean != nil {
+		return result
+	}
+	if s.ResolvedRef != nil {
+		result = append(result, a.conjuncts(s.ResolvedRef, append(path[:len(path):len(path)], "$ref"), seen)...)
+	}
+	for i, sub := range s.AllOf {
+		result = append(result, a.conjuncts(sub, append(path[:len(path):len(path)], fmt.Sprintf("allOf/%d", i)), seen)...)
+	}
+	return result
+}
+
+// enumeratedConjunct returns the values allowed by the first const or enum among the conjuncts.
+func enumeratedConjunct(conjuncts []conjunct) ([]interface{}, string, bool) {
+	for _, c := range conjuncts {
+		keyword := ""
+		var values []interface{}
+		switch {
+		case c.schema.Const != nil && c.schema.Const.IsSet:
+			keyword, values = "const", []interface{}{c.schema.Const.Value}
+		case c.schema.Enum != nil:
+			keyword, values = "enum", c.schema.Enum
+		default:
+			continue
+		}
+		return values, keywordPath(c.path, keyword), true
+	}
+	return nil, "", false
+}
+
+// analyzeTypes intersects the type keywords of the conjuncts.
+func (a *satisfiability) analyzeTypes(d *typeDomain, conjuncts []conjunct) {
+	var declared []string
+	for _, c := range conjuncts {
+		if len(c.schema.Type) == 0 {
+			continue
+		}
+		allowed := map[string]bool{}
+		for _, t := range c.schema.Type {
+			allowed[t] = true
+			if t == "number" {
+				allowed["integer"] = true
+			}
+		}
+		declared = append(declared, "["+strings.Join(c.schema.Type, ", ")+"]")
+		for _, t := range satisfiableTypes {
+			if !allowed[t] {
+				delete(d.types, t)
+			}
+		}
+	}
+	if d.empty() {
+		d.reasons = append(d.reasons, fmt.Sprintf("the type keywords %s have no type in common", strings.Join(declared, " and ")))
+	}
+}
+
+// analyzeApplicators restricts the types to those allowed by anyOf, oneOf and not.
+func (a *satisfiability) analyzeApplicators(d *typeDomain, conjuncts []conjunct) {
+	for _, c := range conjuncts {
+		for _, keyword := range []string{"anyOf", "oneOf"} {
+			branches := c.schema.AnyOf
+			if keyword == "oneOf" {
+				branches = c.schema.OneOf
+			}
+			if len(branches) == 0 {
+				continue
+			}
+			allowed := map[string]bool{}
+			for _, branch := range branches {
+				for t := range a.analyze(branch).types {
+					allowed[t] = true
+				}
+			}
+			location := keywordPath(c.path, keyword)
+			var excluded []string
+			for _, t := range d.sortedTypes() {
+				if !allowed[t] {
+					excluded = append(excluded, t)
+				}
+			}
+			if len(allowed) == 0 {
+				d.exclude(fmt.Sprintf("every %s alternative is unsatisfiable", location), excluded...)
+			} else if len(excluded) == len(d.types) {
+				d.exclude(fmt.Sprintf("no %s alternative accepts %s values", location, strings.Join(excluded, ", ")), excluded...)
+			} else {
+				for _, t := range excluded {
+					delete(d.types, t)
+				}
+			}
+		}
+
+		if c.schema.Not != nil && acceptsEverything(c.schema.Not) {
+			d.exclude(fmt.Sprintf("%s rejects every instance", keywordPath(c.path, "not")), satisfiableTypes...)
+		}
+	}
+}
+
+// acceptsEverything reports whether s is true or has no keywords.
+func acceptsEverything(s *Schema) bool {
+	if s.Boolean != nil {
+		return *s.Boolean
+	}
+	return len(evaluationOrder(s)) == 0
+}
+
+// analyzeNumbers merges the numeric bounds of the conjuncts.
+func analyzeNumbers(d *typeDomain, conjuncts []conjunct) {
+	var low, high *big.Rat
+	lowExcl, highExcl := false, false
+	var multiples []*big.Rat
+	for _, c := range conjuncts {
+		if bound, excl := lowerBound(c.schema); bound != nil {
+			if low == nil || bound.Cmp(low) > 0 || (bound.Cmp(low) == 0 && excl) {
+				low, lowExcl = bound, excl
+			}
+		}
+		if bound, excl := upperBound(c.schema); bound != nil {
+			if high == nil || bound.Cmp(high) < 0 || (bound.Cmp(high) == 0 && excl) {
+				high, highExcl = bound, excl
+			}
+		}
+		if c.schema.MultipleOf != nil {
+			multiples = append(multiples, c.schema.MultipleOf.Rat)
+		}
+	}
+	if low == nil || high == nil {
+		return
+	}
+
+	describe := func(bound *big.Rat, excl bool, inclusive, exclusive string) string {
+		if excl {
+			return exclusive + " " + bound.RatString()
+		}
+		return inclusive + " " + bound.RatString()
+	}
+	lowText := describe(low, lowExcl, "minimum", "exclusiveMinimum")
+	highText := describe(high, highExcl, "maximum", "exclusiveMaximum")
+
+	switch cmp := low.Cmp(high); {
+	case cmp > 0 || (cmp == 0 && (lowExcl || highExcl)):
+		d.exclude(fmt.Sprintf("no number satisfies both %s and %s", lowText, highText), "integer", "number")
+		return
+	case cmp == 0:
+		for _, m := range multiples {
+			if !new(big.Rat).Quo(low, m).IsInt() {
+				d.exclude(fmt.Sprintf("the only number allowed, %s, is not a multiple of %s", low.RatString(), m.RatString()), "integer", "number")
+				return
+			}
+		}
+		if low.IsInt() {
+			delete(d.types, "number")
+		} else {
+			delete(d.types, "integer")
+		}
+	}
+
+	// Look for an integer within the bounds, starting from the smallest one above the lower bound.
+	first := new(big.Int).Div(low.Num(), low.Denom())
+	if !low.IsInt() || lowExcl {
+		first.Add(first, big.NewInt(1))
+	}
+	firstRat := new(big.Rat).SetInt(first)
+	if firstRat.Cmp(high) > 0 || (firstRat.Cmp(high) == 0 && highExcl) {
+		if d.types["number"] {
+			delete(d.types, "integer")
+		} else {
+			d.exclude(fmt.Sprintf("no integer lies between %s and %s", lowText, highText), "integer")
+		}
+	}
+}
+
+// analyzeStrings merges the string length bounds of the conjuncts.
+func analyzeStrings(d *typeDomain, conjuncts []conjunct) {
+	minLength, maxLength := mergeCounts(conjuncts, func(s *Schema) (*float64, *float64) { return s.MinLength, s.MaxLength })
+	if minLength != nil && maxLength != nil && *minLength > *maxLength {
+		d.exclude(fmt.Sprintf("minLength %v exceeds maxLength %v", *minLength, *maxLength), "string")
+	}
+}
+
+// analyzeArrays merges the item count bounds of the conjuncts, taking into account items that
+// cannot be satisfied and contains.
+func (a *satisfiability) analyzeArrays(d *typeDomain, conjuncts []conjunct) {
+	minItems, maxItems := mergeCounts(conjuncts, func(s *Schema) (*float64, *float64) { return s.MinItems, s.MaxItems })
+	if minItems != nil && maxItems != nil && *minItems > *maxItems {
+		d.exclude(fmt.Sprintf("minItems %v exceeds maxItems %v", *minItems, *maxItems), "array")
+		return
+	}
+
+	for _, c := range conjuncts {
+		// The first item a conjunct cannot accept bounds the length of every array.
+		limit := -1
+		for i, item := range c.schema.PrefixItems {
+			if !a.satisfiable(item) {
+				limit = i
+				break
+			}
+		}
+		if limit < 0 && c.schema.Items != nil && !a.satisfiable(c.schema.Items) {
+			limit = len(c.schema.PrefixItems)
+		}
+		if limit >= 0 && minItems != nil && *minItems > float64(limit) {
+			d.exclude(fmt.Sprintf("minItems %v exceeds the %d items allowed by %s", *minItems, limit, keywordPath(c.path, "items")), "array")
+			return
+		}
+
+		if c.schema.Contains == nil {
+			continue
+		}
+		minContains := 1.0
+		if c.schema.MinContains != nil {
+			minContains = *c.schema.MinContains
+		}
+		switch {
+		case minContains > 0 && !a.satisfiable(c.schema.Contains):
+			d.exclude(fmt.Sprintf("%s is unsatisfiable", keywordPath(c.path, "contains")), "array")
+			return
+		case c.schema.MaxContains != nil && minContains > *c.schema.MaxContains:
+			d.exclude(fmt.Sprintf("minContains %v exceeds maxContains %v", minContains, *c.schema.MaxContains), "array")
+			return
+		case maxItems != nil && minContains > *maxItems:
+			d.exclude(fmt.Sprintf("minContains %v exceeds maxItems %v", minContains, *maxItems), "array")
+			return
+		}
+	}
+}
+
+// analyzeObjects merges the property count bounds and required properties of the conjuncts, and
+// checks that every required property can be satisfied.
+func (a *satisfiability) analyzeObjects(d *typeDomain, conjuncts []conjunct) {
+	minProperties, maxProperties := mergeCounts(conjuncts, func(s *Schema) (*float64, *float64) { return s.MinProperties, s.MaxProperties })
+	if minProperties != nil && maxProperties != nil && *minProperties > *maxProperties {
+		d.exclude(fmt.Sprintf("minProperties %v exceeds maxProperties %v", *minProperties, *maxProperties), "object")
+		return
+	}
+
+	required := map[string]bool{}
+	for _, c := range conjuncts {
+		for _, name := range c.schema.Required {
+			required[name] = true
+		}
+	}
+	if maxProperties != nil && float64(len(required)) > *maxProperties {
+		d.exclude(fmt.Sprintf("%d required properties exceed maxProperties %v", len(required), *maxProperties), "object")
+		return
+	}
+
+	names := make([]string, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, c := range conjuncts {
+			if c.schema.PropertyNames != nil && !c.schema.PropertyNames.Validate(name).IsValid() {
+				d.exclude(fmt.Sprintf("the required property name %q is rejected by %s", name, keywordPath(c.path, "propertyNames")), "object")
+				return
+			}
+			for _, schema := range propertySchemas(c.schema, name) {
+				if !a.satisfiable(schema) {
+					d.exclude(fmt.Sprintf("the required property %q cannot be satisfied", name), "object")
+					return
+				}
+			}
+		}
+	}
+}
+
+// mergeCounts returns the largest lower and the smallest upper count bound of the conjuncts.
+func mergeCounts(conjuncts []conjunct, bounds func(*Schema) (*float64, *float64)) (*float64, *float64) {
+	var low, high *float64
+	for _, c := range conjuncts {
+		l, h := bounds(c.schema)
+		if l != nil && (low == nil || *l > *low) {
+			low = l
+		}
+		if h != nil && (high == nil || *h < *high) {
+			high = h
+		}
+	}
+	return low, high
+}
+
+// keywordPath joins the keyword path of a conjunct with a keyword.
+func keywordPath(path, keyword string) string {
+	if path == "" {
+		return keyword
+	}
+	return path + "/" + keyword
+}
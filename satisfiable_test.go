@@ -0,0 +1,108 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsatisfiable(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		findings []UnsatisfiableSchema
+	}{
+		{
+			name:   "bounds of other types do not conflict",
+			schema: `{"type": "string", "minimum": 3, "maxLength": 10}`,
+		},
+		{
+			name:   "conflicting types in allOf",
+			schema: `{"allOf": [{"type": "string"}, {"type": "number", "minimum": 3}]}`,
+			findings: []UnsatisfiableSchema{
+				{KeywordLocation: "#", Reason: "the type keywords [string] and [number] have no type in common"},
+			},
+		},
+		{
+			name:   "const against type",
+			schema: `{"type": "integer", "allOf": [{"const": "yes"}]}`,
+			findings: []UnsatisfiableSchema{
+				{KeywordLocation: "#", Reason: "no value allowed by allOf/0/const satisfies the other keywords"},
+			},
+		},
+		{
+			name:   "enum against bounds through a reference",
+			schema: `{"$defs": {"small": {"maximum": 2}}, "$ref": "#/$defs/small", "enum": [3, 4]}`,
+			findings: []UnsatisfiableSchema{
+				{KeywordLocation: "#", Reason: "no value allowed by enum satisfies the other keywords"},
+			},
+		},
+		{
+			name:   "crossed numeric bounds",
+			schema: `{"type": "number", "allOf": [{"minimum": 10}, {"exclusiveMaximum": 10}]}`,
+			findings: []UnsatisfiableSchema{
+				{KeywordLocation: "#", Reason: "no number satisfies both minimum 10 and exclusiveMaximum 10"},
+			},
+		},
+		{
+			name:   "no integer in range",
+			schema: `{"type": "integer", "exclusiveMinimum": 1, "exclusiveMaximum": 2}`,
+			findings: []UnsatisfiableSchema{
+				{KeywordLocation: "#", Reason: "no integer lies between exclusiveMinimum 1 and exclusiveMaximum 2"},
+			},
+		},
+		{
+			name:   "non-integer numbers in range",
+			schema: `{"type": "number", "exclusiveMinimum": 1, "exclusiveMaximum": 2}`,
+		},
+		{
+			name:   "required property that is false",
+			schema: `{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id", "name"], "additionalProperties": false}`,
+			findings: []UnsatisfiableSchema{
+				{KeywordLocation: "#", Reason: `the required property "name" cannot be satisfied`},
+			},
+		},
+		{
+			name:   "nested unsatisfiable property",
+			schema: `{"type": "object", "properties": {"age": {"type": "integer", "minimum": 5, "maximum": 1}}}`,
+			findings: []UnsatisfiableSchema{
+				{KeywordLocation: "#/properties/age", Reason: "no number satisfies both minimum 5 and maximum 1"},
+			},
+		},
+		{
+			name:   "array bounds",
+			schema: `{"type": ["array", "string"], "minItems": 3, "prefixItems": [{}, {}], "items": false, "minLength": 4, "maxLength": 2}`,
+			findings: []UnsatisfiableSchema{
+				{KeywordLocation: "#", Reason: "minLength 4 exceeds maxLength 2; minItems 3 exceeds the 2 items allowed by items"},
+			},
+		},
+		{
+			name:   "anyOf alternatives",
+			schema: `{"type": "string", "anyOf": [{"type": "integer"}, {"type": "null"}]}`,
+			findings: []UnsatisfiableSchema{
+				{KeywordLocation: "#", Reason: "no anyOf alternative accepts string values"},
+			},
+		},
+		{
+			name:   "not everything",
+			schema: `{"properties": {"legacy": {"not": {}}}}`,
+			findings: []UnsatisfiableSchema{
+				{KeywordLocation: "#/properties/legacy", Reason: "not rejects every instance"},
+			},
+		},
+		{
+			name:   "recursive schema",
+			schema: `{"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}}}}, "$ref": "#/$defs/node"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewCompiler().Compile([]byte(tt.schema))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.findings, schema.Unsatisfiable())
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package jsonschema
 
 import (
-	"regexp"
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/goccy/go-json"
 )
@@ -9,14 +12,22 @@ import (
 // Schema represents a JSON Schema as per the 2020-12 draft, containing all
 // necessary metadata and validation properties defined by the specification.
 type Schema struct {
-	compiledPatterns map[string]*regexp.Regexp // Cached compiled regular expressions for pattern properties.
-	compiler         *Compiler                 // Reference to the associated Compiler instance.
-	parent           *Schema                   // Parent schema for hierarchical resolution.
-	uri              string                    // Internal schema identifier resolved during compilation.
-	baseURI          string                    // Base URI for resolving relative references within the schema.
-	anchors          map[string]*Schema        // Anchors for quick lookup of internal schema references.
-	dynamicAnchors   map[string]*Schema        // Dynamic anchors for more flexible schema references.
-	schemas          map[string]*Schema        // Cache of compiled schemas.
+	compiledPatterns map[string]Regexp   // Compiled regular expressions of pattern and patternProperties.
+	compiler         *Compiler           // Reference to the associated Compiler instance.
+	parent           *Schema             // Parent schema for hierarchical resolution.
+	uri              string              // Internal schema identifier resolved during compilation.
+	baseURI          string              // Base URI for resolving relative references within the schema.
+	anchors          map[string]*Schema  // Anchors for quick lookup of internal schema references.
+	dynamicAnchors   map[string]*Schema  // Dynamic anchors for more flexible schema references.
+	schemas          map[string]*Schema  // Cache of compiled schemas.
+	formatAssertion  bool                // Whether the dialect declared by $schema asserts format.
+	assertFormat     *bool               // Format assertion override set by SetAssertFormat.
+	emailMode        *EmailMode          // Email format strictness override set by SetEmailMode.
+	localRefs        bool                // Whether references resolve only to schemas known to the compiler, see Dialect.LocalRefs.
+	transforms       []InstanceTransform // Instance transforms set by SetTransforms.
+	keywords         []compiledKeyword   // Custom keywords, see Compiler.RegisterKeyword.
+	severities       map[string]Severity // Severities of the errors of keywords, see Compiler.SetSeverity.
+	errorMessages    map[string]string   // Messages of the errors of keywords declared by errorMessage.
 
 	ID     string  `json:"$id,omitempty"`     // Public identifier for the schema.
 	Schema string  `json:"$schema,omitempty"` // URI indicating the specification the schema conforms to.
@@ -106,6 +117,9 @@ type Schema struct {
 	ReadOnly    *bool         `json:"readOnly,omitempty"`    // Indicates that the property is read-only.
 	WriteOnly   *bool         `json:"writeOnly,omitempty"`   // Indicates that the property is write-only.
 	Examples    []interface{} `json:"examples,omitempty"`    // Examples of the instance data that validates against this schema.
+
+	// Keywords the validator does not know, such as "x-internal" or "markdownDescription", preserved as parsed.
+	Extensions map[string]interface{} `json:"-"`
 }
 
 // newSchema parses JSON schema data and returns a Schema object.
@@ -161,6 +175,10 @@ func (s *Schema) initializeSchema(compiler *Compiler, parent *Schema) {
 		root.setSchema(s.uri, s)
 	}
 
+	if s.Schema != "" {
+		s.formatAssertion = compiler.dialectAssertsFormat(s.Schema, s)
+	}
+
 	initializeNestedSchemas(s, compiler)
 	s.resolveReferences()
 }
@@ -322,6 +340,41 @@ func (s *Schema) GetSchemaLocation(anchor string) string {
 	return uri + "#" + anchor
 }
 
+// ResourceURI returns the canonical URI of the schema resource the schema belongs to: its own
+// $id, or that of its nearest enclosing schema with one, or the URI its document was compiled
+// under. It is empty for schemas compiled without any URI.
+func (s *Schema) ResourceURI() string {
+	return s.resource().uri
+}
+
+// Location returns the canonical location of the schema: the URI of its schema resource followed
+// by the JSON Pointer of the schema within the resource, such as
+// https://example.com/user.json#/properties/name, for tooling linking to the source documents.
+// Schemas without a resource URI are located relative to their root, as #/properties/name.
+func (s *Schema) Location() string {
+	resource := s.resource()
+	var tokens []string
+	for schema := s; schema != resource; schema = schema.parent {
+		for _, child := range childSchemas(schema.parent) {
+			if child.schema == schema {
+				tokens = append(tokens, child.pointer)
+				break
+			}
+		}
+	}
+	slices.Reverse(tokens)
+	return resource.uri + "#" + strings.Join(tokens, "")
+}
+
+// resource returns the nearest schema, from s up to its root, that has a URI, or the root.
+func (s *Schema) resource() *Schema {
+	resource := s
+	for resource.uri == "" && resource.parent != nil {
+		resource = resource.parent
+	}
+	return resource
+}
+
 // getRootSchema returns the highest-level parent schema, serving as the root in the schema tree.
 func (s *Schema) getRootSchema() *Schema {
 	if s.parent != nil {
@@ -365,6 +418,7 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 	aux := struct {
 		*Alias
 		RawConst json.RawMessage `json:"const,omitempty"` // Manually parse 'const' to handle specific edge cases.
+		RawEnum  json.RawMessage `json:"enum,omitempty"`  // Manually parse 'enum' to keep large numbers exact.
 	}{
 		Alias: (*Alias)(s),
 	}
@@ -375,26 +429,83 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 
 	if aux.RawConst != nil {
 		s.Const = &ConstValue{IsSet: true}
-		if err := json.Unmarshal(aux.RawConst, &s.Const.Value); err != nil {
+		if err := decodeExactJSON(aux.RawConst, &s.Const.Value); err != nil {
+			return err
+		}
+	}
+	if aux.RawEnum != nil {
+		var enum interface{}
+		if err := decodeExactJSON(aux.RawEnum, &enum); err != nil {
+			return err
+		}
+		values, ok := enum.([]interface{})
+		if !ok && enum != nil {
+			return ErrInvalidEnum
+		}
+		s.Enum = values
+	}
+
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+	for keyword, raw := range keywords {
+		if knownKeywords[keyword] {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
 			return err
 		}
+		if s.Extensions == nil {
+			s.Extensions = make(map[string]interface{})
+		}
+		s.Extensions[keyword] = value
 	}
 	return nil
 }
 
 // MarshalJSON ensures that Schema instances serialize correctly, particularly handling boolean schemas directly.
+// Extension keywords are written after the known keywords.
 func (s *Schema) MarshalJSON() ([]byte, error) {
 	if s.Boolean != nil {
 		return json.Marshal(s.Boolean)
 	}
 	type Alias Schema
-	return json.Marshal(&struct {
+	data, err := json.Marshal(&struct {
 		*Alias
 	}{
 		Alias: (*Alias)(s),
 	})
+	if err != nil || len(s.Extensions) == 0 {
+		return data, err
+	}
+
+	extensions, err := json.Marshal(s.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	out.Write(data[:len(data)-1])
+	if len(data) > 2 {
+		out.WriteByte(',')
+	}
+	out.Write(extensions[1:])
+	return out.Bytes(), nil
 }
 
+// knownKeywords holds the keywords mapped to fields of Schema; every other keyword is kept in Extensions.
+var knownKeywords = func() map[string]bool {
+	known := map[string]bool{}
+	t := reflect.TypeOf(Schema{})
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+	return known
+}()
+
 // SchemaMap represents a map of string keys to *Schema values, used primarily for properties and patternProperties.
 type SchemaMap map[string]*Schema
 
@@ -450,7 +561,7 @@ type ConstValue struct {
 // UnmarshalJSON handles unmarshaling a JSON value into the ConstValue type.
 func (cv *ConstValue) UnmarshalJSON(data []byte) error {
 	cv.IsSet = true // If UnmarshalJSON is called, the 'const' was set in JSON.
-	return json.Unmarshal(data, &cv.Value)
+	return decodeExactJSON(data, &cv.Value)
 }
 
 // MarshalJSON handles marshaling the ConstValue type back to JSON.
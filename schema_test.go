@@ -3,6 +3,7 @@ package jsonschema
 import (
 	"testing"
 
+	"github.com/goccy/go-json"
 	"github.com/test-go/testify/assert"
 )
 
@@ -65,3 +66,68 @@ func TestSchemaInitialization(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaExtensions(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"x-internal": true,
+		"properties": {"name": {"type": "string", "markdownDescription": "The **name**", "x-order": 1}}
+	}`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"x-internal": true}, schema.Extensions)
+	name := (*schema.Properties)["name"]
+	assert.Equal(t, "The **name**", name.Extensions["markdownDescription"])
+
+	data, err := json.Marshal(name)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type": "string", "markdownDescription": "The **name**", "x-order": 1}`, string(data))
+
+	data, err = json.Marshal(&Schema{Extensions: map[string]interface{}{"x-a": "b"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"x-a":"b"}`, string(data))
+
+	result := schema.Validate(map[string]interface{}{"name": "John"})
+	assert.NotContains(t, result.Annotations, "x-internal")
+
+	compiler.SetAnnotateExtensions(true)
+	result = schema.Validate(map[string]interface{}{"name": "John"})
+	assert.Equal(t, true, result.Annotations["x-internal"])
+}
+
+func TestSchemaLocation(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/user.json",
+		"properties": {
+			"name": {"type": "string"},
+			"tags": {"items": {"type": "string"}},
+			"address": {
+				"$id": "address.json",
+				"properties": {"a/b": {"type": "string"}}
+			}
+		},
+		"$defs": {"id": {"type": "integer"}}
+	}`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://example.com/user.json#", schema.Location())
+	assert.Equal(t, "https://example.com/user.json", schema.ResourceURI())
+
+	name := (*schema.Properties)["name"]
+	assert.Equal(t, "https://example.com/user.json#/properties/name", name.Location())
+	assert.Equal(t, "https://example.com/user.json", name.ResourceURI())
+	assert.Equal(t, "https://example.com/user.json#/properties/tags/items", (*schema.Properties)["tags"].Items.Location())
+	assert.Equal(t, "https://example.com/user.json#/$defs/id", schema.Defs["id"].Location())
+
+	address := (*schema.Properties)["address"]
+	assert.Equal(t, "https://example.com/address.json#", address.Location())
+	assert.Equal(t, "https://example.com/address.json#/properties/a~1b", (*address.Properties)["a/b"].Location())
+	assert.Equal(t, "https://example.com/address.json", (*address.Properties)["a/b"].ResourceURI())
+
+	anonymous, err := compiler.Compile([]byte(`{"properties": {"name": {"type": "string"}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "#/properties/name", (*anonymous.Properties)["name"].Location())
+	assert.Equal(t, "", (*anonymous.Properties)["name"].ResourceURI())
+}
@@ -0,0 +1,124 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is the severity of the errors of a keyword, see Compiler.SetSeverity.
+type Severity int
+
+const (
+	// SeverityError makes an instance invalid; it is the severity of every keyword by default.
+	SeverityError Severity = iota
+	// SeverityWarning reports a failed keyword in EvaluationResult.Warnings without invalidating the instance.
+	SeverityWarning
+	// SeverityInfo reports a failed keyword like SeverityWarning, for informational constraints.
+	SeverityInfo
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+	return "unknown"
+}
+
+// MarshalText encodes the severity as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes the name of a severity.
+func (s *Severity) UnmarshalText(text []byte) error {
+	for _, severity := range []Severity{SeverityError, SeverityWarning, SeverityInfo} {
+		if string(text) == severity.String() {
+			*s = severity
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownSeverity, text)
+}
+
+// SetSeverity sets the severity of the errors of the keywords matched by target in the schemas
+// compiled afterwards, so that new constraints can be soft-launched as warnings before they are
+// enforced. The target is either a keyword name, such as maxLength, matching the keyword in every
+// subschema, or the location of a keyword in a document, such as #/properties/name/maxLength or
+// https://example.com/user.json#/properties/name/maxLength, which takes precedence. A location
+// starting with # matches the keyword in any document.
+//
+// Errors of keywords below SeverityError are moved from the Errors to the Warnings of their result,
+// with their Severity set, and do not make the instance invalid. SeverityError restores the default.
+func (c *Compiler) SetSeverity(target string, severity Severity) *Compiler {
+	if c.Severities == nil {
+		c.Severities = make(map[string]Severity)
+	}
+	c.Severities[target] = severity
+	return c
+}
+
+// compileSeverities records the severities that apply to the keywords of each subschema of schema.
+func (c *Compiler) compileSeverities(schema *Schema) {
+	walkSchema(schema, func(pointer string, subschema *Schema) bool {
+		subschema.severities = nil
+		for target, severity := range c.Severities {
+			keyword, matched := c.severityKeyword(target, schema.uri, pointer)
+			if !matched {
+				continue
+			}
+			if _, set := subschema.severities[keyword]; set && !strings.Contains(target, "#") {
+				continue // Locations take precedence over keyword names.
+			}
+			if subschema.severities == nil {
+				subschema.severities = make(map[string]Severity)
+			}
+			subschema.severities[keyword] = severity
+		}
+		return true
+	})
+}
+
+// severityKeyword returns the keyword matched by target in the subschema at pointer of the
+// document at uri, and whether target matches a keyword of that subschema.
+func (c *Compiler) severityKeyword(target, uri, pointer string) (string, bool) {
+	targetURI, location, isLocation := strings.Cut(target, "#")
+	if !isLocation {
+		return target, true
+	}
+	if targetURI != "" && targetURI != uri {
+		return "", false
+	}
+	index := strings.LastIndex(location, "/")
+	if index < 0 || location[:index] != pointer {
+		return "", false
+	}
+	return unescapeJSONPointer(location[index+1:]), true
+}
+
+// applySeverities moves the errors of the keywords below SeverityError to the warnings of the
+// result, which becomes valid when no other error is left.
+func (e *EvaluationResult) applySeverities(severities map[string]Severity) {
+	demoted := false
+	for keyword, err := range e.Errors {
+		severity := severities[keyword]
+		if severity == SeverityError {
+			continue
+		}
+		err.Severity = severity
+		if e.Warnings == nil {
+			e.Warnings = make(map[string]*EvaluationError)
+		}
+		e.Warnings[keyword] = err
+		delete(e.Errors, keyword)
+		demoted = true
+	}
+	if demoted && len(e.Errors) == 0 {
+		e.Valid = true
+	}
+}
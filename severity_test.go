@@ -0,0 +1,80 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSeverity(t *testing.T) {
+	source := []byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "maxLength": 5},
+			"code": {"type": "string", "maxLength": 3}
+		}
+	}`)
+	compiler := NewCompiler().SetSeverity("#/properties/name/maxLength", SeverityWarning)
+	schema, err := compiler.Compile(source)
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{"name": "Alexandra", "code": "AB"})
+	assert.True(t, result.IsValid(), "warnings do not invalidate the instance")
+	list := result.ToList()
+	var warnings []string
+	for _, detail := range list.Details {
+		for keyword, message := range detail.Warnings {
+			warnings = append(warnings, detail.InstanceLocation+" "+keyword+": "+message)
+		}
+	}
+	assert.Equal(t, []string{"/name maxLength: Value should be at most 5 characters"}, warnings)
+
+	assert.False(t, schema.Validate(map[string]interface{}{"name": "Al", "code": "ABCD"}).IsValid(), "other locations are enforced")
+	assert.False(t, schema.Validate(map[string]interface{}{"name": 1}).IsValid(), "other keywords are enforced")
+}
+
+func TestSetSeverityByKeyword(t *testing.T) {
+	compiler := NewCompiler().
+		SetSeverity("maxLength", SeverityInfo).
+		SetSeverity("https://example.com/user.json#/properties/code/maxLength", SeverityError)
+	schema, err := compiler.Compile([]byte(`{
+		"properties": {
+			"name": {"maxLength": 5},
+			"items": {"items": {"maxLength": 1}},
+			"code": {"maxLength": 3}
+		}
+	}`), "https://example.com/user.json")
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{"name": "Alexandra", "items": []interface{}{"ab"}})
+	assert.True(t, result.IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"code": "ABCD"}).IsValid(), "locations take precedence over keywords")
+
+	var detail *EvaluationResult
+	for _, d := range result.Details {
+		if d.InstanceLocation == "/name" {
+			detail = d
+		}
+	}
+	require.NotNil(t, detail)
+	require.Contains(t, detail.Warnings, "maxLength")
+	assert.Equal(t, SeverityInfo, detail.Warnings["maxLength"].Severity)
+	assert.Empty(t, detail.Errors)
+
+	data, err := json.Marshal(detail.Warnings["maxLength"])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"severity":"info"`)
+
+	overlay, err := compiler.Overlay().Compile([]byte(`{"maxLength": 1}`))
+	require.NoError(t, err)
+	assert.True(t, overlay.Validate("ab").IsValid())
+}
+
+func TestSeverityText(t *testing.T) {
+	var severity Severity
+	require.NoError(t, severity.UnmarshalText([]byte("warning")))
+	assert.Equal(t, SeverityWarning, severity)
+	assert.ErrorIs(t, severity.UnmarshalText([]byte("fatal")), ErrUnknownSeverity)
+}
@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+// SchemaStats holds complexity metrics of a schema, for enforcing budgets on schemas submitted by
+// untrusted parties.
+type SchemaStats struct {
+	Subschemas     int            `json:"subschemas"`     // Number of schemas, including the root and boolean subschemas.
+	BooleanSchemas int            `json:"booleanSchemas"` // Number of boolean subschemas, true or false.
+	MaxDepth       int            `json:"maxDepth"`       // Deepest nesting of subschemas; the root is at depth 0.
+	Keywords       map[string]int `json:"keywords"`       // Number of subschemas using each evaluated keyword.
+	Regexes        int            `json:"regexes"`        // Regular expressions in pattern and patternProperties.
+	RemoteRefs     int            `json:"remoteRefs"`     // References to schemas of other documents.
+	EstimatedCost  int            `json:"estimatedCost"`  // Relative, unitless estimate of the cost of one evaluation.
+}
+
+// keywordCosts weighs the keywords that are notably more expensive than a simple comparison.
+// Every other keyword costs 1.
+var keywordCosts = map[string]int{
+	"pattern":               5, // Regular expression match.
+	"patternProperties":     5, // Regular expression match per property and pattern.
+	"format":                3, // Format checkers parse the value.
+	"uniqueItems":           5, // Compares items pairwise.
+	"contains":              3, // Evaluates every item.
+	"unevaluatedItems":      3, // Collects annotations from every applicator.
+	"unevaluatedProperties": 3, // Collects annotations from every applicator.
+	"$dynamicRef":           2, // Walks the dynamic scope.
+	"contentSchema":         5, // Decodes and parses the content.
+}
+
+// Stats returns complexity metrics of s: the number of subschemas, their maximum depth, a
+// histogram of evaluated keywords, the number of regular expressions and remote references, and
+// an estimated evaluation cost.
+//
+// The estimate sums the cost of the keywords of every subschema, counting regular expressions and
+// other expensive keywords more heavily. It does not depend on instances and does not follow
+// references, so it is meant for comparing schemas against a budget rather than predicting time.
+func (s *Schema) Stats() *SchemaStats {
+	stats := &SchemaStats{Keywords: map[string]int{}}
+	root := s.getRootSchema()
+
+	walkSchema(s, func(pointer string, schema *Schema) bool {
+		stats.Subschemas++
+		if depth := schemaDepth(pointer); depth > stats.MaxDepth {
+			stats.MaxDepth = depth
+		}
+		if schema.IsBoolean() {
+			stats.BooleanSchemas++
+			return false
+		}
+
+		for _, keyword := range evaluationOrder(schema) {
+			stats.Keywords[keyword]++
+			cost, ok := keywordCosts[keyword]
+			if !ok {
+				cost = 1
+			}
+			if keyword == "patternProperties" {
+				cost *= len(*schema.PatternProperties)
+			}
+			stats.EstimatedCost += cost
+		}
+
+		if schema.Pattern != nil {
+			stats.Regexes++
+		}
+		if schema.PatternProperties != nil {
+			stats.Regexes += len(*schema.PatternProperties)
+		}
+		if isRemoteRef(schema.Ref, schema.ResolvedRef, root) {
+			stats.RemoteRefs++
+		}
+		if isRemoteRef(schema.DynamicRef, schema.ResolvedDynamicRef, root) {
+			stats.RemoteRefs++
+		}
+		return true
+	})
+	return stats
+}
+
+// schemaDepth returns the nesting depth of the subschema at a pointer produced by walkSchema.
+func schemaDepth(pointer string) int {
+	depth := 0
+	tokens := strings.Split(pointer, "/")[1:]
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "$defs", "allOf", "anyOf", "oneOf", "dependentSchemas", "prefixItems", "properties", "patternProperties":
+			i++ // The next token is the index or name of the subschema.
+		}
+		depth++
+	}
+	return depth
+}
+
+// isRemoteRef reports whether a reference points to a document other than root's. Unresolved
+// references that are not fragments are counted as remote.
+func isRemoteRef(ref string, resolved *Schema, root *Schema) bool {
+	if ref == "" {
+		return false
+	}
+	if resolved == nil {
+		return !strings.HasPrefix(ref, "#")
+	}
+	return resolved.getRootSchema() != root
+}
@@ -0,0 +1,51 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{"$id": "https://example.com/name.json", "type": "string"}`))
+	require.NoError(t, err)
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"$ref": "https://example.com/name.json"},
+			"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}, "uniqueItems": true}
+		},
+		"patternProperties": {"^x-": true, "^y-": {"not": {"type": "null"}}}
+	}`))
+	require.NoError(t, err)
+
+	stats := schema.Stats()
+	assert.Equal(t, 7, stats.Subschemas)
+	assert.Equal(t, 2, stats.MaxDepth)
+	assert.Equal(t, 3, stats.Regexes)
+	assert.Equal(t, 1, stats.RemoteRefs)
+	assert.Equal(t, map[string]int{
+		"type":              4,
+		"properties":        1,
+		"patternProperties": 1,
+		"$ref":              1,
+		"items":             1,
+		"uniqueItems":       1,
+		"pattern":           1,
+		"not":               1,
+	}, stats.Keywords)
+	// Keywords cost 1, except patternProperties (5 per pattern), uniqueItems and pattern (5).
+	assert.Equal(t, 4+1+10+1+1+5+5+1, stats.EstimatedCost)
+}
+
+func TestStatsLocalRefs(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"$defs": {"a": {"type": "integer"}}, "$ref": "#/$defs/a"}`))
+	require.NoError(t, err)
+
+	stats := schema.Stats()
+	assert.Equal(t, 0, stats.RemoteRefs)
+	assert.Equal(t, 2, stats.Subschemas)
+	assert.Equal(t, 1, stats.MaxDepth)
+}
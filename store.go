@@ -0,0 +1,238 @@
+package jsonschema
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+// StoreScheme is the URI scheme of the schemas of a SchemaStore. Version name@version of a
+// schema is compiled with the URI store:name@version, which other stored schemas can reference.
+const StoreScheme = "store"
+
+// Labels resolving to the stable and canary versions of a stored schema in name@label references.
+const (
+	StableLabel = "stable"
+	CanaryLabel = "canary"
+)
+
+// SchemaStore manages the versions of named schemas for safe contract rollouts. Each name has
+// immutable versions, a stable version, and optionally a canary version served to a share of the
+// consumers; consumers can also be pinned to a version.
+//
+// Schemas reference versions added before them by exact version with $ref "store:name@version",
+// since compiled references are fixed; labels are resolved by Resolve and SchemaFor only. A
+// SchemaStore is safe for concurrent use.
+type SchemaStore struct {
+	compiler *Compiler
+	mu       sync.RWMutex
+	schemas  map[string]*storedSchema
+	pins     map[string]map[string]string // Pinned version of each name, by consumer.
+}
+
+// storedSchema holds the versions and rollout state of a named schema.
+type storedSchema struct {
+	versions map[string]*Schema
+	order    []string // Versions in the order they were added.
+	stable   string
+	canary   string
+	percent  int // Share of the consumers served the canary version.
+}
+
+// NewSchemaStore creates a SchemaStore compiling its schemas with compiler. The compiler must
+// not be used to compile other schemas concurrently with Add.
+func NewSchemaStore(compiler *Compiler) *SchemaStore {
+	return &SchemaStore{
+		compiler: compiler,
+		schemas:  make(map[string]*storedSchema),
+		pins:     make(map[string]map[string]string),
+	}
+}
+
+// Add compiles a new version of the schema name. Versions are immutable: adding an existing
+// version fails with ErrSchemaVersionExists. The first version of a name becomes its stable version.
+func (st *SchemaStore) Add(name, version string, data []byte) (*Schema, error) {
+	if name == "" || version == "" || strings.Contains(name, "@") || version == StableLabel || version == CanaryLabel {
+		return nil, fmt.Errorf("%w: %s@%s", ErrInvalidStoreRef, name, version)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	stored := st.schemas[name]
+	if stored == nil {
+		stored = &storedSchema{versions: make(map[string]*Schema)}
+		st.schemas[name] = stored
+	}
+	if _, exists := stored.versions[version]; exists {
+		return nil, fmt.Errorf("%w: %s@%s", ErrSchemaVersionExists, name, version)
+	}
+
+	uri := storeURI(name, version)
+	schema, err := st.compiler.Compile(data, uri)
+	if err != nil {
+		if len(stored.versions) == 0 {
+			delete(st.schemas, name)
+		}
+		return nil, err
+	}
+	st.compiler.SetSchema(uri, schema)
+
+	stored.versions[version] = schema
+	stored.order = append(stored.order, version)
+	if stored.stable == "" {
+		stored.stable = version
+	}
+	return schema, nil
+}
+
+// Versions returns the versions of the schema name, in the order they were added.
+func (st *SchemaStore) Versions(name string) []string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	if stored := st.schemas[name]; stored != nil {
+		return append([]string(nil), stored.order...)
+	}
+	return nil
+}
+
+// SetStable makes version the stable version of the schema name.
+func (st *SchemaStore) SetStable(name, version string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	stored, err := st.version(name, version)
+	if err != nil {
+		return err
+	}
+	stored.stable = version
+	if stored.canary == version {
+		stored.canary, stored.percent = "", 0
+	}
+	return nil
+}
+
+// SetCanary makes version the canary version of the schema name, served by SchemaFor to percent
+// of the consumers, chosen deterministically by consumer name. A percent of 0 serves the canary
+// only through name@canary references and pins.
+func (st *SchemaStore) SetCanary(name, version string, percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("%w: canary percent %d", ErrInvalidStoreRef, percent)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	stored, err := st.version(name, version)
+	if err != nil {
+		return err
+	}
+	stored.canary, stored.percent = version, percent
+	return nil
+}
+
+// Promote makes the canary version of the schema name its stable version.
+func (st *SchemaStore) Promote(name string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	stored := st.schemas[name]
+	if stored == nil || stored.canary == "" {
+		return fmt.Errorf("%w: %s@%s", ErrSchemaVersionNotFound, name, CanaryLabel)
+	}
+	stored.stable, stored.canary, stored.percent = stored.canary, "", 0
+	return nil
+}
+
+// Pin serves version of the schema name to consumer, whatever the stable and canary versions.
+func (st *SchemaStore) Pin(consumer, name, version string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if _, err := st.version(name, version); err != nil {
+		return err
+	}
+	if st.pins[consumer] == nil {
+		st.pins[consumer] = make(map[string]string)
+	}
+	st.pins[consumer][name] = version
+	return nil
+}
+
+// Unpin removes the pin of consumer to a version of the schema name.
+func (st *SchemaStore) Unpin(consumer, name string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	delete(st.pins[consumer], name)
+}
+
+// Resolve returns the schema referenced by ref: name@version for an exact version, name@stable or
+// name alone for the stable version, and name@canary for the canary version.
+func (st *SchemaStore) Resolve(ref string) (*Schema, error) {
+	name, label, found := strings.Cut(ref, "@")
+	if !found {
+		label = StableLabel
+	}
+
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.resolve(name, label)
+}
+
+// SchemaFor returns the version of the schema name served to consumer: the pinned version if
+// any, the canary version if consumer is in its share, and the stable version otherwise.
+func (st *SchemaStore) SchemaFor(consumer, name string) (*Schema, error) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	if version, ok := st.pins[consumer][name]; ok {
+		return st.resolve(name, version)
+	}
+	if stored := st.schemas[name]; stored != nil && stored.canary != "" && canaryBucket(consumer, name) < stored.percent {
+		return st.resolve(name, CanaryLabel)
+	}
+	return st.resolve(name, StableLabel)
+}
+
+// resolve returns the schema of name at a version or label. The caller holds mu.
+func (st *SchemaStore) resolve(name, label string) (*Schema, error) {
+	stored := st.schemas[name]
+	if stored != nil {
+		version := label
+		switch label {
+		case StableLabel:
+			version = stored.stable
+		case CanaryLabel:
+			version = stored.canary
+		}
+		if schema, ok := stored.versions[version]; ok {
+			return schema, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s@%s", ErrSchemaVersionNotFound, name, label)
+}
+
+// version returns the stored schema name if it has version. The caller holds mu.
+func (st *SchemaStore) version(name, version string) (*storedSchema, error) {
+	stored := st.schemas[name]
+	if stored == nil || stored.versions[version] == nil {
+		return nil, fmt.Errorf("%w: %s@%s", ErrSchemaVersionNotFound, name, version)
+	}
+	return stored, nil
+}
+
+// storeURI returns the URI of version of the schema name.
+func storeURI(name, version string) string {
+	return StoreScheme + ":" + name + "@" + version
+}
+
+// canaryBucket assigns consumer to one of 100 buckets for the canary of the schema name.
+func canaryBucket(consumer, name string) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name + "\x00" + consumer))
+	return int(hash.Sum32() % 100)
+}
@@ -0,0 +1,98 @@
+package jsonschema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaStoreVersions(t *testing.T) {
+	store := NewSchemaStore(NewCompiler())
+	v1, err := store.Add("user", "1.0.0", []byte(`{"required": ["name"]}`))
+	require.NoError(t, err)
+	v2, err := store.Add("user", "2.0.0", []byte(`{"required": ["name", "email"]}`))
+	require.NoError(t, err)
+
+	_, err = store.Add("user", "1.0.0", []byte(`{}`))
+	require.ErrorIs(t, err, ErrSchemaVersionExists)
+	_, err = store.Add("user", "stable", []byte(`{}`))
+	require.ErrorIs(t, err, ErrInvalidStoreRef)
+	_, err = store.Add("order", "1", []byte(`{"type": `))
+	require.Error(t, err)
+	assert.Nil(t, store.Versions("order"))
+
+	assert.Equal(t, []string{"1.0.0", "2.0.0"}, store.Versions("user"))
+
+	for ref, expected := range map[string]*Schema{
+		"user":        v1,
+		"user@stable": v1,
+		"user@1.0.0":  v1,
+		"user@2.0.0":  v2,
+	} {
+		schema, err := store.Resolve(ref)
+		require.NoError(t, err, ref)
+		assert.Same(t, expected, schema, ref)
+	}
+	_, err = store.Resolve("user@canary")
+	require.ErrorIs(t, err, ErrSchemaVersionNotFound)
+	_, err = store.Resolve("user@3.0.0")
+	require.ErrorIs(t, err, ErrSchemaVersionNotFound)
+
+	require.NoError(t, store.SetStable("user", "2.0.0"))
+	schema, err := store.Resolve("user")
+	require.NoError(t, err)
+	assert.Same(t, v2, schema)
+}
+
+func TestSchemaStoreRollout(t *testing.T) {
+	store := NewSchemaStore(NewCompiler())
+	v1, err := store.Add("user", "1", []byte(`{"required": ["name"]}`))
+	require.NoError(t, err)
+	v2, err := store.Add("user", "2", []byte(`{"required": ["name", "email"]}`))
+	require.NoError(t, err)
+
+	require.NoError(t, store.SetCanary("user", "2", 30))
+	canaries := 0
+	for i := 0; i < 1000; i++ {
+		consumer := fmt.Sprintf("service-%d", i)
+		schema, err := store.SchemaFor(consumer, "user")
+		require.NoError(t, err)
+		if schema == v2 {
+			canaries++
+		}
+		again, _ := store.SchemaFor(consumer, "user")
+		assert.Same(t, schema, again, "consumers stay in their cohort")
+	}
+	assert.InDelta(t, 300, canaries, 60)
+
+	require.NoError(t, store.Pin("billing", "user", "1"))
+	require.NoError(t, store.Promote("user"))
+	schema, err := store.SchemaFor("billing", "user")
+	require.NoError(t, err)
+	assert.Same(t, v1, schema, "pinned consumers keep their version")
+	schema, err = store.SchemaFor("checkout", "user")
+	require.NoError(t, err)
+	assert.Same(t, v2, schema)
+
+	store.Unpin("billing", "user")
+	schema, err = store.SchemaFor("billing", "user")
+	require.NoError(t, err)
+	assert.Same(t, v2, schema)
+
+	require.ErrorIs(t, store.Promote("user"), ErrSchemaVersionNotFound)
+	require.ErrorIs(t, store.Pin("billing", "user", "9"), ErrSchemaVersionNotFound)
+	require.ErrorIs(t, store.SetCanary("user", "1", 101), ErrInvalidStoreRef)
+}
+
+func TestSchemaStoreReferences(t *testing.T) {
+	store := NewSchemaStore(NewCompiler())
+	_, err := store.Add("address", "1", []byte(`{"required": ["city"]}`))
+	require.NoError(t, err)
+	user, err := store.Add("user", "1", []byte(`{"properties": {"address": {"$ref": "store:address@1"}}}`))
+	require.NoError(t, err)
+
+	assert.False(t, user.Validate(map[string]interface{}{"address": map[string]interface{}{}}).IsValid())
+	assert.True(t, user.Validate(map[string]interface{}{"address": map[string]interface{}{"city": "Oslo"}}).IsValid())
+}
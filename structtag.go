@@ -0,0 +1,178 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// StructTagKey is the key of the struct tag holding the constraints of a field, for example
+//
+//	Name  string `json:"name" jsonschema:"required,minLength=1,pattern=^[a-z]+$"`
+//	Email string `json:"email" jsonschema:"format=email"`
+const StructTagKey = "jsonschema"
+
+// StructTag is the parsed form of a `jsonschema` struct tag, shared by the code deriving schemas
+// from Go types and the code validating structs, so that constraints can live next to the types.
+type StructTag struct {
+	Keywords map[string]interface{} // Keywords of the field's schema, in generic JSON form.
+	Required bool                   // Whether the field is listed in the required keywords of its struct.
+	Ignore   bool                   // Whether the field is left out of the schema, written as "-".
+}
+
+// Keywords accepted in struct tags, grouped by the form of their value.
+var (
+	structTagIntegers = []string{"minLength", "maxLength", "minItems", "maxItems", "minContains", "maxContains", "minProperties", "maxProperties"}
+	structTagNumbers  = []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"}
+	structTagStrings  = []string{"pattern", "format", "title", "description", "contentEncoding", "contentMediaType"}
+	structTagBooleans = []string{"uniqueItems", "deprecated", "readOnly", "writeOnly"}
+	structTagLists    = []string{"type", "enum", "examples"}
+	structTagValues   = []string{"const", "default"}
+)
+
+// ParseStructTag parses the value of a `jsonschema` struct tag.
+//
+// The tag is a comma-separated list of entries. An entry is either a keyword and its value,
+// written keyword=value, or one of the flags required, uniqueItems, deprecated, readOnly and
+// writeOnly. A tag of "-" ignores the field. Commas and backslashes in values are escaped with a
+// backslash, as in pattern=^[a-z]{1\,8}$; since struct tag values are quoted Go strings, that
+// backslash is itself written twice in the struct tag.
+//
+// Length and count keywords take non-negative integers, numeric keywords take decimal numbers and
+// pattern must be a valid regular expression. The values of const and default, and the values of
+// enum and examples separated by |, are decoded as JSON when they are valid JSON and taken as
+// strings otherwise; type takes one or more type names separated by |.
+func ParseStructTag(tag string) (*StructTag, error) {
+	parsed := &StructTag{Keywords: map[string]interface{}{}}
+	if tag == "-" {
+		parsed.Ignore = true
+		return parsed, nil
+	}
+
+	for _, entry := range splitStructTag(tag, ',') {
+		if entry == "" {
+			continue
+		}
+		keyword, value, hasValue := strings.Cut(entry, "=")
+		if !hasValue {
+			switch {
+			case keyword == "required":
+				parsed.Required = true
+			case containsString(structTagBooleans, keyword):
+				parsed.Keywords[keyword] = true
+			default:
+				return nil, fmt.Errorf("%w: %q needs a value", ErrInvalidStructTag, keyword)
+			}
+			continue
+		}
+
+		parsedValue, err := parseStructTagValue(keyword, value)
+		if err != nil {
+			return nil, err
+		}
+		parsed.Keywords[keyword] = parsedValue
+	}
+	return parsed, nil
+}
+
+// parseStructTagValue converts the value of a keyword=value entry to its generic JSON form.
+func parseStructTagValue(keyword, value string) (interface{}, error) {
+	switch {
+	case containsString(structTagIntegers, keyword):
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a non-negative integer, got %q", ErrInvalidStructTag, keyword, value)
+		}
+		return json.Number(strconv.FormatUint(n, 10)), nil
+
+	case containsString(structTagNumbers, keyword):
+		if _, ok := new(big.Rat).SetString(value); !ok || strings.Contains(value, "/") {
+			return nil, fmt.Errorf("%w: %s must be a number, got %q", ErrInvalidStructTag, keyword, value)
+		}
+		return json.Number(value), nil
+
+	case containsString(structTagStrings, keyword):
+		if keyword == "pattern" {
+			if _, err := regexp.Compile(value); err != nil {
+				return nil, fmt.Errorf("%w: invalid pattern %q: %w", ErrInvalidStructTag, value, err)
+			}
+		}
+		return value, nil
+
+	case containsString(structTagBooleans, keyword):
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be true or false, got %q", ErrInvalidStructTag, keyword, value)
+		}
+		return b, nil
+
+	case containsString(structTagLists, keyword):
+		items := splitStructTag(value, '|')
+		values := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			if keyword == "type" {
+				if !containsString([]string{"null", "boolean", "object", "array", "number", "string", "integer"}, item) {
+					return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidStructTag, item)
+				}
+				values = append(values, item)
+				continue
+			}
+			values = append(values, structTagLiteral(item))
+		}
+		if keyword == "type" && len(values) == 1 {
+			return values[0], nil
+		}
+		return values, nil
+
+	case containsString(structTagValues, keyword):
+		return structTagLiteral(value), nil
+
+	case keyword == "required":
+		return nil, fmt.Errorf("%w: required is a flag and takes no value", ErrInvalidStructTag)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownStructTagKeyword, keyword)
+}
+
+// structTagLiteral decodes value as JSON, falling back to the value as a string.
+func structTagLiteral(value string) interface{} {
+	var decoded interface{}
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil || decoder.More() {
+		return value
+	}
+	return decoded
+}
+
+// splitStructTag splits a tag at each unescaped separator and removes the escaping backslashes.
+func splitStructTag(tag string, separator byte) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && (tag[i+1] == separator || tag[i+1] == '\\'):
+			i++
+			part.WriteByte(tag[i])
+		case tag[i] == separator:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(tag[i])
+		}
+	}
+	return append(parts, part.String())
+}
+
+// Schema returns the schema holding the keywords of the tag. The schema is not compiled; it is
+// meant to be merged into the schema of the field.
+func (t *StructTag) Schema() (*Schema, error) {
+	data, err := json.Marshal(t.Keywords)
+	if err != nil {
+		return nil, err
+	}
+	return newSchema(data)
+}
@@ -0,0 +1,61 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStructTag(t *testing.T) {
+	type user struct {
+		Name string `json:"name" jsonschema:"required,minLength=1,pattern=^[a-z]{1\\,8}$"`
+	}
+	field, _ := reflect.TypeOf(user{}).FieldByName("Name")
+
+	tag, err := ParseStructTag(field.Tag.Get(StructTagKey))
+	require.NoError(t, err)
+	assert.True(t, tag.Required)
+	assert.False(t, tag.Ignore)
+	assert.Equal(t, map[string]interface{}{
+		"minLength": json.Number("1"),
+		"pattern":   "^[a-z]{1,8}$",
+	}, tag.Keywords)
+
+	tag, err = ParseStructTag(`type=string|null,format=email,enum="a"|b|1,default=null,uniqueItems,maximum=2.5`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"type":        []interface{}{"string", "null"},
+		"format":      "email",
+		"enum":        []interface{}{"a", "b", json.Number("1")},
+		"default":     nil,
+		"uniqueItems": true,
+		"maximum":     json.Number("2.5"),
+	}, tag.Keywords)
+
+	tag, err = ParseStructTag("-")
+	require.NoError(t, err)
+	assert.True(t, tag.Ignore)
+}
+
+func TestParseStructTagErrors(t *testing.T) {
+	for _, tag := range []string{"minLength=-1", "minimum=1/3", "pattern=[", "type=text", "format", "required=true"} {
+		_, err := ParseStructTag(tag)
+		assert.ErrorIs(t, err, ErrInvalidStructTag, tag)
+	}
+	_, err := ParseStructTag("minSize=1")
+	assert.ErrorIs(t, err, ErrUnknownStructTagKeyword)
+}
+
+func TestStructTagSchema(t *testing.T) {
+	tag, err := ParseStructTag("minLength=2,format=email,maximum=10")
+	require.NoError(t, err)
+	schema, err := tag.Schema()
+	require.NoError(t, err)
+	require.NotNil(t, schema.MinLength)
+	assert.Equal(t, 2.0, *schema.MinLength)
+	assert.Equal(t, "email", *schema.Format)
+	assert.Equal(t, "10", FormatRat(schema.Maximum))
+}
@@ -0,0 +1,513 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// SubsumptionResult is the outcome of Subsumes or Equivalent.
+type SubsumptionResult struct {
+	// Holds is true when the relation was proven. It is false when a counterexample was found
+	// or when part of the schemas could not be decided.
+	Holds bool `json:"holds"`
+
+	// Counterexamples sketch instances accepted by one schema and rejected by the other.
+	Counterexamples []Counterexample `json:"counterexamples,omitempty"`
+
+	// Undecided lists the places where the schemas use keywords the checker cannot reason
+	// about, such as not, if or patternProperties.
+	Undecided []string `json:"undecided,omitempty"`
+}
+
+// Counterexample sketches instances that break a subsumption.
+type Counterexample struct {
+	// InstanceLocation is where the offending value sits in the instance, as a JSON Pointer
+	// in which "*" stands for any array index or any property not declared by the schemas.
+	InstanceLocation string `json:"instanceLocation"`
+
+	// Reason describes the values accepted by one schema and rejected by the other.
+	Reason string `json:"reason"`
+}
+
+// Subsumes decides, where tractable, whether every instance valid against b is also valid
+// against a, for example whether a new version a of a registry schema still accepts all data
+// written under the previous version b.
+//
+// The check compares the keywords of both schemas structurally, following $ref, allOf and the
+// alternatives of anyOf and oneOf. Enumerated values of b (enum, const) are checked exactly by
+// validating them against a. Keywords that cannot be compared structurally are reported in
+// Undecided rather than guessed; the result then does not hold.
+func Subsumes(a, b *Schema) *SubsumptionResult {
+	c := &subsumption{wider: "a", narrower: "b", visited: make(map[[2]*Schema]bool)}
+	c.check(a, b, "", true)
+	return c.result()
+}
+
+// Equivalent decides, where tractable, whether a and b accept exactly the same instances.
+// Counterexamples and undecided places of both directions are reported.
+func Equivalent(a, b *Schema) *SubsumptionResult {
+	forward := &subsumption{wider: "a", narrower: "b", visited: make(map[[2]*Schema]bool)}
+	forward.check(a, b, "", true)
+	backward := &subsumption{wider: "b", narrower: "a", visited: make(map[[2]*Schema]bool)}
+	backward.check(b, a, "", true)
+
+	forward.counterexamples = append(forward.counterexamples, backward.counterexamples...)
+	forward.undecided = append(forward.undecided, backward.undecided...)
+	return forward.result()
+}
+
+// subsumption checks that the wider schema accepts every instance of the narrower one.
+type subsumption struct {
+	wider, narrower string // Names of the schemas used in reasons.
+	counterexamples []Counterexample
+	undecided       []string
+	visited         map[[2]*Schema]bool
+}
+
+// result builds the public result with deterministic ordering.
+func (c *subsumption) result() *SubsumptionResult {
+	sort.SliceStable(c.counterexamples, func(i, j int) bool {
+		return c.counterexamples[i].InstanceLocation < c.counterexamples[j].InstanceLocation
+	})
+	sort.Strings(c.undecided)
+	return &SubsumptionResult{
+		Holds:           len(c.counterexamples) == 0 && len(c.undecided) == 0,
+		Counterexamples: c.counterexamples,
+		Undecided:       dedupeStrings(c.undecided),
+	}
+}
+
+// fail records that the narrower schema accepts values the wider one rejects. When the narrower
+// schema was approximated, the finding may be spurious and is recorded as undecided instead.
+func (c *subsumption) fail(location string, exact bool, format string, args ...interface{}) {
+	reason := fmt.Sprintf(format, args...)
+	if !exact {
+		c.undecide(location, "possibly "+reason)
+		return
+	}
+	c.counterexamples = append(c.counterexamples, Counterexample{InstanceLocation: location, Reason: reason})
+}
+
+// undecide records a place the checker cannot decide.
+func (c *subsumption) undecide(location, reason string) {
+	if location == "" {
+		location = "/"
+	}
+	c.undecided = append(c.undecided, location+": "+reason)
+}
+
+// try runs check in isolation and reports whether it held.
+func (c *subsumption) try(wider, narrower *Schema, location string) bool {
+	sub := &subsumption{wider: c.wider, narrower: c.narrower, visited: c.visited}
+	sub.check(wider, narrower, location, true)
+	return len(sub.counterexamples) == 0 && len(sub.undecided) == 0
+}
+
+// check verifies that every instance valid against n at location is valid against w.
+// exact is false when n stands for a superset of the narrower schema's instances.
+func (c *subsumption) check(w, n *Schema, location string, exact bool) {
+	if w == nil || (w.Boolean != nil && *w.Boolean) {
+		return
+	}
+	if n != nil && n.Boolean != nil && !*n.Boolean {
+		return
+	}
+	if w.Boolean != nil {
+		c.fail(location, exact, "%s accepts values, %s accepts none", c.narrower, c.wider)
+		return
+	}
+	if n == nil {
+		n = &Schema{}
+	}
+
+	pair := [2]*Schema{w, n}
+	if c.visited[pair] {
+		return // Assume recursive references hold; any difference shows up on another path.
+	}
+	c.visited[pair] = true
+	defer delete(c.visited, pair)
+
+	// Unwrap the narrower schema when it only delegates to another schema.
+	if n.Boolean == nil && !hasOwnAssertions(n) {
+		switch {
+		case n.ResolvedRef != nil && len(n.AllOf) == 0 && len(n.AnyOf) == 0 && len(n.OneOf) == 0:
+			c.check(w, n.ResolvedRef, location, exact)
+			return
+		case n.ResolvedRef == nil && len(n.AllOf) == 1 && len(n.AnyOf) == 0 && len(n.OneOf) == 0:
+			c.check(w, n.AllOf[0], location, exact)
+			return
+		case n.ResolvedRef == nil && len(n.AllOf) == 0 && len(n.AnyOf) > 0 && len(n.OneOf) == 0:
+			for _, branch := range n.AnyOf {
+				c.check(w, branch, location, exact)
+			}
+			return
+		case n.ResolvedRef == nil && len(n.AllOf) == 0 && len(n.AnyOf) == 0 && len(n.OneOf) > 0:
+			for _, branch := range n.OneOf {
+				c.check(w, branch, location, false)
+			}
+			return
+		}
+	}
+
+	// Enumerated instances are checked exactly.
+	if values, ok := enumeratedValues(n); ok {
+		for _, value := range values {
+			if n.Validate(value).IsValid() && !w.Validate(value).IsValid() {
+				c.fail(location, true, "%s accepts %s, %s rejects it", c.narrower, describeValue(value), c.wider)
+			}
+		}
+		return
+	}
+
+	// Constraints of the narrower schema that are ignored below only shrink its instances,
+	// so ignoring them keeps a proof sound but may produce spurious counterexamples.
+	if n.ResolvedRef != nil || len(n.AllOf) > 0 || len(n.AnyOf) > 0 || len(n.OneOf) > 0 || n.Not != nil ||
+		n.If != nil || n.DependentSchemas != nil || n.Contains != nil || n.UnevaluatedItems != nil ||
+		n.UnevaluatedProperties != nil || n.PatternProperties != nil || n.PropertyNames != nil ||
+		n.ResolvedDynamicRef != nil {
+		exact = false
+	}
+
+	// Composition in the wider schema.
+	if w.ResolvedRef != nil {
+		c.check(w.ResolvedRef, n, location, exact)
+	}
+	for _, sub := range w.AllOf {
+		c.check(sub, n, location, exact)
+	}
+	if len(w.AnyOf) > 0 {
+		c.checkAlternatives(w.AnyOf, n, location, "anyOf")
+	}
+	if len(w.OneOf) == 1 {
+		c.check(w.OneOf[0], n, location, exact)
+	} else if len(w.OneOf) > 1 {
+		c.undecide(location, fmt.Sprintf("%s uses oneOf with several alternatives", c.wider))
+	}
+	for keyword, present := range map[string]bool{
+		"not":                   w.Not != nil,
+		"if":                    w.If != nil,
+		"dependentSchemas":      w.DependentSchemas != nil,
+		"contains":              w.Contains != nil,
+		"unevaluatedItems":      w.UnevaluatedItems != nil,
+		"unevaluatedProperties": w.UnevaluatedProperties != nil,
+		"propertyNames":         w.PropertyNames != nil,
+		"$dynamicRef":           w.ResolvedDynamicRef != nil,
+	} {
+		if present {
+			c.undecide(location, fmt.Sprintf("%s uses %s", c.wider, keyword))
+		}
+	}
+
+	if w.Const != nil || len(w.Enum) > 0 {
+		// The narrower schema has no finite set of instances, so it accepts values outside the enumeration.
+		c.fail(location, exact, "%s only accepts enumerated values, %s accepts others", c.wider, c.narrower)
+		return
+	}
+
+	types := c.checkTypes(w, n, location, exact)
+	if types["number"] || types["integer"] {
+		c.checkNumbers(w, n, location, exact)
+	}
+	if types["string"] {
+		c.checkStrings(w, n, location, exact)
+	}
+	if types["array"] {
+		c.checkArrays(w, n, location, exact)
+	}
+	if types["object"] {
+		c.checkObjects(w, n, location, exact)
+	}
+}
+
+// checkAlternatives verifies that n is subsumed by at least one of the alternatives.
+func (c *subsumption) checkAlternatives(alternatives []*Schema, n *Schema, location, keyword string) {
+	for _, alternative := range alternatives {
+		if c.try(alternative, n, location) {
+			return
+		}
+	}
+	c.undecide(location, fmt.Sprintf("no single %s alternative of %s covers %s", keyword, c.wider, c.narrower))
+}
+
+// checkTypes compares the type keywords and returns the types the narrower schema accepts.
+func (c *subsumption) checkTypes(w, n *Schema, location string, exact bool) map[string]bool {
+	narrowTypes := map[string]bool{}
+	if len(n.Type) == 0 {
+		for _, t := range []string{"null", "boolean", "number", "integer", "string", "array", "object"} {
+			narrowTypes[t] = true
+		}
+	}
+	for _, t := range n.Type {
+		narrowTypes[t] = true
+		if t == "number" {
+			narrowTypes["integer"] = true
+		}
+	}
+
+	if len(w.Type) == 0 {
+		return narrowTypes
+	}
+	wideTypes := map[string]bool{}
+	for _, t := range w.Type {
+		wideTypes[t] = true
+	}
+
+	var missing []string
+	for _, t := range []string{"null", "boolean", "number", "integer", "string", "array", "object"} {
+		if !narrowTypes[t] || wideTypes[t] || (t == "integer" && wideTypes["number"]) {
+			continue
+		}
+		if t == "integer" && narrowTypes["number"] {
+			continue // Reported as number.
+		}
+		if t == "number" && wideTypes["integer"] {
+			c.fail(location, exact, "%s accepts non-integer numbers, %s only integers", c.narrower, c.wider)
+			delete(narrowTypes, "number")
+			continue
+		}
+		missing = append(missing, t)
+		delete(narrowTypes, t)
+		if t == "number" {
+			delete(narrowTypes, "integer")
+		}
+	}
+	if len(missing) > 0 {
+		c.fail(location, exact, "%s accepts %s values, %s does not", c.narrower, strings.Join(missing, ", "), c.wider)
+	}
+	return narrowTypes
+}
+
+// checkNumbers compares the numeric bounds and multipleOf.
+func (c *subsumption) checkNumbers(w, n *Schema, location string, exact bool) {
+	wLow, wLowExcl := lowerBound(w)
+	nLow, nLowExcl := lowerBound(n)
+	if wLow != nil && (nLow == nil || nLow.Cmp(wLow) < 0 || (nLow.Cmp(wLow) == 0 && wLowExcl && !nLowExcl)) {
+		c.fail(location, exact, "%s accepts numbers below the lower bound %s of %s", c.narrower, wLow.RatString(), c.wider)
+	}
+
+	wHigh, wHighExcl := upperBound(w)
+	nHigh, nHighExcl := upperBound(n)
+	if wHigh != nil && (nHigh == nil || nHigh.Cmp(wHigh) > 0 || (nHigh.Cmp(wHigh) == 0 && wHighExcl && !nHighExcl)) {
+		c.fail(location, exact, "%s accepts numbers above the upper bound %s of %s", c.narrower, wHigh.RatString(), c.wider)
+	}
+
+	if w.MultipleOf != nil {
+		if n.MultipleOf == nil || !new(big.Rat).Quo(n.MultipleOf.Rat, w.MultipleOf.Rat).IsInt() {
+			c.fail(location, exact, "%s accepts numbers that are not multiples of %s", c.narrower, w.MultipleOf.RatString())
+		}
+	}
+}
+
+// checkStrings compares the string length bounds, pattern and asserted format.
+func (c *subsumption) checkStrings(w, n *Schema, location string, exact bool) {
+	if w.MinLength != nil && (n.MinLength == nil || *n.MinLength < *w.MinLength) {
+		c.fail(location, exact, "%s accepts strings shorter than %v characters", c.narrower, *w.MinLength)
+	}
+	if w.MaxLength != nil && (n.MaxLength == nil || *n.MaxLength > *w.MaxLength) {
+		c.fail(location, exact, "%s accepts strings longer than %v characters", c.narrower, *w.MaxLength)
+	}
+
+	if w.Pattern != nil && (n.Pattern == nil || *n.Pattern != *w.Pattern) {
+		if n.Pattern == nil {
+			c.fail(location, exact, "%s accepts strings not matching the pattern %q", c.narrower, *w.Pattern)
+		} else {
+			c.undecide(location, fmt.Sprintf("patterns %q and %q cannot be compared", *w.Pattern, *n.Pattern))
+		}
+	}
+
+	if w.Format != nil && w.assertsFormat() && (n.Format == nil || *n.Format != *w.Format) {
+		c.fail(location, exact, "%s accepts strings that are not of format %q", c.narrower, *w.Format)
+	}
+}
+
+// checkArrays compares the item count bounds, uniqueness and item schemas.
+func (c *subsumption) checkArrays(w, n *Schema, location string, exact bool) {
+	if w.MinItems != nil && (n.MinItems == nil || *n.MinItems < *w.MinItems) {
+		c.fail(location, exact, "%s accepts arrays with fewer than %v items", c.narrower, *w.MinItems)
+	}
+	if w.MaxItems != nil && (n.MaxItems == nil || *n.MaxItems > *w.MaxItems) {
+		c.fail(location, exact, "%s accepts arrays with more than %v items", c.narrower, *w.MaxItems)
+	}
+	if w.UniqueItems != nil && *w.UniqueItems && (n.UniqueItems == nil || !*n.UniqueItems) {
+		c.fail(location, exact, "%s accepts arrays with duplicate items", c.narrower)
+	}
+
+	prefix := len(w.PrefixItems)
+	if len(n.PrefixItems) > prefix {
+		prefix = len(n.PrefixItems)
+	}
+	for i := 0; i < prefix; i++ {
+		c.check(itemSchema(w, i), itemSchema(n, i), fmt.Sprintf("%s/%d", location, i), exact)
+	}
+	c.check(w.Items, n.Items, location+"/*", exact)
+}
+
+// checkObjects compares required properties, property schemas and property count bounds.
+func (c *subsumption) checkObjects(w, n *Schema, location string, exact bool) {
+	required := map[string]bool{}
+	for _, name := range n.Required {
+		required[name] = true
+	}
+	for _, name := range w.Required {
+		if !required[name] {
+			c.fail(location, exact, "%s accepts objects without the property %q required by %s", c.narrower, name, c.wider)
+		}
+	}
+
+	for name, dependents := range w.DependentRequired {
+		for _, dependent := range dependents {
+			if !required[dependent] && !containsString(n.DependentRequired[name], dependent) {
+				c.fail(location, exact, "%s accepts objects with %q but without %q", c.narrower, name, dependent)
+			}
+		}
+	}
+
+	if w.MinProperties != nil && (n.MinProperties == nil || *n.MinProperties < *w.MinProperties) {
+		c.fail(location, exact, "%s accepts objects with fewer than %v properties", c.narrower, *w.MinProperties)
+	}
+	if w.MaxProperties != nil && (n.MaxProperties == nil || *n.MaxProperties > *w.MaxProperties) {
+		c.fail(location, exact, "%s accepts objects with more than %v properties", c.narrower, *w.MaxProperties)
+	}
+
+	if w.PatternProperties != nil {
+		c.undecide(location, fmt.Sprintf("%s uses patternProperties", c.wider))
+		return
+	}
+
+	names := map[string]bool{}
+	for _, s := range []*Schema{w, n} {
+		if s.Properties != nil {
+			for name := range *s.Properties {
+				names[name] = true
+			}
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		c.check(declaredSchema(w, name), declaredSchema(n, name), location+"/"+escapeJSONPointer(name), exact)
+	}
+	c.check(w.AdditionalProperties, n.AdditionalProperties, location+"/*", exact)
+}
+
+// hasOwnAssertions reports whether s has keywords other than references and applicators.
+func hasOwnAssertions(s *Schema) bool {
+	for _, keyword := range evaluationOrder(s) {
+		switch keyword {
+		case "$ref", "allOf", "anyOf", "oneOf":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// enumeratedValues returns the finite set of candidate instances of s, if it has one.
+func enumeratedValues(s *Schema) ([]interface{}, bool) {
+	switch {
+	case s.Const != nil && s.Const.IsSet:
+		return []interface{}{s.Const.Value}, true
+	case len(s.Enum) > 0:
+		return s.Enum, true
+	case len(s.Type) > 0:
+		var values []interface{}
+		for _, t := range s.Type {
+			switch t {
+			case "null":
+				values = append(values, nil)
+			case "boolean":
+				values = append(values, true, false)
+			default:
+				return nil, false
+			}
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// lowerBound returns the effective lower numeric bound of s and whether it is exclusive.
+func lowerBound(s *Schema) (*big.Rat, bool) {
+	var bound *big.Rat
+	exclusive := false
+	if s.Minimum != nil {
+		bound = s.Minimum.Rat
+	}
+	if s.ExclusiveMinimum != nil && (bound == nil || s.ExclusiveMinimum.Cmp(bound) >= 0) {
+		bound = s.ExclusiveMinimum.Rat
+		exclusive = true
+	}
+	return bound, exclusive
+}
+
+// upperBound returns the effective upper numeric bound of s and whether it is exclusive.
+func upperBound(s *Schema) (*big.Rat, bool) {
+	var bound *big.Rat
+	exclusive := false
+	if s.Maximum != nil {
+		bound = s.Maximum.Rat
+	}
+	if s.ExclusiveMaximum != nil && (bound == nil || s.ExclusiveMaximum.Cmp(bound) <= 0) {
+		bound = s.ExclusiveMaximum.Rat
+		exclusive = true
+	}
+	return bound, exclusive
+}
+
+// itemSchema returns the schema s applies to the array item at index i.
+func itemSchema(s *Schema, i int) *Schema {
+	if i < len(s.PrefixItems) {
+		return s.PrefixItems[i]
+	}
+	return s.Items
+}
+
+// declaredSchema returns the schema s applies to the property name.
+func declaredSchema(s *Schema, name string) *Schema {
+	if s.Properties != nil {
+		if schema, ok := (*s.Properties)[name]; ok {
+			return schema
+		}
+	}
+	return s.AdditionalProperties
+}
+
+// describeValue renders a value for a counterexample.
+func describeValue(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupeStrings removes adjacent duplicates from a sorted slice.
+func dedupeStrings(list []string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	result := list[:1]
+	for _, s := range list[1:] {
+		if s != result[len(result)-1] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
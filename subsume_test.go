@@ -0,0 +1,144 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsumes(t *testing.T) {
+	tests := []struct {
+		name            string
+		a, b            string
+		holds           bool
+		counterexamples []Counterexample
+		undecided       int
+	}{
+		{
+			name:  "identical",
+			a:     `{"type": "string", "maxLength": 5}`,
+			b:     `{"type": "string", "maxLength": 5}`,
+			holds: true,
+		},
+		{
+			name:  "relaxed bounds",
+			a:     `{"type": "number", "minimum": 0, "maximum": 100}`,
+			b:     `{"type": "integer", "exclusiveMinimum": 0, "maximum": 10, "multipleOf": 2}`,
+			holds: true,
+		},
+		{
+			name: "tightened bounds",
+			a:    `{"type": "integer", "exclusiveMinimum": 0}`,
+			b:    `{"type": "number", "minimum": 0}`,
+			counterexamples: []Counterexample{
+				{InstanceLocation: "", Reason: "b accepts non-integer numbers, a only integers"},
+				{InstanceLocation: "", Reason: "b accepts numbers below the lower bound 0 of a"},
+			},
+		},
+		{
+			name: "new required property",
+			a:    `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}, "required": ["id", "name"]}`,
+			b:    `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": ["string", "null"]}}, "required": ["id"]}`,
+			counterexamples: []Counterexample{
+				{InstanceLocation: "", Reason: `b accepts objects without the property "name" required by a`},
+				{InstanceLocation: "/name", Reason: "b accepts null values, a does not"},
+			},
+		},
+		{
+			name: "closed object",
+			a:    `{"type": "object", "properties": {"id": {}}, "additionalProperties": false}`,
+			b:    `{"type": "object", "properties": {"id": {}}}`,
+			counterexamples: []Counterexample{
+				{InstanceLocation: "/*", Reason: "b accepts values, a accepts none"},
+			},
+		},
+		{
+			name: "enum checked exactly",
+			a:    `{"type": "string", "maxLength": 3}`,
+			b:    `{"enum": ["red", "blue", "green"]}`,
+			counterexamples: []Counterexample{
+				{InstanceLocation: "", Reason: `b accepts "blue", a rejects it`},
+				{InstanceLocation: "", Reason: `b accepts "green", a rejects it`},
+			},
+		},
+		{
+			name:  "enum widened",
+			a:     `{"enum": ["red", "blue", "green"]}`,
+			b:     `{"const": "red"}`,
+			holds: true,
+		},
+		{
+			name:  "items through refs",
+			a:     `{"$defs": {"tag": {"type": "string"}}, "type": "array", "items": {"$ref": "#/$defs/tag"}}`,
+			b:     `{"type": "array", "items": {"type": "string", "minLength": 1}, "maxItems": 10}`,
+			holds: true,
+		},
+		{
+			name: "anyOf alternatives of b",
+			a:    `{"type": ["string", "integer"]}`,
+			b:    `{"anyOf": [{"type": "string"}, {"type": "integer"}, {"type": "boolean"}]}`,
+			counterexamples: []Counterexample{
+				{InstanceLocation: "", Reason: "b accepts true, a rejects it"},
+				{InstanceLocation: "", Reason: "b accepts false, a rejects it"},
+			},
+		},
+		{
+			name:  "anyOf alternative of a covers b",
+			a:     `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`,
+			b:     `{"type": "integer", "minimum": 1}`,
+			holds: true,
+		},
+		{
+			name:      "undecidable pattern",
+			a:         `{"type": "string", "pattern": "^a"}`,
+			b:         `{"type": "string", "pattern": "^ab"}`,
+			undecided: 1,
+		},
+		{
+			name:      "not in b makes findings inexact",
+			a:         `{"type": "string"}`,
+			b:         `{"not": {"type": "number"}, "type": ["string", "number"]}`,
+			undecided: 1,
+		},
+		{
+			name:  "recursive schemas",
+			a:     `{"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}}}}, "$ref": "#/$defs/node"}`,
+			b:     `{"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}}, "required": ["next"]}}, "$ref": "#/$defs/node"}`,
+			holds: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewCompiler()
+			a, err := compiler.Compile([]byte(tt.a))
+			require.NoError(t, err)
+			b, err := compiler.Compile([]byte(tt.b))
+			require.NoError(t, err)
+
+			result := Subsumes(a, b)
+			assert.Equal(t, tt.holds, result.Holds)
+			assert.Equal(t, tt.counterexamples, result.Counterexamples)
+			assert.Len(t, result.Undecided, tt.undecided, "undecided: %v", result.Undecided)
+		})
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	compiler := NewCompiler()
+	a, err := compiler.Compile([]byte(`{"allOf": [{"type": "integer", "minimum": 1}]}`))
+	require.NoError(t, err)
+	b, err := compiler.Compile([]byte(`{"type": "integer", "exclusiveMinimum": 0, "minimum": 1}`))
+	require.NoError(t, err)
+	c, err := compiler.Compile([]byte(`{"type": "integer", "minimum": 0}`))
+	require.NoError(t, err)
+
+	assert.True(t, Equivalent(a, b).Holds)
+
+	result := Equivalent(a, c)
+	assert.False(t, result.Holds)
+	assert.Equal(t, []Counterexample{
+		{InstanceLocation: "", Reason: "b accepts numbers below the lower bound 1 of a"},
+	}, result.Counterexamples)
+}
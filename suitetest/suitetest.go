@@ -0,0 +1,294 @@
+// Package suitetest runs the official JSON-Schema-Test-Suite against the jsonschema compiler.
+//
+// The suite is read from a local checkout, such as the git submodule in this repository's
+// testdata directory. Remote references to http://localhost:1234/ are served from the
+// checkout's remotes directory through a compiler loader, so no HTTP server is needed.
+package suitetest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// RemoteBaseURL is the base URL the test suite uses for its remote schemas.
+const RemoteBaseURL = "http://localhost:1234/"
+
+// ErrNoTestFiles is returned when the selected draft directory contains no test files.
+var ErrNoTestFiles = errors.New("no test files found")
+
+// Options configures a run of the test suite.
+type Options struct {
+	// Dir is the root of a JSON-Schema-Test-Suite checkout, containing the tests and remotes directories.
+	Dir string
+
+	// Draft selects the directory below tests, for example "draft2020-12".
+	Draft string
+
+	// Optional includes the tests in the draft's optional directory.
+	Optional bool
+
+	// Files restricts the run to the given file paths, relative to the draft directory (e.g. "optional/bignum.json").
+	Files []string
+
+	// Skip lists files, test cases or single tests to skip, addressed as "file",
+	// "file/case description" or "file/case description/test description".
+	Skip []string
+
+	// NewCompiler creates the compiler used for each test case. It defaults to jsonschema.NewCompiler.
+	NewCompiler func() *jsonschema.Compiler
+}
+
+// Group is one test case of the suite: a schema and the instances evaluated against it.
+type Group struct {
+	Description string      `json:"description"`
+	Schema      interface{} `json:"schema"`
+	Tests       []Test      `json:"tests"`
+}
+
+// Test is a single instance evaluated against a Group's schema.
+type Test struct {
+	Description string      `json:"description"`
+	Data        interface{} `json:"data"`
+	Valid       bool        `json:"valid"`
+}
+
+// Report summarizes the outcome of a run.
+type Report struct {
+	Passed   int      `json:"passed"`
+	Failed   int      `json:"failed"`
+	Skipped  int      `json:"skipped"`
+	Failures []string `json:"failures,omitempty"` // Keys of the failing tests, in the same form as Options.Skip.
+}
+
+// Total returns the number of tests that were run or skipped.
+func (r *Report) Total() int {
+	return r.Passed + r.Failed + r.Skipped
+}
+
+// Run executes the suite as subtests of t, one subtest per file, test case and test.
+func Run(t *testing.T, opts Options) {
+	t.Helper()
+
+	files, err := opts.files()
+	if err != nil {
+		t.Fatalf("Failed to list test files: %v", err)
+	}
+
+	skip := opts.skipSet()
+	for _, file := range files {
+		file := file
+		t.Run(file, func(t *testing.T) {
+			if skip[file] {
+				t.Skip("Skipping file due to skip list.")
+			}
+
+			groups, err := opts.readFile(file)
+			if err != nil {
+				t.Fatalf("Failed to read test file: %v", err)
+			}
+
+			for _, group := range groups {
+				group := group
+				groupKey := file + "/" + group.Description
+				t.Run(group.Description, func(t *testing.T) {
+					if skip[groupKey] {
+						t.Skip("Skipping test case due to skip list.")
+					}
+
+					schema, err := opts.compile(file, group)
+					if err != nil {
+						t.Fatalf("Failed to compile schema: %v", err)
+					}
+
+					for _, test := range group.Tests {
+						test := test
+						t.Run(test.Description, func(t *testing.T) {
+							if skip[groupKey+"/"+test.Description] {
+								t.Skip("Skipping test due to skip list.")
+							}
+
+							result := schema.Validate(test.Data)
+							if result.IsValid() != test.Valid {
+								t.Errorf("Expected valid=%v, got %v: %v", test.Valid, result.IsValid(), result.ToList())
+							}
+						})
+					}
+				})
+			}
+		})
+	}
+}
+
+// Measure executes the suite outside of the testing package and reports how many tests pass.
+// A schema that fails to compile counts as a failure for each of its tests, and so does a
+// test whose evaluation panics.
+func Measure(opts Options) (*Report, error) {
+	files, err := opts.files()
+	if err != nil {
+		return nil, err
+	}
+
+	skip := opts.skipSet()
+	report := &Report{}
+	for _, file := range files {
+		groups, err := opts.readFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups {
+			groupKey := file + "/" + group.Description
+			skipGroup := skip[file] || skip[groupKey]
+
+			var schema *jsonschema.Schema
+			var compileErr error
+			if !skipGroup {
+				schema, compileErr = opts.compile(file, group)
+			}
+
+			for _, test := range group.Tests {
+				key := groupKey + "/" + test.Description
+				switch {
+				case skipGroup || skip[key]:
+					report.Skipped++
+				case compileErr == nil && validates(schema, test):
+					report.Passed++
+				default:
+					report.Failed++
+					report.Failures = append(report.Failures, key)
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// validates reports whether the schema's verdict on the test data matches the expected one.
+// A panic during evaluation counts as a mismatch.
+func validates(schema *jsonschema.Schema, test Test) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	return schema.Validate(test.Data).IsValid() == test.Valid
+}
+
+// draftDir returns the directory holding the tests of the selected draft.
+func (opts Options) draftDir() string {
+	return filepath.Join(opts.Dir, "tests", opts.Draft)
+}
+
+// files lists the test files of the run, relative to the draft directory and using forward slashes.
+func (opts Options) files() ([]string, error) {
+	if len(opts.Files) > 0 {
+		return opts.Files, nil
+	}
+
+	root := opts.draftDir()
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel == "optional" && !opts.Optional {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path.Ext(rel) == ".json" {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoTestFiles, root)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// readFile parses a test file of the selected draft.
+func (opts Options) readFile(file string) ([]Group, error) {
+	data, err := os.ReadFile(filepath.Join(opts.draftDir(), filepath.FromSlash(file)))
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []Group
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return groups, nil
+}
+
+// compile compiles the schema of a test case with a fresh compiler.
+func (opts Options) compile(file string, group Group) (*jsonschema.Schema, error) {
+	schemaJSON, err := json.Marshal(group.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	newCompiler := opts.NewCompiler
+	if newCompiler == nil {
+		newCompiler = jsonschema.NewCompiler
+	}
+	compiler := newCompiler()
+
+	// Format tests in optional/format expect format to be asserted.
+	if strings.HasPrefix(file, "optional/format/") {
+		compiler.SetAssertFormat(true)
+	}
+	registerRemotes(compiler, filepath.Join(opts.Dir, "remotes"))
+
+	return compiler.Compile(schemaJSON)
+}
+
+// skipSet indexes the skip list.
+func (opts Options) skipSet() map[string]bool {
+	skip := make(map[string]bool, len(opts.Skip))
+	for _, key := range opts.Skip {
+		skip[key] = true
+	}
+	return skip
+}
+
+// registerRemotes serves RemoteBaseURL from the remotes directory of the suite,
+// delegating every other http URL to the previously registered loader.
+func registerRemotes(compiler *jsonschema.Compiler, dir string) {
+	next := compiler.Loaders["http"]
+	compiler.RegisterLoader("http", func(url string) (io.ReadCloser, error) {
+		if rel, ok := strings.CutPrefix(url, RemoteBaseURL); ok {
+			rel, _, _ = strings.Cut(rel, "#")
+			return os.Open(filepath.Join(dir, filepath.FromSlash(rel))) //nolint:gosec
+		}
+		if next == nil {
+			return nil, jsonschema.ErrNoLoaderRegistered
+		}
+		return next(url)
+	})
+}
@@ -0,0 +1,107 @@
+package suitetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+const suiteDir = "../testdata/JSON-Schema-Test-Suite"
+
+// knownFailures lists the parts of the draft 2020-12 suite the compiler does not pass yet,
+// including tests that need network access to the official meta-schemas.
+var knownFailures = []string{
+	"content.json/validation of string-encoded content based on media type/an invalid JSON document; validates true",
+	"content.json/validation of binary string-encoding/an invalid base64 string (% is not a valid character); validates true",
+	"content.json/validation of binary-encoded media type documents",
+	"content.json/validation of binary-encoded media type documents with schema",
+	"defs.json/validate definition against metaschema",
+	"format.json/idn-email format",
+	"format.json/idn-hostname format",
+	"ref.json/remote ref, containing refs itself",
+	"ref.json/order of evaluation: $id and $anchor and $ref",
+	"vocabulary.json",
+	"optional/cross-draft.json",
+	"optional/dependencies-compatibility.json",
+	"optional/ecmascript-regex.json",
+	"optional/format/idn-email.json",
+	"optional/format/idn-hostname.json",
+	"optional/refOfUnknownKeyword.json",
+}
+
+func TestDraft202012(t *testing.T) {
+	Run(t, Options{
+		Dir:      suiteDir,
+		Draft:    "draft2020-12",
+		Optional: true,
+		Skip:     knownFailures,
+	})
+}
+
+// draftKnownFailures lists the parts of the suites of the earlier drafts the compiler does not
+// pass yet, besides those they share with 2020-12.
+var draftKnownFailures = map[string][]string{
+	"draft4": {"definitions.json/validate definition against metaschema"},
+	"draft6": {"definitions.json/validate definition against metaschema"},
+	"draft7": {"definitions.json/validate definition against metaschema"},
+	"draft2019-09": {
+		"anchor.json/same $anchor with different base uri",
+		"recursiveRef.json/$recursiveRef with $recursiveAnchor: false works like $ref",
+		"recursiveRef.json/$recursiveRef with no $recursiveAnchor in the initial target schema resource",
+		"recursiveRef.json/$recursiveRef with no $recursiveAnchor works like $ref",
+		"ref.json/$ref with $recursiveAnchor",
+	},
+}
+
+func TestEarlierDrafts(t *testing.T) {
+	drafts := map[string]jsonschema.Draft{
+		"draft4":       jsonschema.Draft4,
+		"draft6":       jsonschema.Draft6,
+		"draft7":       jsonschema.Draft7,
+		"draft2019-09": jsonschema.Draft2019,
+	}
+	for name, draft := range drafts {
+		t.Run(name, func(t *testing.T) {
+			Run(t, Options{
+				Dir:         suiteDir,
+				Draft:       name,
+				Skip:        append(append([]string{}, knownFailures...), draftKnownFailures[name]...),
+				NewCompiler: func() *jsonschema.Compiler { return jsonschema.NewCompiler().SetDefaultDraft(draft) },
+			})
+		})
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	report, err := Measure(Options{
+		Dir:   suiteDir,
+		Draft: "draft2020-12",
+		Files: []string{"refRemote.json", "optional/bignum.json"},
+	})
+	require.NoError(t, err)
+
+	assert.Zero(t, report.Failed, "unexpected failures: %v", report.Failures)
+	assert.Positive(t, report.Passed)
+	assert.Equal(t, report.Passed, report.Total())
+}
+
+func TestMeasureSkipAndFailures(t *testing.T) {
+	report, err := Measure(Options{
+		Dir:   suiteDir,
+		Draft: "draft2020-12",
+		Files: []string{"optional/refOfUnknownKeyword.json", "minimum.json"},
+		Skip:  []string{"minimum.json"},
+	})
+	require.NoError(t, err)
+
+	assert.Positive(t, report.Skipped)
+	assert.Contains(t, report.Failures, "optional/refOfUnknownKeyword.json/reference of an arbitrary keyword of a sub-schema/mismatch")
+}
+
+func TestMeasureMissingDraft(t *testing.T) {
+	_, err := Measure(Options{Dir: suiteDir, Draft: "draft1999"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package jsonschema
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SuppressionRule downgrades the errors of a keyword at matching instance locations to warnings,
+// so that known violations of legacy data are tolerated during a migration without editing the
+// schema, see Compiler.RegisterSuppression.
+type SuppressionRule struct {
+	// InstancePath is a JSON Pointer to the suppressed locations of the instance, whose segments
+	// may be * to match any one member, or ** to match any number of members: /orders/*/total
+	// matches the total of every order, and /legacy/** everything below legacy, legacy included.
+	InstancePath string
+	// Keyword is the name of the suppressed keyword, such as required; every keyword when empty.
+	Keyword string
+	// Until ends the migration window: the rule no longer applies to validations started at or
+	// after it. The rule applies indefinitely when zero.
+	Until time.Time
+}
+
+// RegisterSuppression adds a rule downgrading the errors of a keyword at matching instance
+// locations to warnings in the validations of schemas of the compiler. Suppressed errors are
+// moved from the Errors to the Warnings of their result, with SeverityWarning, like the keywords
+// of SetSeverity, so that they no longer make the instance invalid. Unlike SetSeverity, which
+// applies to a keyword of the schema wherever it validates, rules target locations of instances.
+// TokenValidator does not apply them, so ValidateJSON and ValidateReader parse whole documents
+// while rules are registered.
+func (c *Compiler) RegisterSuppression(rule SuppressionRule) *Compiler {
+	c.Suppressions = append(c.Suppressions, rule)
+	return c
+}
+
+// activeSuppressions returns the suppression rules of the compiler that apply to a validation
+// starting now, or nil when there are none.
+func (c *Compiler) activeSuppressions() []SuppressionRule {
+	if c == nil || len(c.Suppressions) == 0 {
+		return nil
+	}
+	now := c.Now()
+	var active []SuppressionRule
+	for _, rule := range c.Suppressions {
+		if rule.Until.IsZero() || now.Before(rule.Until) {
+			active = append(active, rule)
+		}
+	}
+	return active
+}
+
+// applySuppressions moves the errors suppressed by rules at the instance location path to the
+// warnings of the result, which becomes valid when no other error is left.
+func (e *EvaluationResult) applySuppressions(rules []SuppressionRule, path []string) {
+	demoted := false
+	for keyword, err := range e.Errors {
+		if !suppresses(rules, keyword, path) {
+			continue
+		}
+		err.Severity = SeverityWarning
+		if e.Warnings == nil {
+			e.Warnings = make(map[string]*EvaluationError)
+		}
+		e.Warnings[keyword] = err
+		delete(e.Errors, keyword)
+		demoted = true
+	}
+	if demoted && len(e.Errors) == 0 {
+		e.Valid = true
+	}
+}
+
+// suppresses reports whether one of rules suppresses keyword at the instance location path.
+func suppresses(rules []SuppressionRule, keyword string, path []string) bool {
+	for _, rule := range rules {
+		if rule.Keyword != "" && rule.Keyword != keyword {
+			continue
+		}
+		if matchInstancePath(splitInstancePath(rule.InstancePath), path) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitInstancePath returns the unescaped segments of the JSON Pointer pointer, none for the root.
+func splitInstancePath(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = unescapeJSONPointer(segment)
+	}
+	return segments
+}
+
+// matchInstancePath reports whether the segments of an instance location match those of a
+// pattern, where * matches any one segment and ** any number of them.
+func matchInstancePath(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(path); i++ {
+				if matchInstancePath(pattern[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 || pattern[0] != "*" && pattern[0] != path[0] {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
+// pushInstance enters the member name of the instance evaluated, when validations track instance
+// locations for suppression rules.
+func (ds *DynamicScope) pushInstance(name string) {
+	if ds.suppressions != nil {
+		ds.instancePath = append(ds.instancePath, name)
+	}
+}
+
+// pushIndex enters the item at index of the instance evaluated, like pushInstance.
+func (ds *DynamicScope) pushIndex(index int) {
+	if ds.suppressions != nil {
+		ds.instancePath = append(ds.instancePath, strconv.Itoa(index))
+	}
+}
+
+// popInstance leaves the member entered last with pushInstance or pushIndex.
+func (ds *DynamicScope) popInstance() {
+	if ds.suppressions != nil {
+		ds.instancePath = ds.instancePath[:len(ds.instancePath)-1]
+	}
+}
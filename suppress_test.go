@@ -0,0 +1,104 @@
+package jsonschema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const suppressionSchema = `{
+	"type": "object",
+	"properties": {
+		"orders": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"required": ["id", "total"],
+				"properties": {"total": {"type": "number", "minimum": 0}}
+			}
+		},
+		"legacy": {"type": "object", "properties": {"code": {"type": "string"}, "tags": {"items": {"type": "string"}}}}
+	},
+	"additionalProperties": false
+}`
+
+func TestRegisterSuppression(t *testing.T) {
+	compiler := NewCompiler().
+		RegisterSuppression(SuppressionRule{InstancePath: "/orders/*", Keyword: "required"}).
+		RegisterSuppression(SuppressionRule{InstancePath: "/legacy/**"})
+	schema, err := compiler.Compile([]byte(suppressionSchema))
+	require.NoError(t, err)
+
+	instance := map[string]interface{}{
+		"orders": []interface{}{map[string]interface{}{"total": 5}, map[string]interface{}{"id": 2, "total": 1}},
+		"legacy": map[string]interface{}{"code": 7, "tags": []interface{}{"a", 1}},
+	}
+	result := schema.Validate(instance)
+	assert.True(t, result.IsValid())
+	code := findDetail(result, "/properties/legacy/properties/code")
+	require.NotNil(t, code, "** matches nested members")
+	require.Contains(t, code.Warnings, "type")
+	assert.Equal(t, SeverityWarning, code.Warnings["type"].Severity)
+	assert.Empty(t, code.Errors)
+
+	instance["orders"] = []interface{}{map[string]interface{}{"id": 1, "total": -1}}
+	assert.False(t, schema.Validate(instance).IsValid(), "other keywords are not suppressed")
+
+	valid, err := schema.ValidateJSON([]byte(`{"orders": [{"total": 5}]}`))
+	require.NoError(t, err)
+	assert.True(t, valid.IsValid(), "raw documents are validated with the rules")
+}
+
+func TestSuppressionUntil(t *testing.T) {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	compiler := NewCompiler().SetClock(clock).
+		RegisterSuppression(SuppressionRule{InstancePath: "", Keyword: "required", Until: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)})
+	schema, err := compiler.Compile([]byte(`{"required": ["id"]}`))
+	require.NoError(t, err)
+
+	result := schema.Validate(map[string]interface{}{})
+	assert.True(t, result.IsValid())
+	assert.Contains(t, result.Warnings, "required")
+
+	clock.Set(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.False(t, schema.Validate(map[string]interface{}{}).IsValid(), "rules expire at Until")
+	assert.Len(t, compiler.Overlay().Suppressions, 1)
+}
+
+func TestMatchInstancePath(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, path string
+		match         bool
+	}{
+		{"", "", true},
+		{"", "/a", false},
+		{"/a/*/c", "/a/0/c", true},
+		{"/a/*/c", "/a/c", false},
+		{"/a/**", "/a", true},
+		{"/a/**/d", "/a/b/c/d", true},
+		{"/**/d", "/d", true},
+		{"/a~1b", "/a~1b", true},
+	} {
+		assert.Equal(t, tc.match, matchInstancePath(splitInstancePath(tc.pattern), splitInstancePath(tc.path)), "%s %s", tc.pattern, tc.path)
+	}
+}
+
+// findDetail returns the detail of result at evaluationPath, searching the whole tree.
+func findDetail(result *EvaluationResult, evaluationPath string) *EvaluationResult {
+	return findDetailAt(result, "", evaluationPath)
+}
+
+func findDetailAt(result *EvaluationResult, prefix, evaluationPath string) *EvaluationResult {
+	path := prefix + result.EvaluationPath
+	if path == evaluationPath {
+		return result
+	}
+	for _, detail := range result.Details {
+		if found := findDetailAt(detail, path, evaluationPath); found != nil {
+			return found
+		}
+	}
+	return nil
+}
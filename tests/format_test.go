@@ -5,8 +5,7 @@ import "testing"
 // TestFormatForTestSuite executes the format validation tests for Schema Test Suite.
 func TestFormatForTestSuite(t *testing.T) {
 	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/format.json",
-		"idn-email format",
-		"idn-hostname format")
+		"idn-email format")
 }
 
 func TestFormatDateTimeForTestSuite(t *testing.T) {
@@ -80,3 +79,11 @@ func TestFormatUriForTestSuite(t *testing.T) {
 func TestFormatUuidForTestSuite(t *testing.T) {
 	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional/format/uuid.json")
 }
+
+func TestFormatAssertionForTestSuite(t *testing.T) {
+	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional/format-assertion.json")
+}
+
+func TestFormatIdnHostnameForTestSuite(t *testing.T) {
+	testJSONSchemaTestSuiteWithFilePath(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional/format/idn-hostname.json")
+}
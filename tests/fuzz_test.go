@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// fuzzSchema seeds the fuzz targets with a schema touching most applicators.
+const fuzzSchema = `{
+	"$defs": {"name": {"type": "string", "minLength": 1, "examples": ["Ada"]}},
+	"type": "object",
+	"properties": {
+		"name": {"$ref": "#/$defs/name"},
+		"age": {"type": "integer", "minimum": 0, "default": 18},
+		"tags": {"type": "array", "items": {"enum": ["a", "b"]}, "uniqueItems": true}
+	},
+	"patternProperties": {"^x-": {"const": true}},
+	"if": {"required": ["age"]},
+	"then": {"required": ["name"]}
+}`
+
+func FuzzCompile(f *testing.F) {
+	f.Add([]byte(fuzzSchema))
+	f.Add([]byte(`true`))
+	f.Add([]byte(`{"$ref": "#/$defs/a", "$defs": {"a": {"$ref": "#"}}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		jsonschema.FuzzCompile(data)
+	})
+}
+
+func FuzzValidate(f *testing.F) {
+	schema, err := jsonschema.NewCompiler().Compile([]byte(fuzzSchema))
+	require.NoError(f, err)
+	for _, seed := range schema.FuzzSeeds() {
+		f.Add(seed.Schema, seed.Instance)
+	}
+
+	f.Fuzz(func(t *testing.T, schemaData, instanceData []byte) {
+		jsonschema.FuzzValidate(schemaData, instanceData)
+	})
+}
+
+func TestFuzzSeeds(t *testing.T) {
+	schema, err := jsonschema.NewCompiler().Compile([]byte(fuzzSchema))
+	require.NoError(t, err)
+
+	seeds := schema.FuzzSeeds()
+	instances := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		assert.Equal(t, 1, jsonschema.FuzzValidate(seed.Schema, seed.Instance))
+		instances = append(instances, string(seed.Instance))
+	}
+	assert.Contains(t, instances, `"Ada"`)
+	assert.Contains(t, instances, `18`)
+	assert.Contains(t, instances, `"b"`)
+
+	dir := filepath.Join(t.TempDir(), "testdata", "fuzz", "FuzzValidate")
+	require.NoError(t, jsonschema.WriteFuzzCorpus(dir, seeds))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, len(seeds))
+
+	content, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "go test fuzz v1\n[]byte("))
+}
@@ -104,7 +104,7 @@ func testJSONSchemaTestSuiteWithFilePath(t *testing.T, filePath string, exclusio
 			compiler := jsonschema.NewCompiler()
 
 			// Assert format for optional/format test cases.
-			if strings.Contains(filePath, "optional/format") {
+			if strings.Contains(filePath, "optional/format/") {
 				compiler.SetAssertFormat(true)
 			}
 
@@ -0,0 +1,257 @@
+package jsonschema
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySamples is the number of most recent validation latencies kept per schema for the
+// percentiles reported by Compiler.Stats.
+const latencySamples = 1024
+
+// ValidationStats holds the compile and validation measurements of a schema, collected while
+// Compiler.CollectStats is enabled.
+type ValidationStats struct {
+	URI             string        `json:"uri"`             // URI of the schema, empty for schemas compiled without one.
+	CompileDuration time.Duration `json:"compileDuration"` // Time spent compiling the schema, including loaded references.
+	Validations     int64         `json:"validations"`     // Number of validations against the schema.
+	Invalid         int64         `json:"invalid"`         // Number of validations that failed.
+	ErrorRate       float64       `json:"errorRate"`       // Invalid divided by Validations.
+	P50             time.Duration `json:"p50"`             // Median latency of the recent validations.
+	P99             time.Duration `json:"p99"`             // 99th percentile latency of the recent validations.
+
+	Keywords []KeywordStats `json:"keywords,omitempty"` // Failures of each keyword, collected while Compiler.CollectFailures is enabled.
+}
+
+// KeywordStats counts the validations a keyword of a schema rejected, see Compiler.SetCollectFailures.
+type KeywordStats struct {
+	Location string `json:"location"` // Canonical location of the keyword, such as https://example.com/user.json#/properties/age/minimum.
+	Keyword  string `json:"keyword"`  // Name of the keyword.
+	Failures int64  `json:"failures"` // Number of rejected instances the keyword failed for.
+}
+
+// keywordKey identifies a keyword of a subschema.
+type keywordKey struct {
+	schema  *Schema
+	keyword string
+}
+
+// schemaTimings accumulates the measurements of one schema.
+type schemaTimings struct {
+	uri         string
+	compile     time.Duration
+	validations int64
+	invalid     int64
+	latencies   []time.Duration // Ring buffer of the most recent latencies.
+	next        int
+	failures    map[keywordKey]int64 // Failures of each keyword, when failures are collected.
+	deprecated  map[*Schema]int64    // Validations that evaluated each deprecated subschema, when failures are collected.
+}
+
+// timingRegistry holds the measurements of the schemas of a compiler.
+type timingRegistry struct {
+	mu      sync.Mutex
+	schemas map[*Schema]*schemaTimings
+	order   []*Schema // Schemas in the order they were first measured.
+}
+
+// SetCollectStats enables or disables the collection of compile and validation timings reported
+// by Stats. Collection adds two clock readings and a lock per validation.
+func (c *Compiler) SetCollectStats(collect bool) *Compiler {
+	c.CollectStats = collect
+	return c
+}
+
+// SetCollectFailures enables or disables counting, for each keyword, the validations it rejected,
+// reported by Stats along with the measurements of CollectStats. Across many validations this
+// shows which constraints do real work and which never fire. Only the keywords whose failures make
+// the instance invalid are counted: not those of a failed anyOf branch when another one matched.
+// The validations evaluating subschemas marked deprecated are counted too, for DeprecationReport.
+func (c *Compiler) SetCollectFailures(collect bool) *Compiler {
+	c.CollectFailures = collect
+	return c
+}
+
+// Stats returns the compile and validation measurements of every schema compiled or validated
+// while CollectStats or CollectFailures was enabled, in the order they were first measured.
+// Validations are counted against the schema Validate is called on.
+func (c *Compiler) Stats() []ValidationStats {
+	c.timings.mu.Lock()
+	defer c.timings.mu.Unlock()
+
+	stats := make([]ValidationStats, 0, len(c.timings.order))
+	for _, schema := range c.timings.order {
+		timings := c.timings.schemas[schema]
+		stat := ValidationStats{
+			URI:             timings.uri,
+			CompileDuration: timings.compile,
+			Validations:     timings.validations,
+			Invalid:         timings.invalid,
+		}
+		if timings.validations > 0 {
+			stat.ErrorRate = float64(timings.invalid) / float64(timings.validations)
+		}
+		if len(timings.latencies) > 0 {
+			sorted := append([]time.Duration(nil), timings.latencies...)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+			stat.P50 = percentile(sorted, 50)
+			stat.P99 = percentile(sorted, 99)
+		}
+		if timings.failures != nil {
+			stat.Keywords = keywordStats(schema, timings.failures)
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// timingsOf returns the measurements of schema, creating them on first use. The caller holds mu.
+func (r *timingRegistry) timingsOf(schema *Schema) *schemaTimings {
+	if r.schemas == nil {
+		r.schemas = make(map[*Schema]*schemaTimings)
+	}
+	timings, ok := r.schemas[schema]
+	if !ok {
+		timings = &schemaTimings{uri: schema.GetSchemaURI()}
+		r.schemas[schema] = timings
+		r.order = append(r.order, schema)
+	}
+	return timings
+}
+
+// registry returns the registry recording the measurements of the schemas of c.
+func (c *Compiler) registry() *timingRegistry {
+	if c.measuredBy != nil {
+		return &c.measuredBy.timings
+	}
+	return &c.timings
+}
+
+// forget removes the measurements of root and of its subschemas.
+func (r *timingRegistry) forget(root *Schema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.schemas) == 0 {
+		return
+	}
+	order := r.order[:0]
+	for _, schema := range r.order {
+		if schema.getRootSchema() == root {
+			delete(r.schemas, schema)
+		} else {
+			order = append(order, schema)
+		}
+	}
+	clear(r.order[len(order):])
+	r.order = order
+}
+
+// reset removes every measurement.
+func (r *timingRegistry) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas = nil
+	r.order = nil
+}
+
+// recordCompile records the time spent compiling schema.
+func (r *timingRegistry) recordCompile(schema *Schema, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timingsOf(schema).compile = duration
+}
+
+// recordValidation records a validation against schema, and the failures of its keywords when
+// failures is set.
+func (r *timingRegistry) recordValidation(schema *Schema, duration time.Duration, result *EvaluationResult, failures bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timings := r.timingsOf(schema)
+	timings.validations++
+	if !result.IsValid() {
+		timings.invalid++
+	}
+	if failures {
+		if timings.failures == nil {
+			timings.failures = make(map[keywordKey]int64)
+		}
+		countFailures(result, timings.failures)
+	}
+	if len(timings.latencies) < latencySamples {
+		timings.latencies = append(timings.latencies, duration)
+	} else {
+		timings.latencies[timings.next] = duration
+		timings.next = (timings.next + 1) % latencySamples
+	}
+}
+
+// recordDeprecated records the deprecated subschemas evaluated by a validation against schema.
+func (r *timingRegistry) recordDeprecated(schema *Schema, deprecated map[*Schema]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timings := r.timingsOf(schema)
+	if timings.deprecated == nil {
+		timings.deprecated = make(map[*Schema]int64)
+	}
+	for subschema := range deprecated {
+		timings.deprecated[subschema]++
+	}
+}
+
+// countFailures counts the failed keywords of an invalid result and of its invalid details.
+func countFailures(result *EvaluationResult, failures map[keywordKey]int64) {
+	if result.IsValid() {
+		return
+	}
+	if result.schema != nil {
+		for keyword := range result.Errors {
+			failures[keywordKey{result.schema, keyword}]++
+		}
+	}
+	for _, detail := range result.Details {
+		countFailures(detail, failures)
+	}
+}
+
+// keywordStats returns the failures of the keywords of schema and its subschemas, including those
+// that never failed, and of the referenced keywords that failed, the most frequent first.
+func keywordStats(schema *Schema, failures map[keywordKey]int64) []KeywordStats {
+	counts := make(map[keywordKey]int64, len(failures))
+	walkSchema(schema, func(_ string, subschema *Schema) bool {
+		for _, keyword := range evaluationOrder(subschema) {
+			counts[keywordKey{subschema, keyword}] = 0
+		}
+		return true
+	})
+	for key, count := range failures {
+		counts[key] = count
+	}
+
+	stats := make([]KeywordStats, 0, len(counts))
+	for key, count := range counts {
+		stats = append(stats, KeywordStats{
+			Location: key.schema.Location() + "/" + escapeJSONPointer(key.keyword),
+			Keyword:  key.keyword,
+			Failures: count,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Failures != stats[j].Failures {
+			return stats[i].Failures > stats[j].Failures
+		}
+		return stats[i].Location < stats[j].Location
+	})
+	return stats
+}
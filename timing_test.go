@@ -0,0 +1,85 @@
+package jsonschema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilerStats(t *testing.T) {
+	compiler := NewCompiler().SetCollectStats(true)
+	schema, err := compiler.Compile([]byte(`{"$id": "https://example.com/age.json", "type": "integer", "minimum": 0}`))
+	require.NoError(t, err)
+
+	for _, instance := range []interface{}{1, 2, -1, "x"} {
+		schema.Validate(instance)
+	}
+
+	stats := compiler.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, "https://example.com/age.json", stats[0].URI)
+	assert.Positive(t, stats[0].CompileDuration)
+	assert.Equal(t, int64(4), stats[0].Validations)
+	assert.Equal(t, int64(2), stats[0].Invalid)
+	assert.InDelta(t, 0.5, stats[0].ErrorRate, 1e-9)
+	assert.Positive(t, stats[0].P50)
+	assert.GreaterOrEqual(t, stats[0].P99, stats[0].P50)
+}
+
+func TestCompilerStatsDisabled(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{"type": "integer"}`))
+	require.NoError(t, err)
+	schema.Validate(1)
+	assert.Empty(t, compiler.Stats())
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+	assert.Equal(t, time.Duration(50), percentile(sorted, 50))
+	assert.Equal(t, time.Duration(99), percentile(sorted, 99))
+	assert.Equal(t, time.Duration(7), percentile([]time.Duration{7}, 99))
+}
+
+func TestCompilerStatsFailures(t *testing.T) {
+	compiler := NewCompiler().SetCollectFailures(true)
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/person.json",
+		"type": "object",
+		"properties": {
+			"age": {"minimum": 0},
+			"id": {"anyOf": [{"type": "string"}, {"type": "integer"}]}
+		}
+	}`))
+	require.NoError(t, err)
+
+	for _, instance := range []interface{}{
+		map[string]interface{}{"age": -1, "id": 3},
+		map[string]interface{}{"age": -2, "id": "a"},
+		map[string]interface{}{"age": 1, "id": 3},
+	} {
+		schema.Validate(instance)
+	}
+
+	stats := compiler.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(3), stats[0].Validations)
+	assert.Equal(t, []KeywordStats{
+		{Location: "https://example.com/person.json#/properties", Keyword: "properties", Failures: 2},
+		{Location: "https://example.com/person.json#/properties/age/minimum", Keyword: "minimum", Failures: 2},
+		{Location: "https://example.com/person.json#/properties/id/anyOf", Keyword: "anyOf", Failures: 0},
+		{Location: "https://example.com/person.json#/properties/id/anyOf/0/type", Keyword: "type", Failures: 0},
+		{Location: "https://example.com/person.json#/properties/id/anyOf/1/type", Keyword: "type", Failures: 0},
+		{Location: "https://example.com/person.json#/type", Keyword: "type", Failures: 0},
+	}, stats[0].Keywords, "failed anyOf branches do not count when another one matched")
+
+	compiler.SetCollectFailures(false).SetCollectStats(true)
+	stats = compiler.Stats()
+	schema.Validate(map[string]interface{}{"age": -1})
+	assert.Equal(t, stats[0].Keywords, compiler.Stats()[0].Keywords)
+}
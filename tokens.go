@@ -0,0 +1,572 @@
+package jsonschema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/goccy/go-json"
+)
+
+// TokenVerdict reports the evaluation of a complete value of an instance validated from tokens
+// against the schemas the value must conform to for the instance to be valid.
+type TokenVerdict struct {
+	InstanceLocation string              // JSON Pointer of the value in the instance.
+	Valid            bool                // Whether the value conforms; when false, the instance is invalid.
+	Results          []*EvaluationResult // Results of the evaluation of the value against each schema.
+}
+
+// TokenValidator validates an instance given as a sequence of tokens, such as the events of a
+// streaming parser, without the instance ever being complete in memory.
+//
+// Each value is evaluated as soon as its last token is given. An object or array only keeps the
+// results of its members, unless the schemas applied to it need the members themselves, as enum,
+// const, uniqueItems, unevaluatedProperties, unevaluatedItems and $dynamicRef do; such a value is
+// then assembled and evaluated once complete.
+//
+// A TokenValidator is not safe for concurrent use.
+type TokenValidator struct {
+	schema  *Schema
+	scope   *DynamicScope
+	verdict func(TokenVerdict)
+	frames  []*tokenFrame
+	result  *EvaluationResult
+	invalid bool
+}
+
+// tokenTarget is a schema a value is evaluated against. A value that does not conform to a
+// required target makes the whole instance invalid.
+type tokenTarget struct {
+	schema   *Schema
+	required bool
+}
+
+// tokenFrame is an object or array of the instance that is not complete yet.
+type tokenFrame struct {
+	location string
+	targets  []tokenTarget
+	applied  []tokenTarget // The targets and the schemas they apply in place, such as allOf.
+	assemble bool          // Whether the members are kept, to evaluate the complete value.
+	isArray  bool
+	object   map[string]interface{}
+	array    []interface{}
+	key      string
+	hasKey   bool
+	skip     bool // Whether the member of key is a duplicate ignored by DuplicateKeysFirstWins.
+}
+
+// tokenValue stands for a member of an object or array validated from tokens, whose value was not
+// kept, with the results of its evaluation.
+type tokenValue struct {
+	results map[*Schema]*EvaluationResult
+}
+
+// NewTokenValidator returns a validator of the instance given as tokens to its methods. verdict,
+// which may be nil, is called each time a value is complete, so that invalid instances can be
+// rejected before their last token.
+func (s *Schema) NewTokenValidator(verdict func(TokenVerdict)) *TokenValidator {
+	scope := NewDynamicScope()
+	scope.assertFormat = s.assertFormat
+
+	return &TokenValidator{schema: s, scope: scope, verdict: verdict}
+}
+
+// BeginObject starts an object.
+func (v *TokenValidator) BeginObject() error {
+	return v.begin(false)
+}
+
+// Key gives the name of the next member of the current object. A name the object already has is
+// handled with the DuplicateKeyPolicy of the compiler: the value replaces the previous one, is
+// ignored, or Key returns a DuplicateKeyError. With DuplicateKeysLastWins, the previous value was
+// already evaluated, so Valid and the verdicts may report it although the result does not.
+func (v *TokenValidator) Key(name string) error {
+	frame := v.top()
+	if frame == nil || frame.isArray || frame.hasKey {
+		return fmt.Errorf("%w: key %q outside of an object", ErrUnexpectedToken, name)
+	}
+	if _, duplicate := frame.object[name]; duplicate {
+		switch v.schema.duplicateKeyPolicy() {
+		case DuplicateKeysError:
+			return &DuplicateKeyError{Location: frame.location + "/" + escapeJSONPointer(name)}
+		case DuplicateKeysFirstWins:
+			frame.skip = true
+		}
+	}
+	frame.key, frame.hasKey = name, true
+	return nil
+}
+
+// EndObject ends the current object.
+func (v *TokenValidator) EndObject() error {
+	return v.end(false)
+}
+
+// BeginArray starts an array.
+func (v *TokenValidator) BeginArray() error {
+	return v.begin(true)
+}
+
+// EndArray ends the current array.
+func (v *TokenValidator) EndArray() error {
+	return v.end(true)
+}
+
+// Value gives a complete value: a string, number, boolean or nil, or any value as decoded by
+// json.Unmarshal into an interface{}, such as a small object a parser decoded at once. A
+// json.RawMessage, such as a member of a map[string]json.RawMessage, is given as its tokens
+// instead, without being decoded.
+func (v *TokenValidator) Value(value interface{}) error {
+	targets, location, err := v.next()
+	if err != nil {
+		return err
+	}
+	if raw, ok := value.(json.RawMessage); ok {
+		return v.rawValue(raw)
+	}
+	v.complete(value, targets, location)
+	return nil
+}
+
+// Token gives a token as returned by the Token method of a json.Decoder, of this package or of
+// encoding/json. Strings are taken as keys where the current object expects one.
+func (v *TokenValidator) Token(token json.Token) error {
+	switch token {
+	case json.Delim('{'):
+		return v.BeginObject()
+	case json.Delim('}'):
+		return v.EndObject()
+	case json.Delim('['):
+		return v.BeginArray()
+	case json.Delim(']'):
+		return v.EndArray()
+	}
+	if name, ok := token.(string); ok {
+		if frame := v.top(); frame != nil && !frame.isArray && !frame.hasKey {
+			return v.Key(name)
+		}
+	}
+	return v.Value(token)
+}
+
+// Valid reports false as soon as a complete value is known to make the instance invalid. Until the
+// instance is complete, true only means that no such value was found yet.
+func (v *TokenValidator) Valid() bool {
+	return !v.invalid && (v.result == nil || v.result.IsValid())
+}
+
+// Result returns the result of the validation of the complete instance, which is the same as the
+// result of Validate.
+func (v *TokenValidator) Result() (*EvaluationResult, error) {
+	if v.result == nil {
+		return nil, ErrIncompleteTokens
+	}
+	return v.result, nil
+}
+
+// ValidateJSON validates the JSON document data in a single pass over its tokens, with a
+// TokenValidator, rather than decoding it into a tree of Go values first: objects and arrays are
+// only kept in memory when their schemas need them, so multi-megabyte documents are validated
+// without holding their decoded form. The result is the same as the result of Validate for the
+// decoded document; the error reports data that is not a single JSON document.
+//
+// Properties given more than once are handled with the DuplicateKeyPolicy of the compiler; a
+// DuplicateKeyError has the position of the duplicate key in data. A parser set with
+// SetJSONParser parses the whole document first instead.
+func (s *Schema) ValidateJSON(data []byte) (*EvaluationResult, error) {
+	result, err := s.ValidateReader(bytes.NewReader(data))
+	var duplicate *DuplicateKeyError
+	if errors.As(err, &duplicate) {
+		locateDuplicateKey(data, duplicate)
+	}
+	return result, err
+}
+
+// ValidateReader validates the JSON document read from r like ValidateJSON, reading it as it is
+// validated. Trailing data after the document is an error, and a DuplicateKeyError has no position.
+func (s *Schema) ValidateReader(r io.Reader) (*EvaluationResult, error) {
+	if s.jsonParser() != nil {
+		return s.validateParsed(r)
+	}
+	if s.compiler != nil && len(s.compiler.Suppressions) > 0 {
+		return s.validateParsed(r) // Suppression rules need the locations of members.
+	}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	validator := s.NewTokenValidator(nil)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err := validator.decoded(token, err); err != nil {
+			return nil, err
+		}
+	}
+	return validator.decodedResult()
+}
+
+// ValidateDecoder validates the next JSON value read from decoder, a json.Decoder of goccy/go-json,
+// token by token like ValidateReader, and leaves decoder after the value. Services reading their
+// input with goccy/go-json validate values where they stand, such as each item of a large array
+// after reading its '[' token, without decoding them into maps first:
+//
+//	if _, err := decoder.Token(); err != nil { // [
+//		return err
+//	}
+//	for decoder.More() {
+//		result, err := schema.ValidateDecoder(decoder)
+//		...
+//	}
+//
+// Numbers are read as json.Number when decoder uses UseNumber, so that large integers stay exact.
+// The value is decoded whole when the compiler has suppression rules, and the parser set with
+// SetJSONParser is not used.
+func (s *Schema) ValidateDecoder(decoder *json.Decoder) (*EvaluationResult, error) {
+	if s.compiler != nil && len(s.compiler.Suppressions) > 0 {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+		}
+		value, err := parseJSONKeys(raw, s.duplicateKeyPolicy())
+		if errors.Is(err, ErrDuplicateKey) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+		}
+		return s.Validate(value), nil
+	}
+	validator := s.NewTokenValidator(nil)
+	if err := validator.decodeValue(decoder); err != nil {
+		return nil, err
+	}
+	return validator.decodedResult()
+}
+
+// decodeValue gives the tokens of the next value read from decoder to v.
+func (v *TokenValidator) decodeValue(decoder *json.Decoder) error {
+	depth := len(v.frames)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		if err := v.decoded(token, err); err != nil {
+			return err
+		}
+		if len(v.frames) == depth {
+			return nil
+		}
+	}
+}
+
+// rawValue gives the tokens of raw, a single JSON value, to v.
+func (v *TokenValidator) rawValue(raw json.RawMessage) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := v.decodeValue(decoder); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: data after the raw value", ErrUnexpectedToken)
+	}
+	return nil
+}
+
+// decoded gives token, read by a json.Decoder with the error err, to v. The errors other than
+// duplicate keys wrap ErrJSONUnmarshalError.
+func (v *TokenValidator) decoded(token json.Token, err error) error {
+	if err == nil {
+		err = v.Token(token)
+	}
+	if err == nil || errors.Is(err, ErrDuplicateKey) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+}
+
+// decodedResult returns the result of the instance given by json.Decoder tokens.
+func (v *TokenValidator) decodedResult() (*EvaluationResult, error) {
+	result, err := v.Result()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+	}
+	return result, nil
+}
+
+func (v *TokenValidator) top() *tokenFrame {
+	if len(v.frames) == 0 {
+		return nil
+	}
+	return v.frames[len(v.frames)-1]
+}
+
+// next returns the schemas the next value is evaluated against and its location, or an error when
+// no value can be given.
+func (v *TokenValidator) next() ([]tokenTarget, string, error) {
+	if v.result != nil {
+		return nil, "", fmt.Errorf("%w: the instance is already complete", ErrUnexpectedToken)
+	}
+	frame := v.top()
+	switch {
+	case frame == nil:
+		return []tokenTarget{{schema: v.schema, required: true}}, "", nil
+	case frame.isArray:
+		index := len(frame.array)
+		return frame.itemTargets(index), frame.location + "/" + strconv.Itoa(index), nil
+	case !frame.hasKey:
+		return nil, "", fmt.Errorf("%w: value without a key in an object", ErrUnexpectedToken)
+	case frame.skip:
+		return nil, frame.location + "/" + escapeJSONPointer(frame.key), nil
+	}
+	return frame.propertyTargets(frame.key), frame.location + "/" + escapeJSONPointer(frame.key), nil
+}
+
+// begin starts an object, or an array when isArray is true.
+func (v *TokenValidator) begin(isArray bool) error {
+	targets, location, err := v.next()
+	if err != nil {
+		return err
+	}
+	frame := &tokenFrame{location: location, targets: targets, isArray: isArray}
+	if parent := v.top(); parent != nil && parent.assemble {
+		frame.assemble = true
+	} else {
+		frame.applied = appliedInPlace(targets)
+		frame.assemble = !streamable(targets, frame.applied)
+	}
+	if isArray {
+		frame.array = []interface{}{}
+	} else {
+		frame.object = map[string]interface{}{}
+	}
+
+	if !frame.assemble {
+		// Members are evaluated in the dynamic scope of the schemas of the value.
+		for _, target := range targets {
+			v.scope.Push(target.schema)
+		}
+	}
+	v.frames = append(v.frames, frame)
+	return nil
+}
+
+// end ends an object, or an array when isArray is true.
+func (v *TokenValidator) end(isArray bool) error {
+	frame := v.top()
+	if frame == nil || frame.isArray != isArray || frame.hasKey {
+		if isArray {
+			return fmt.Errorf("%w: end of an array", ErrUnexpectedToken)
+		}
+		return fmt.Errorf("%w: end of an object", ErrUnexpectedToken)
+	}
+	v.frames = v.frames[:len(v.frames)-1]
+	if !frame.assemble {
+		for range frame.targets {
+			v.scope.Pop()
+		}
+	}
+
+	if isArray {
+		v.complete(frame.array, frame.targets, frame.location)
+	} else {
+		v.complete(frame.object, frame.targets, frame.location)
+	}
+	return nil
+}
+
+// complete evaluates a complete value against its targets, or adds it to the incomplete value that
+// contains it when that one is assembled.
+func (v *TokenValidator) complete(value interface{}, targets []tokenTarget, location string) {
+	parent := v.top()
+	if parent != nil && parent.assemble {
+		parent.add(value)
+		return
+	}
+
+	results := make(map[*Schema]*EvaluationResult, len(targets))
+	verdict := TokenVerdict{InstanceLocation: location, Valid: true}
+	for _, target := range targets {
+		result, _, _ := target.schema.evaluate(value, v.scope)
+		results[target.schema] = result
+		if target.required {
+			verdict.Results = append(verdict.Results, result)
+			verdict.Valid = verdict.Valid && result.IsValid()
+		}
+	}
+	if !verdict.Valid {
+		v.invalid = true
+	}
+	if v.verdict != nil && len(verdict.Results) > 0 {
+		v.verdict(verdict)
+	}
+
+	if parent == nil {
+		v.result = results[v.schema]
+		return
+	}
+	parent.add(&tokenValue{results: results})
+}
+
+// add adds a complete member.
+func (f *tokenFrame) add(value interface{}) {
+	if f.isArray {
+		f.array = append(f.array, value)
+		return
+	}
+	if !f.skip {
+		f.object[f.key] = value
+	}
+	f.key, f.hasKey, f.skip = "", false, false
+}
+
+// itemTargets returns the schemas the item at index of the array is evaluated against.
+func (f *tokenFrame) itemTargets(index int) []tokenTarget {
+	if f.assemble {
+		return nil
+	}
+	var targets []tokenTarget
+	for _, applied := range f.applied {
+		schema := applied.schema
+		if index < len(schema.PrefixItems) {
+			targets = addTokenTarget(targets, schema.PrefixItems[index], applied.required)
+		} else if schema.Items != nil {
+			targets = addTokenTarget(targets, schema.Items, applied.required)
+		}
+		if schema.Contains != nil {
+			targets = addTokenTarget(targets, schema.Contains, false)
+		}
+	}
+	return targets
+}
+
+// propertyTargets returns the schemas the property name of the object is evaluated against.
+func (f *tokenFrame) propertyTargets(name string) []tokenTarget {
+	if f.assemble {
+		return nil
+	}
+	var targets []tokenTarget
+	for _, applied := range f.applied {
+		schema := applied.schema
+		matched := false
+		if schema.Properties != nil {
+			if propSchema, ok := (*schema.Properties)[name]; ok {
+				targets = addTokenTarget(targets, propSchema, applied.required)
+				matched = true
+			}
+		}
+		if schema.PatternProperties != nil {
+			for pattern, patternSchema := range *schema.PatternProperties {
+				if regex, err := schema.regex(pattern); err == nil && regex.MatchString(name) {
+					targets = addTokenTarget(targets, patternSchema, applied.required)
+					matched = true
+				}
+			}
+		}
+		if schema.AdditionalProperties != nil && !matched {
+			targets = addTokenTarget(targets, schema.AdditionalProperties, applied.required)
+		}
+	}
+	return targets
+}
+
+// addTokenTarget adds schema to targets, or makes it required if it is already there.
+func addTokenTarget(targets []tokenTarget, schema *Schema, required bool) []tokenTarget {
+	for i := range targets {
+		if targets[i].schema == schema {
+			targets[i].required = targets[i].required || required
+			return targets
+		}
+	}
+	return append(targets, tokenTarget{schema: schema, required: required})
+}
+
+// appliedInPlace returns the targets with the schemas they apply to the same value, through $ref,
+// allOf, anyOf, oneOf, not, if, then, else and dependentSchemas. The schemas are required only
+// when reached through $ref and allOf, whose failure makes the value invalid.
+func appliedInPlace(targets []tokenTarget) []tokenTarget {
+	var applied []tokenTarget
+	index := map[*Schema]int{}
+
+	var walk func(schema *Schema, required bool)
+	walk = func(schema *Schema, required bool) {
+		if schema == nil {
+			return
+		}
+		if i, ok := index[schema]; ok {
+			if applied[i].required || !required {
+				return
+			}
+			applied[i].required = true
+		} else {
+			index[schema] = len(applied)
+			applied = append(applied, tokenTarget{schema: schema, required: required})
+		}
+
+		walk(schema.ResolvedRef, required)
+		for _, subSchema := range schema.AllOf {
+			walk(subSchema, required)
+		}
+		for _, subSchema := range schema.AnyOf {
+			walk(subSchema, false)
+		}
+		for _, subSchema := range schema.OneOf {
+			walk(subSchema, false)
+		}
+		walk(schema.Not, false)
+		walk(schema.If, false)
+		walk(schema.Then, false)
+		walk(schema.Else, false)
+		for _, subSchema := range schema.DependentSchemas {
+			walk(subSchema, false)
+		}
+	}
+	for _, target := range targets {
+		walk(target.schema, target.required)
+	}
+	return applied
+}
+
+// streamable tells whether an object or array can be evaluated from the results of its members
+// against the applied schemas, without the members themselves. Members are evaluated in the
+// dynamic scope of the targets only, so the schemas applied in place must not declare dynamic
+// anchors that $dynamicRef in the members could resolve to.
+func streamable(targets, applied []tokenTarget) bool {
+	for _, target := range applied {
+		schema := target.schema
+		if schema.Enum != nil || schema.Const != nil ||
+			schema.UniqueItems != nil && *schema.UniqueItems ||
+			schema.UnevaluatedProperties != nil || schema.UnevaluatedItems != nil ||
+			schema.DynamicRef != "" {
+			return false
+		}
+		if len(schema.dynamicAnchors) > 0 && !isTokenTarget(targets, schema) {
+			return false
+		}
+	}
+	return true
+}
+
+// isTokenTarget tells whether schema is one of targets.
+func isTokenTarget(targets []tokenTarget, schema *Schema) bool {
+	for _, target := range targets {
+		if target.schema == schema {
+			return true
+		}
+	}
+	return false
+}
+
+// result returns a copy of the result of the evaluation of the value against schema, or nil when
+// the value was not evaluated against it.
+func (t *tokenValue) result(schema *Schema) *EvaluationResult {
+	result, ok := t.results[schema]
+	if !ok {
+		return nil
+	}
+	copied := *result
+	return &copied
+}
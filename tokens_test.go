@@ -0,0 +1,262 @@
+package jsonschema
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validateTokens validates a JSON document given as the tokens of a json.Decoder.
+func validateTokens(t *testing.T, schema *Schema, document string, verdict func(TokenVerdict)) *TokenValidator {
+	t.Helper()
+	validator := schema.NewTokenValidator(verdict)
+	decoder := json.NewDecoder(bytes.NewReader([]byte(document)))
+	decoder.UseNumber()
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		require.NoError(t, validator.Token(token))
+	}
+	return validator
+}
+
+func TestTokenValidatorMatchesValidate(t *testing.T) {
+	schemas := map[string]string{
+		"streamed": `{
+			"$defs": {"tag": {"type": "string", "minLength": 2}},
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}, "maxItems": 3},
+				"point": {"prefixItems": [{"type": "number"}, {"type": "number"}], "items": false}
+			},
+			"patternProperties": {"^x-": {"type": "boolean"}},
+			"additionalProperties": false,
+			"allOf": [{"required": ["name"]}],
+			"anyOf": [{"properties": {"name": {"const": "Ada"}}}, {"required": ["tags"]}]
+		}`,
+		"assembled": `{
+			"type": "object",
+			"properties": {
+				"roles": {"type": "array", "uniqueItems": true, "items": {"enum": ["admin", "user"]}},
+				"meta": {"properties": {"a": true}, "unevaluatedProperties": false}
+			}
+		}`,
+		"contains": `{"type": "array", "contains": {"type": "integer"}, "minContains": 2, "items": {"type": ["integer", "string"]}}`,
+	}
+	// Messages naming several properties follow the iteration of maps, so documents fail each
+	// keyword with at most one property.
+	documents := []string{
+		`{"name": "Ada", "tags": ["go", "json"], "point": [1, 2], "x-beta": true}`,
+		`{"name": 1, "x-beta": "no", "extra": {}}`,
+		`{"name": "Bo", "tags": ["g", "json", "x", "y"]}`,
+		`{"name": "Bo", "point": [1, "2", 3]}`,
+		`{"roles": ["admin", "admin"]}`,
+		`{"roles": ["user", "guest"]}`,
+		`{"meta": {"a": 1, "b": 2}}`,
+		`{"meta": {"a": [1, {"b": null}]}}`,
+		`[1, "two", 3]`,
+		`[1, "two", {}]`,
+		`"text"`,
+	}
+
+	compiler := NewCompiler()
+	for name, source := range schemas {
+		schema, err := compiler.Compile([]byte(source))
+		require.NoError(t, err)
+
+		for _, document := range documents {
+			decoder := json.NewDecoder(bytes.NewReader([]byte(document)))
+			decoder.UseNumber()
+			var instance interface{}
+			require.NoError(t, decoder.Decode(&instance))
+
+			result, err := validateTokens(t, schema, document, nil).Result()
+			require.NoError(t, err)
+			assert.Equal(t, sortedList(schema.Validate(instance).ToList()), sortedList(result.ToList()), "%s: %s", name, document)
+		}
+	}
+}
+
+func TestTokenValidatorVerdicts(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"type": "array",
+		"items": {
+			"type": "object",
+			"properties": {"id": {"type": "integer"}},
+			"anyOf": [{"properties": {"id": {"minimum": 10}}}, {"required": ["name"]}]
+		}
+	}`))
+	require.NoError(t, err)
+
+	var verdicts []TokenVerdict
+	validator := schema.NewTokenValidator(func(verdict TokenVerdict) {
+		verdicts = append(verdicts, verdict)
+	})
+	require.NoError(t, validator.BeginArray())
+	require.NoError(t, validator.BeginObject())
+	require.NoError(t, validator.Key("id"))
+	require.NoError(t, validator.Value(1))
+	assert.True(t, validator.Valid(), "the id conforms to its type, and anyOf may still match")
+	require.NoError(t, validator.EndObject())
+	assert.False(t, validator.Valid(), "the first item fails anyOf")
+
+	require.Len(t, verdicts, 2)
+	assert.Equal(t, "/0/id", verdicts[0].InstanceLocation)
+	assert.True(t, verdicts[0].Valid)
+	assert.Equal(t, "/0", verdicts[1].InstanceLocation)
+	assert.False(t, verdicts[1].Valid)
+
+	_, err = validator.Result()
+	require.ErrorIs(t, err, ErrIncompleteTokens)
+	require.NoError(t, validator.EndArray())
+	result, err := validator.Result()
+	require.NoError(t, err)
+	assert.False(t, result.IsValid())
+	assert.Equal(t, "", verdicts[len(verdicts)-1].InstanceLocation)
+}
+
+func TestTokenValidatorUnexpectedTokens(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{"type": "object"}`))
+	require.NoError(t, err)
+
+	validator := schema.NewTokenValidator(nil)
+	require.ErrorIs(t, validator.Key("a"), ErrUnexpectedToken)
+	require.ErrorIs(t, validator.EndObject(), ErrUnexpectedToken)
+	require.NoError(t, validator.BeginObject())
+	require.ErrorIs(t, validator.Value(1), ErrUnexpectedToken)
+	require.ErrorIs(t, validator.EndArray(), ErrUnexpectedToken)
+	require.NoError(t, validator.Key("a"))
+	require.ErrorIs(t, validator.Key("b"), ErrUnexpectedToken)
+	require.ErrorIs(t, validator.EndObject(), ErrUnexpectedToken)
+	require.NoError(t, validator.Value(1))
+	require.NoError(t, validator.EndObject())
+	require.ErrorIs(t, validator.Value(1), ErrUnexpectedToken)
+
+	result, err := validator.Result()
+	require.NoError(t, err)
+	assert.True(t, result.IsValid())
+}
+
+func TestTokenValidatorKeepsOnlyNeededMembers(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"properties": {
+			"events": {"type": "array", "items": {"type": "object"}},
+			"roles": {"type": "array", "uniqueItems": true}
+		}
+	}`))
+	require.NoError(t, err)
+
+	validator := schema.NewTokenValidator(nil)
+	require.NoError(t, validator.BeginObject())
+	assert.False(t, validator.top().assemble)
+	require.NoError(t, validator.Key("events"))
+	require.NoError(t, validator.BeginArray())
+	assert.False(t, validator.top().assemble, "items only need the results of the events")
+	require.NoError(t, validator.EndArray())
+	require.NoError(t, validator.Key("roles"))
+	require.NoError(t, validator.BeginArray())
+	assert.True(t, validator.top().assemble, "uniqueItems needs the roles themselves")
+}
+
+func TestValidateJSON(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "maximum": 9007199254740993},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"events": {"type": "array", "items": {"required": ["at"]}}
+		},
+		"required": ["id"]
+	}`))
+	require.NoError(t, err)
+
+	documents := []string{
+		`{"id": 9007199254740993, "tags": ["a", "b"], "events": [{"at": 1}, {"at": 2}]}`,
+		`{"id": 9007199254740994}`,
+		`{"id": 1, "tags": ["a", "a"]}`,
+		`{"id": 2, "events": [{}, {"at": 1}]}`,
+		`{"tags": []}`,
+		`[]`,
+	}
+	// Messages naming several properties follow the iteration of maps, so documents fail each
+	// keyword with at most one property.
+	for _, document := range documents {
+		instance, err := decodeInstance([]byte(document))
+		require.NoError(t, err)
+
+		result, err := schema.ValidateJSON([]byte(document))
+		require.NoError(t, err)
+		assert.Equal(t, sortedList(schema.Validate(instance).ToList()), sortedList(result.ToList()), document)
+
+		result, err = schema.ValidateReader(strings.NewReader(document))
+		require.NoError(t, err)
+		assert.Equal(t, schema.Validate(instance).IsValid(), result.IsValid(), document)
+	}
+
+	for _, document := range []string{``, `{"id": 1`, `{"id": }`, `{"id": 1} {"id": 2}`} {
+		_, err := schema.ValidateJSON([]byte(document))
+		require.ErrorIs(t, err, ErrJSONUnmarshalError, document)
+	}
+}
+
+func TestValidateDecoder(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"type": "object", "properties": {"id": {"type": "integer"}, "tags": {"uniqueItems": true}}, "required": ["id"]}`))
+	require.NoError(t, err)
+
+	decoder := json.NewDecoder(strings.NewReader(`[{"id": 1, "tags": ["a", "b"]}, {"id": "2"}, {"tags": ["a", "a"]}, 7]`))
+	decoder.UseNumber()
+	token, err := decoder.Token()
+	require.NoError(t, err)
+	require.Equal(t, json.Delim('['), token)
+	var valid []bool
+	for decoder.More() {
+		result, err := schema.ValidateDecoder(decoder)
+		require.NoError(t, err)
+		valid = append(valid, result.IsValid())
+	}
+	assert.Equal(t, []bool{true, false, false, false}, valid)
+	token, err = decoder.Token()
+	require.NoError(t, err)
+	assert.Equal(t, json.Delim(']'), token, "the decoder is left after each value")
+
+	_, err = schema.ValidateDecoder(json.NewDecoder(strings.NewReader(`{"id": 1`)))
+	assert.ErrorIs(t, err, ErrJSONUnmarshalError)
+}
+
+func TestTokenValidatorRawMessage(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"properties": {"user": {"properties": {"name": {"type": "string"}}}, "ids": {"items": {"type": "integer"}}}}`))
+	require.NoError(t, err)
+
+	var members map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(`{"user": {"name": 5}, "ids": [1, 2]}`), &members))
+	validator := schema.NewTokenValidator(nil)
+	require.NoError(t, validator.BeginObject())
+	for _, name := range []string{"ids", "user"} {
+		require.NoError(t, validator.Key(name))
+		require.NoError(t, validator.Value(members[name]))
+	}
+	require.NoError(t, validator.EndObject())
+	result, err := validator.Result()
+	require.NoError(t, err)
+	assert.False(t, result.IsValid())
+	assert.Equal(t, sortedList(schema.Validate(map[string]interface{}{"user": map[string]interface{}{"name": 5}, "ids": []interface{}{1, 2}}).ToList()), sortedList(result.ToList()))
+
+	validator = schema.NewTokenValidator(nil)
+	assert.ErrorIs(t, validator.Value(json.RawMessage(`1 2`)), ErrUnexpectedToken)
+	validator = schema.NewTokenValidator(nil)
+	assert.ErrorIs(t, validator.Value(json.RawMessage(`[1`)), ErrJSONUnmarshalError)
+}
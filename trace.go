@@ -0,0 +1,131 @@
+package jsonschema
+
+import (
+	"sort"
+)
+
+// TraceEvent is the kind of a TraceStep.
+type TraceEvent int
+
+const (
+	// TraceEnter is emitted when the evaluation of a subschema starts.
+	TraceEnter TraceEvent = iota
+	// TraceKeyword is emitted for every keyword of a subschema once it has been evaluated.
+	TraceKeyword
+	// TraceExit is emitted when the evaluation of a subschema ends.
+	TraceExit
+)
+
+// String returns the name of the event.
+func (e TraceEvent) String() string {
+	switch e {
+	case TraceEnter:
+		return "enter"
+	case TraceKeyword:
+		return "keyword"
+	case TraceExit:
+		return "exit"
+	}
+	return "unknown"
+}
+
+// TraceStep is a step of an evaluation reported by ValidateWithTrace.
+type TraceStep struct {
+	Event          TraceEvent       // Kind of step.
+	Depth          int              // Nesting of the subschema in the evaluation, 1 for the validated schema.
+	SchemaLocation string           // Absolute location of the subschema, such as https://example.com/user.json#/properties/name.
+	Keyword        string           // Evaluated keyword, for TraceKeyword steps.
+	Instance       interface{}      // Instance the subschema is applied to.
+	Valid          bool             // Outcome of the keyword or subschema; always true for TraceEnter steps.
+	Error          *EvaluationError // Error of the keyword, for invalid TraceKeyword steps.
+}
+
+// tracer emits the steps of an evaluation.
+type tracer struct {
+	emit      func(TraceStep)
+	locations map[*Schema]string // JSON Pointer of each subschema of the roots indexed so far.
+}
+
+// ValidateWithTrace validates instance like Validate and reports every evaluation step to trace,
+// in evaluation order, for debuggers showing why an instance is accepted or rejected. Each
+// evaluated subschema, including those reached through references, produces a TraceEnter step,
+// then the steps of the subschemas it applies, a TraceKeyword step per keyword with its outcome,
+// and a TraceExit step with the outcome of the subschema.
+func (s *Schema) ValidateWithTrace(instance interface{}, trace func(TraceStep)) *EvaluationResult {
+	dynamicScope := NewDynamicScope()
+	dynamicScope.assertFormat = s.assertFormat
+	dynamicScope.tracer = &tracer{emit: trace, locations: map[*Schema]string{}}
+
+	return s.validate(instance, dynamicScope)
+}
+
+// enter emits the TraceEnter step of schema, which is on top of the dynamic scope.
+func (t *tracer) enter(schema *Schema, instance interface{}, depth int) {
+	t.emit(TraceStep{
+		Event:          TraceEnter,
+		Depth:          depth,
+		SchemaLocation: t.location(schema),
+		Instance:       instance,
+		Valid:          true,
+	})
+}
+
+// exit emits the TraceKeyword steps and the TraceExit step of schema.
+func (t *tracer) exit(schema *Schema, instance interface{}, depth int, result *EvaluationResult) {
+	step := TraceStep{
+		Event:          TraceKeyword,
+		Depth:          depth,
+		SchemaLocation: t.location(schema),
+		Instance:       instance,
+	}
+
+	reported := map[string]bool{}
+	keywords := evaluationOrder(schema)
+	if schema.Boolean != nil {
+		keywords = []string{"schema"}
+	}
+	for _, keyword := range keywords {
+		reported[keyword] = true
+		step.Keyword, step.Error = keyword, result.Errors[keyword]
+		step.Valid = step.Error == nil
+		t.emit(step)
+	}
+
+	// Errors reported under a keyword other than the one evaluated, such as
+	// minContains failures, come last in a deterministic order.
+	var others []string
+	for keyword := range result.Errors {
+		if !reported[keyword] {
+			others = append(others, keyword)
+		}
+	}
+	sort.Strings(others)
+	for _, keyword := range others {
+		step.Keyword, step.Error, step.Valid = keyword, result.Errors[keyword], false
+		t.emit(step)
+	}
+
+	t.emit(TraceStep{
+		Event:          TraceExit,
+		Depth:          depth,
+		SchemaLocation: step.SchemaLocation,
+		Instance:       instance,
+		Valid:          result.IsValid(),
+	})
+}
+
+// location returns the absolute location of schema, indexing the subschemas of its root on first use.
+func (t *tracer) location(schema *Schema) string {
+	pointer, ok := t.locations[schema]
+	if !ok {
+		walkSchema(schema.getRootSchema(), func(pointer string, subschema *Schema) bool {
+			if _, seen := t.locations[subschema]; seen {
+				return false
+			}
+			t.locations[subschema] = pointer
+			return true
+		})
+		pointer = t.locations[schema]
+	}
+	return schema.GetSchemaLocation(pointer)
+}
@@ -0,0 +1,73 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWithTrace(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.Compile([]byte(`{
+		"$id": "https://example.com/user.json",
+		"type": "object",
+		"properties": {
+			"name": {"$ref": "#/$defs/name"}
+		},
+		"$defs": {
+			"name": {"type": "string", "minLength": 3}
+		}
+	}`))
+	require.NoError(t, err)
+
+	var steps []TraceStep
+	result := schema.ValidateWithTrace(map[string]interface{}{"name": "ab"}, func(step TraceStep) {
+		steps = append(steps, step)
+	})
+	assert.False(t, result.IsValid())
+
+	type step struct {
+		event    TraceEvent
+		depth    int
+		location string
+		keyword  string
+		valid    bool
+	}
+	var got []step
+	for _, s := range steps {
+		got = append(got, step{s.Event, s.Depth, s.SchemaLocation, s.Keyword, s.Valid})
+	}
+	assert.Equal(t, []step{
+		{TraceEnter, 1, "https://example.com/user.json#", "", true},
+		{TraceEnter, 2, "https://example.com/user.json#/properties/name", "", true},
+		{TraceEnter, 3, "https://example.com/user.json#/$defs/name", "", true},
+		{TraceKeyword, 3, "https://example.com/user.json#/$defs/name", "type", true},
+		{TraceKeyword, 3, "https://example.com/user.json#/$defs/name", "minLength", false},
+		{TraceExit, 3, "https://example.com/user.json#/$defs/name", "", false},
+		{TraceKeyword, 2, "https://example.com/user.json#/properties/name", "$ref", false},
+		{TraceExit, 2, "https://example.com/user.json#/properties/name", "", false},
+		{TraceKeyword, 1, "https://example.com/user.json#", "type", true},
+		{TraceKeyword, 1, "https://example.com/user.json#", "properties", false},
+		{TraceExit, 1, "https://example.com/user.json#", "", false},
+	}, got)
+
+	assert.Equal(t, "ab", steps[4].Instance)
+	require.NotNil(t, steps[4].Error)
+	assert.Equal(t, "string_too_short", steps[4].Error.Code)
+	assert.Equal(t, "keyword", TraceKeyword.String())
+}
+
+func TestValidateWithTraceBooleanSchema(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"items": false}`))
+	require.NoError(t, err)
+
+	var keywords []string
+	result := schema.ValidateWithTrace([]interface{}{1}, func(step TraceStep) {
+		if step.Event == TraceKeyword {
+			keywords = append(keywords, step.Keyword)
+		}
+	})
+	assert.False(t, result.IsValid())
+	assert.Equal(t, []string{"schema", "items"}, keywords)
+}
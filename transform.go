@@ -0,0 +1,72 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+// InstanceTransform normalizes the value a schema is about to evaluate, such as trimming strings,
+// and returns the value to evaluate in its place. Transforms receive every value their schemas
+// evaluate, objects and arrays included, and must therefore return the values they do not
+// normalize unchanged. They may be applied to the same value more than once, by each schema
+// evaluating it, and must be idempotent.
+type InstanceTransform func(schema *Schema, value interface{}) (interface{}, error)
+
+// TrimSpace removes leading and trailing white space from strings.
+func TrimSpace(_ *Schema, value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s), nil
+	}
+	return value, nil
+}
+
+// LowercaseEmails lowercases the strings evaluated by schemas whose format is email or idn-email.
+func LowercaseEmails(schema *Schema, value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok && schema.Format != nil && (*schema.Format == "email" || *schema.Format == "idn-email") {
+		return strings.ToLower(s), nil
+	}
+	return value, nil
+}
+
+// RegisterTransform adds transforms applied, in order, to the values evaluated by every schema of
+// the compiler, unless the schema or an enclosing one sets its own with Schema.SetTransforms.
+// Normalization and validation so stay consistent: a value is validated as it will be stored.
+func (c *Compiler) RegisterTransform(transforms ...InstanceTransform) *Compiler {
+	c.Transforms = append(c.Transforms, transforms...)
+	return c
+}
+
+// SetTransforms sets the transforms applied, in order, to the values evaluated by s and its
+// subschemas, overriding the compiler. Calling it without transforms disables them for s. Like
+// the other setters of Schema, it changes the cached schema, so schemas referencing s transform
+// their values too, and it must not be called while s is being validated.
+func (s *Schema) SetTransforms(transforms ...InstanceTransform) *Schema {
+	s.transforms = append([]InstanceTransform{}, transforms...)
+	return s
+}
+
+// instanceTransforms returns the transforms applied to the values evaluated by s.
+func (s *Schema) instanceTransforms() []InstanceTransform {
+	for schema := s; schema != nil; schema = schema.parent {
+		if schema.transforms != nil {
+			return schema.transforms
+		}
+	}
+	if s.compiler == nil {
+		return nil
+	}
+	return s.compiler.Transforms
+}
+
+// transformInstance applies the transforms of s to instance.
+func (s *Schema) transformInstance(instance interface{}, transforms []InstanceTransform) (interface{}, *EvaluationError) {
+	for _, transform := range transforms {
+		transformed, err := transform(s, instance)
+		if err != nil {
+			return nil, NewEvaluationError("transform", "transform_failed", "Value could not be transformed: {error}", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		instance = transformed
+	}
+	return instance, nil
+}
@@ -0,0 +1,26 @@
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformError(t *testing.T) {
+	compiler := NewCompiler().RegisterTransform(func(schema *Schema, value interface{}) (interface{}, error) {
+		if value == "invalid" {
+			return nil, errors.New("unsupported value")
+		}
+		return value, nil
+	})
+	schema, err := compiler.Compile([]byte(`{"items": {"type": "string"}}`))
+	require.NoError(t, err)
+
+	assert.False(t, schema.Validate([]interface{}{"valid", "invalid"}).IsValid())
+
+	list := schema.Validate("invalid").ToList()
+	assert.False(t, list.Valid)
+	assert.Equal(t, map[string]string{"transform": "Value could not be transformed: unsupported value"}, list.Errors)
+}
@@ -0,0 +1,244 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/goccy/go-json"
+)
+
+// typeSchemaDialect is the dialect of the schemas derived from Go types.
+const typeSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Reflect derives a schema from the Go type of v and compiles it with a new compiler; see
+// Compiler.Reflect.
+func Reflect(v interface{}) (*Schema, error) {
+	return NewCompiler().Reflect(v)
+}
+
+// Reflect derives a schema from the Go type of v, or from v itself when it is a reflect.Type, and
+// compiles it. The schema describes the JSON encoding of the type, following encoding/json:
+//
+//   - Booleans, numbers and strings have their JSON type; unsigned integers have a minimum of 0.
+//   - Structs are objects whose properties are named by json struct tags, with the fields of
+//     embedded structs promoted. The keywords of `jsonschema` struct tags, see ParseStructTag,
+//     apply to their field, and fields tagged required are listed in the required keyword. The
+//     string option of json tags makes the property a string.
+//   - Slices and arrays are arrays, arrays having their length as minItems and maxItems; []byte
+//     is a base64 string.
+//   - Maps are objects whose values match additionalProperties; maps keyed by integers have
+//     integer property names.
+//   - Pointers have the schema of the type they point to, and interfaces accept any value.
+//   - Pointers, slices and maps also accept null, which encoding/json encodes their nil values
+//     as, unless they are fields with the omitempty option, which omits them instead.
+//   - time.Time is a date-time string, types implementing encoding.TextMarshaler are strings and
+//     other types implementing json.Marshaler accept any value.
+//
+// Named struct types other than the root are defined once in $defs and referenced with $ref, so
+// recursive types are supported; references to the root use "#". Types registered with
+// RegisterType are referenced by the URI of their schema instead. Channels, functions and
+// complex numbers return ErrUnsupportedGoType, and invalid struct tags ErrInvalidStructTag or
+// ErrUnknownStructTagKeyword.
+func (c *Compiler) Reflect(v interface{}) (*Schema, error) {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	if t == nil {
+		return nil, fmt.Errorf("%w: nil", ErrUnsupportedGoType)
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	r := &typeReflector{compiler: c, refs: map[reflect.Type]string{}, defs: map[string]interface{}{}}
+	var document map[string]interface{}
+	var err error
+	if t.Kind() == reflect.Struct && t.Name() != "" && !isTypeWithFormat(t) {
+		r.refs[t] = "#"
+		document, err = r.object(t)
+	} else {
+		document, err = r.schema(t)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	document["$schema"] = typeSchemaDialect
+	if len(r.defs) > 0 {
+		document["$defs"] = r.defs
+	}
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	return c.Compile(data)
+}
+
+// typeReflector derives the schema of a Go type and of the named struct types it refers to.
+type typeReflector struct {
+	compiler *Compiler
+	refs     map[reflect.Type]string // Reference of each named struct type defined so far.
+	defs     map[string]interface{}  // Schemas of the named struct types, by name.
+}
+
+// schema returns the schema of t, in generic JSON form.
+func (r *typeReflector) schema(t reflect.Type) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if uri, ok := r.compiler.typeSchemaURI(t); ok {
+		return map[string]interface{}{"$ref": uri}, nil
+	}
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		return map[string]interface{}{}, nil
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer", "minimum": 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && !isMarshaler(reflect.PointerTo(t.Elem())) {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := r.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		schema := map[string]interface{}{"type": "array", "items": nullable(t.Elem(), items)}
+		if t.Kind() == reflect.Array {
+			schema["minItems"], schema["maxItems"] = t.Len(), t.Len()
+		}
+		return schema, nil
+	case reflect.Map:
+		return r.mapSchema(t)
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t)
+		}
+		return r.define(t)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedGoType, t)
+}
+
+// define defines the named struct type t in $defs, once, and returns a reference to it.
+func (r *typeReflector) define(t reflect.Type) (map[string]interface{}, error) {
+	if ref, ok := r.refs[t]; ok {
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+
+	name := t.Name()
+	for i := 2; r.defs[name] != nil; i++ {
+		name = t.Name() + strconv.Itoa(i)
+	}
+	// The reference is known before the fields are, so that recursive types refer to themselves.
+	r.refs[t] = "#/$defs/" + escapeJSONPointer(name)
+	r.defs[name] = true
+
+	schema, err := r.object(t)
+	if err != nil {
+		return nil, err
+	}
+	r.defs[name] = schema
+	return map[string]interface{}{"$ref": r.refs[t]}, nil
+}
+
+// object returns the schema of the struct type t.
+func (r *typeReflector) object(t reflect.Type) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	var required []string
+	for _, field := range reflectFields(t) {
+		structField := t.FieldByIndex(field.index)
+		tag, err := ParseStructTag(structField.Tag.Get(StructTagKey))
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %s: %w", structField.Name, t, err)
+		}
+		if tag.Ignore {
+			continue
+		}
+
+		var schema map[string]interface{}
+		if field.quoted {
+			schema = map[string]interface{}{"type": "string"}
+		} else if schema, err = r.schema(structField.Type); err != nil {
+			return nil, err
+		}
+		if !field.omitEmpty {
+			schema = nullable(structField.Type, schema)
+		}
+		for keyword, value := range tag.Keywords {
+			schema[keyword] = value
+		}
+		properties[field.name] = schema
+		if tag.Required {
+			required = append(required, field.name)
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// mapSchema returns the schema of the map type t.
+func (r *typeReflector) mapSchema(t reflect.Type) (map[string]interface{}, error) {
+	values, err := r.schema(t.Elem())
+	if err != nil {
+		return nil, err
+	}
+	schema := map[string]interface{}{"type": "object", "additionalProperties": nullable(t.Elem(), values)}
+
+	key := t.Key()
+	switch {
+	case key.Kind() == reflect.String || key.Implements(textMarshalerType):
+	case key.Kind() >= reflect.Int && key.Kind() <= reflect.Int64:
+		schema["propertyNames"] = map[string]interface{}{"pattern": "^-?[0-9]+$"}
+	case key.Kind() >= reflect.Uint && key.Kind() <= reflect.Uintptr:
+		schema["propertyNames"] = map[string]interface{}{"pattern": "^[0-9]+$"}
+	default:
+		return nil, fmt.Errorf("%w: map key %s", ErrUnsupportedGoType, key)
+	}
+	return schema, nil
+}
+
+// nullable returns schema, the schema of the Go type t, accepting null as well when t is a
+// pointer, slice or map type, whose nil values encoding/json encodes as null.
+func nullable(t reflect.Type, schema map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+	default:
+		return schema
+	}
+	if len(schema) == 0 {
+		return schema // Any value, null included.
+	}
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+		return schema
+	}
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
+
+// isTypeWithFormat reports whether the struct type t is encoded as a single value rather than
+// as an object.
+func isTypeWithFormat(t reflect.Type) bool {
+	return t == timeType || isMarshaler(t) || isMarshaler(reflect.PointerTo(t))
+}
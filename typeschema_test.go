@@ -0,0 +1,160 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTreeNode struct {
+	Name     string          `json:"name" jsonschema:"required,minLength=1"`
+	Children []*testTreeNode `json:"children,omitempty"`
+	Owner    *testOwner      `json:"owner,omitempty"`
+}
+
+type testOwner struct {
+	Email string `json:"email" jsonschema:"required,format=email"`
+	Team  *testTreeNode
+}
+
+type testProduct struct {
+	testAudit
+	ID        int64               `json:"id,string"`
+	Price     float64             `json:"price" jsonschema:"minimum=0,exclusiveMaximum=1000"`
+	Stock     uint                `json:"stock"`
+	Tags      []string            `json:"tags" jsonschema:"uniqueItems"`
+	Size      [2]int              `json:"size"`
+	Image     []byte              `json:"image,omitempty"`
+	Ratings   map[int]float64     `json:"ratings"`
+	Attrs     map[string]any      `json:"attrs"`
+	Color     testColor           `json:"color"`
+	UpdatedAt *time.Time          `json:"updatedAt"`
+	Secret    string              `json:"-"`
+	Internal  string              `json:"internal" jsonschema:"-"`
+	Variants  []struct{ SKU int } `json:"variants"`
+}
+
+func TestReflect(t *testing.T) {
+	schema, err := Reflect(testProduct{})
+	require.NoError(t, err)
+
+	properties := schema.Properties
+	require.NotNil(t, properties)
+	assert.ElementsMatch(t, []string{
+		"createdBy", "createdAt", "version", "id", "price", "stock", "tags", "size",
+		"image", "ratings", "attrs", "color", "updatedAt", "variants",
+	}, sortedKeys(*properties))
+
+	assert.Equal(t, SchemaType{"string"}, (*properties)["id"].Type)
+	assert.Equal(t, "date-time", *(*properties)["createdAt"].Format)
+	assert.Equal(t, "date-time", *(*properties)["updatedAt"].Format)
+	assert.Equal(t, "base64", *(*properties)["image"].ContentEncoding)
+	assert.Equal(t, SchemaType{"string"}, (*properties)["color"].Type, "text marshalers are strings")
+
+	valid := map[string]interface{}{
+		"createdBy": "ann", "createdAt": "2024-05-01T12:00:00Z", "version": 1,
+		"id": "12", "price": 9.5, "stock": 3, "tags": []interface{}{"a"}, "size": []interface{}{1, 2},
+		"ratings": map[string]interface{}{"5": 1.0}, "attrs": map[string]interface{}{"any": true},
+		"color": "red", "updatedAt": "2024-05-01T12:00:00Z", "variants": []interface{}{map[string]interface{}{"SKU": 1}},
+	}
+	assert.True(t, schema.Validate(valid).IsValid())
+
+	for property, value := range map[string]interface{}{
+		"price":    1000,
+		"stock":    -1,
+		"tags":     []interface{}{"a", "a"},
+		"size":     []interface{}{1},
+		"ratings":  map[string]interface{}{"five": 1.0},
+		"variants": []interface{}{map[string]interface{}{"SKU": "a"}},
+	} {
+		invalid := copyJSON(valid).(map[string]interface{})
+		invalid[property] = value
+		assert.False(t, schema.Validate(invalid).IsValid(), property)
+	}
+}
+
+func TestReflectRecursiveTypes(t *testing.T) {
+	schema, err := NewCompiler().Reflect(reflect.TypeOf(&testTreeNode{}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name"}, schema.Required)
+	require.Contains(t, schema.Defs, "testOwner")
+	assert.Equal(t, "#/$defs/testOwner", (*schema.Properties)["owner"].Ref)
+	team := (*schema.Defs["testOwner"].Properties)["Team"]
+	require.Len(t, team.AnyOf, 2, "the pointer without omitempty may be null")
+	assert.Equal(t, "#", team.AnyOf[0].Ref)
+
+	tree := map[string]interface{}{
+		"name": "root",
+		"children": []interface{}{
+			map[string]interface{}{"name": "leaf", "owner": map[string]interface{}{
+				"email": "ann@example.com",
+				"Team":  map[string]interface{}{"name": "core"},
+			}},
+		},
+	}
+	assert.True(t, schema.Validate(tree).IsValid())
+
+	tree["children"].([]interface{})[0].(map[string]interface{})["owner"].(map[string]interface{})["Team"] = map[string]interface{}{"name": ""}
+	assert.False(t, schema.Validate(tree).IsValid())
+}
+
+type testLinkedNode struct {
+	Name string              `json:"name"`
+	M    map[string]int      `json:"m"`
+	Next *testLinkedNode     `json:"next"`
+	Tags []string            `json:"tags"`
+	Refs []*testLinkedNode   `json:"refs"`
+	Meta map[string]*float64 `json:"meta"`
+	Skip *int                `json:"skip,omitempty"`
+}
+
+func TestReflectNilValues(t *testing.T) {
+	schema, err := Reflect(testLinkedNode{})
+	require.NoError(t, err)
+
+	one := 1.0
+	for name, value := range map[string]testLinkedNode{
+		"zero value":   {},
+		"nil pointers": {Name: "a", Next: &testLinkedNode{Name: "b"}},
+		"nil items":    {Name: "a", Refs: []*testLinkedNode{nil}, Meta: map[string]*float64{"a": nil, "b": &one}},
+	} {
+		result := schema.Validate(value)
+		assert.True(t, result.IsValid(), "%s: %v", name, result.ToList())
+	}
+
+	assert.False(t, schema.Validate(map[string]interface{}{"skip": nil}).IsValid(), "omitempty fields are never null")
+	assert.False(t, schema.Validate(map[string]interface{}{"next": "a"}).IsValid())
+}
+
+func TestReflectRegisteredTypes(t *testing.T) {
+	compiler := NewCompiler()
+	_, err := compiler.Compile([]byte(`{"type": "object", "required": ["email"]}`), "https://example.com/owner.json")
+	require.NoError(t, err)
+	RegisterType[testOwner](compiler, "https://example.com/owner.json")
+
+	schema, err := compiler.Reflect(testTreeNode{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/owner.json", (*schema.Properties)["owner"].Ref)
+	assert.False(t, schema.Validate(map[string]interface{}{"name": "a", "owner": map[string]interface{}{}}).IsValid())
+}
+
+func TestReflectErrors(t *testing.T) {
+	_, err := Reflect(struct {
+		Done chan bool `json:"done"`
+	}{})
+	require.ErrorIs(t, err, ErrUnsupportedGoType)
+
+	_, err = Reflect(map[float64]string{})
+	require.ErrorIs(t, err, ErrUnsupportedGoType)
+
+	_, err = Reflect(nil)
+	require.ErrorIs(t, err, ErrUnsupportedGoType)
+
+	_, err = Reflect(struct {
+		Name string `json:"name" jsonschema:"minLength=a"`
+	}{})
+	require.ErrorIs(t, err, ErrInvalidStructTag)
+}
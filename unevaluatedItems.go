@@ -32,7 +32,9 @@ func evaluateUnevaluatedItems(schema *Schema, data interface{}, evaluatedProps m
 		// Evaluate un-evaluated items against the schema.
 		for i, item := range items {
 			if _, evaluated := evaluatedItems[i]; !evaluated {
+				dynamicScope.pushIndex(i)
 				result, _, _ := schema.UnevaluatedItems.evaluate(item, dynamicScope)
+				dynamicScope.popInstance()
 				if result != nil {
 					result.SetEvaluationPath(fmt.Sprintf("/unevaluatedItems/%d", i)).
 						SetSchemaLocation(schema.GetSchemaLocation(fmt.Sprintf("/unevaluatedItems/%d", i))).
@@ -48,6 +50,12 @@ func evaluateUnevaluatedItems(schema *Schema, data interface{}, evaluatedProps m
 		}
 	}
 
+	if schema.UnevaluatedItems.IsFalse() {
+		if err := notAllowedItemsError("unevaluatedItems", invalid_indexs); err != nil {
+			return results, err
+		}
+	}
+
 	if len(invalid_indexs) == 1 {
 		return results, NewEvaluationError("unevaluatedItems", "unevaluated_item_mismatch", "Item at index {index} does not match the unevaluatedItems schema", map[string]interface{}{
 			"index": invalid_indexs[0],
@@ -31,7 +31,9 @@ func evaluateUnevaluatedProperties(schema *Schema, data interface{}, evaluatedPr
 	for propName, propValue := range object {
 		if _, evaluated := evaluatedProps[propName]; !evaluated {
 			// If property has not been evaluated, validate it against the "unevaluatedProperties" schema.
+			dynamicScope.pushInstance(propName)
 			result, _, _ := schema.UnevaluatedProperties.evaluate(propValue, dynamicScope)
+			dynamicScope.popInstance()
 			if result != nil {
 				result.SetEvaluationPath("/unevaluatedProperties").
 					SetSchemaLocation(schema.GetSchemaLocation("/unevaluatedProperties")).
@@ -47,6 +49,12 @@ func evaluateUnevaluatedProperties(schema *Schema, data interface{}, evaluatedPr
 		}
 	}
 
+	if schema.UnevaluatedProperties.IsFalse() {
+		if err := notAllowedPropertiesError("properties", "unevaluated_property_not_allowed", "Property {property} is not allowed", "unevaluated_properties_not_allowed", "Properties {properties} are not allowed", invalid_properties); err != nil {
+			return results, err
+		}
+	}
+
 	if len(invalid_properties) == 1 {
 		return results, NewEvaluationError("properties", "unevaluated_property_mismatch", "Property {property} does not match the unevaluatedProperties schema", map[string]interface{}{
 			"property": fmt.Sprintf("'%s'", invalid_properties[0]),
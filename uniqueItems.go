@@ -2,6 +2,9 @@ package jsonschema
 
 import (
 	"fmt"
+	"hash/maphash"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/goccy/go-json"
@@ -23,30 +26,38 @@ func evaluateUniqueItems(schema *Schema, data []interface{}) *EvaluationError {
 		return nil // If uniqueItems is not set to true, no validation is required.
 	}
 
-	// Using a map to track all indices of each item using serialized strings as keys.
-	seen := make(map[string][]int)
+	// Items are grouped by a canonical hash, equal for values equal under JSON Schema such as 1
+	// and 1.0, so that arrays are checked in linear time; items with the same hash are compared
+	// to tell duplicates from collisions.
+	hasher := itemHasher{seed: maphash.MakeSeed()}
+	buckets := make(map[uint64][]int, len(data)) // Indices of the groups of each hash.
+	var groups [][]int                           // Indices of the items of each group of equal items.
 	for index, item := range data {
-		itemBytes, err := json.Marshal(item)
-		if err != nil {
-			// Handle serialization error
-			return NewEvaluationError("uniqueItems", "item_serialization_error", "Error serializing item at index {index}", map[string]interface{}{
-				"index": fmt.Sprint(index),
-			})
+		hash := hasher.hash(item)
+		found := false
+		for _, group := range buckets[hash] {
+			if equalJSON(data[groups[group][0]], item) {
+				groups[group] = append(groups[group], index)
+				found = true
+				break
+			}
+		}
+		if !found {
+			buckets[hash] = append(buckets[hash], len(groups))
+			groups = append(groups, []int{index})
 		}
-		itemKey := string(itemBytes)
-		seen[itemKey] = append(seen[itemKey], index) // Append the current index to the list of indices for this item
 	}
 
-	// Prepare to report locations of all duplicate items
+	// Prepare to report locations of all duplicate items, in the order of their first occurrence
 	var duplicates []string
-	for _, indices := range seen {
-		if len(indices) > 1 { // Only consider keys with more than one index as duplicates
+	for _, indices := range groups {
+		if len(indices) > 1 { // Only consider groups with more than one index as duplicates
 			// Convert indices to 1-based for user-friendly output
-			for i := range indices {
-				indices[i] += 1
+			positions := make([]string, len(indices))
+			for i, index := range indices {
+				positions[i] = strconv.Itoa(index + 1)
 			}
-			// Format the indices as a tuple string
-			duplicates = append(duplicates, fmt.Sprintf("(%s)", strings.Trim(strings.Join(strings.Fields(fmt.Sprint(indices)), ", "), "[]")))
+			duplicates = append(duplicates, fmt.Sprintf("(%s)", strings.Join(positions, ", ")))
 		}
 	}
 
@@ -58,3 +69,98 @@ func evaluateUniqueItems(schema *Schema, data []interface{}) *EvaluationError {
 	}
 	return nil
 }
+
+// Tags of the kinds of values hashed by itemHasher.
+const (
+	hashNull uint64 = iota + 1
+	hashFalse
+	hashTrue
+	hashNumber
+	hashString
+	hashArray
+	hashObject
+	hashOther
+)
+
+// itemHasher hashes generic JSON values such that values equal under equalJSON hash the same,
+// without serializing them. Objects are hashed independently of the order of their properties.
+type itemHasher struct {
+	seed maphash.Seed
+}
+
+// mix combines a hash with a value.
+func mix(hash, value uint64) uint64 {
+	hash ^= value
+	hash *= 0x100000001b3 // The 64-bit FNV prime.
+	return hash ^ hash>>32
+}
+
+func (h itemHasher) hash(value interface{}) uint64 {
+	switch value := value.(type) {
+	case nil:
+		return hashNull
+	case bool:
+		if value {
+			return hashTrue
+		}
+		return hashFalse
+	case string:
+		return mix(hashString, maphash.String(h.seed, value))
+	case json.Number, float64, float32, int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
+		return mix(hashNumber, math.Float64bits(nearestFloat(value)))
+	case []interface{}:
+		hash := mix(hashArray, uint64(len(value)))
+		for _, item := range value {
+			hash = mix(hash, h.hash(item))
+		}
+		return hash
+	case map[string]interface{}:
+		var sum uint64
+		for key, item := range value {
+			sum += mix(maphash.String(h.seed, key), h.hash(item))
+		}
+		return mix(mix(hashObject, uint64(len(value))), sum)
+	default:
+		return mix(hashOther, maphash.String(h.seed, canonicalJSON(value)))
+	}
+}
+
+// nearestFloat returns the float64 nearest to a number, equal for numbers equal under equalJSON.
+// Numbers that are close but different may have the same nearest float64, which only makes their
+// hashes collide.
+func nearestFloat(value interface{}) float64 {
+	var f float64
+	switch value := value.(type) {
+	case json.Number:
+		f, _ = strconv.ParseFloat(string(value), 64) // Out of range numbers are infinities.
+	case float64:
+		f = value
+	case float32:
+		// Compared through their shortest representation, such as 0.1, as with numberRat.
+		f, _ = strconv.ParseFloat(strconv.FormatFloat(float64(value), 'g', -1, 32), 64)
+	case int:
+		f = float64(value)
+	case int64:
+		f = float64(value)
+	case int32:
+		f = float64(value)
+	case int16:
+		f = float64(value)
+	case int8:
+		f = float64(value)
+	case uint:
+		f = float64(value)
+	case uint64:
+		f = float64(value)
+	case uint32:
+		f = float64(value)
+	case uint16:
+		f = float64(value)
+	case uint8:
+		f = float64(value)
+	}
+	if f == 0 {
+		return 0 // Negative zero equals zero.
+	}
+	return f
+}
@@ -0,0 +1,102 @@
+package jsonschema
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemHasherEqualValues(t *testing.T) {
+	hasher := itemHasher{seed: maphash.MakeSeed()}
+	equal := [][]interface{}{
+		{1, int64(1), 1.0, float32(1), uint8(1), json.Number("1"), json.Number("1.0"), json.Number("1e0")},
+		{0.5, float32(0.5), json.Number("0.5"), json.Number("5e-1")},
+		{0.1, json.Number("0.1")},
+		{1e20, json.Number("100000000000000000000"), json.Number("1e20")},
+		{uint64(math.MaxUint64), json.Number("18446744073709551615")},
+		{
+			map[string]interface{}{"a": 1, "b": []interface{}{"x", nil, true}},
+			map[string]interface{}{"b": []interface{}{"x", nil, true}, "a": json.Number("1.0")},
+		},
+	}
+	for _, values := range equal {
+		for _, value := range values[1:] {
+			require.True(t, equalJSON(values[0], value), "%v %v", values[0], value)
+			assert.Equal(t, hasher.hash(values[0]), hasher.hash(value), "%#v %#v", values[0], value)
+		}
+	}
+
+	assert.NotEqual(t, hasher.hash(1), hasher.hash("1"))
+	assert.NotEqual(t, hasher.hash([]interface{}{1, 2}), hasher.hash([]interface{}{2, 1}))
+	assert.NotEqual(t, hasher.hash(map[string]interface{}{"a": 1}), hasher.hash(map[string]interface{}{"a": 2}))
+}
+
+func TestUniqueItemsDuplicateGroups(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"uniqueItems": true}`))
+	require.NoError(t, err)
+
+	result := schema.Validate([]interface{}{
+		map[string]interface{}{"id": 1, "tags": []interface{}{"a"}},
+		"x",
+		map[string]interface{}{"tags": []interface{}{"a"}, "id": 1.0},
+		"y",
+		"x",
+		map[string]interface{}{"id": 1, "tags": []interface{}{"b"}},
+		"x",
+	})
+	require.False(t, result.IsValid())
+	assert.Equal(t, "Found duplicates at the following index groups: (1, 3), (2, 5, 7)", result.Errors["uniqueItems"].Error())
+}
+
+func TestUniqueItemsHashCollisions(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"uniqueItems": true}`))
+	require.NoError(t, err)
+
+	// Both numbers are nearest to the same float64, so that their hashes collide.
+	collision := []interface{}{json.Number("0.1"), json.Number("0.10000000000000000001")}
+	hasher := itemHasher{seed: maphash.MakeSeed()}
+	require.Equal(t, hasher.hash(collision[0]), hasher.hash(collision[1]))
+	assert.True(t, schema.Validate(collision).IsValid())
+	assert.False(t, schema.Validate(append(collision, 0.1)).IsValid())
+}
+
+func TestUniqueItemsLargeArrays(t *testing.T) {
+	schema, err := NewCompiler().Compile([]byte(`{"uniqueItems": true}`))
+	require.NoError(t, err)
+
+	items := make([]interface{}, 100000)
+	for i := range items {
+		items[i] = map[string]interface{}{
+			"id":    i,
+			"name":  fmt.Sprintf("item %d", i),
+			"price": float64(i) / 4,
+			"tags":  []interface{}{"a", "b", i % 7},
+		}
+	}
+
+	start := time.Now()
+	assert.True(t, schema.Validate(items).IsValid())
+	items = append(items, map[string]interface{}{"tags": []interface{}{"a", "b", 1}, "price": 0.25, "name": "item 1", "id": json.Number("1")})
+	assert.False(t, schema.Validate(items).IsValid())
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func BenchmarkUniqueItems(b *testing.B) {
+	schema, err := NewCompiler().Compile([]byte(`{"uniqueItems": true}`))
+	require.NoError(b, err)
+	items := make([]interface{}, 10000)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": i, "name": fmt.Sprintf("item %d", i), "price": float64(i) / 4}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		schema.Validate(items)
+	}
+}
@@ -1,11 +1,13 @@
 package jsonschema
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"net/url"
 	"path"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/goccy/go-json"
@@ -49,9 +51,9 @@ func getDataType(v interface{}) string {
 		if _, ok := new(big.Int).SetString(string(v), 10); ok {
 			return "integer" // json.Number without a decimal part, can be considered an integer
 		}
-		// Fallback to big float to check if it is an integer
-		if bigFloat, ok := new(big.Float).SetString(string(v)); ok {
-			if _, acc := bigFloat.Int(nil); acc == big.Exact {
+		// Fall back to an exact rational, as a float rounds 9007199254740993.5 to an integer
+		if r, ok := new(big.Rat).SetString(string(v)); ok {
+			if r.IsInt() {
 				return "integer"
 			}
 			return "number"
@@ -100,8 +102,8 @@ func resolveRelativeURI(baseURI, relativeURL string) string {
 		return relativeURL
 	}
 	base, err := url.Parse(baseURI)
-	if err != nil || base.Scheme == "" || base.Host == "" {
-		return relativeURL // Return the original if there's a base URL parsing error
+	if err != nil || base.Scheme == "" || base.Host == "" && !strings.HasPrefix(base.Path, "/") {
+		return relativeURL // Return the original if the base is not a hierarchical URL, such as a URN
 	}
 	rel, err := url.Parse(relativeURL)
 	if err != nil {
@@ -122,7 +124,7 @@ func getBaseURI(id string) string {
 		return ""
 	}
 	u, err := url.Parse(id)
-	if err != nil {
+	if err != nil || u.Scheme == "" || u.Host == "" && !strings.HasPrefix(u.Path, "/") {
 		return ""
 	}
 	if strings.HasSuffix(u.Path, "/") {
@@ -135,9 +137,6 @@ func getBaseURI(id string) string {
 	if u.Path != "/" && !strings.HasSuffix(u.Path, "/") {
 		u.Path += "/"
 	}
-	if u.Scheme == "" || u.Host == "" {
-		return ""
-	}
 	return u.String()
 }
 
@@ -154,3 +153,130 @@ func splitRef(ref string) (baseURI string, anchor string) {
 func isJSONPointer(s string) bool {
 	return strings.HasPrefix(s, "/")
 }
+
+// isYAMLPath reports whether a URL or file path refers to a YAML document by its extension.
+func isYAMLPath(uri string) bool {
+	ext := strings.ToLower(path.Ext(uri))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// escapeJSONPointer escapes a reference token for use in a JSON Pointer, as defined in RFC 6901.
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// numberRat converts a numeric instance value of any Go numeric type, or a json.Number, to a
+// rational, reporting whether value is a number.
+func numberRat(value interface{}) (*big.Rat, bool) {
+	switch value.(type) {
+	case json.Number, float64, float32, int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8:
+		r, err := convertToBigRat(value)
+		return r, err == nil
+	}
+	return nil, false
+}
+
+// equalJSON reports whether two instance values are equal as defined by JSON Schema: numbers are
+// equal when their mathematical values are, whatever their Go types, and arrays and objects when
+// their items and properties are.
+func equalJSON(a, b interface{}) bool {
+	if ra, ok := numberRat(a); ok {
+		rb, ok := numberRat(b)
+		return ok && ra.Cmp(rb) == 0
+	}
+	if _, ok := numberRat(b); ok {
+		return false
+	}
+	switch a := a.(type) {
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok {
+			if len(a) != len(b) {
+				return false
+			}
+			for i := range a {
+				if !equalJSON(a[i], b[i]) {
+					return false
+				}
+			}
+			return true
+		}
+	case map[string]interface{}:
+		if b, ok := b.(map[string]interface{}); ok {
+			if len(a) != len(b) {
+				return false
+			}
+			for key, value := range a {
+				other, ok := b[key]
+				if !ok || !equalJSON(value, other) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	if isContainer(a) || isContainer(b) {
+		return canonicalJSON(a) == canonicalJSON(b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// isContainer reports whether value is an array or an object.
+func isContainer(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// decodeExactJSON decodes a JSON value, keeping numbers that float64 cannot represent exactly,
+// such as 9007199254740993, as json.Number. Other numbers are float64, as with json.Unmarshal.
+func decodeExactJSON(data []byte, value *interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(value); err != nil {
+		return err
+	}
+	*value = exactNumbers(*value)
+	return nil
+}
+
+// exactNumbers replaces the json.Number values of a generic JSON value by float64 values where
+// the conversion is exact.
+func exactNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = exactNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = exactNumbers(item)
+		}
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return v
+		}
+		exact, ok := new(big.Rat).SetString(string(v))
+		if !ok {
+			return v
+		}
+		if shortest, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64)); ok && shortest.Cmp(exact) == 0 {
+			return f
+		}
+	}
+	return value
+}
+
+// decodeInstance decodes a JSON document into an instance, keeping numbers exact.
+func decodeInstance(data []byte) (interface{}, error) {
+	instance, err := parseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONUnmarshalError, err)
+	}
+	return instance, nil
+}
@@ -3,17 +3,85 @@ package jsonschema
 // Evaluate checks if the given instance conforms to the schema.
 func (s *Schema) Validate(instance interface{}) *EvaluationResult {
 	dynamicScope := NewDynamicScope()
-	result, _, _ := s.evaluate(instance, dynamicScope)
+	dynamicScope.assertFormat = s.assertFormat
+
+	return s.validate(instance, dynamicScope)
+}
+
+// ValidateWithAssertFormat checks if the given instance conforms to the schema like Validate, but
+// enables or disables format assertion for this call only, overriding the schema, the compiler
+// and the dialect. Referenced schemas are evaluated with the same setting.
+func (s *Schema) ValidateWithAssertFormat(instance interface{}, assert bool) *EvaluationResult {
+	dynamicScope := NewDynamicScope()
+	dynamicScope.assertFormat = &assert
 
+	return s.validate(instance, dynamicScope)
+}
+
+// validate evaluates the instance, timing the validation when the compiler collects stats or
+// failures.
+func (s *Schema) validate(instance interface{}, dynamicScope *DynamicScope) *EvaluationResult {
+	dynamicScope.suppressions = s.compiler.activeSuppressions()
+	if s.compiler != nil && (s.compiler.CollectStats || s.compiler.CollectFailures) {
+		if s.compiler.CollectFailures {
+			dynamicScope.deprecated = make(map[*Schema]bool)
+		}
+		start := s.compiler.Now()
+		result := s.validateInstance(instance, dynamicScope)
+		s.compiler.registry().recordValidation(s, s.compiler.Now().Sub(start), result, s.compiler.CollectFailures)
+		if len(dynamicScope.deprecated) > 0 {
+			s.compiler.registry().recordDeprecated(s, dynamicScope.deprecated)
+		}
+		return result
+	}
+	return s.validateInstance(instance, dynamicScope)
+}
+
+// validateInstance converts the instance with the registered type adapters and evaluates it.
+func (s *Schema) validateInstance(instance interface{}, dynamicScope *DynamicScope) *EvaluationResult {
+	instance, err := s.adaptInstance(instance)
+	if err != nil {
+		result := dynamicScope.newResult(s)
+		result.AddError(err)
+		return result
+	}
+
+	result, _, _ := s.evaluate(instance, dynamicScope)
 	return result
 }
 
 func (s *Schema) evaluate(instance interface{}, dynamicScope *DynamicScope) (*EvaluationResult, map[string]bool, map[int]bool) {
+	// Members of the values validated from tokens were evaluated as soon as they were complete.
+	if value, ok := instance.(*tokenValue); ok {
+		if result := value.result(s); result != nil {
+			return result, nil, nil
+		}
+	}
+
+	// Subschemas beyond the budget of the validation are not evaluated, and cannot be valid.
+	if dynamicScope.budget != nil && !dynamicScope.budget.enter(dynamicScope.Size()+1) {
+		return dynamicScope.newResult(s).SetInvalid(), nil, nil
+	}
+
+	if transforms := s.instanceTransforms(); len(transforms) > 0 {
+		transformed, err := s.transformInstance(instance, transforms)
+		if err != nil {
+			result := dynamicScope.newResult(s)
+			result.AddError(err)
+			return result, nil, nil
+		}
+		instance = transformed
+	}
+
 	dynamicScope.Push(s)
-	result := NewEvaluationResult(s)
+	result := dynamicScope.newResult(s)
 
-	evaluatedProps := make(map[string]bool)
-	evaluatedItems := make(map[int]bool)
+	if dynamicScope.tracer != nil {
+		dynamicScope.tracer.enter(s, instance, dynamicScope.Size())
+	}
+
+	evaluatedProps := dynamicScope.newPropertySet()
+	evaluatedItems := dynamicScope.newItemSet()
 
 	if s.Boolean != nil {
 		// Check if the schema is a boolean
@@ -28,11 +96,6 @@ func (s *Schema) evaluate(instance interface{}, dynamicScope *DynamicScope) (*Ev
 		// 	}
 		// }
 
-		// Compile patterns for PatternProperties if not already compiled
-		if s.PatternProperties != nil {
-			s.compilePatterns()
-		}
-
 		// Check if there is a resolved reference and validate against it if present
 		if s.ResolvedRef != nil {
 			refResult, props, items := s.ResolvedRef.evaluate(instance, dynamicScope)
@@ -49,6 +112,8 @@ func (s *Schema) evaluate(instance interface{}, dynamicScope *DynamicScope) (*Ev
 
 			mergeStringMaps(evaluatedProps, props)
 			mergeIntMaps(evaluatedItems, items)
+		} else if s.Ref != "" {
+			s.evaluateUnresolvedRef("$ref", s.Ref, result)
 		}
 
 		if s.ResolvedDynamicRef != nil {
@@ -76,6 +141,8 @@ func (s *Schema) evaluate(instance interface{}, dynamicScope *DynamicScope) (*Ev
 
 			mergeStringMaps(evaluatedProps, props)
 			mergeIntMaps(evaluatedItems, items)
+		} else if s.DynamicRef != "" {
+			s.evaluateUnresolvedRef("$dynamicRef", s.DynamicRef, result)
 		}
 
 		// Validation keywords for any instance type
@@ -184,7 +251,7 @@ func (s *Schema) evaluate(instance interface{}, dynamicScope *DynamicScope) (*Ev
 		}
 
 		if s.Format != nil {
-			formatError := evaluateFormat(s, instance)
+			formatError := evaluateFormat(s, instance, dynamicScope)
 			if formatError != nil {
 				result.AddError(formatError)
 			}
@@ -251,6 +318,32 @@ func (s *Schema) evaluate(instance interface{}, dynamicScope *DynamicScope) (*Ev
 				result.AddError(contentError)
 			}
 		}
+
+		if s.keywords != nil {
+			s.evaluateKeywords(instance, result)
+		}
+	}
+
+	if s.errorMessages != nil {
+		result.applyErrorMessages(s.errorMessages, instance)
+	}
+	if s.severities != nil {
+		result.applySeverities(s.severities)
+	}
+	if dynamicScope.suppressions != nil && len(result.Errors) > 0 {
+		result.applySuppressions(dynamicScope.suppressions, dynamicScope.instancePath)
+	}
+	if dynamicScope.coverage != nil {
+		dynamicScope.coverage.record(s, result.IsValid())
+	}
+	if dynamicScope.deprecated != nil && s.Deprecated != nil && *s.Deprecated {
+		dynamicScope.deprecated[s] = true
+	}
+	if dynamicScope.tracer != nil {
+		dynamicScope.tracer.exit(s, instance, dynamicScope.Size(), result)
+	}
+	if dynamicScope.budget != nil {
+		dynamicScope.budget.exit(result)
 	}
 
 	// Pop the schema from the dynamic scope
@@ -532,7 +625,16 @@ func evaluateArray(schema *Schema, data interface{}, evaluatedProps map[string]b
 
 // DynamicScope struct defines a stack specifically for handling Schema types
 type DynamicScope struct {
-	schemas []*Schema // Slice storing pointers to Schema
+	schemas      []*Schema         // Slice storing pointers to Schema
+	coverage     *Coverage         // Records the evaluated schemas when validating through a Coverage
+	assertFormat *bool             // Overrides format assertion for the whole evaluation when set
+	tracer       *tracer           // Receives the evaluation steps when validating with ValidateWithTrace
+	budget       *budgetState      // Limits the evaluation when validating with ValidateWithBudget
+	deprecated   map[*Schema]bool  // Collects the deprecated schemas evaluated while the compiler collects failures
+	suppressions []SuppressionRule // Suppression rules of the validation, see Compiler.RegisterSuppression
+	instancePath []string          // Unescaped segments of the instance location evaluated, tracked with suppressions
+
+	allocator EvaluationAllocator // Supplies results and sets when validating with ValidateWithAllocator
 }
 
 // NewDynamicScope creates and returns a new empty DynamicScope
@@ -0,0 +1,309 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// ValidatorTagKey is the key of the struct tags read by github.com/go-playground/validator.
+const ValidatorTagKey = "validate"
+
+// validatorFormats maps validator tags to the format with the same meaning.
+var validatorFormats = map[string]string{
+	"email":            "email",
+	"url":              "uri",
+	"uri":              "uri",
+	"uuid":             "uuid",
+	"hostname_rfc1123": "hostname",
+	"ipv4":             "ipv4",
+	"ipv6":             "ipv6",
+}
+
+// validatorPatterns maps validator tags to the pattern with the same meaning.
+var validatorPatterns = map[string]string{
+	"alpha":       "^[a-zA-Z]+$",
+	"alphanum":    "^[a-zA-Z0-9]+$",
+	"numeric":     "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":      "^[0-9]+$",
+	"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+	"lowercase":   "^[^A-Z]*$",
+	"uppercase":   "^[^a-z]*$",
+}
+
+// validatorBounds names the keywords holding the lower and upper bound of a value of each kind,
+// as validator applies min, max and len to the length of strings, slices and maps.
+var validatorBounds = map[string][2]string{
+	"string": {"minLength", "maxLength"},
+	"number": {"minimum", "maximum"},
+	"array":  {"minItems", "maxItems"},
+	"object": {"minProperties", "maxProperties"},
+}
+
+// ParseValidatorTag converts a github.com/go-playground/validator tag of a field of type t, such
+// as `validate:"required,gte=1,email"`, into the equivalent jsonschema struct tag, to ease the
+// migration of types from tag-based validation to schemas.
+//
+// The supported tags are required, omitempty, -, min, max, len, eq, gt, gte, lt, lte, oneof,
+// unique, the formats email, url, uri, uuid, hostname_rfc1123, ipv4 and ipv6, and the character
+// classes alpha, alphanum, numeric, number, hexadecimal, lowercase and uppercase, together with
+// startswith, endswith and contains. Like validator, bounds apply to the length of strings and
+// to the number of items of slices and maps. Other tags, alternatives written with | and tags
+// that would need more than one pattern return ErrUnsupportedValidatorTag.
+func ParseValidatorTag(tag string, t reflect.Type) (*StructTag, error) {
+	parsed := &StructTag{Keywords: map[string]interface{}{}}
+	if tag == "-" {
+		parsed.Ignore = true
+		return parsed, nil
+	}
+	kind := validatorKind(t)
+
+	for _, entry := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(entry, "=")
+		if strings.Contains(entry, "|") {
+			return nil, fmt.Errorf("%w: alternatives in %q", ErrUnsupportedValidatorTag, entry)
+		}
+
+		var err error
+		switch name {
+		case "", "omitempty":
+		case "required":
+			parsed.Required = true
+		case "min", "gte":
+			err = parsed.setBound(kind, 0, param, false)
+		case "max", "lte":
+			err = parsed.setBound(kind, 1, param, false)
+		case "gt":
+			err = parsed.setBound(kind, 0, param, true)
+		case "lt":
+			err = parsed.setBound(kind, 1, param, true)
+		case "eq":
+			if kind == "array" || kind == "object" {
+				// Like validator, eq compares the number of items of slices and maps.
+				if err = parsed.setBound(kind, 0, param, false); err == nil {
+					err = parsed.setBound(kind, 1, param, false)
+				}
+				break
+			}
+			parsed.Keywords["const"], err = validatorLiteral(kind, param)
+		case "len":
+			if err = parsed.setBound(kind, 0, param, false); err == nil {
+				err = parsed.setBound(kind, 1, param, false)
+			}
+		case "oneof":
+			values := make([]interface{}, 0)
+			for _, field := range strings.Fields(param) {
+				value, literalErr := validatorLiteral(kind, field)
+				if literalErr != nil {
+					return nil, literalErr
+				}
+				values = append(values, value)
+			}
+			parsed.Keywords["enum"] = values
+		case "unique":
+			parsed.Keywords["uniqueItems"] = true
+		case "startswith":
+			err = parsed.setPattern("^" + regexp.QuoteMeta(param))
+		case "endswith":
+			err = parsed.setPattern(regexp.QuoteMeta(param) + "$")
+		case "contains":
+			err = parsed.setPattern(regexp.QuoteMeta(param))
+		default:
+			if format, ok := validatorFormats[name]; ok {
+				parsed.Keywords["format"] = format
+			} else if pattern, ok := validatorPatterns[name]; ok {
+				err = parsed.setPattern(pattern)
+			} else {
+				err = fmt.Errorf("%w: %q", ErrUnsupportedValidatorTag, name)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parsed, nil
+}
+
+// setBound sets the lower (side 0) or upper (side 1) bound of a value of kind.
+func (t *StructTag) setBound(kind string, side int, param string, exclusive bool) error {
+	keywords, ok := validatorBounds[kind]
+	if !ok {
+		return fmt.Errorf("%w: bounds of a value that is not a string, number, slice or map", ErrUnsupportedValidatorTag)
+	}
+	bound, ok := new(big.Rat).SetString(param)
+	if !ok || strings.Contains(param, "/") {
+		return fmt.Errorf("%w: bound %q is not a number", ErrInvalidStructTag, param)
+	}
+
+	if kind == "number" {
+		keyword := keywords[side]
+		if exclusive {
+			keyword = []string{"exclusiveMinimum", "exclusiveMaximum"}[side]
+		}
+		t.Keywords[keyword] = json.Number(param)
+		return nil
+	}
+
+	// Lengths and counts are integers, so an exclusive bound moves to the next integer.
+	if !bound.IsInt() || bound.Sign() < 0 {
+		return fmt.Errorf("%w: length %q is not a non-negative integer", ErrInvalidStructTag, param)
+	}
+	n := bound.Num().Int64()
+	if exclusive {
+		n += []int64{1, -1}[side]
+	}
+	if n < 0 {
+		return fmt.Errorf("%w: length below %q cannot be satisfied", ErrInvalidStructTag, param)
+	}
+	t.Keywords[keywords[side]] = json.Number(strconv.FormatInt(n, 10))
+	return nil
+}
+
+// setPattern sets the pattern keyword, which holds a single regular expression.
+func (t *StructTag) setPattern(pattern string) error {
+	if _, ok := t.Keywords["pattern"]; ok {
+		return fmt.Errorf("%w: more than one pattern", ErrUnsupportedValidatorTag)
+	}
+	t.Keywords["pattern"] = pattern
+	return nil
+}
+
+// validatorKind returns the JSON type of the values of t that validator bounds apply to.
+func validatorKind(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map:
+		return "object"
+	case reflect.Bool:
+		return "boolean"
+	}
+	return ""
+}
+
+// validatorLiteral converts the parameter of eq or oneof to a value of kind.
+func validatorLiteral(kind, param string) (interface{}, error) {
+	switch kind {
+	case "number":
+		if _, ok := new(big.Rat).SetString(param); !ok || strings.Contains(param, "/") {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrInvalidStructTag, param)
+		}
+		return json.Number(param), nil
+	case "boolean":
+		b, err := strconv.ParseBool(param)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a boolean", ErrInvalidStructTag, param)
+		}
+		return b, nil
+	}
+	return param, nil
+}
+
+// validatorTagOrder lists the keywords in the order ValidatorTag writes them.
+var validatorTagOrder = []string{
+	"minLength", "maxLength", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"minItems", "maxItems", "minProperties", "maxProperties", "const", "enum", "uniqueItems", "format", "pattern",
+}
+
+// structTagAnnotations are the keywords that do not constrain values, which ValidatorTag leaves out.
+var structTagAnnotations = []string{"title", "description", "default", "examples", "deprecated", "readOnly", "writeOnly"}
+
+// ValidatorTag converts t back into a github.com/go-playground/validator tag, the reverse of
+// ParseValidatorTag. Annotations such as title and default have no
+// counterpart and are left out; constraints validator cannot express, such as multipleOf or an
+// arbitrary pattern, return ErrUnsupportedValidatorTag.
+func (t *StructTag) ValidatorTag() (string, error) {
+	if t.Ignore {
+		return "-", nil
+	}
+	for keyword := range t.Keywords {
+		if !containsString(validatorTagOrder, keyword) && !containsString(structTagAnnotations, keyword) {
+			return "", fmt.Errorf("%w: %q has no validator tag", ErrUnsupportedValidatorTag, keyword)
+		}
+	}
+
+	var entries []string
+	if t.Required {
+		entries = append(entries, "required")
+	}
+	for _, keyword := range validatorTagOrder {
+		value, ok := t.Keywords[keyword]
+		if !ok {
+			continue
+		}
+		entry, err := validatorEntry(keyword, value)
+		if err != nil {
+			return "", err
+		}
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return strings.Join(entries, ","), nil
+}
+
+// validatorEntry returns the validator tag expressing a keyword with value.
+func validatorEntry(keyword string, value interface{}) (string, error) {
+	switch keyword {
+	case "minLength", "minimum", "minItems", "minProperties":
+		return "min=" + fmt.Sprint(value), nil
+	case "maxLength", "maximum", "maxItems", "maxProperties":
+		return "max=" + fmt.Sprint(value), nil
+	case "exclusiveMinimum":
+		return "gt=" + fmt.Sprint(value), nil
+	case "exclusiveMaximum":
+		return "lt=" + fmt.Sprint(value), nil
+	case "const":
+		param := fmt.Sprint(value)
+		if strings.ContainsAny(param, ",|") {
+			return "", fmt.Errorf("%w: const value %q", ErrUnsupportedValidatorTag, param)
+		}
+		return "eq=" + param, nil
+	case "uniqueItems":
+		if value == true {
+			return "unique", nil
+		}
+		return "", nil
+	case "enum":
+		values, _ := value.([]interface{})
+		params := make([]string, 0, len(values))
+		for _, v := range values {
+			param := fmt.Sprint(v)
+			if param == "" || strings.ContainsAny(param, " ,|") {
+				return "", fmt.Errorf("%w: enum value %q", ErrUnsupportedValidatorTag, param)
+			}
+			params = append(params, param)
+		}
+		return "oneof=" + strings.Join(params, " "), nil
+	case "format":
+		for name, format := range validatorFormats {
+			if format == value && name != "url" {
+				return name, nil
+			}
+		}
+	case "pattern":
+		for name, pattern := range validatorPatterns {
+			if pattern == value {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: %s %v has no validator tag", ErrUnsupportedValidatorTag, keyword, value)
+}
@@ -0,0 +1,67 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValidatorTag(t *testing.T) {
+	type account struct {
+		Email string            `validate:"required,email,max=254"`
+		Age   int               `validate:"omitempty,gte=18,lt=130"`
+		Code  string            `validate:"len=6,alphanum"`
+		Roles []string          `validate:"gt=0,unique"`
+		Plan  string            `validate:"oneof=free pro"`
+		Meta  map[string]string `validate:"eq=2"`
+	}
+	typ := reflect.TypeOf(account{})
+	expected := map[string]map[string]interface{}{
+		"Email": {"format": "email", "maxLength": json.Number("254")},
+		"Age":   {"minimum": json.Number("18"), "exclusiveMaximum": json.Number("130")},
+		"Code":  {"minLength": json.Number("6"), "maxLength": json.Number("6"), "pattern": "^[a-zA-Z0-9]+$"},
+		"Roles": {"minItems": json.Number("1"), "uniqueItems": true},
+		"Plan":  {"enum": []interface{}{"free", "pro"}},
+		"Meta":  {"minProperties": json.Number("2"), "maxProperties": json.Number("2")},
+	}
+	for name, keywords := range expected {
+		field, _ := typ.FieldByName(name)
+		tag, err := ParseValidatorTag(field.Tag.Get(ValidatorTagKey), field.Type)
+		require.NoError(t, err, name)
+		assert.Equal(t, keywords, tag.Keywords, name)
+		assert.Equal(t, name == "Email", tag.Required, name)
+	}
+}
+
+func TestParseValidatorTagUnsupported(t *testing.T) {
+	for _, tag := range []string{"dive", "email|url", "alpha,startswith=a", "min=1"} {
+		_, err := ParseValidatorTag(tag, reflect.TypeOf(true))
+		assert.ErrorIs(t, err, ErrUnsupportedValidatorTag, tag)
+	}
+	_, err := ParseValidatorTag("min=x", reflect.TypeOf(""))
+	assert.ErrorIs(t, err, ErrInvalidStructTag)
+}
+
+func TestStructTagValidatorTag(t *testing.T) {
+	tag, err := ParseStructTag("required,minLength=1,maxLength=64,format=email,title=Email")
+	require.NoError(t, err)
+	validate, err := tag.ValidatorTag()
+	require.NoError(t, err)
+	assert.Equal(t, "required,min=1,max=64,email", validate)
+
+	roundTrip, err := ParseValidatorTag(validate, reflect.TypeOf(""))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"minLength": json.Number("1"),
+		"maxLength": json.Number("64"),
+		"format":    "email",
+	}, roundTrip.Keywords)
+
+	tag, err = ParseStructTag("multipleOf=5")
+	require.NoError(t, err)
+	_, err = tag.ValidatorTag()
+	assert.ErrorIs(t, err, ErrUnsupportedValidatorTag)
+}
@@ -0,0 +1,83 @@
+package jsonschema
+
+import (
+	"sort"
+	"strconv"
+)
+
+// subschema is a schema nested in another schema, together with its JSON Pointer relative to the parent.
+type subschema struct {
+	pointer string
+	schema  *Schema
+}
+
+// childSchemas returns the direct subschemas of s in a deterministic order.
+// Referenced schemas ($ref, $dynamicRef) are not children and are not returned.
+func childSchemas(s *Schema) []subschema {
+	if s == nil || s.Boolean != nil {
+		return nil
+	}
+
+	var children []subschema
+	addSchema := func(pointer string, schema *Schema) {
+		if schema != nil {
+			children = append(children, subschema{pointer: pointer, schema: schema})
+		}
+	}
+	addList := func(keyword string, schemas []*Schema) {
+		for i, schema := range schemas {
+			addSchema("/"+keyword+"/"+strconv.Itoa(i), schema)
+		}
+	}
+	addMap := func(keyword string, schemas map[string]*Schema) {
+		keys := make([]string, 0, len(schemas))
+		for key := range schemas {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			addSchema("/"+keyword+"/"+escapeJSONPointer(key), schemas[key])
+		}
+	}
+
+	addMap("$defs", s.Defs)
+	addList("allOf", s.AllOf)
+	addList("anyOf", s.AnyOf)
+	addList("oneOf", s.OneOf)
+	addSchema("/not", s.Not)
+	addSchema("/if", s.If)
+	addSchema("/then", s.Then)
+	addSchema("/else", s.Else)
+	addMap("dependentSchemas", s.DependentSchemas)
+	addList("prefixItems", s.PrefixItems)
+	addSchema("/items", s.Items)
+	addSchema("/contains", s.Contains)
+	if s.Properties != nil {
+		addMap("properties", *s.Properties)
+	}
+	if s.PatternProperties != nil {
+		addMap("patternProperties", *s.PatternProperties)
+	}
+	addSchema("/additionalProperties", s.AdditionalProperties)
+	addSchema("/propertyNames", s.PropertyNames)
+	addSchema("/unevaluatedItems", s.UnevaluatedItems)
+	addSchema("/unevaluatedProperties", s.UnevaluatedProperties)
+	addSchema("/contentSchema", s.ContentSchema)
+
+	return children
+}
+
+// walkSchema calls fn for s and every schema nested in it, depth first, with the JSON Pointer
+// of each schema relative to s. Returning false from fn skips the subschemas of that schema.
+func walkSchema(s *Schema, fn func(pointer string, schema *Schema) bool) {
+	walkSchemaFrom("", s, fn)
+}
+
+func walkSchemaFrom(pointer string, s *Schema, fn func(pointer string, schema *Schema) bool) {
+	if s == nil || !fn(pointer, s) {
+		return
+	}
+	for _, child := range childSchemas(s) {
+		walkSchemaFrom(pointer+child.pointer, child.schema, fn)
+	}
+}
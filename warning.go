@@ -0,0 +1,152 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CompileWarning is a non-fatal issue of a schema document that compiles, reported so that
+// mistakes surface before they show up as data being accepted or rejected unexpectedly.
+//
+// The codes of warnings are:
+//   - deprecated_draft: the document declares a draft before 2020-12.
+//   - ignored_keyword: the draft of the schema ignores the keyword, such as const in draft-04 or
+//     the siblings of $ref before 2019-09.
+//   - unknown_keyword: the keyword is unknown but close to a standard one, likely misspelled.
+//   - unanchored_pattern: a regular expression of pattern or patternProperties matches anywhere
+//     in the string, see Schema.UnanchoredPatterns.
+//   - unsatisfiable_schema: no instance can satisfy the subschema, see Schema.Unsatisfiable.
+type CompileWarning struct {
+	URI             string `json:"uri,omitempty"`   // URI of the document, if it has one.
+	KeywordLocation string `json:"keywordLocation"` // JSON Pointer of the keyword or subschema in the document.
+	Code            string `json:"code"`
+	Message         string `json:"message"`
+}
+
+// String returns the location and the message of the warning.
+func (w CompileWarning) String() string {
+	return w.URI + w.KeywordLocation + ": " + w.Message
+}
+
+// SetWarningHandler sets the function receiving the warnings of the documents compiled afterwards,
+// including the documents fetched to resolve references. Warnings never make compilation fail;
+// without a handler, documents are not analyzed for them.
+func (c *Compiler) SetWarningHandler(handler func(CompileWarning)) *Compiler {
+	c.WarningHandler = handler
+	return c
+}
+
+// CompileWithWarnings compiles a schema like Compile, and also returns the warnings of the
+// documents compiled meanwhile, which are reported to the warning handler as well.
+func (c *Compiler) CompileWithWarnings(jsonSchema []byte, uris ...string) (*Schema, []CompileWarning, error) {
+	c.compileMu.Lock()
+	defer c.compileMu.Unlock()
+
+	var warnings []CompileWarning
+	handler := c.WarningHandler
+	c.WarningHandler = func(warning CompileWarning) {
+		warnings = append(warnings, warning)
+		if handler != nil {
+			handler(warning)
+		}
+	}
+	defer func() { c.WarningHandler = handler }()
+
+	schema, err := c.compile(jsonSchema, uris...)
+	return schema, warnings, err
+}
+
+// compileWarnings collects the warnings of a document until its URI is known. Adding to a nil
+// list does nothing, so that documents are not analyzed without a warning handler.
+type compileWarnings []CompileWarning
+
+// add adds a warning about the keyword or subschema at pointer.
+func (w *compileWarnings) add(pointer, code, message string) {
+	if w != nil {
+		*w = append(*w, CompileWarning{KeywordLocation: "#" + pointer, Code: code, Message: message})
+	}
+}
+
+// reportWarnings analyzes the compiled schema and reports its warnings, with those found while
+// compiling it, to the warning handler in order of location.
+func (c *Compiler) reportWarnings(schema *Schema, warnings compileWarnings) {
+	walkSchema(schema, func(pointer string, subschema *Schema) bool {
+		for _, keyword := range sortedKeys(subschema.Extensions) {
+			if c.isCustomKeyword(keyword) {
+				continue
+			}
+			if suggestion := similarKeyword(keyword); suggestion != "" {
+				warnings.add(pointer+"/"+escapeJSONPointer(keyword), "unknown_keyword", fmt.Sprintf("Unknown keyword %s, did you mean %s?", keyword, suggestion))
+			}
+		}
+		return true
+	})
+	if !c.AnchorPatterns {
+		for _, pattern := range schema.UnanchoredPatterns() {
+			warnings.add(strings.TrimPrefix(pattern.KeywordLocation, "#"), "unanchored_pattern", fmt.Sprintf("Pattern %s matches anywhere in the string; anchor it with ^ and $ to match whole strings", pattern.Pattern))
+		}
+	}
+	for _, finding := range schema.Unsatisfiable() {
+		warnings.add(strings.TrimPrefix(finding.KeywordLocation, "#"), "unsatisfiable_schema", "No instance can satisfy the schema: "+finding.Reason)
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].KeywordLocation < warnings[j].KeywordLocation
+	})
+	for _, warning := range warnings {
+		warning.URI = schema.uri
+		c.WarningHandler(warning)
+	}
+}
+
+// isCustomKeyword reports whether keyword is an extension keyword of x-, or a registered keyword.
+func (c *Compiler) isCustomKeyword(keyword string) bool {
+	if strings.HasPrefix(keyword, "x-") || c.Keywords[keyword] != nil {
+		return true
+	}
+	for _, vocabulary := range c.Vocabularies {
+		if vocabulary.Keywords[keyword] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// similarKeyword returns the standard keyword that keyword most likely misspells, or an empty
+// string: the closest one in edit distance, ignoring case, within one edit for short keywords and
+// two otherwise.
+func similarKeyword(keyword string) string {
+	limit := 2
+	if len(keyword) <= 4 {
+		limit = 1
+	}
+	best, bestDistance := "", limit+1
+	for _, known := range sortedKeys(knownKeywords) {
+		if distance := editDistance(strings.ToLower(keyword), strings.ToLower(known)); distance < bestDistance {
+			best, bestDistance = known, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,94 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileWarnings(t *testing.T) {
+	compiler := NewCompiler()
+	schema, warnings, err := compiler.CompileWithWarnings([]byte(`{
+		"id": "https://example.com/legacy.json",
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"properties": {
+			"name": {"type": "string", "minLenght": 1, "x-label": "Name"},
+			"code": {"type": "string", "pattern": "[0-9]+"},
+			"total": {"type": "number", "const": 1},
+			"owner": {"$ref": "#/definitions/owner", "description": "ignored silently", "type": "object"},
+			"never": {"allOf": [{"type": "string"}, {"type": "integer"}]}
+		},
+		"definitions": {"owner": {"type": "object"}}
+	}`))
+	require.NoError(t, err)
+	require.NotNil(t, schema)
+
+	locations := map[string]string{}
+	for _, warning := range warnings {
+		assert.Equal(t, "https://example.com/legacy.json", warning.URI)
+		locations[warning.KeywordLocation] = warning.Code
+	}
+	assert.Equal(t, map[string]string{
+		"#/$schema":                   "deprecated_draft",
+		"#/properties/code/pattern":   "unanchored_pattern",
+		"#/properties/name/minLenght": "unknown_keyword",
+		"#/properties/never":          "unsatisfiable_schema",
+		"#/properties/owner/type":     "ignored_keyword",
+		"#/properties/total/const":    "ignored_keyword",
+	}, locations)
+
+	for _, warning := range warnings {
+		if warning.Code == "unknown_keyword" {
+			assert.Equal(t, "Unknown keyword minLenght, did you mean minLength?", warning.Message)
+			assert.Equal(t, "https://example.com/legacy.json#/properties/name/minLenght: Unknown keyword minLenght, did you mean minLength?", warning.String())
+		}
+	}
+	assert.Nil(t, compiler.WarningHandler, "the handler is restored")
+}
+
+func TestWarningHandler(t *testing.T) {
+	var warnings []CompileWarning
+	compiler := NewCompiler().SetWarningHandler(func(warning CompileWarning) {
+		warnings = append(warnings, warning)
+	})
+
+	_, err := compiler.Compile([]byte(`{"type": "object", "propertys": {}, "required": ["a"]}`))
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "#/propertys", warnings[0].KeywordLocation)
+	assert.Equal(t, "Unknown keyword propertys, did you mean properties?", warnings[0].Message)
+
+	warnings = nil
+	_, err = compiler.Compile([]byte(`{"properties": {"a": {"maximum": 1, "titel": "A", "unknownThing": true}}}`))
+	require.NoError(t, err)
+	require.Len(t, warnings, 1, "only keywords close to standard ones are reported")
+	assert.Equal(t, "#/properties/a/titel", warnings[0].KeywordLocation)
+
+	warnings = nil
+	compiler.SetAnchorPatterns(true)
+	_, err = compiler.Compile([]byte(`{"pattern": "[0-9]+"}`))
+	require.NoError(t, err)
+	assert.Empty(t, warnings, "patterns are anchored by the compiler")
+}
+
+func TestWarningsCustomKeywords(t *testing.T) {
+	var warnings []CompileWarning
+	compiler := NewCompiler().
+		SetWarningHandler(func(warning CompileWarning) { warnings = append(warnings, warning) }).
+		RegisterKeyword("maximal", func(*Schema, interface{}) (Keyword, error) {
+			return KeywordFunc(func(*KeywordContext, interface{}) *EvaluationError { return nil }), nil
+		})
+
+	_, err := compiler.Compile([]byte(`{"maximal": 1}`))
+	require.NoError(t, err)
+	assert.Empty(t, warnings, "registered keywords are not misspellings")
+}
+
+func TestSimilarKeyword(t *testing.T) {
+	assert.Equal(t, "maxItems", similarKeyword("maxitems"))
+	assert.Equal(t, "required", similarKeyword("requried"))
+	assert.Equal(t, "$ref", similarKeyword("ref"))
+	assert.Equal(t, "", similarKeyword("foo"))
+	assert.Equal(t, "", similarKeyword("format_version"))
+}
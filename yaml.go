@@ -0,0 +1,88 @@
+//go:build !jsonschema_core
+
+package jsonschema
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+
+	"github.com/goccy/go-json"
+	"github.com/goccy/go-yaml"
+)
+
+// setupMarkupMediaTypes configures the handlers of the XML and YAML media types.
+func (c *Compiler) setupMarkupMediaTypes() {
+	c.MediaTypes["application/xml"] = func(data []byte) (interface{}, error) {
+		var temp interface{}
+		if err := xml.Unmarshal(data, &temp); err != nil {
+			return nil, ErrXMLUnmarshalError
+		}
+		return temp, nil
+	}
+
+	c.MediaTypes["application/yaml"] = func(data []byte) (interface{}, error) {
+		var temp interface{}
+		if err := yaml.Unmarshal(data, &temp); err != nil {
+			return nil, ErrYAMLUnmarshalError
+		}
+		return temp, nil
+	}
+}
+
+// yamlToJSON converts a YAML document into its JSON equivalent.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, ErrYAMLUnmarshalError
+	}
+
+	value, err := normalizeYAMLValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(value)
+}
+
+// normalizeYAMLValue rewrites values decoded from YAML into types that have a JSON representation.
+// Non-string mapping keys are converted to strings, and infinite or NaN numbers are rejected.
+func normalizeYAMLValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			normalized, err := normalizeYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = normalized
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized, err := normalizeYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			object[fmt.Sprint(key)] = normalized
+		}
+		return object, nil
+	case []interface{}:
+		for i, item := range v {
+			normalized, err := normalizeYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = normalized
+		}
+		return v, nil
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return nil, ErrYAMLNonFiniteNumber
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
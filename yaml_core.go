@@ -0,0 +1,12 @@
+//go:build jsonschema_core
+
+package jsonschema
+
+// setupMarkupMediaTypes registers no XML or YAML media type in the core build, which leaves out
+// their parsers.
+func (c *Compiler) setupMarkupMediaTypes() {}
+
+// yamlToJSON reports that YAML is not supported in the core build.
+func yamlToJSON([]byte) ([]byte, error) {
+	return nil, ErrYAMLUnsupported
+}
@@ -0,0 +1,47 @@
+//go:build !jsonschema_core
+
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileYAML(t *testing.T) {
+	compiler := NewCompiler()
+	schema, err := compiler.CompileYAML([]byte(`
+# Schemas authored in YAML compile like their JSON counterparts.
+$id: http://example.com/yaml-schema
+type: object
+properties:
+  answer:
+    enum: [yes, no]
+  released:
+    type: string
+    format: date
+  200:
+    type: integer
+required: [answer]
+`))
+	require.NoError(t, err)
+
+	assert.True(t, schema.Validate(map[string]interface{}{"answer": "yes", "released": "2024-01-01", "200": 1}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"answer": true}).IsValid())
+	assert.False(t, schema.Validate(map[string]interface{}{"answer": "no", "200": "x"}).IsValid())
+
+	cached, err := compiler.GetSchema("http://example.com/yaml-schema")
+	require.NoError(t, err)
+	assert.Same(t, schema, cached)
+}
+
+func TestCompileYAMLErrors(t *testing.T) {
+	compiler := NewCompiler()
+
+	_, err := compiler.CompileYAML([]byte("type: object\n  properties: {}\n bad"))
+	assert.ErrorIs(t, err, ErrYAMLUnmarshalError)
+
+	_, err = compiler.CompileYAML([]byte("maximum: .inf\n"))
+	assert.ErrorIs(t, err, ErrYAMLNonFiniteNumber)
+}